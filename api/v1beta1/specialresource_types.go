@@ -17,8 +17,11 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 
 	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
 	operatorv1 "github.com/openshift/api/operator/v1"
@@ -85,21 +88,174 @@ type SpecialResourceDriverContainer struct {
 
 	// +kubebuilder:validation:Optional
 	Artifacts SpecialResourceArtifacts `json:"artifacts,omitempty"`
+
+	// BuildRetries is the number of times a driver-container Build that failed for a transient reason, e.g. fetching
+	// the source or pushing the image, is triggered again.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	BuildRetries int32 `json:"buildRetries,omitempty"`
+
+	// BuildOverrides are applied to the BuildConfigs and build Jobs rendered from the chart, so that driver builds can
+	// be scheduled on dedicated build nodes.
+	// +kubebuilder:validation:Optional
+	BuildOverrides SpecialResourceBuildOverrides `json:"buildOverrides,omitempty"`
+
+	// Ccache is a persistent compiler cache shared by the build Jobs, so that rebuilding the driver for a new kernel
+	// only recompiles what changed.
+	// +kubebuilder:validation:Optional
+	Ccache SpecialResourceCcache `json:"ccache,omitempty"`
+
+	// Entitled mounts the cluster entitlement into the driver-container BuildConfigs, for builds that need to install
+	// RHEL packages. The etc-pki-entitlement Secret of the openshift-config-managed namespace is required.
+	// +kubebuilder:validation:Optional
+	Entitled bool `json:"entitled,omitempty"`
+}
+
+// SpecialResourceCcache describes the PersistentVolumeClaim backing the compiler cache of the driver builds.
+type SpecialResourceCcache struct {
+	// Enabled creates the PersistentVolumeClaim in the namespace of the SpecialResource and mounts it into the build
+	// Jobs. The claim is deleted once disabled.
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Size of the volume, defaults to 5Gi. ccache evicts the least recently used objects above 90% of it.
+	// +kubebuilder:validation:Optional
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// StorageClassName of the volume, the default storage class is used if empty.
+	// +kubebuilder:validation:Optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// AccessMode of the volume, defaults to ReadWriteOnce. ReadWriteMany allows concurrent builds on different nodes.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteMany
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+}
+
+// SpecialResourceBuildOverrides describes scheduling and resource settings applied to the driver builds.
+type SpecialResourceBuildOverrides struct {
+	// Resources overrides the resource requests and limits of the build.
+	// +kubebuilder:validation:Optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector replaces the nodeSelector of the build.
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are appended to the tolerations of the Job pod template. BuildConfigs do not support tolerations.
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// SpecialResourcePrerequisites describes the host settings of the nodes a SpecialResource needs.
+type SpecialResourcePrerequisites struct {
+	// Sysctls are the kernel parameters set on boot, by name.
+	// +kubebuilder:validation:Optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+
+	// Hugepages are the huge pages reserved on boot, per size.
+	// +kubebuilder:validation:Optional
+	Hugepages []SpecialResourceHugepages `json:"hugepages,omitempty"`
+
+	// UdevRules are the lines of the udev rules file of the SpecialResource, e.g. to set the permissions of the
+	// device nodes.
+	// +kubebuilder:validation:Optional
+	UdevRules []string `json:"udevRules,omitempty"`
+}
+
+// SpecialResourceHugepages describes the huge pages of a size reserved on every node.
+type SpecialResourceHugepages struct {
+	// Size is the size of the pages, 2Mi or 1Gi.
+	// +kubebuilder:validation:Pattern=`^(2Mi|1Gi)$`
+	Size string `json:"size"`
+
+	// Count is the number of pages reserved on every node.
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count"`
 }
 
+// SpecialResourcePodOverrides describes scheduling and resource settings applied to all the workloads rendered from
+// the chart.
+type SpecialResourcePodOverrides struct {
+	// Tolerations are appended to the tolerations of the pod template.
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PriorityClassName overrides the priorityClassName of the pod template.
+	// +kubebuilder:validation:Optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Resources overrides the resource requests and limits of every container in the pod template.
+	// +kubebuilder:validation:Optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Labels are added to the workload and to its pod template.
+	// +kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// SpecialResourceManifests describes plain manifests used instead of a Helm chart.
+type SpecialResourceManifests struct {
+	// ConfigMap is the name of the ConfigMap in the operator namespace holding the manifests, one file per key.
+	// Keys starting with a four-digit number are states, like the templates of a chart.
+	// +kubebuilder:validation:Required
+	ConfigMap string `json:"configMap"`
+
+	// Kustomize builds the kustomization.yaml of the ConfigMap instead of applying its keys as they are.
+	// +kubebuilder:validation:Optional
+	Kustomize bool `json:"kustomize,omitempty"`
+}
+
+// HardwareFact is a class of per-node hardware information gathered from NFD labels.
+// +kubebuilder:validation:Enum=pci;numa
+type HardwareFact string
+
+const (
+	// HardwareFactPCI collects the PCI devices present on each node.
+	HardwareFactPCI HardwareFact = "pci"
+
+	// HardwareFactNUMA collects whether each node has more than one NUMA node.
+	HardwareFactNUMA HardwareFact = "numa"
+)
+
+// SchedulerDefaults is whether the workloads of a SpecialResource follow the defaults of the cluster Scheduler config.
+// +kubebuilder:validation:Enum=Inherit;Ignore
+type SchedulerDefaults string
+
+const (
+	// SchedulerDefaultsInherit adds the default node selector of the cluster to the nodeSelector of the workloads.
+	SchedulerDefaultsInherit SchedulerDefaults = "Inherit"
+
+	// SchedulerDefaultsIgnore opts the namespace of the SpecialResource out of the default node selector.
+	SchedulerDefaultsIgnore SchedulerDefaults = "Ignore"
+)
+
 // SpecialResourceSpec describes the desired state of the resource, such as the chart to be used and a selector
 // on which nodes it should be installed.
 // More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
 // +kubebuilder:validation:Required
 type SpecialResourceSpec struct {
 	// Chart describes the Helm chart that needs to be installed.
-	// +kubebuilder:validation:Required
+	// It is required unless Manifests is set. An empty version defaults to the
+	// latest version of the chart in the repository.
+	// +kubebuilder:validation:Optional
 	Chart helmerv1beta1.HelmChart `json:"chart"`
 
+	// Manifests are plain manifests installed instead of the chart.
+	// +kubebuilder:validation:Optional
+	Manifests *SpecialResourceManifests `json:"manifests,omitempty"`
+
 	// Namespace describes in which namespace the chart will be installed.
+	// The webhook defaults it to the name of the SpecialResource.
 	// +kubebuilder:validation:Required
 	Namespace string `json:"namespace"`
 
+	// ServiceAccountName is the ServiceAccount of Namespace impersonated to apply the objects of the chart,
+	// it restricts what the chart may create to what the ServiceAccount is allowed to.
+	// The operator's own identity is used if empty.
+	// +kubebuilder:validation:Optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
 	// ForceUpgrade is not used.
 	// +kubebuilder:validation:Optional
 	ForceUpgrade bool `json:"forceUpgrade"`
@@ -109,19 +265,77 @@ type SpecialResourceSpec struct {
 	Debug bool `json:"debug"`
 
 	// Set is a user-defined hierarchical value tree from where the chart takes its parameters.
+	// The tags of the image values are replaced by their digest.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:EmbeddedResource
 	Set unstructured.Unstructured `json:"set,omitempty"`
 
+	// ValuesFrom are values fetched from a secret store when the chart is rendered, they override Set.
+	// They are never written to the SpecialResource, e.g. for license tokens.
+	// +kubebuilder:validation:Optional
+	ValuesFrom []SpecialResourceValueFrom `json:"valuesFrom,omitempty"`
+
+	// DisableStates are the states of the chart that are not applied, by name without the order prefix and the
+	// extension, e.g. device-monitoring for templates/3000-device-monitoring.yaml. The objects of a state applied
+	// before it was disabled are kept.
+	// +kubebuilder:validation:Optional
+	DisableStates []string `json:"disableStates,omitempty"`
+
 	// DriverContainer is not used.
 	// +kubebuilder:validation:Optional
 	DriverContainer SpecialResourceDriverContainer `json:"driverContainer,omitempty"`
 
 	// NodeSelector is used to determine on which nodes the software stack should be installed.
+	// Defaults to the worker nodes.
 	// +kubebuilder:validation:Optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
+	// ReadyNodesOnly leaves the NotReady and cordoned nodes out of the runtime values, e.g. the kernel versions
+	// the driver images are built for, so that a node stuck in the middle of an upgrade does not trigger a build
+	// for a kernel that will never run workloads.
+	// +kubebuilder:validation:Optional
+	ReadyNodesOnly bool `json:"readyNodesOnly,omitempty"`
+
+	// KernelArguments are appended to the kernel command line of the machines of MachineConfigPool.
+	// +kubebuilder:validation:Optional
+	KernelArguments []string `json:"kernelArguments,omitempty"`
+
+	// ModuleBlacklist are kernel modules the machines of MachineConfigPool never load, e.g. an in-tree driver
+	// conflicting with the one of the chart.
+	// +kubebuilder:validation:Optional
+	ModuleBlacklist []string `json:"moduleBlacklist,omitempty"`
+
+	// MachineConfigPool is the pool the MachineConfig of KernelArguments, ModuleBlacklist and Prerequisites is rolled
+	// out to, its machines reboot. The states are applied once they all run it. Defaults to worker.
+	// +kubebuilder:validation:Optional
+	MachineConfigPool string `json:"machineConfigPool,omitempty"`
+
+	// Prerequisites are host settings the devices need, applied through the MachineConfig of MachineConfigPool and
+	// verified on the selected nodes before the device-plugin state.
+	// +kubebuilder:validation:Optional
+	Prerequisites *SpecialResourcePrerequisites `json:"prerequisites,omitempty"`
+
+	// SchedulerDefaults is whether the workloads follow the default node selector of the cluster Scheduler config.
+	// Inherit, the default, adds it to NodeSelector, which wins on conflicting keys. Ignore opts the namespace out
+	// of it, e.g. for drivers that also run on infra nodes.
+	// +kubebuilder:validation:Optional
+	SchedulerDefaults SchedulerDefaults `json:"schedulerDefaults,omitempty"`
+
+	// PodOverrides are applied to all the DaemonSets and Deployments rendered from the chart.
+	// +kubebuilder:validation:Optional
+	PodOverrides SpecialResourcePodOverrides `json:"podOverrides,omitempty"`
+
+	// Priority orders the reconciles of the SpecialResources, e.g. after a restart of the operator, a
+	// SpecialResource waits until the ones of a higher priority were reconciled once. Defaults to 0.
+	// +kubebuilder:validation:Optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// HardwareFacts is the list of per-node hardware facts exposed to the chart as .Values.nodeHardware.
+	// Only the facts listed here are collected.
+	// +kubebuilder:validation:Optional
+	HardwareFacts []HardwareFact `json:"hardwareFacts,omitempty"`
+
 	// Dependencies is a list of dependencies required by this SpecialReosurce.
 	// +kubebuilder:validation:Optional
 	Dependencies []SpecialResourceDependency `json:"dependencies,omitempty"`
@@ -140,6 +354,34 @@ type SpecialResourceDependency struct {
 	Set unstructured.Unstructured `json:"set,omitempty"`
 }
 
+// SpecialResourceValueFrom is a chart value fetched from a secret store.
+type SpecialResourceValueFrom struct {
+	// Key is the path of the value in the chart values, with dots between the levels, e.g. license.token.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+
+	// SecretRef references the secret holding the value.
+	// +kubebuilder:validation:Required
+	SecretRef SpecialResourceSecretRef `json:"secretRef"`
+}
+
+// SpecialResourceSecretRef references a key of a secret of a secret store.
+type SpecialResourceSecretRef struct {
+	// Provider is the secret store holding the secret. The kubernetes provider reads the Secrets of the namespace
+	// of the SpecialResource, other providers have to be registered with the operator.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=kubernetes
+	Provider string `json:"provider,omitempty"`
+
+	// Name is the name of the secret in the store.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Key is the key of the value in the secret.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+}
+
 // These are valid conditions of a SpecialResource.
 const (
 	// Ready means the SpecialResource is operational, ie. the recipe was reconciled.
@@ -165,6 +407,101 @@ type SpecialResourceStatus struct {
 	// +patchStrategy=merge
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// BuildAttempts is the number of retries of the failed Builds, per BuildConfig.
+	// +optional
+	BuildAttempts map[string]int32 `json:"buildAttempts,omitempty"`
+
+	// NodeUnload is the state of the driver unload before a node reboot, per node:
+	// Pending, Unloaded or Failed.
+	// +optional
+	NodeUnload map[string]string `json:"nodeUnload,omitempty"`
+
+	// StateHashes are the hashes of the templates and values of the applied states, per state and, for the
+	// kernel affine states, kernel. A state with an unchanged hash is not applied again.
+	// +optional
+	StateHashes map[string]string `json:"stateHashes,omitempty"`
+
+	// MatchingNodes is the number of nodes matching the nodeSelector and the required node affinity of the
+	// workloads of the SpecialResource when they were last applied, per workload, e.g. DaemonSet simple-kmod/driver.
+	// +optional
+	MatchingNodes map[string]int32 `json:"matchingNodes,omitempty"`
+
+	// Chart is the chart loaded for the SpecialResource. Its digest is used by the next reconciles, until the
+	// chart of the spec changes.
+	// +optional
+	Chart *ResolvedChart `json:"chart,omitempty"`
+
+	// MachineConfig is the rollout of the MachineConfig of the KernelArguments and the ModuleBlacklist.
+	// +optional
+	MachineConfig *MachineConfigRollout `json:"machineConfig,omitempty"`
+
+	// Objects are the objects applied by the last run of every state, per state and, for the kernel affine states,
+	// kernel. The objects created before the chart are listed under prerequisites and the ones of the templates
+	// that are not states under nostate. At most 100 objects are listed per state.
+	// +optional
+	Objects map[string][]ObjectReference `json:"objects,omitempty"`
+}
+
+// MachineConfigRollout is the progress of the MachineConfig of a SpecialResource in its MachineConfigPool.
+type MachineConfigRollout struct {
+	// Name is the name of the MachineConfig.
+	Name string `json:"name"`
+
+	// Pool is the name of the MachineConfigPool.
+	Pool string `json:"pool"`
+
+	// MachineCount is the number of machines of the pool.
+	MachineCount int32 `json:"machineCount"`
+
+	// UpdatedMachineCount is the number of machines of the pool that rebooted with the MachineConfig.
+	UpdatedMachineCount int32 `json:"updatedMachineCount"`
+
+	// DegradedMachineCount is the number of machines of the pool that failed to apply a config.
+	DegradedMachineCount int32 `json:"degradedMachineCount"`
+
+	// Paused is true if the pool is paused, the MachineConfig is not rolled out until it is resumed.
+	Paused bool `json:"paused,omitempty"`
+
+	// Done is true once every machine of the pool rebooted with the MachineConfig.
+	Done bool `json:"done"`
+}
+
+// ObjectReference identifies an object applied for a SpecialResource.
+type ObjectReference struct {
+	// APIVersion is the group and version of the object.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the object.
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the object, empty for cluster scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the object.
+	Name string `json:"name"`
+
+	// UID is the UID of the object.
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// ResolvedChart is a chart version and repository, resolved to the digest of its archive.
+type ResolvedChart struct {
+	// Name is the name of the chart.
+	Name string `json:"name"`
+
+	// Version is the version of the chart that was loaded: the one of the spec, empty for the latest one, or the
+	// one it was upgraded to.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Repository is the URL of the repository of the chart.
+	Repository string `json:"repository"`
+
+	// Digest is the sha256 digest of the chart archive.
+	Digest string `json:"digest"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,50 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// SpecialResourceStore holds internal bookkeeping of the operator, e.g. the dependencies between SpecialResources.
+// It is cluster-scoped so that it outlives the namespaces of the operator and of the SpecialResources.
+// +kubebuilder:resource:path=specialresourcestores,scope=Cluster
+type SpecialResourceStore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Data contains the entries of the store.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SpecialResourceStoreList is a list of SpecialResourceStore objects.
+type SpecialResourceStoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of SpecialResourceStores.
+	Items []SpecialResourceStore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SpecialResourceStore{}, &SpecialResourceStoreList{})
+}
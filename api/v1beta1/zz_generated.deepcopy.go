@@ -6,10 +6,56 @@
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineConfigRollout) DeepCopyInto(out *MachineConfigRollout) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineConfigRollout.
+func (in *MachineConfigRollout) DeepCopy() *MachineConfigRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineConfigRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
+func (in *ObjectReference) DeepCopy() *ObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedChart) DeepCopyInto(out *ResolvedChart) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedChart.
+func (in *ResolvedChart) DeepCopy() *ResolvedChart {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedChart)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpecialResource) DeepCopyInto(out *SpecialResource) {
 	*out = *in
@@ -84,6 +130,56 @@ func (in *SpecialResourceBuildArgs) DeepCopy() *SpecialResourceBuildArgs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecialResourceBuildOverrides) DeepCopyInto(out *SpecialResourceBuildOverrides) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourceBuildOverrides.
+func (in *SpecialResourceBuildOverrides) DeepCopy() *SpecialResourceBuildOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecialResourceBuildOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecialResourceCcache) DeepCopyInto(out *SpecialResourceCcache) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourceCcache.
+func (in *SpecialResourceCcache) DeepCopy() *SpecialResourceCcache {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecialResourceCcache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpecialResourceClaims) DeepCopyInto(out *SpecialResourceClaims) {
 	*out = *in
@@ -141,6 +237,8 @@ func (in *SpecialResourceDriverContainer) DeepCopyInto(out *SpecialResourceDrive
 	*out = *in
 	out.Source = in.Source
 	in.Artifacts.DeepCopyInto(&out.Artifacts)
+	in.BuildOverrides.DeepCopyInto(&out.BuildOverrides)
+	in.Ccache.DeepCopyInto(&out.Ccache)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourceDriverContainer.
@@ -168,6 +266,21 @@ func (in *SpecialResourceGit) DeepCopy() *SpecialResourceGit {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecialResourceHugepages) DeepCopyInto(out *SpecialResourceHugepages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourceHugepages.
+func (in *SpecialResourceHugepages) DeepCopy() *SpecialResourceHugepages {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecialResourceHugepages)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpecialResourceImages) DeepCopyInto(out *SpecialResourceImages) {
 	*out = *in
@@ -235,6 +348,98 @@ func (in *SpecialResourcePaths) DeepCopy() *SpecialResourcePaths {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecialResourceManifests) DeepCopyInto(out *SpecialResourceManifests) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourceManifests.
+func (in *SpecialResourceManifests) DeepCopy() *SpecialResourceManifests {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecialResourceManifests)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecialResourcePodOverrides) DeepCopyInto(out *SpecialResourcePodOverrides) {
+	*out = *in
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourcePodOverrides.
+func (in *SpecialResourcePodOverrides) DeepCopy() *SpecialResourcePodOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecialResourcePodOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecialResourcePrerequisites) DeepCopyInto(out *SpecialResourcePrerequisites) {
+	*out = *in
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hugepages != nil {
+		in, out := &in.Hugepages, &out.Hugepages
+		*out = make([]SpecialResourceHugepages, len(*in))
+		copy(*out, *in)
+	}
+	if in.UdevRules != nil {
+		in, out := &in.UdevRules, &out.UdevRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourcePrerequisites.
+func (in *SpecialResourcePrerequisites) DeepCopy() *SpecialResourcePrerequisites {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecialResourcePrerequisites)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecialResourceSecretRef) DeepCopyInto(out *SpecialResourceSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourceSecretRef.
+func (in *SpecialResourceSecretRef) DeepCopy() *SpecialResourceSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecialResourceSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpecialResourceSource) DeepCopyInto(out *SpecialResourceSource) {
 	*out = *in
@@ -255,7 +460,22 @@ func (in *SpecialResourceSource) DeepCopy() *SpecialResourceSource {
 func (in *SpecialResourceSpec) DeepCopyInto(out *SpecialResourceSpec) {
 	*out = *in
 	in.Chart.DeepCopyInto(&out.Chart)
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = new(SpecialResourceManifests)
+		**out = **in
+	}
 	in.Set.DeepCopyInto(&out.Set)
+	if in.ValuesFrom != nil {
+		in, out := &in.ValuesFrom, &out.ValuesFrom
+		*out = make([]SpecialResourceValueFrom, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisableStates != nil {
+		in, out := &in.DisableStates, &out.DisableStates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.DriverContainer.DeepCopyInto(&out.DriverContainer)
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
@@ -264,6 +484,27 @@ func (in *SpecialResourceSpec) DeepCopyInto(out *SpecialResourceSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.KernelArguments != nil {
+		in, out := &in.KernelArguments, &out.KernelArguments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ModuleBlacklist != nil {
+		in, out := &in.ModuleBlacklist, &out.ModuleBlacklist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Prerequisites != nil {
+		in, out := &in.Prerequisites, &out.Prerequisites
+		*out = new(SpecialResourcePrerequisites)
+		(*in).DeepCopyInto(*out)
+	}
+	in.PodOverrides.DeepCopyInto(&out.PodOverrides)
+	if in.HardwareFacts != nil {
+		in, out := &in.HardwareFacts, &out.HardwareFacts
+		*out = make([]HardwareFact, len(*in))
+		copy(*out, *in)
+	}
 	if in.Dependencies != nil {
 		in, out := &in.Dependencies, &out.Dependencies
 		*out = make([]SpecialResourceDependency, len(*in))
@@ -293,6 +534,59 @@ func (in *SpecialResourceStatus) DeepCopyInto(out *SpecialResourceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.BuildAttempts != nil {
+		in, out := &in.BuildAttempts, &out.BuildAttempts
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeUnload != nil {
+		in, out := &in.NodeUnload, &out.NodeUnload
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StateHashes != nil {
+		in, out := &in.StateHashes, &out.StateHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MatchingNodes != nil {
+		in, out := &in.MatchingNodes, &out.MatchingNodes
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(ResolvedChart)
+		**out = **in
+	}
+	if in.MachineConfig != nil {
+		in, out := &in.MachineConfig, &out.MachineConfig
+		*out = new(MachineConfigRollout)
+		**out = **in
+	}
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make(map[string][]ObjectReference, len(*in))
+		for key, val := range *in {
+			var outVal []ObjectReference
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]ObjectReference, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourceStatus.
@@ -304,3 +598,83 @@ func (in *SpecialResourceStatus) DeepCopy() *SpecialResourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecialResourceStore) DeepCopyInto(out *SpecialResourceStore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourceStore.
+func (in *SpecialResourceStore) DeepCopy() *SpecialResourceStore {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecialResourceStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpecialResourceStore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecialResourceStoreList) DeepCopyInto(out *SpecialResourceStoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SpecialResourceStore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourceStoreList.
+func (in *SpecialResourceStoreList) DeepCopy() *SpecialResourceStoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecialResourceStoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpecialResourceStoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecialResourceValueFrom) DeepCopyInto(out *SpecialResourceValueFrom) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecialResourceValueFrom.
+func (in *SpecialResourceValueFrom) DeepCopy() *SpecialResourceValueFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecialResourceValueFrom)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -2,11 +2,41 @@ package cli
 
 import (
 	"flag"
+	"time"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/plugin"
+	"github.com/openshift-psap/special-resource-operator/pkg/scaffold"
 )
 
 type CommandLine struct {
+	APIBurst             int
+	APICacheUnstructured bool
+	APIQPS               float64
+	AuditLog             bool
+	ChartKeyring         string
+	ChartUpgradeInterval time.Duration
+	ChartVerification    string
+	DebugAddr            string
+	DecisionPlugin       plugin.Options
 	EnableLeaderElection bool
+	EnableWebhook        bool
+	FeatureGates         string
+	HealthProbeAddr      string
+	LibraryCharts        string
+	LogRedaction         bool
+	LogRedactionPatterns string
+	LogSpans             bool
 	MetricsAddr          string
+	RecordManifests      bool
+	RegistryBurst        int
+	RegistryQPS          float64
+	ResourceBudget       string
+	Scaffold             scaffold.Options
+	ScaffoldOutput       string
+	SelfTest             bool
+	SelfTestTimeout      time.Duration
+	StorageBackend       string
+	Uninstall            bool
 }
 
 func ParseCommandLine(programName string, args []string) (*CommandLine, error) {
@@ -14,10 +44,81 @@ func ParseCommandLine(programName string, args []string) (*CommandLine, error) {
 
 	fs := flag.NewFlagSet(programName, flag.ContinueOnError)
 
+	fs.IntVar(&cl.APIBurst, "api-burst", 30,
+		"Maximum burst of requests to the API server.")
+	fs.BoolVar(&cl.APICacheUnstructured, "api-cache-unstructured", false,
+		"Read the unstructured objects, e.g. the ones created by the charts, from the cache instead of the API server. "+
+			"An informer is started for every kind read.")
+	fs.Float64Var(&cl.APIQPS, "api-qps", 20,
+		"Maximum number of requests to the API server per second, 0 disables the limit.")
+	fs.BoolVar(&cl.AuditLog, "audit-log", false,
+		"Keep the last decisions of every reconcile: states applied, objects changed, kernels and chart upgrades.")
+	fs.StringVar(&cl.ChartKeyring, "chart-keyring", "",
+		"Path of the keyring holding the public keys of the trusted chart signers.")
+	fs.DurationVar(&cl.ChartUpgradeInterval, "chart-upgrade-interval", time.Hour,
+		"How often the repositories are checked for newer versions of the charts with an upgrade policy.")
+	fs.StringVar(&cl.ChartVerification, "chart-verification", "",
+		"Comma separated list of repositories whose charts are verified against --chart-keyring, "+
+			"each as <repository URL>=Required or <repository URL>=Optional.")
+	fs.StringVar(&cl.DebugAddr, "debug-addr", "",
+		"The address the pprof and debug endpoints bind to, e.g. localhost:6060. Disabled if empty.")
+	fs.StringVar(&cl.DecisionPlugin.URL, "decision-plugin-url", "",
+		"URL the manifests of every state are sent to before they are applied, to be allowed, denied or changed. "+
+			"Disabled if empty.")
+	fs.StringVar(&cl.DecisionPlugin.CAFile, "decision-plugin-ca-file", "",
+		"Path of the CA bundle of an HTTPS decision plugin, the system roots are used if empty.")
+	fs.DurationVar(&cl.DecisionPlugin.Timeout, "decision-plugin-timeout", 10*time.Second,
+		"How long the decision plugin has to answer.")
+	fs.StringVar((*string)(&cl.DecisionPlugin.FailurePolicy), "decision-plugin-failure-policy", string(plugin.Fail),
+		"What happens to a state when the decision plugin fails: Fail fails the state, Ignore applies it as rendered.")
 	fs.StringVar(&cl.MetricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	fs.StringVar(&cl.FeatureGates, "feature-gates", "",
+		"Comma separated list of experimental behaviors to enable, e.g. StateHashes=true,ServerSideApply=true.")
+	fs.StringVar(&cl.HealthProbeAddr, "health-probe-addr", ":8081", "The address the health probe endpoint binds to.")
+	fs.StringVar(&cl.LibraryCharts, "library-charts", "",
+		"Comma separated list of library charts added as dependencies to every recipe chart, "+
+			"each as <repository URL>#<name>@<version>.")
+	fs.BoolVar(&cl.LogRedaction, "log-redaction", true,
+		"Redact the credentials of pull secrets, authorization headers and signed URLs from the logs.")
+	fs.StringVar(&cl.LogRedactionPatterns, "log-redaction-patterns", "",
+		"Comma separated list of regular expressions whose matches are redacted from the logs besides the built-in ones.")
+	fs.BoolVar(&cl.LogSpans, "log-spans", false,
+		"Log the duration of the reconcile steps: chart loads, renders, registry lookups, applies and waits.")
 	fs.BoolVar(&cl.EnableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	fs.BoolVar(&cl.EnableWebhook, "enable-webhook", false,
+		"Serve the webhook defaulting the SpecialResources, the webhook certificates are required.")
+	fs.BoolVar(&cl.RecordManifests, "record-manifests", false,
+		"Keep the last manifests applied for every state, they are dumped with the "+
+			"specialresource.openshift.io/dump-manifests annotation.")
+	fs.IntVar(&cl.RegistryBurst, "registry-burst", 5,
+		"Maximum burst of registry requests.")
+	fs.Float64Var(&cl.RegistryQPS, "registry-qps", 2,
+		"Maximum number of registry requests per second, 0 disables the limit.")
+	fs.StringVar(&cl.ResourceBudget, "resource-budget", "",
+		"Comma separated list of the maximum number of objects of a kind a SpecialResource may create, "+
+			"e.g. ClusterRoleBinding=0,DaemonSet=2. Kinds that are not listed are not limited.")
+	fs.StringVar(&cl.Scaffold.Template, "scaffold", "",
+		"Write a starter recipe from the catalog embedded in the operator and exit, "+
+			"one of csi, kmod-device-plugin or simple-kmod.")
+	fs.StringVar(&cl.Scaffold.Name, "scaffold-name", "",
+		"Name of the recipe written by --scaffold, of its chart and of its SpecialResource.")
+	fs.StringVar(&cl.Scaffold.Image, "scaffold-image", "",
+		"Repository of the driver-container image of the recipe written by --scaffold, "+
+			"it is tagged with the kernel version.")
+	fs.StringVar(&cl.Scaffold.Module, "scaffold-module", "",
+		"Kernel module loaded by the recipe written by --scaffold, defaults to --scaffold-name.")
+	fs.StringVar(&cl.ScaffoldOutput, "scaffold-output", ".",
+		"Directory the recipe written by --scaffold is created in.")
+	fs.BoolVar(&cl.SelfTest, "self-test", false,
+		"Build and load a trivial kernel module through the running operator, report whether it succeeded and exit.")
+	fs.DurationVar(&cl.SelfTestTimeout, "self-test-timeout", 30*time.Minute,
+		"How long --self-test waits for the kernel module to be loaded.")
+	fs.StringVar(&cl.StorageBackend, "storage-backend", "configmap",
+		"Where the operator keeps its internal state, either configmap or crd.")
+	fs.BoolVar(&cl.Uninstall, "uninstall", false,
+		"Delete all SpecialResources, wait for their objects to be removed, report leftovers and exit.")
 
 	return &cl, fs.Parse(args)
 }
@@ -2,10 +2,13 @@ package cli_test
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/openshift-psap/special-resource-operator/cmd/cli"
+	"github.com/openshift-psap/special-resource-operator/pkg/plugin"
+	"github.com/openshift-psap/special-resource-operator/pkg/scaffold"
 )
 
 func TestCli(t *testing.T) {
@@ -19,21 +22,120 @@ var _ = Describe("Cli", func() {
 			cl, err := cli.ParseCommandLine("test", nil)
 			Expect(err).NotTo(HaveOccurred())
 
+			Expect(cl.APIBurst).To(Equal(30))
+			Expect(cl.APICacheUnstructured).To(BeFalse())
+			Expect(cl.APIQPS).To(Equal(20.0))
+			Expect(cl.AuditLog).To(BeFalse())
+			Expect(cl.ChartKeyring).To(BeEmpty())
+			Expect(cl.ChartUpgradeInterval).To(Equal(time.Hour))
+			Expect(cl.ChartVerification).To(BeEmpty())
+			Expect(cl.DebugAddr).To(BeEmpty())
+			Expect(cl.DecisionPlugin).To(Equal(plugin.Options{Timeout: 10 * time.Second, FailurePolicy: plugin.Fail}))
 			Expect(cl.EnableLeaderElection).To(BeFalse())
+			Expect(cl.EnableWebhook).To(BeFalse())
+			Expect(cl.FeatureGates).To(BeEmpty())
+			Expect(cl.HealthProbeAddr).To(Equal(":8081"))
+			Expect(cl.LibraryCharts).To(BeEmpty())
+			Expect(cl.LogRedaction).To(BeTrue())
+			Expect(cl.LogRedactionPatterns).To(BeEmpty())
+			Expect(cl.LogSpans).To(BeFalse())
 			Expect(cl.MetricsAddr).To(Equal(":8080"))
+			Expect(cl.RecordManifests).To(BeFalse())
+			Expect(cl.RegistryBurst).To(Equal(5))
+			Expect(cl.RegistryQPS).To(Equal(2.0))
+			Expect(cl.ResourceBudget).To(BeEmpty())
+			Expect(cl.Scaffold).To(Equal(scaffold.Options{}))
+			Expect(cl.ScaffoldOutput).To(Equal("."))
+			Expect(cl.SelfTest).To(BeFalse())
+			Expect(cl.SelfTestTimeout).To(Equal(30 * time.Minute))
+			Expect(cl.StorageBackend).To(Equal("configmap"))
+			Expect(cl.Uninstall).To(BeFalse())
 		})
 
 		It("should set all flags correctly", func() {
-			const metricsAddr = "1.2.3.4:5678"
+			const (
+				debugAddr       = "1.2.3.4:5680"
+				healthProbeAddr = "1.2.3.4:5679"
+				libraryCharts   = "file:///charts/library#sro-helpers@0.0.1"
+				metricsAddr     = "1.2.3.4:5678"
+			)
 
 			expected := &cli.CommandLine{
+				APIBurst:             50,
+				APICacheUnstructured: true,
+				APIQPS:               5,
+				AuditLog:             true,
+				ChartKeyring:         "/etc/sro/keyring.gpg",
+				ChartUpgradeInterval: 10 * time.Minute,
+				ChartVerification:    "https://example.com/charts=Required",
+				DebugAddr:            debugAddr,
+				DecisionPlugin: plugin.Options{
+					URL:           "https://policy.example.com/decide",
+					CAFile:        "/etc/sro/plugin-ca.crt",
+					Timeout:       time.Second,
+					FailurePolicy: plugin.Ignore,
+				},
 				EnableLeaderElection: true,
+				EnableWebhook:        true,
+				FeatureGates:         "StateHashes=true",
+				HealthProbeAddr:      healthProbeAddr,
+				LibraryCharts:        libraryCharts,
+				LogRedaction:         false,
+				LogRedactionPatterns: "acme-[0-9a-f]{8}",
+				LogSpans:             true,
 				MetricsAddr:          metricsAddr,
+				RecordManifests:      true,
+				RegistryBurst:        20,
+				RegistryQPS:          0.5,
+				ResourceBudget:       "DaemonSet=2",
+				Scaffold: scaffold.Options{
+					Template: "simple-kmod",
+					Name:     "my-driver",
+					Image:    "quay.io/acme/my-driver",
+					Module:   "my_driver",
+				},
+				ScaffoldOutput:  "/tmp/recipes",
+				SelfTest:        true,
+				SelfTestTimeout: time.Hour,
+				StorageBackend:  "crd",
+				Uninstall:       true,
 			}
 
 			args := []string{
+				"--api-burst", "50",
+				"--api-cache-unstructured",
+				"--api-qps", "5",
+				"--audit-log",
+				"--chart-keyring", "/etc/sro/keyring.gpg",
+				"--chart-upgrade-interval", "10m",
+				"--chart-verification", "https://example.com/charts=Required",
+				"--debug-addr", debugAddr,
+				"--decision-plugin-ca-file", "/etc/sro/plugin-ca.crt",
+				"--decision-plugin-failure-policy", "Ignore",
+				"--decision-plugin-timeout", "1s",
+				"--decision-plugin-url", "https://policy.example.com/decide",
 				"--enable-leader-election",
+				"--enable-webhook",
+				"--feature-gates", "StateHashes=true",
+				"--health-probe-addr", healthProbeAddr,
+				"--library-charts", libraryCharts,
+				"--log-redaction=false",
+				"--log-redaction-patterns", "acme-[0-9a-f]{8}",
+				"--log-spans",
 				"--metrics-addr", metricsAddr,
+				"--record-manifests",
+				"--registry-burst", "20",
+				"--registry-qps", "0.5",
+				"--resource-budget", "DaemonSet=2",
+				"--scaffold", "simple-kmod",
+				"--scaffold-image", "quay.io/acme/my-driver",
+				"--scaffold-module", "my_driver",
+				"--scaffold-name", "my-driver",
+				"--scaffold-output", "/tmp/recipes",
+				"--self-test",
+				"--self-test-timeout", "1h",
+				"--storage-backend", "crd",
+				"--uninstall",
 			}
 
 			cl, err := cli.ParseCommandLine("test", args)
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-logr/logr"
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/audit"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// FreezeChartUpgradesAnnotation set to true stops the chart upgrades of a
+// SpecialResource, which stays at the version it was last upgraded to.
+const FreezeChartUpgradesAnnotation = "specialresource.openshift.io/freeze-chart-upgrades"
+
+// hasUpgradePolicy returns true if the chart of sr is upgraded to its newer
+// versions.
+func hasUpgradePolicy(sr *srov1beta1.SpecialResource) bool {
+	ch := sr.Spec.Chart
+	return sr.Spec.Manifests == nil && ch.Digest == "" &&
+		ch.UpgradePolicy != "" && ch.UpgradePolicy != helmerv1beta1.UpgradePinned
+}
+
+// upgradeChart returns the chart of sr at the newest version allowed by its
+// upgrade policy. The version the chart was last loaded at is kept if the
+// upgrades of sr are frozen or its repository cannot be checked.
+func (r *SpecialResourceReconciler) upgradeChart(ctx context.Context, log logr.Logger, sr *srov1beta1.SpecialResource) helmerv1beta1.HelmChart {
+	ch := sr.Spec.Chart
+	if !hasUpgradePolicy(sr) {
+		return ch
+	}
+
+	current := ch.Version
+	if loaded := sr.Status.Chart; loaded != nil && loaded.Name == ch.Name && loaded.Repository == ch.Repository.URL &&
+		isNewer(loaded.Version, current) {
+		current = loaded.Version
+	}
+
+	if sr.GetAnnotations()[FreezeChartUpgradesAnnotation] == "true" {
+		ch.Version = current
+		return ch
+	}
+
+	latest, err := r.Helmer.LatestVersion(ctx, ch)
+	if err != nil {
+		log.Info("Could not check for chart upgrades", "chart", ch.Name, "error", err)
+		ch.Version = current
+		return ch
+	}
+
+	if latest != current {
+		r.KubeClient.Event(sr, v1.EventTypeNormal, "ChartUpgrade",
+			fmt.Sprintf("Upgrading chart %s from version %s to %s", ch.Name, current, latest))
+		r.Audit.Record(sr.Name, audit.ChartUpgraded, ch.Name+"@"+latest,
+			fmt.Sprintf("%s upgrade policy, was %s", ch.UpgradePolicy, current))
+	}
+
+	ch.Version = latest
+
+	return ch
+}
+
+// isNewer returns true if version is newer than base, or base is empty.
+func isNewer(version, base string) bool {
+	if base == "" {
+		return version != ""
+	}
+
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+
+	b, err := semver.NewVersion(base)
+	if err != nil {
+		return false
+	}
+
+	return v.GreaterThan(b)
+}
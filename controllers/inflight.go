@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// inFlight keeps the cancel functions of the running reconciles, so that
+// deleting a SpecialResource interrupts its builds and waits instead of
+// delaying its finalizer until they time out.
+type inFlight struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// start returns a context that is cancelled when the SpecialResource name is
+// deleted, done has to be called once the reconcile returns.
+func (f *inFlight) start(ctx context.Context, name string) (context.Context, func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.cancels == nil {
+		f.cancels = make(map[string]context.CancelFunc)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	f.cancels[name] = cancel
+
+	done := func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		delete(f.cancels, name)
+		cancel()
+	}
+
+	return ctx, done
+}
+
+// cancel interrupts the running reconcile of the SpecialResource name, if any.
+func (f *inFlight) cancel(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cancel, ok := f.cancels[name]; ok {
+		cancel()
+	}
+}
+
+// handler cancels the reconcile of a SpecialResource once it is marked for
+// deletion or deleted. It does not enqueue anything, the SpecialResource
+// watch of the controller does.
+func (f *inFlight) handler() handler.EventHandler {
+	return handler.Funcs{
+		UpdateFunc: func(e event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+			if e.ObjectNew.GetDeletionTimestamp() != nil {
+				f.cancel(e.ObjectNew.GetName())
+			}
+		},
+		DeleteFunc: func(e event.DeleteEvent, _ workqueue.RateLimitingInterface) {
+			f.cancel(e.Object.GetName())
+		},
+	}
+}
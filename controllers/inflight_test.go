@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("inFlight", func() {
+	var f *inFlight
+
+	BeforeEach(func() {
+		f = &inFlight{}
+	})
+
+	It("should cancel the reconcile of a SpecialResource only", func() {
+		ctx, done := f.start(context.Background(), "a")
+		defer done()
+		otherCtx, otherDone := f.start(context.Background(), "b")
+		defer otherDone()
+
+		f.cancel("a")
+
+		Expect(ctx.Err()).To(MatchError(context.Canceled))
+		Expect(otherCtx.Err()).NotTo(HaveOccurred())
+	})
+
+	It("should cancel the context and forget the reconcile once it is done", func() {
+		ctx, done := f.start(context.Background(), "a")
+		done()
+
+		Expect(ctx.Err()).To(MatchError(context.Canceled))
+		Expect(f.cancels).NotTo(HaveKey("a"))
+
+		// Cancelling a SpecialResource without a reconcile is a no-op
+		f.cancel("a")
+	})
+
+	It("should cancel the reconcile once the SpecialResource is marked for deletion", func() {
+		ctx, done := f.start(context.Background(), "a")
+		defer done()
+
+		old := &srov1beta1.SpecialResource{}
+		old.SetName("a")
+
+		updated := old.DeepCopy()
+		updated.SetDeletionTimestamp(&metav1.Time{})
+
+		h := f.handler()
+
+		h.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: old}, nil)
+		Expect(ctx.Err()).NotTo(HaveOccurred())
+
+		h.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated}, nil)
+		Expect(ctx.Err()).To(MatchError(context.Canceled))
+	})
+
+	It("should cancel the reconcile once the SpecialResource is deleted", func() {
+		ctx, done := f.start(context.Background(), "a")
+		defer done()
+
+		sr := &srov1beta1.SpecialResource{}
+		sr.SetName("a")
+
+		f.handler().Delete(event.DeleteEvent{Object: sr}, nil)
+		Expect(ctx.Err()).To(MatchError(context.Canceled))
+	})
+})
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
+	"github.com/openshift-psap/special-resource-operator/pkg/runtime"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reportInventory counts the objects owned by the SpecialResource per kind.
+// The typed lists are read from the cache backing the watches of the
+// controller, so that reporting does not hit the API server on every
+// reconcile. The metric is best effort, errors are only logged.
+func (r *SpecialResourceReconciler) reportInventory(ctx context.Context, wi *WorkItem) {
+
+	r.reportInfo(wi)
+
+	clusterVersion := ""
+	if wi.RunInfo != nil {
+		clusterVersion = wi.RunInfo.ClusterVersion
+	}
+
+	for _, gvk := range ownership.OwnedKinds {
+		obj, err := r.Scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err != nil {
+			wi.Log.Info("Could not count owned objects", "kind", gvk.Kind, "error", err)
+			continue
+		}
+
+		list, ok := obj.(client.ObjectList)
+		if !ok {
+			continue
+		}
+
+		if err = r.KubeClient.List(ctx, list, client.HasLabels{ownership.SpecialResourceOwnedLabel}); err != nil {
+			// OpenShift kinds on vanilla Kubernetes
+			var noMatch *meta.NoKindMatchError
+			if !errors.As(err, &noMatch) {
+				wi.Log.Info("Could not count owned objects", "kind", gvk.Kind, "error", err)
+			}
+			continue
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			wi.Log.Info("Could not count owned objects", "kind", gvk.Kind, "error", err)
+			continue
+		}
+
+		count := 0
+		for _, item := range items {
+			if o, ok := item.(client.Object); ok && r.Ownership.IsOwnedBy(o, wi.SpecialResource) {
+				count++
+			}
+		}
+
+		r.Metrics.SetOwnedObjects(wi.SpecialResource.Name, gvk.Kind, clusterVersion, count)
+	}
+}
+
+// reportInfo exports the chart, driver and kernel versions managed by the
+// SpecialResource. The driver version is the appVersion of its chart.
+func (r *SpecialResourceReconciler) reportInfo(wi *WorkItem) {
+
+	var chartName, chartVersion, driverVersion string
+	if wi.Chart != nil && wi.Chart.Metadata != nil {
+		chartName = wi.Chart.Metadata.Name
+		chartVersion = wi.Chart.Metadata.Version
+		driverVersion = wi.Chart.Metadata.AppVersion
+	}
+
+	r.Metrics.SetSpecialResourceInfo(
+		wi.SpecialResource.Name,
+		wi.SpecialResource.Spec.Namespace,
+		chartName,
+		chartVersion,
+		driverVersion,
+		strings.Join(clusterKernels(wi.RunInfo), ","))
+}
+
+// clusterKernels returns the sorted kernel versions running in the cluster.
+func clusterKernels(runInfo *runtime.RuntimeInformation) []string {
+	kernels := make([]string, 0)
+	if runInfo != nil {
+		for kernel := range runInfo.ClusterUpgradeInfo {
+			kernels = append(kernels, kernel)
+		}
+	}
+	sort.Strings(kernels)
+
+	return kernels
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"context"
+
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// specialResourcesForNode returns a request for every SpecialResource whose
+// nodeSelector selects node, so that a node reporting a new kernel or a
+// reboot only reconciles the SpecialResources targeting it.
+func (r *SpecialResourceReconciler) specialResourcesForNode(node client.Object) []reconcile.Request {
+	return r.specialResourcesSelecting(node, func(*srov1beta1.SpecialResource) bool { return true })
+}
+
+// specialResourcesForNodeReadiness returns a request for every SpecialResource
+// computing its runtime values from the ready nodes only whose nodeSelector
+// selects node, the other ones do not depend on its readiness.
+func (r *SpecialResourceReconciler) specialResourcesForNodeReadiness(node client.Object) []reconcile.Request {
+	return r.specialResourcesSelecting(node, func(sr *srov1beta1.SpecialResource) bool { return sr.Spec.ReadyNodesOnly })
+}
+
+func (r *SpecialResourceReconciler) specialResourcesSelecting(node client.Object, include func(*srov1beta1.SpecialResource) bool) []reconcile.Request {
+	list := &srov1beta1.SpecialResourceList{}
+
+	if err := r.KubeClient.List(context.Background(), list); err != nil {
+		r.Log.Error(err, "Could not list the SpecialResources of a node", "node", node.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0)
+
+	for i := range list.Items {
+		sr := &list.Items[i]
+
+		if !include(sr) || !labels.SelectorFromSet(sr.Spec.NodeSelector).Matches(labels.Set(node.GetLabels())) {
+			continue
+		}
+
+		r.Log.Info("Targeted node changed", "node", node.GetName(), "specialresource", sr.Name)
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: sr.Name}})
+	}
+
+	return requests
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+// PriorityDelay is how long a SpecialResource is requeued for while one of a
+// higher priority was not reconciled yet.
+var PriorityDelay = 5 * time.Second
+
+// PriorityTimeout is how long a SpecialResource waits for the ones of a
+// higher priority at most, it is reconciled anyway afterwards.
+var PriorityTimeout = 10 * time.Minute
+
+// MachineConfigRolloutDelay is how long a SpecialResource is requeued for while
+// the machines of its MachineConfigPool reboot with its MachineConfig.
+var MachineConfigRolloutDelay = 30 * time.Second
+
+// priorities keeps the SpecialResources reconciled since the operator started.
+// The queue of the controller is first in, first out and cannot be replaced,
+// the SpecialResources of a lower priority are requeued instead, so that e.g.
+// a storage driver is deployed before an optional monitoring stack.
+type priorities struct {
+	mu         sync.Mutex
+	reconciled map[string]bool
+	// waiting is when the SpecialResources started to wait, until none of a
+	// higher priority is left to wait for
+	waiting map[string]time.Time
+}
+
+// done marks the SpecialResource name as reconciled, whatever the result, a
+// failing SpecialResource does not hold back the ones of a lower priority.
+func (p *priorities) done(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.reconciled == nil {
+		p.reconciled = make(map[string]bool)
+	}
+
+	p.reconciled[name] = true
+}
+
+// waitingFor returns the name of a SpecialResource of srs with a higher
+// priority than sr that was not reconciled yet, an empty string if none. sr
+// does not wait for it anymore once it waited for PriorityTimeout. The
+// Unmanaged SpecialResources are never reconciled, they are not waited for.
+func (p *priorities) waitingFor(sr *srov1beta1.SpecialResource, srs []srov1beta1.SpecialResource) (name string, wait bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, other := range srs {
+		if other.Spec.Priority <= sr.Spec.Priority ||
+			other.GetDeletionTimestamp() != nil ||
+			other.Spec.ManagementState == operatorv1.Unmanaged ||
+			p.reconciled[other.Name] {
+			continue
+		}
+
+		if p.waiting == nil {
+			p.waiting = make(map[string]time.Time)
+		}
+
+		since, ok := p.waiting[sr.Name]
+		if !ok {
+			since = time.Now()
+			p.waiting[sr.Name] = since
+		}
+
+		return other.Name, time.Since(since) < PriorityTimeout
+	}
+
+	delete(p.waiting, sr.Name)
+	return "", false
+}
+
+// higherPriority returns the name of a SpecialResource of a higher priority
+// than sr that was not reconciled yet, and whether sr has to wait for it.
+func (r *SpecialResourceReconciler) higherPriority(ctx context.Context, sr *srov1beta1.SpecialResource) (string, bool, error) {
+	list := &srov1beta1.SpecialResourceList{}
+
+	if err := r.KubeClient.List(ctx, list); err != nil {
+		return "", false, err
+	}
+
+	name, wait := r.priorities.waitingFor(sr, list.Items)
+	return name, wait, nil
+}
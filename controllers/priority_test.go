@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestControllers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Controllers Suite")
+}
+
+func specialResource(name string, priority int32) srov1beta1.SpecialResource {
+	sr := srov1beta1.SpecialResource{}
+	sr.SetName(name)
+	sr.Spec.Priority = priority
+
+	return sr
+}
+
+var _ = Describe("priorities", func() {
+	var (
+		p      *priorities
+		low    srov1beta1.SpecialResource
+		high   srov1beta1.SpecialResource
+		others srov1beta1.SpecialResource
+	)
+
+	BeforeEach(func() {
+		p = &priorities{}
+		low = specialResource("low", 0)
+		high = specialResource("high", 100)
+		others = specialResource("others", 0)
+	})
+
+	It("should wait for a SpecialResource of a higher priority until it is reconciled", func() {
+		srs := []srov1beta1.SpecialResource{low, high}
+
+		name, wait := p.waitingFor(&low, srs)
+		Expect(name).To(Equal("high"))
+		Expect(wait).To(BeTrue())
+
+		p.done("high")
+
+		name, wait = p.waitingFor(&low, srs)
+		Expect(name).To(BeEmpty())
+		Expect(wait).To(BeFalse())
+	})
+
+	It("should not wait for the SpecialResources of the same or a lower priority", func() {
+		srs := []srov1beta1.SpecialResource{low, high, others}
+
+		name, wait := p.waitingFor(&high, srs)
+		Expect(name).To(BeEmpty())
+		Expect(wait).To(BeFalse())
+
+		p.done("high")
+
+		name, wait = p.waitingFor(&low, srs)
+		Expect(name).To(BeEmpty())
+		Expect(wait).To(BeFalse())
+	})
+
+	It("should not wait for a deleted SpecialResource", func() {
+		high.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+		name, wait := p.waitingFor(&low, []srov1beta1.SpecialResource{low, high})
+		Expect(name).To(BeEmpty())
+		Expect(wait).To(BeFalse())
+	})
+
+	It("should not wait for an Unmanaged SpecialResource", func() {
+		high.Spec.ManagementState = operatorv1.Unmanaged
+
+		name, wait := p.waitingFor(&low, []srov1beta1.SpecialResource{low, high})
+		Expect(name).To(BeEmpty())
+		Expect(wait).To(BeFalse())
+	})
+
+	It("should stop waiting after PriorityTimeout", func() {
+		defer func(timeout time.Duration) { PriorityTimeout = timeout }(PriorityTimeout)
+		PriorityTimeout = 50 * time.Millisecond
+
+		srs := []srov1beta1.SpecialResource{low, high}
+
+		_, wait := p.waitingFor(&low, srs)
+		Expect(wait).To(BeTrue())
+
+		time.Sleep(PriorityTimeout)
+
+		name, wait := p.waitingFor(&low, srs)
+		Expect(name).To(Equal("high"))
+		Expect(wait).To(BeFalse())
+
+		// A SpecialResource of a higher priority created later is waited for
+		// from its creation
+		p.done("high")
+		_, wait = p.waitingFor(&low, srs)
+		Expect(wait).To(BeFalse())
+
+		_, wait = p.waitingFor(&low, append(srs, specialResource("higher", 200)))
+		Expect(wait).To(BeTrue())
+	})
+})
@@ -5,22 +5,41 @@ import (
 	"fmt"
 	"path"
 	"regexp"
-	"sort"
+	"strings"
 
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	s "github.com/openshift-psap/special-resource-operator/internal/controllers/state"
+	"github.com/openshift-psap/special-resource-operator/internal/resourcehelper"
+	"github.com/openshift-psap/special-resource-operator/pkg/assets"
+	"github.com/openshift-psap/special-resource-operator/pkg/audit"
+	"github.com/openshift-psap/special-resource-operator/pkg/dashboard"
+	"github.com/openshift-psap/special-resource-operator/pkg/featuregates"
+	"github.com/openshift-psap/special-resource-operator/pkg/machineconfig"
+	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	"github.com/openshift-psap/special-resource-operator/pkg/plugin"
+	"github.com/openshift-psap/special-resource-operator/pkg/redact"
+	"github.com/openshift-psap/special-resource-operator/pkg/secrets"
+	"github.com/openshift-psap/special-resource-operator/pkg/selinux"
+	"github.com/openshift-psap/special-resource-operator/pkg/states"
 	"github.com/openshift-psap/special-resource-operator/pkg/upgrade"
+	machinev1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 	"helm.sh/helm/v3/pkg/chart"
-	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/postrender"
+	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	k8syaml "sigs.k8s.io/yaml"
 )
 
+const entitlementNamespace = "openshift-config-managed"
+
 var (
 	affineRegex = regexp.MustCompile(`\n\s+specialresource\.openshift\.io\/kernel\-affine`)
+	verifyRegex = regexp.MustCompile(`\n\s+specialresource\.openshift\.io\/verify:\s*["']?([^"'\s]+)`)
 )
 
 func (r *SpecialResourceReconciler) createImagePullerRoleBinding(ctx context.Context, wi *WorkItem) error {
@@ -121,31 +140,134 @@ func (r *SpecialResourceReconciler) createImagePullerRoleBinding(ctx context.Con
 	return nil
 }
 
+// stateVerifications returns the verification templates of every state, they
+// are removed from nostate.
+func stateVerifications(stateYAMLS []*chart.File, nostate *chart.Chart) (map[string][]*chart.File, error) {
+	names := make(map[string][]string)
+	all := make([]string, 0)
+
+	for _, stateYAML := range stateYAMLS {
+		for _, match := range verifyRegex.FindAllSubmatch(stateYAML.Data, -1) {
+			name := path.Join("templates", string(match[1]))
+			names[stateYAML.Name] = append(names[stateYAML.Name], name)
+			all = append(all, name)
+		}
+	}
+
+	templates := states.Extract(nostate, all)
+
+	verifications := make(map[string][]*chart.File)
+	for state, stateNames := range names {
+		for _, name := range stateNames {
+			template, ok := templates[name]
+			if !ok {
+				return nil, fmt.Errorf("state %s: verification template %s not found", state, name)
+			}
+			verifications[state] = append(verifications[state], template)
+		}
+	}
+
+	return verifications, nil
+}
+
+// verifyState runs the verification templates of a state with the values of
+// the state. The Jobs they create have to complete before the next state.
+func (r *SpecialResourceReconciler) verifyState(ctx context.Context, wi *WorkItem, nostate chart.Chart, templates []*chart.File, values map[string]interface{}) error {
+	for _, template := range templates {
+		wi.Log.Info("Verifying", "template", template.Name)
+
+		err := r.Helmer.Run(
+			ctx,
+			states.Step(nostate, template),
+			values,
+			wi.SpecialResource,
+			wi.SpecialResource.Name,
+			wi.SpecialResource.Spec.Namespace,
+			wi.NodeSelector,
+			wi.RunInfo.KernelFullVersion,
+			wi.RunInfo.OperatingSystemDecimal,
+			wi.SpecialResource.Spec.Debug,
+			wi.PostRenderer)
+		if err != nil {
+			return fmt.Errorf("verification %s failed: %w", template.Name, err)
+		}
+	}
+
+	return nil
+}
+
 // ReconcileChartStates Reconcile Hardware States
 func (r *SpecialResourceReconciler) ReconcileChartStates(ctx context.Context, wi *WorkItem) error {
 
-	nostate := *wi.Chart
-	nostate.Templates = []*chart.File{}
+	// First get all non-state related files from the templates
+	// and save the states in a temporary slice for single execution, the
+	// built-in selinux-policy, device-inventory and dashboard states are added
+	// to the charts without one
+	ch := dashboard.WithState(wi.Chart, wi.SpecialResource.Spec.Set.Object)
+	ch = selinux.WithState(ch, wi.SpecialResource.Spec.Set.Object)
+	ch = assets.WithInventory(ch, wi.SpecialResource.Spec.Set.Object)
+	stateYAMLS, nostate := states.Split(ch, r.Assets.ValidStateName)
+
+	// The verification templates of the states are only rendered after the
+	// state that refers to them, never along with the other templates
+	verifications, err := stateVerifications(stateYAMLS, &nostate)
+	if err != nil {
+		return err
+	}
 
-	stateYAMLS := []*chart.File{}
+	// Disabled states are neither applied nor verified
+	stateYAMLS, disabled := states.Disable(stateYAMLS, wi.SpecialResource.Spec.DisableStates)
+	for _, stateYAML := range disabled {
+		wi.Log.Info("Disabled, skipping", "State", stateYAML.Name)
+		r.Audit.Record(wi.SpecialResource.Name, audit.StateSkipped, path.Base(stateYAML.Name), "disabled")
+	}
 
-	// First get all non-state related files from the templates
-	// and save the states in a temporary slice for single execution
-	for _, template := range wi.Chart.Templates {
-		if r.Assets.ValidStateName(template.Name) {
-			stateYAMLS = append(stateYAMLS, template)
-		} else {
-			nostate.Templates = append(nostate.Templates, template)
-		}
+	// The overlay of the chart patches the manifests of every state
+	wi.PostRenderer, err = r.Manifests.PostRenderer(ctx, wi.SpecialResource.Spec.Chart.PostRenderer)
+	if err != nil {
+		return err
+	}
+
+	// The set values with the secrets fetched for this reconcile only
+	set, err := r.Secrets.Values(ctx, wi.SpecialResource)
+	if err != nil {
+		return err
+	}
+	wi.Secrets = secrets.Fetched(wi.SpecialResource, set)
+
+	// States whose templates and values did not change since they were
+	// applied are skipped, the hashes are dropped without the gate so that
+	// enabling it again does not trust stale ones
+	stateHashes := r.FeatureGates.Enabled(featuregates.StateHashes, wi.SpecialResource)
+	if !stateHashes {
+		wi.SpecialResource.Status.StateHashes = nil
 	}
 
-	sort.Slice(stateYAMLS, func(i, j int) bool {
-		return stateYAMLS[i].Name < stateYAMLS[j].Name
-	})
+	// The objects and hashes of the kernels no node runs anymore, and the
+	// node counts of the workloads that are not applied anymore
+	r.pruneKernels(wi.SpecialResource, wi.RunInfo.ClusterUpgradeInfo)
+	pruneMatchingNodes(wi.SpecialResource)
+
+	// The namespace and the other objects created before the chart
+	r.recordManifests(ctx, wi, "prerequisites", "")
+
+	// A failed state stops the reconciliation, the remaining ones are neither
+	var completed, failed int
+	defer func() {
+		r.Metrics.SetStates(wi.SpecialResource.Name, "completed", completed)
+		r.Metrics.SetStates(wi.SpecialResource.Name, "failed", failed)
+		r.Summary.SetProgress(wi.SpecialResource.Name, completed, len(stateYAMLS))
+	}()
+
+	// Kernels covered by the kernel affine states, and their cluster version.
+	// A kernel is only covered once all the states succeeded for it.
+	coverage := make(map[string]string)
+	uncovered := make(map[string]bool)
 
 	for _, stateYAML := range stateYAMLS {
 
 		wi.Log.Info("Executing", "State", stateYAML.Name)
+		r.Debug.SetState(wi.SpecialResource.Name, stateYAML.Name)
 		if suErr := r.StatusUpdater.SetAsProgressing(ctx, wi.SpecialResource, s.HandlingState, fmt.Sprintf("Working on: %s", stateYAML.Name)); suErr != nil {
 			wi.Log.Error(suErr, "failed to update CR's status to Progressing")
 			return suErr
@@ -155,8 +277,16 @@ func (r *SpecialResourceReconciler) ReconcileChartStates(ctx context.Context, wi
 			wi.Log.Info("Debug active. Showing YAML contents", "name", stateYAML.Name, "data", stateYAML.Data)
 		}
 
-		step := nostate
-		step.Templates = append(nostate.Templates, stateYAML)
+		// The devices are only advertised once the host prerequisites are in
+		// effect on the nodes
+		if states.Name(stateYAML.Name) == wi.RunInfo.GroupName.DevicePlugin {
+			if err := r.verifyPrerequisites(ctx, wi); err != nil {
+				failed = 1
+				return fmt.Errorf("could not apply state %s: %w", stateYAML.Name, err)
+			}
+		}
+
+		step := states.Step(nostate, stateYAML)
 
 		// We are kernel-affine if the yamlSpec uses kernel-affine label.
 		// then we need to replicate the object and set a name + os + kernel version
@@ -181,33 +311,84 @@ func (r *SpecialResourceReconciler) ReconcileChartStates(ctx context.Context, wi
 			wi.RunInfo.OperatingSystemMajorMinor = version.OSMajorMinor
 			wi.RunInfo.OperatingSystemMajor = version.OSMajor
 
+			// The values derived from the kernel version follow it
+			var err error
+			if wi.RunInfo.KernelPatchVersion, err = r.KernelData.PatchVersion(wi.RunInfo.KernelFullVersion); err != nil {
+				return fmt.Errorf("could not get the patch version of kernel %s: %w", wi.RunInfo.KernelFullVersion, err)
+			}
+			if wi.RunInfo.KernelVersion, err = r.KernelData.ParseVersion(wi.RunInfo.KernelFullVersion); err != nil {
+				return fmt.Errorf("could not parse kernel %s: %w", wi.RunInfo.KernelFullVersion, err)
+			}
+
 			if kernelAffine {
 				wi.Log.Info("KernelAffine: ClusterUpgradeInfo",
 					"kernel", wi.RunInfo.KernelFullVersion,
 					"os", wi.RunInfo.OperatingSystemDecimal,
 					"cluster", wi.RunInfo.ClusterVersionMajorMinor)
-			}
 
-			var err error
-
-			step.Values, err = chartutil.CoalesceValues(&step, wi.SpecialResource.Spec.Set.Object)
-			if err != nil {
-				return err
+				coverage[wi.RunInfo.KernelFullVersion] = version.ClusterVersion
+				if !uncovered[wi.RunInfo.KernelFullVersion] {
+					r.Metrics.SetKernelCoverage(wi.SpecialResource.Name, version.ClusterVersion, wi.RunInfo.KernelFullVersion, metrics.CoverageInProgress)
+				}
 			}
 
-			rinfo, err := runtime.DefaultUnstructuredConverter.ToUnstructured(wi.RunInfo)
+			runtimeValues, err := wi.RunInfo.Values()
 			if err != nil {
 				return err
 			}
 
-			step.Values, err = chartutil.CoalesceValues(&step, rinfo)
+			step.Values, err = states.Values(&step, set, runtimeValues)
 			if err != nil {
 				return err
 			}
 
 			if wi.SpecialResource.Spec.Debug {
 				d, _ := yaml.Marshal(step.Values)
-				wi.Log.Info("Debug active. Showing YAML values", "values", d)
+				wi.Log.Info("Debug active. Showing YAML values", "values", redact.String(redact.Literals(string(d), wi.Secrets)))
+			}
+
+			hashKey := path.Base(stateYAML.Name)
+			if kernelAffine {
+				hashKey += "/" + wi.RunInfo.KernelFullVersion
+			}
+
+			inputs := states.Inputs{
+				Namespace:    wi.SpecialResource.Spec.Namespace,
+				NodeSelector: wi.NodeSelector,
+				PostRenderer: postRendererID(r.statePostRenderer(wi, hashKey)),
+			}
+
+			hash, err := states.Hash(&step, step.Values, inputs, verifications[stateYAML.Name]...)
+			if err != nil {
+				return fmt.Errorf("could not hash state %s: %w", stateYAML.Name, err)
+			}
+
+			unchanged := stateHashes && wi.SpecialResource.Status.StateHashes[hashKey] == hash
+
+			// The objects of the state deleted outside of SRO are restored
+			if unchanged {
+				if missing, err := r.missingObject(ctx, wi.SpecialResource, hashKey); err != nil {
+					wi.Log.Info("Could not check the objects of the state, applying", "State", hashKey, "error", err)
+					unchanged = false
+				} else if missing != "" {
+					wi.Log.Info("Object of the state was deleted, applying", "State", hashKey, "object", missing)
+					unchanged = false
+				}
+			}
+
+			if unchanged {
+				wi.Log.Info("Unchanged, skipping", "State", hashKey)
+				// The objects of the state are not applied but still exist
+				if err := r.chargeBudget(wi, hashKey); err != nil {
+					failed = 1
+					return fmt.Errorf("could not skip state %s: %w", stateYAML.Name, err)
+				}
+				r.Audit.Record(wi.SpecialResource.Name, audit.StateSkipped, hashKey, "templates and values unchanged")
+				replicas += 1
+				if !kernelAffine {
+					break
+				}
+				continue
 			}
 
 			err = r.Helmer.Run(
@@ -217,18 +398,52 @@ func (r *SpecialResourceReconciler) ReconcileChartStates(ctx context.Context, wi
 				wi.SpecialResource,
 				wi.SpecialResource.Name,
 				wi.SpecialResource.Spec.Namespace,
-				wi.SpecialResource.Spec.NodeSelector,
+				wi.NodeSelector,
 				wi.RunInfo.KernelFullVersion,
 				wi.RunInfo.OperatingSystemDecimal,
-				wi.SpecialResource.Spec.Debug)
+				wi.SpecialResource.Spec.Debug,
+				r.statePostRenderer(wi, hashKey))
+
+			if kernelAffine {
+				r.recordManifests(ctx, wi, path.Base(stateYAML.Name), wi.RunInfo.KernelFullVersion)
+			} else {
+				r.recordManifests(ctx, wi, path.Base(stateYAML.Name), "")
+			}
+
+			if err == nil {
+				err = r.verifyState(ctx, wi, nostate, verifications[stateYAML.Name], step.Values)
+			}
+
+			if err == nil {
+				r.Audit.Record(wi.SpecialResource.Name, audit.StateApplied, hashKey, "")
+			} else {
+				r.Audit.Record(wi.SpecialResource.Name, audit.StateFailed, hashKey, err.Error())
+			}
+
+			if stateHashes {
+				if err == nil {
+					if wi.SpecialResource.Status.StateHashes == nil {
+						wi.SpecialResource.Status.StateHashes = make(map[string]string)
+					}
+					wi.SpecialResource.Status.StateHashes[hashKey] = hash
+				} else {
+					delete(wi.SpecialResource.Status.StateHashes, hashKey)
+				}
+			}
 
 			replicas += 1
 
+			if kernelAffine && err != nil {
+				uncovered[wi.RunInfo.KernelFullVersion] = true
+				r.Metrics.SetKernelCoverage(wi.SpecialResource.Name, version.ClusterVersion, wi.RunInfo.KernelFullVersion, metrics.CoverageFailed)
+			}
+
 			// If the first replica fails we want to create all remaining
 			// ones for parallel startup, otherwise we would wait for the first
 			// then for the second etc.
 			if err != nil && replicas == len(wi.RunInfo.ClusterUpgradeInfo) {
 				r.Metrics.SetCompletedState(wi.SpecialResource.Name, stateYAML.Name, 0)
+				failed = 1
 				return fmt.Errorf("failed to create state %s: %w ", stateYAML.Name, err)
 			}
 
@@ -239,6 +454,7 @@ func (r *SpecialResourceReconciler) ReconcileChartStates(ctx context.Context, wi
 		}
 
 		r.Metrics.SetCompletedState(wi.SpecialResource.Name, stateYAML.Name, 1)
+		completed += 1
 		// Every YAML is one state, we generate the name of the
 		// state special-resource + first 4 digits of the state
 		// e.g.: simple-kmod-0000 this can be used for scheduling or
@@ -247,40 +463,206 @@ func (r *SpecialResourceReconciler) ReconcileChartStates(ctx context.Context, wi
 
 		// If resource available, label the nodes according to the current state
 		// if e.g driver-container ready -> specialresource.openshift.io/driver-container:ready
-		if err := r.labelNodesAccordingToState(ctx, wi.Log, wi.SpecialResource.Spec.NodeSelector, stateName); err != nil {
+		if err := r.labelNodesAccordingToState(ctx, wi.Log, wi.NodeSelector, stateName); err != nil {
 			return err
 		}
 	}
 
-	// We're done with states now execute the part of the chart without
-	// states we need to reconcile the nostate Chart
-	var err error
-	nostate.Values, err = chartutil.CoalesceValues(&nostate, wi.SpecialResource.Spec.Set.Object)
-	if err != nil {
-		return err
+	for kernel, clusterVersion := range coverage {
+		if !uncovered[kernel] {
+			r.Metrics.SetKernelCoverage(wi.SpecialResource.Name, clusterVersion, kernel, metrics.CoverageBuilt)
+		}
 	}
 
-	rinfo, err := runtime.DefaultUnstructuredConverter.ToUnstructured(wi.RunInfo)
+	// We're done with states now execute the part of the chart without
+	// states we need to reconcile the nostate Chart
+	r.Debug.SetState(wi.SpecialResource.Name, "nostate")
+	runtimeValues, err := wi.RunInfo.Values()
 	if err != nil {
 		return err
 	}
 
-	nostate.Values, err = chartutil.CoalesceValues(&nostate, rinfo)
+	nostate.Values, err = states.Values(&nostate, set, runtimeValues)
 	if err != nil {
 		return err
 	}
 
-	return r.Helmer.Run(
+	err = r.Helmer.Run(
 		ctx,
 		nostate,
 		nostate.Values,
 		wi.SpecialResource,
 		wi.SpecialResource.Name,
 		wi.SpecialResource.Spec.Namespace,
-		wi.SpecialResource.Spec.NodeSelector,
+		wi.NodeSelector,
 		wi.RunInfo.KernelFullVersion,
 		wi.RunInfo.OperatingSystemDecimal,
-		false)
+		false,
+		r.statePostRenderer(wi, "nostate"))
+
+	r.recordManifests(ctx, wi, "nostate", "")
+
+	return err
+}
+
+// chargeBudget counts the objects listed in the status of the SpecialResource
+// for the state key against its resource budget.
+func (r *SpecialResourceReconciler) chargeBudget(wi *WorkItem, key string) error {
+	for _, ref := range wi.SpecialResource.Status.Objects[key] {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(ref.APIVersion)
+		obj.SetKind(ref.Kind)
+		obj.SetNamespace(ref.Namespace)
+		obj.SetName(ref.Name)
+
+		if err := r.Budget.Allow(wi.SpecialResource.Name, obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// missingObject returns the first object listed in the status of sr for the
+// state key that does not exist anymore, or was recreated, an empty string if
+// none.
+func (r *SpecialResourceReconciler) missingObject(ctx context.Context, sr *srov1beta1.SpecialResource, key string) (string, error) {
+	for _, ref := range sr.Status.Objects[key] {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(ref.APIVersion)
+		obj.SetKind(ref.Kind)
+
+		name := ref.Name
+		if ref.Namespace != "" {
+			name = ref.Namespace + "/" + ref.Name
+		}
+
+		err := r.KubeClient.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj)
+		if apierrors.IsNotFound(err) || (err == nil && ref.UID != "" && obj.GetUID() != ref.UID) {
+			return ref.Kind + " " + name, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("could not get %s %s: %w", ref.Kind, name, err)
+		}
+	}
+
+	return "", nil
+}
+
+// pruneKernels drops the entries of the kernel affine states for the kernels
+// that are not in kernels from the status of sr, and their coverage metrics.
+// Their keys are suffixed with the kernel version.
+func (r *SpecialResourceReconciler) pruneKernels(sr *srov1beta1.SpecialResource, kernels map[string]upgrade.NodeVersion) {
+	running := make([]string, 0, len(kernels))
+	for kernel := range kernels {
+		running = append(running, kernel)
+	}
+	r.Metrics.DeleteKernelCoverage(sr.Name, running)
+
+	stale := func(key string) bool {
+		i := strings.Index(key, "/")
+		if i == -1 {
+			return false
+		}
+		_, found := kernels[key[i+1:]]
+		return !found
+	}
+
+	for key := range sr.Status.Objects {
+		if stale(key) {
+			delete(sr.Status.Objects, key)
+		}
+	}
+
+	for key := range sr.Status.StateHashes {
+		if stale(key) {
+			delete(sr.Status.StateHashes, key)
+		}
+	}
+}
+
+// pruneMatchingNodes drops the node counts of the workloads that are not
+// listed in the objects of any state from the status of sr, e.g. the
+// DaemonSets of a kernel no node runs anymore.
+func pruneMatchingNodes(sr *srov1beta1.SpecialResource) {
+	applied := make(map[string]bool)
+	for _, refs := range sr.Status.Objects {
+		for _, ref := range refs {
+			workload := ref.Kind + " " + ref.Name
+			if ref.Namespace != "" {
+				workload = ref.Kind + " " + ref.Namespace + "/" + ref.Name
+			}
+			applied[workload] = true
+		}
+	}
+
+	for workload := range sr.Status.MatchingNodes {
+		if !applied[workload] {
+			delete(sr.Status.MatchingNodes, workload)
+		}
+	}
+
+	if len(sr.Status.MatchingNodes) == 0 {
+		sr.Status.MatchingNodes = nil
+	}
+}
+
+// recordManifests stores the objects applied since the last call for the
+// state, and lists them in the status of the SpecialResource under the state,
+// suffixed with the kernel version for a kernel affine state. Recording is
+// best effort, errors are only logged.
+func (r *SpecialResourceReconciler) recordManifests(ctx context.Context, wi *WorkItem, state, version string) {
+	if err := r.Recorder.Flush(ctx, wi.SpecialResource.Name, state, version, wi.Secrets); err != nil {
+		wi.Log.Info("Could not record the applied manifests", "state", state, "error", err)
+	}
+
+	key := state
+	if version != "" {
+		key += "/" + version
+	}
+
+	if wi.SpecialResource.Status.Objects == nil {
+		wi.SpecialResource.Status.Objects = make(map[string][]srov1beta1.ObjectReference)
+	}
+	wi.SpecialResource.Status.Objects[key] = r.Creator.AppliedObjects(wi.SpecialResource.Name)
+}
+
+// statePostRenderer returns the post-renderer of the state, the one of the
+// chart followed by the decision plugin.
+func (r *SpecialResourceReconciler) statePostRenderer(wi *WorkItem, state string) postrender.PostRenderer {
+	return r.Plugin.PostRenderer(plugin.Request{
+		SpecialResource:    wi.SpecialResource.Name,
+		State:              state,
+		Kernel:             wi.RunInfo.KernelFullVersion,
+		RuntimeInformation: wi.RunInfo,
+	}, wi.PostRenderer)
+}
+
+// postRendererID identifies postRenderer in the hash of a state, by its type
+// unless it tells its configuration.
+func postRendererID(postRenderer postrender.PostRenderer) string {
+	if postRenderer == nil {
+		return ""
+	}
+	if s, ok := postRenderer.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return fmt.Sprintf("%T", postRenderer)
+}
+
+// flushAudit stores the decisions taken for the SpecialResource along with the
+// kernels it was reconciled for. Auditing is best effort, errors are only
+// logged.
+func (r *SpecialResourceReconciler) flushAudit(ctx context.Context, wi *WorkItem) {
+	kernels := make([]string, 0, len(wi.RunInfo.ClusterUpgradeInfo))
+	for kernel := range wi.RunInfo.ClusterUpgradeInfo {
+		kernels = append(kernels, kernel)
+	}
+
+	if err := r.Audit.Flush(ctx, wi.SpecialResource.Name, kernels); err != nil {
+		wi.Log.Info("Could not store the audit log", "error", err)
+	}
 }
 
 func (r *SpecialResourceReconciler) createSpecialResourceNamespace(ctx context.Context, wi *WorkItem) error {
@@ -291,7 +673,15 @@ metadata:
   annotations:
     specialresource.openshift.io/wait: "true"
     openshift.io/cluster-monitoring: "true"
-  name: `)
+`)
+
+	// An empty node selector overrides the cluster default one
+	if wi.SpecialResource.Spec.SchedulerDefaults == srov1beta1.SchedulerDefaultsIgnore {
+		ns = append(ns, []byte(`    openshift.io/node-selector: ""
+`)...)
+	}
+
+	ns = append(ns, []byte(`  name: `)...)
 
 	if wi.SpecialResource.Spec.Namespace != "" {
 		add := []byte(wi.SpecialResource.Spec.Namespace)
@@ -310,6 +700,210 @@ metadata:
 	return nil
 }
 
+// reconcileCcacheVolume creates the PersistentVolumeClaim backing the ccache of
+// the driver builds, or deletes it once the cache is disabled.
+func (r *SpecialResourceReconciler) reconcileCcacheVolume(ctx context.Context, wi *WorkItem) error {
+
+	ccache := wi.SpecialResource.Spec.DriverContainer.Ccache
+	key := types.NamespacedName{
+		Namespace: wi.SpecialResource.Spec.Namespace,
+		Name:      resourcehelper.CcacheClaimName(wi.SpecialResource.Name),
+	}
+
+	pvc := &v1.PersistentVolumeClaim{}
+
+	err := r.KubeClient.Get(ctx, key, pvc)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("could not get the ccache PersistentVolumeClaim: %w", err)
+	}
+	exists := err == nil
+
+	if !ccache.Enabled {
+		if !exists {
+			return nil
+		}
+
+		// Never prune a claim with the same name that we did not create
+		if !metav1.IsControlledBy(pvc, wi.SpecialResource) {
+			return nil
+		}
+
+		wi.Log.Info("ccache disabled, deleting PersistentVolumeClaim", "name", key.Name)
+		if err = r.KubeClient.Delete(ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("could not delete the ccache PersistentVolumeClaim: %w", err)
+		}
+
+		return nil
+	}
+
+	// The spec of a bound claim is mostly immutable, an existing claim is
+	// kept as is until it is deleted
+	if exists {
+		return nil
+	}
+
+	accessMode := ccache.AccessMode
+	if accessMode == "" {
+		accessMode = v1.ReadWriteOnce
+	}
+
+	size := resourcehelper.CcacheSize(ccache)
+
+	manifest := fmt.Sprintf(`apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  accessModes:
+  - %s
+  resources:
+    requests:
+      storage: %s
+`, key.Name, key.Namespace, accessMode, size.String())
+
+	if ccache.StorageClassName != "" {
+		manifest += fmt.Sprintf("  storageClassName: %s\n", ccache.StorageClassName)
+	}
+
+	return r.Creator.CreateFromYAML(ctx, []byte(manifest), false, wi.SpecialResource, wi.SpecialResource.Name, key.Namespace, nil, "", "")
+}
+
+// reconcileEntitlement copies the cluster entitlement Secret into the namespace
+// of the SpecialResource, BuildConfigs can only mount Secrets of their namespace.
+func (r *SpecialResourceReconciler) reconcileEntitlement(ctx context.Context, wi *WorkItem) error {
+
+	if !wi.SpecialResource.Spec.DriverContainer.Entitled {
+		return nil
+	}
+
+	entitlement, err := r.KubeClient.GetSecret(ctx, entitlementNamespace, resourcehelper.EntitlementSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("entitled builds requested but the cluster is not entitled, %s/%s not found",
+			entitlementNamespace, resourcehelper.EntitlementSecretName)
+	} else if err != nil {
+		return fmt.Errorf("could not get the cluster entitlement: %w", err)
+	}
+
+	secret := v1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourcehelper.EntitlementSecretName,
+			Namespace: wi.SpecialResource.Spec.Namespace,
+		},
+		Type: entitlement.Type,
+		Data: entitlement.Data,
+	}
+
+	// Updated with the hash of the data, renewed certificates are propagated
+	manifest, err := k8syaml.Marshal(&secret)
+	if err != nil {
+		return fmt.Errorf("could not marshal the entitlement Secret: %w", err)
+	}
+
+	return r.Creator.CreateFromYAML(ctx, manifest, false, wi.SpecialResource, wi.SpecialResource.Name, secret.Namespace, nil, "", "")
+}
+
+// errMachineConfigRollout is returned until the machines of the pool rebooted
+// with the MachineConfig of the SpecialResource, the states are only applied
+// once the kernel arguments and the module blacklist are in effect.
+var errMachineConfigRollout = errors.New("waiting for the MachineConfig rollout")
+
+func (r *SpecialResourceReconciler) reconcileMachineConfig(ctx context.Context, wi *WorkItem) error {
+
+	sr := wi.SpecialResource
+	name := machineconfig.Name(sr.Name)
+
+	manifest, err := machineconfig.Manifest(sr)
+	if err != nil {
+		return err
+	}
+
+	if manifest == nil {
+		// Only the MachineConfig recorded in the status was created by us
+		if sr.Status.MachineConfig == nil {
+			return nil
+		}
+
+		mc := &unstructured.Unstructured{}
+		mc.SetAPIVersion(machinev1.SchemeGroupVersion.String())
+		mc.SetKind("MachineConfig")
+
+		err = r.KubeClient.Get(ctx, types.NamespacedName{Name: name}, mc)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("could not get MachineConfig %s: %w", name, err)
+		}
+
+		// Never prune a MachineConfig with the same name that we did not create
+		if err == nil && metav1.IsControlledBy(mc, sr) {
+			wi.Log.Info("No kernel arguments nor module blacklist, deleting MachineConfig", "name", name)
+			if err = r.KubeClient.Delete(ctx, mc); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("could not delete MachineConfig %s: %w", name, err)
+			}
+		}
+
+		sr.Status.MachineConfig = nil
+		return nil
+	}
+
+	pool := machineconfig.Pool(sr)
+	if _, found := wi.RunInfo.MachineConfigPools[pool]; !found {
+		return fmt.Errorf("MachineConfigPool %s not found, kernelArguments, moduleBlacklist and prerequisites need the Machine Config Operator", pool)
+	}
+
+	if err = r.Creator.CreateFromYAML(ctx, manifest, false, sr, sr.Name, "", nil, "", ""); err != nil {
+		return err
+	}
+
+	mcp := &unstructured.Unstructured{}
+	mcp.SetAPIVersion(machinev1.SchemeGroupVersion.String())
+	mcp.SetKind("MachineConfigPool")
+
+	if err = r.KubeClient.Get(ctx, types.NamespacedName{Name: pool}, mcp); err != nil {
+		return fmt.Errorf("could not get MachineConfigPool %s: %w", pool, err)
+	}
+
+	rollout := machineconfig.Rollout(mcp, name)
+	sr.Status.MachineConfig = &rollout
+
+	if !rollout.Done {
+		message := fmt.Sprintf("%d/%d machines of MachineConfigPool %s updated", rollout.UpdatedMachineCount, rollout.MachineCount, pool)
+		if rollout.Paused {
+			message += ", the pool is paused"
+		}
+		return fmt.Errorf("%w: %s", errMachineConfigRollout, message)
+	}
+
+	return nil
+}
+
+// verifyPrerequisites returns a machineconfig.ErrPrerequisitesNotMet error
+// until the nodes of the SpecialResource run the MachineConfig of its
+// prerequisites.
+func (r *SpecialResourceReconciler) verifyPrerequisites(ctx context.Context, wi *WorkItem) error {
+
+	if wi.SpecialResource.Spec.Prerequisites == nil {
+		return nil
+	}
+
+	pool := machineconfig.Pool(wi.SpecialResource)
+
+	mcp := &unstructured.Unstructured{}
+	mcp.SetAPIVersion(machinev1.SchemeGroupVersion.String())
+	mcp.SetKind("MachineConfigPool")
+
+	if err := r.KubeClient.Get(ctx, types.NamespacedName{Name: pool}, mcp); err != nil {
+		return fmt.Errorf("could not get MachineConfigPool %s: %w", pool, err)
+	}
+
+	nodeList, err := r.KubeClient.GetNodesByLabels(ctx, wi.NodeSelector)
+	if err != nil {
+		return fmt.Errorf("could not list the nodes: %w", err)
+	}
+
+	return machineconfig.Verify(wi.SpecialResource, mcp, nodeList.Items)
+}
+
 // ReconcileChart Reconcile Hardware Configurations
 func (r *SpecialResourceReconciler) ReconcileChart(ctx context.Context, wi *WorkItem) error {
 	// Leave this here, this is crucial for all following work
@@ -323,6 +917,18 @@ func (r *SpecialResourceReconciler) ReconcileChart(ctx context.Context, wi *Work
 		return fmt.Errorf("could not create ImagePuller RoleBinding: %w", err)
 	}
 
+	if err := r.reconcileCcacheVolume(ctx, wi); err != nil {
+		return fmt.Errorf("could not reconcile the ccache volume: %w", err)
+	}
+
+	if err := r.reconcileEntitlement(ctx, wi); err != nil {
+		return fmt.Errorf("could not reconcile the entitlement: %w", err)
+	}
+
+	if err := r.reconcileMachineConfig(ctx, wi); err != nil {
+		return fmt.Errorf("could not reconcile the MachineConfig: %w", err)
+	}
+
 	if err := r.ReconcileChartStates(ctx, wi); err != nil {
 		return fmt.Errorf("cannot reconcile hardware states: %w", err)
 	}
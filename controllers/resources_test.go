@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("missingObject", func() {
+	var (
+		kubeClient *clients.MockClientsInterface
+		r          *SpecialResourceReconciler
+		sr         *srov1beta1.SpecialResource
+	)
+
+	BeforeEach(func() {
+		kubeClient = clients.NewMockClientsInterface(gomock.NewController(GinkgoT()))
+		r = &SpecialResourceReconciler{KubeClient: kubeClient}
+
+		sr = &srov1beta1.SpecialResource{}
+		sr.Status.Objects = map[string][]srov1beta1.ObjectReference{
+			"0000-config.yaml": {
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "ns", Name: "config", UID: "config-uid"},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "ns", Name: "other"},
+			},
+		}
+	})
+
+	It("should not report the existing objects", func() {
+		kubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "ns", Name: "config"}, gomock.Any()).
+			Do(func(_ context.Context, _ types.NamespacedName, obj client.Object) {
+				obj.SetUID("config-uid")
+			})
+		kubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "ns", Name: "other"}, gomock.Any())
+
+		Expect(r.missingObject(context.Background(), sr, "0000-config.yaml")).To(BeEmpty())
+		Expect(r.missingObject(context.Background(), sr, "0001-other.yaml")).To(BeEmpty())
+	})
+
+	It("should report a deleted object", func() {
+		kubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "ns", Name: "config"}, gomock.Any()).
+			Return(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "config"))
+
+		Expect(r.missingObject(context.Background(), sr, "0000-config.yaml")).To(Equal("ConfigMap ns/config"))
+	})
+
+	It("should report a recreated object", func() {
+		kubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "ns", Name: "config"}, gomock.Any()).
+			Do(func(_ context.Context, _ types.NamespacedName, obj client.Object) {
+				obj.SetUID("other-uid")
+			})
+
+		Expect(r.missingObject(context.Background(), sr, "0000-config.yaml")).To(Equal("ConfigMap ns/config"))
+	})
+
+	It("should return the errors of the API server", func() {
+		kubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "ns", Name: "config"}, gomock.Any()).
+			Return(apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "config", nil))
+
+		_, err := r.missingObject(context.Background(), sr, "0000-config.yaml")
+		Expect(err).To(HaveOccurred())
+	})
+})
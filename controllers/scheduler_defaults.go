@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+)
+
+// nodeSelector returns the nodeSelector of the workloads of sr. Unless sr opts
+// out of the cluster defaults, it is the default node selector of the cluster
+// Scheduler config merged with the one of sr, which wins on conflicting keys,
+// so that the nodes the workloads are previewed on and the nodes labeled for
+// their states are the ones they are actually scheduled on.
+func (r *SpecialResourceReconciler) nodeSelector(ctx context.Context, sr *srov1beta1.SpecialResource) (map[string]string, error) {
+	if sr.Spec.SchedulerDefaults == srov1beta1.SchedulerDefaultsIgnore {
+		return sr.Spec.NodeSelector, nil
+	}
+
+	defaults, err := r.Cluster.DefaultNodeSelector(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the default node selector of the cluster: %w", err)
+	}
+
+	if len(defaults) == 0 {
+		return sr.Spec.NodeSelector, nil
+	}
+
+	selector := make(map[string]string, len(defaults)+len(sr.Spec.NodeSelector))
+	for k, v := range defaults {
+		selector[k] = v
+	}
+	for k, v := range sr.Spec.NodeSelector {
+		selector[k] = v
+	}
+
+	return selector, nil
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-psap/special-resource-operator/internal/controllers/finalizers"
+	"github.com/openshift-psap/special-resource-operator/internal/controllers/state"
+	"github.com/openshift-psap/special-resource-operator/internal/resourcehelper"
+	"github.com/openshift-psap/special-resource-operator/pkg/assets"
+	"github.com/openshift-psap/special-resource-operator/pkg/audit"
+	"github.com/openshift-psap/special-resource-operator/pkg/budget"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/cluster"
+	"github.com/openshift-psap/special-resource-operator/pkg/debug"
+	"github.com/openshift-psap/special-resource-operator/pkg/featuregates"
+	"github.com/openshift-psap/special-resource-operator/pkg/filter"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
+	"github.com/openshift-psap/special-resource-operator/pkg/lifecycle"
+	"github.com/openshift-psap/special-resource-operator/pkg/manifests"
+	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
+	"github.com/openshift-psap/special-resource-operator/pkg/plugin"
+	"github.com/openshift-psap/special-resource-operator/pkg/poll"
+	"github.com/openshift-psap/special-resource-operator/pkg/proxy"
+	"github.com/openshift-psap/special-resource-operator/pkg/recorder"
+	"github.com/openshift-psap/special-resource-operator/pkg/registry"
+	"github.com/openshift-psap/special-resource-operator/pkg/resource"
+	"github.com/openshift-psap/special-resource-operator/pkg/runtime"
+	"github.com/openshift-psap/special-resource-operator/pkg/secrets"
+	"github.com/openshift-psap/special-resource-operator/pkg/storage"
+	"github.com/openshift-psap/special-resource-operator/pkg/summary"
+	"github.com/openshift-psap/special-resource-operator/pkg/unload"
+	"github.com/openshift-psap/special-resource-operator/pkg/upgrade"
+	"github.com/openshift-psap/special-resource-operator/pkg/watcher"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Options are the settings of the operator the reconciler is built from.
+type Options struct {
+	API                  clients.LimitOptions
+	AuditLog             bool
+	ChartUpgradeInterval time.Duration
+	ChartVerification    helmer.ChartVerification
+	DecisionPlugin       plugin.Options
+	FeatureGates         string
+	LibraryCharts        []helmerv1beta1.HelmChart
+	RecordManifests      bool
+	RegistryBurst        int
+	RegistryQPS          float64
+	ResourceBudget       string
+	StorageBackend       string
+
+	// Cluster, Registry and PollActions replace the ones backed by the
+	// cluster and the registries when set, e.g. by pkg/srotest.
+	Cluster     cluster.Cluster
+	Registry    registry.Registry
+	PollActions poll.PollActions
+}
+
+// NewSpecialResourceReconciler wires the reconciler on top of the client of
+// the manager, as the operator runs it. The registry is returned as well, the
+// webhook and the ready checks use it.
+func NewSpecialResourceReconciler(mgr ctrl.Manager, scheme *k8sruntime.Scheme, opts Options) (*SpecialResourceReconciler, registry.Registry, error) {
+	metricsClient := metrics.New()
+
+	kubeClient, err := clients.NewClients(mgr.GetClient(), mgr.GetConfig(), mgr.GetEventRecorderFor("specialresource"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create the k8s clients: %w", err)
+	}
+	kubeClient = clients.NewLimitedClients(kubeClient, scheme, metricsClient, opts.API)
+
+	featureGates, err := featuregates.New(opts.FeatureGates, metricsClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid feature gates: %w", err)
+	}
+
+	st, err := storage.New(opts.StorageBackend, kubeClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create the storage: %w", err)
+	}
+
+	pluginAPI, err := plugin.New(opts.DecisionPlugin)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid decision plugin: %w", err)
+	}
+
+	budgetAPI, err := budget.New(opts.ResourceBudget)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid resource budget: %w", err)
+	}
+
+	helmSettings, err := helmer.DefaultSettings()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create the Helm settings: %w", err)
+	}
+
+	clusterAPI := opts.Cluster
+	if clusterAPI == nil {
+		clusterAPI = cluster.NewCluster(kubeClient)
+	}
+
+	registryAPI := opts.Registry
+	if registryAPI == nil {
+		registryAPI = registry.NewRegistry(kubeClient, opts.RegistryQPS, opts.RegistryBurst)
+	}
+
+	lc := lifecycle.New(kubeClient, st)
+
+	pollActions := opts.PollActions
+	if pollActions == nil {
+		pollActions = poll.New(kubeClient, lc, st)
+	}
+
+	kernelAPI := kernel.NewKernelData()
+	proxyAPI := proxy.NewProxyAPI(kubeClient)
+	recorderAPI := recorder.New(kubeClient, st, opts.RecordManifests)
+	auditAPI := audit.New(kubeClient, st, opts.AuditLog)
+	debugAPI := debug.New()
+	watcherAPI := watcher.New(debugAPI)
+
+	creator := resource.NewCreator(
+		kubeClient,
+		metricsClient,
+		pollActions,
+		kernelAPI,
+		scheme,
+		lc,
+		proxyAPI,
+		resourcehelper.New(),
+		recorderAPI,
+		featureGates,
+		watcherAPI,
+		budgetAPI,
+		auditAPI)
+
+	clusterInfoAPI := upgrade.NewClusterInfo(registryAPI, clusterAPI)
+
+	ownershipAPI := ownership.New(kubeClient)
+	if opts.API.CacheUnstructured {
+		if err = ownership.AddOwnerIndex(context.Background(), mgr.GetFieldIndexer()); err != nil {
+			return nil, nil, fmt.Errorf("could not index the owned objects: %w", err)
+		}
+		ownershipAPI = ownership.NewIndexed(kubeClient)
+	}
+
+	helmerAPI := helmer.NewHelmer(creator, helmSettings, kubeClient, opts.LibraryCharts, opts.ChartVerification)
+
+	return &SpecialResourceReconciler{
+		Cluster:       clusterAPI,
+		ClusterInfo:   clusterInfoAPI,
+		Creator:       creator,
+		PollActions:   pollActions,
+		Filter:        filter.NewFilter(filter.Kind, filter.OwnedLabel, scheme, lc, st, kernelAPI, metricsClient),
+		Finalizer:     finalizers.NewSpecialResourceFinalizer(kubeClient, pollActions, ownershipAPI, helmerAPI),
+		StatusUpdater: state.NewStatusUpdater(kubeClient),
+		Storage:       st,
+		Helmer:        helmerAPI,
+		Manifests:     manifests.New(kubeClient),
+		Assets:        assets.NewAssets(),
+		KernelData:    kernelAPI,
+		Log:           ctrl.Log,
+		Metrics:       metricsClient,
+		Scheme:        scheme,
+		ProxyAPI:      proxyAPI,
+		RuntimeAPI:    runtime.NewRuntimeAPI(kubeClient, clusterAPI, kernelAPI, clusterInfoAPI, proxyAPI),
+		KubeClient:    kubeClient,
+		Ownership:     ownershipAPI,
+		Debug:         debugAPI,
+		FeatureGates:  featureGates,
+		Recorder:      recorderAPI,
+		Unload:        unload.New(kubeClient),
+		Secrets:       secrets.New(kubeClient, nil),
+		Watcher:       watcherAPI,
+		Budget:        budgetAPI,
+		Audit:         auditAPI,
+		Plugin:        pluginAPI,
+		Summary:       summary.New(),
+
+		ChartUpgradeInterval: opts.ChartUpgradeInterval,
+	}, registryAPI, nil
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"text/template"
 
 	"github.com/go-logr/logr"
@@ -13,13 +15,19 @@ import (
 	"github.com/openshift-psap/special-resource-operator/internal/controllers/finalizers"
 	"github.com/openshift-psap/special-resource-operator/internal/controllers/state"
 	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
+	"github.com/openshift-psap/special-resource-operator/pkg/machineconfig"
+	"github.com/openshift-psap/special-resource-operator/pkg/resource"
 	"github.com/openshift-psap/special-resource-operator/pkg/runtime"
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
+	"github.com/openshift-psap/special-resource-operator/pkg/tracing"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
 	operatorv1 "github.com/openshift/api/operator/v1"
-	"github.com/pkg/errors"
 	"helm.sh/helm/v3/pkg/chart"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -63,11 +71,27 @@ func (r *SpecialResourceReconciler) SpecialResourcesReconcile(ctx context.Contex
 
 	log.Info("Resolving Dependencies")
 	var err error
-	wi.Chart, err = r.Helmer.Load(wi.SpecialResource.Spec.Chart)
-	if err != nil {
-		if suErr := r.StatusUpdater.SetAsErrored(ctx, wi.SpecialResource, state.ChartFailure, fmt.Sprintf("Failed to load Helm Chart: %v", err)); suErr != nil {
-			log.Error(suErr, "failed to update CR's status to Errored")
+	if m := wi.SpecialResource.Spec.Manifests; m != nil {
+		_, span := tracing.Start(ctx, "chart.load", "manifests", m.ConfigMap)
+		wi.Chart, err = r.Manifests.Load(ctx, wi.SpecialResource.Name, m)
+		span.End(err)
+	} else {
+		_, span := tracing.Start(ctx, "chart.load", "chart", wi.SpecialResource.Spec.Chart.Name)
+		spec := pinnedChart(wi.SpecialResource, r.upgradeChart(ctx, log, wi.SpecialResource))
+		var digest string
+		wi.Chart, digest, err = r.Helmer.Load(ctx, spec)
+		span.End(err)
+		if err == nil && digest != "" {
+			wi.SpecialResource.Status.Chart = &srov1beta1.ResolvedChart{
+				Name:       spec.Name,
+				Version:    spec.Version,
+				Repository: spec.Repository.URL,
+				Digest:     digest,
+			}
 		}
+	}
+	if err != nil {
+		r.setAsErrored(ctx, log, wi.SpecialResource, state.ChartFailure, "Failed to load Helm Chart", err)
 		return reconcile.Result{}, err
 	}
 
@@ -79,11 +103,11 @@ func (r *SpecialResourceReconciler) SpecialResourcesReconcile(ctx context.Contex
 		clog := log.WithName(utils.Print(dependency.Name, utils.Purple))
 		clog.Info("Getting Dependency")
 
-		cchart, err := r.Helmer.Load(dependency.HelmChart)
+		_, span := tracing.Start(ctx, "chart.load", "chart", dependency.HelmChart.Name)
+		cchart, _, err := r.Helmer.Load(ctx, dependency.HelmChart)
+		span.End(err)
 		if err != nil {
-			if suErr := r.StatusUpdater.SetAsErrored(ctx, wi.SpecialResource, state.DependencyChartFailure, fmt.Sprintf("Failed to load dependency Helm Chart: %v", err)); suErr != nil {
-				clog.Error(suErr, "failed to update CR's status to Errored")
-			}
+			r.setAsErrored(ctx, clog, wi.SpecialResource, state.DependencyChartFailure, "Failed to load dependency Helm Chart", err)
 			return ctrl.Result{}, err
 		}
 
@@ -95,15 +119,16 @@ func (r *SpecialResourceReconciler) SpecialResourcesReconcile(ctx context.Contex
 			Name:      "special-resource-dependencies",
 		}
 		if err = r.Storage.UpdateConfigMapEntry(ctx, dependency.Name, wi.SpecialResource.Name, ins); err != nil {
-			if suErr := r.StatusUpdater.SetAsErrored(ctx, wi.SpecialResource, state.FailedToStoreDependencyInfo, fmt.Sprintf("Failed to store dependency information: %v", err)); suErr != nil {
-				clog.Error(suErr, "failed to update CR's status to Errored")
-			}
+			r.setAsErrored(ctx, clog, wi.SpecialResource, state.FailedToStoreDependencyInfo, "Failed to store dependency information", err)
 			return reconcile.Result{}, err
 		}
 
-		var child srov1beta1.SpecialResource
-		if child, err = getDependencyFrom(wi.AllSRs, dependency.Name); err != nil {
-			clog.Info("Failed to find dependency in list of all SpecialResources")
+		child := &srov1beta1.SpecialResource{}
+		if err = r.KubeClient.Get(ctx, types.NamespacedName{Name: dependency.Name}, child); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			clog.Info("Failed to find dependency SpecialResource")
 			if err = r.createSpecialResourceFrom(ctx, clog, cchart, dependency.HelmChart); err != nil {
 				clog.Error(err, "Failed to create SpecialResource for dependency")
 				return reconcile.Result{}, err
@@ -112,12 +137,14 @@ func (r *SpecialResourceReconciler) SpecialResourcesReconcile(ctx context.Contex
 			return reconcile.Result{Requeue: true}, nil
 		}
 
+		// The objects of the dependency count against the budget of the
+		// SpecialResource
+		r.Budget.Share(child.Name, wi.SpecialResource.Name)
+
 		child.Spec.Set = dependency.Set
-		childWorkItem := wi.CreateForChild(&child, cchart)
+		childWorkItem := wi.CreateForChild(child, cchart)
 		if err := r.ReconcileSpecialResourceChart(ctx, childWorkItem); err != nil {
-			if suErr := r.StatusUpdater.SetAsErrored(ctx, &child, state.FailedToDeployDependencyChart, fmt.Sprintf("Failed to deploy dependency: %v", err)); suErr != nil {
-				clog.Error(suErr, "failed to update CR's status to Errored")
-			}
+			r.setAsErrored(ctx, clog, child, reasonFor(err, state.FailedToDeployDependencyChart), "Failed to deploy dependency", err)
 			clog.Error(err, "Failed to reconcile chart")
 			return reconcile.Result{Requeue: true}, nil
 		}
@@ -125,22 +152,104 @@ func (r *SpecialResourceReconciler) SpecialResourcesReconcile(ctx context.Contex
 	}
 
 	log.Info("Done resolving dependencies - reconciling main SpecialResource")
-	if err := r.ReconcileSpecialResourceChart(ctx, wi); err != nil {
-		if suErr := r.StatusUpdater.SetAsErrored(ctx, wi.SpecialResource, state.FailedToDeployChart, fmt.Sprintf("Failed to deploy SpecialResource's chart: %v", err)); suErr != nil {
-			log.Error(suErr, "failed to update CR's status to Errored")
+	err = r.ReconcileSpecialResourceChart(ctx, wi)
+	r.reportInventory(ctx, wi)
+	if errors.Is(err, errMachineConfigRollout) {
+		// Rebooting the machines of the pool takes minutes, it is not an error
+		log.Info("Waiting for the MachineConfig rollout", "status", err.Error())
+		if suErr := r.StatusUpdater.SetAsProgressing(ctx, wi.SpecialResource, state.MachineConfigRollout, err.Error()); suErr != nil {
+			log.Error(suErr, "failed to update CR's status to Progressing")
+			return reconcile.Result{}, suErr
 		}
+		return reconcile.Result{RequeueAfter: MachineConfigRolloutDelay}, nil
+	}
+	if err != nil {
+		r.setAsErrored(ctx, log, wi.SpecialResource, reasonFor(err, state.FailedToDeployChart), "Failed to deploy SpecialResource's chart", err)
 		log.Error(err, "RECONCILE REQUEUE: Could not reconcile chart")
 		return reconcile.Result{Requeue: true}, nil
 	}
 
-	if suErr := r.StatusUpdater.SetAsReady(ctx, wi.SpecialResource, state.Success, ""); suErr != nil {
+	// Monitoring objects the cluster would not act on are noted in the
+	// Ready condition
+	message := ""
+	if skipped := r.Creator.SkippedObjects(wi.SpecialResource.Name); len(skipped) > 0 {
+		message = "Skipped " + strings.Join(skipped, "; ")
+	}
+
+	if suErr := r.StatusUpdater.SetAsReady(ctx, wi.SpecialResource, state.Success, message); suErr != nil {
 		log.Error(suErr, "failed to update CR's status to Ready")
 		return reconcile.Result{}, suErr
 	}
 	log.Info("RECONCILE SUCCESS: All resources done")
+
+	if hasUpgradePolicy(wi.SpecialResource) {
+		return reconcile.Result{RequeueAfter: r.ChartUpgradeInterval}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
+// pinnedChart returns spec, the chart of sr, pinned to the digest recorded in
+// the status of sr as long as the chart does not change, so that a chart
+// republished under the same version is not picked up silently.
+func pinnedChart(sr *srov1beta1.SpecialResource, spec helmerv1beta1.HelmChart) helmerv1beta1.HelmChart {
+	resolved := sr.Status.Chart
+	if spec.Digest == "" && resolved != nil &&
+		resolved.Name == spec.Name && resolved.Version == spec.Version && resolved.Repository == spec.Repository.URL {
+		spec.Digest = resolved.Digest
+	}
+
+	return spec
+}
+
+// setAsErrored sets the Errored condition of sr and counts err in the reconcile
+// errors metric by its category.
+func (r *SpecialResourceReconciler) setAsErrored(ctx context.Context, log logr.Logger, sr *srov1beta1.SpecialResource, reason, message string, err error) {
+	r.Metrics.IncReconcileError(sr.Name, string(sroerrors.CategoryOf(err)))
+
+	if suErr := r.StatusUpdater.SetAsErrored(ctx, sr, reason, fmt.Sprintf("%s: %v", message, err)); suErr != nil {
+		log.Error(suErr, "failed to update CR's status to Errored")
+	}
+}
+
+// reasonFor returns the reason of the Errored condition for err: an unsupported
+// kernel or a forbidden API call, else the category of err, so that a broken
+// chart can be told apart from a broken cluster, and fallback otherwise.
+func reasonFor(err error, fallback string) string {
+	var policyErr *resource.PolicyDeniedError
+	if errors.Is(err, kernel.ErrUnsupportedKernel) {
+		return state.UnsupportedKernel
+	} else if errors.Is(err, machineconfig.ErrPrerequisitesNotMet) {
+		return state.PrerequisitesNotMet
+	} else if errors.As(err, &policyErr) {
+		return state.PreApplyPolicyFailure
+	} else if isForbidden(err) {
+		return state.Forbidden
+	}
+
+	if category := sroerrors.CategoryOf(err); category != sroerrors.Unknown {
+		return string(category)
+	}
+
+	return fallback
+}
+
+// isForbidden returns true if err, or one of the errors it aggregates, is a
+// Forbidden API error.
+func isForbidden(err error) bool {
+	var agg utilerrors.Aggregate
+	if errors.As(err, &agg) {
+		for _, e := range agg.Errors() {
+			if isForbidden(e) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return apierrors.IsForbidden(err)
+}
+
 func TemplateFragment(sr interface{}, runInfo *runtime.RuntimeInformation) error {
 	spec, err := json.Marshal(sr)
 	if err != nil {
@@ -181,13 +290,30 @@ func TemplateFragment(sr interface{}, runInfo *runtime.RuntimeInformation) error
 func (r *SpecialResourceReconciler) ReconcileSpecialResourceChart(ctx context.Context, wi *WorkItem) error {
 	wi.Log.Info("Reconciling chart", "chart", wi.Chart.Name)
 
+	// The objects of every state are counted again, so that the ones removed
+	// from the chart do not use the budget anymore
+	r.Budget.Reset(wi.SpecialResource.Name)
+
 	var err error
 	wi.RunInfo, err = r.RuntimeAPI.GetRuntimeInformation(ctx, wi.SpecialResource)
 	if err != nil {
 		return err
 	}
 
+	if wi.NodeSelector, err = r.nodeSelector(ctx, wi.SpecialResource); err != nil {
+		return err
+	}
+
+	// The decisions of this reconcile are kept whatever its outcome
+	defer r.flushAudit(ctx, wi)
+
 	r.RuntimeAPI.LogRuntimeInformation(wi.RunInfo)
+	r.Debug.SetRuntimeInformation(wi.SpecialResource.Name, wi.RunInfo)
+
+	// Refuse to build for kernels the chart does not support
+	if err := kernel.CheckRange(wi.Chart.Metadata.Annotations[kernel.RangeAnnotation], clusterKernels(wi.RunInfo)); err != nil {
+		return err
+	}
 
 	for idx, dep := range wi.SpecialResource.Spec.Dependencies {
 		if dep.Set.Object == nil {
@@ -249,25 +375,6 @@ func (r *SpecialResourceReconciler) ReconcileSpecialResourceChart(ctx context.Co
 	return r.ReconcileChart(ctx, wi)
 }
 
-func FindSR(a []srov1beta1.SpecialResource, x string, by string) (int, bool) {
-	for i, n := range a {
-		if by == "Name" {
-			if x == n.GetName() {
-				return i, true
-			}
-		}
-	}
-	return -1, false
-}
-
-func getDependencyFrom(specialresources *srov1beta1.SpecialResourceList, name string) (srov1beta1.SpecialResource, error) {
-	if idx, found := FindSR(specialresources.Items, name, "Name"); found {
-		return specialresources.Items[idx], nil
-	}
-
-	return srov1beta1.SpecialResource{}, errors.New("Not found")
-}
-
 func noop() error {
 	return nil
 }
@@ -286,6 +393,7 @@ func (r *SpecialResourceReconciler) createSpecialResourceFrom(ctx context.Contex
 	sr.Spec.Chart.Repository.Name = dp.Repository.Name
 	sr.Spec.Chart.Repository.URL = dp.Repository.URL
 	sr.Spec.Chart.Tags = make([]string, 0)
+	sr.Spec.Chart.PostRenderer = dp.PostRenderer.DeepCopy()
 	sr.Spec.Set = vals
 	sr.Spec.Dependencies = make([]srov1beta1.SpecialResourceDependency, 0)
 
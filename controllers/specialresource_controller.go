@@ -19,9 +19,12 @@ package controllers
 import (
 	"context"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	buildv1 "github.com/openshift/api/build/v1"
+	configv1 "github.com/openshift/api/config/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	secv1 "github.com/openshift/api/security/v1"
 	"github.com/pkg/errors"
@@ -29,30 +32,47 @@ import (
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/internal/controllers/finalizers"
 	"github.com/openshift-psap/special-resource-operator/internal/controllers/state"
 	"github.com/openshift-psap/special-resource-operator/pkg/assets"
+	"github.com/openshift-psap/special-resource-operator/pkg/audit"
+	"github.com/openshift-psap/special-resource-operator/pkg/budget"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
 	"github.com/openshift-psap/special-resource-operator/pkg/cluster"
+	"github.com/openshift-psap/special-resource-operator/pkg/debug"
+	"github.com/openshift-psap/special-resource-operator/pkg/featuregates"
 	"github.com/openshift-psap/special-resource-operator/pkg/filter"
 	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
 	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
+	"github.com/openshift-psap/special-resource-operator/pkg/manifests"
 	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
+	"github.com/openshift-psap/special-resource-operator/pkg/plugin"
 	"github.com/openshift-psap/special-resource-operator/pkg/poll"
 	"github.com/openshift-psap/special-resource-operator/pkg/proxy"
+	"github.com/openshift-psap/special-resource-operator/pkg/recorder"
 	"github.com/openshift-psap/special-resource-operator/pkg/resource"
 	"github.com/openshift-psap/special-resource-operator/pkg/runtime"
+	"github.com/openshift-psap/special-resource-operator/pkg/secrets"
 	"github.com/openshift-psap/special-resource-operator/pkg/storage"
+	"github.com/openshift-psap/special-resource-operator/pkg/summary"
+	"github.com/openshift-psap/special-resource-operator/pkg/tracing"
+	"github.com/openshift-psap/special-resource-operator/pkg/unload"
 	"github.com/openshift-psap/special-resource-operator/pkg/upgrade"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	"github.com/openshift-psap/special-resource-operator/pkg/watcher"
 )
 
 // SpecialResourceReconciler reconciles a SpecialResource object
@@ -67,6 +87,7 @@ type SpecialResourceReconciler struct {
 	Filter        filter.Filter
 	Finalizer     finalizers.SpecialResourceFinalizer
 	Helmer        helmer.Helmer
+	Manifests     manifests.Manifests
 	Assets        assets.Assets
 	PollActions   poll.PollActions
 	StatusUpdater state.StatusUpdater
@@ -75,6 +96,24 @@ type SpecialResourceReconciler struct {
 	ProxyAPI      proxy.ProxyAPI
 	RuntimeAPI    runtime.RuntimeAPI
 	KubeClient    clients.ClientsInterface
+	Ownership     ownership.Ownership
+	Debug         debug.Debug
+	FeatureGates  featuregates.FeatureGates
+	Recorder      recorder.Recorder
+	Unload        unload.Unload
+	Secrets       secrets.Secrets
+	Watcher       watcher.Watcher
+	Budget        budget.Budget
+	Audit         audit.Log
+	Plugin        plugin.Plugin
+	Summary       summary.Summary
+
+	// ChartUpgradeInterval is how often the charts with an upgrade policy
+	// are checked for newer versions.
+	ChartUpgradeInterval time.Duration
+
+	inFlight   inFlight
+	priorities priorities
 }
 
 // Reconcile Reconiliation entry point
@@ -87,25 +126,79 @@ func (r *SpecialResourceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	log.Info("TODO: preflight checks")
 
-	sr, srs, err := r.getSpecialResources(ctx, req)
+	sr, err := r.getSpecialResource(ctx, req)
 	if err != nil {
-		log.Error(err, "failed to get SpecialResources")
+		log.Error(err, "failed to get SpecialResource")
 		return ctrl.Result{}, err
 	} else if sr == nil {
 		log.Info("SpecialResource not found - probably deleted. Not reconciling.")
+		r.Debug.Forget(req.Name)
+		r.Summary.Forget(req.Name)
+		r.Metrics.DeleteSpecialResourceInfo(req.Name)
+		r.Metrics.DeleteCompletedStates(req.Name)
+		r.Metrics.DeleteOwnedObjects(req.Name)
+		r.Metrics.DeleteStates(req.Name)
+		r.Metrics.DeleteKernelCoverage(req.Name, nil)
+		r.Metrics.DeleteFeatureGates(req.Name)
+		r.Budget.Forget(req.Name)
 		return ctrl.Result{}, nil
 	}
 
-	r.Metrics.SetSpecialResourcesCreated(len(srs.Items))
+	r.FeatureGates.Report(sr)
+
+	if r.FeatureGates.Enabled(featuregates.GracefulUnload, sr) {
+		if err = r.Unload.Reconcile(ctx, sr); err != nil {
+			log.Info("Could not unload the driver from the rebooting nodes", "error", err)
+		}
+	}
+
+	if err = r.Recorder.Dump(ctx, sr); err != nil {
+		log.Info("Could not dump the recorded manifests", "error", err)
+	}
+
+	if err = r.countSpecialResources(ctx); err != nil {
+		log.Info("Could not count the SpecialResources", "error", err)
+	}
+
+	// The SpecialResources of a higher priority are reconciled first, a
+	// deleted one does not wait for them
+	if sr.GetDeletionTimestamp() == nil {
+		if name, wait, err := r.higherPriority(ctx, sr); err != nil {
+			log.Info("Could not check the priorities of the SpecialResources", "error", err)
+		} else if wait {
+			log.Info("Waiting for a SpecialResource of higher priority", "specialresource", name)
+			return reconcile.Result{RequeueAfter: PriorityDelay}, nil
+		} else if name != "" {
+			log.Info("Waited too long for a SpecialResource of higher priority, reconciling anyway", "specialresource", name, "timeout", PriorityTimeout)
+		}
+		defer r.priorities.done(sr.Name)
+	}
 
 	wi := &WorkItem{
 		SpecialResource: sr,
-		AllSRs:          srs,
 		Log:             log,
 	}
 
+	// Deleting the SpecialResource interrupts the builds and waits of this
+	// reconcile, the finalizer runs on the next one
+	if sr.GetDeletionTimestamp() == nil {
+		var done func()
+		ctx, done = r.inFlight.start(ctx, sr.Name)
+		defer done()
+	}
+
+	ctx, span := tracing.Start(ctx, "reconcile", "specialresource", sr.Name)
+
 	// Reconcile all specialresources
-	if res, err = r.SpecialResourcesReconcile(ctx, wi); err == nil || !res.Requeue {
+	res, err = r.SpecialResourcesReconcile(ctx, wi)
+	span.End(err)
+	r.Summary.Update(wi.SpecialResource)
+
+	if ctx.Err() != nil {
+		log.Info("Reconcile interrupted, the SpecialResource is being deleted")
+		return reconcile.Result{Requeue: true}, nil
+	}
+	if err == nil || !res.Requeue {
 		return res, errors.Wrap(err, "Failed to reconcile SpecialResource")
 	}
 
@@ -113,37 +206,57 @@ func (r *SpecialResourceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	return reconcile.Result{}, nil
 }
 
-func (r *SpecialResourceReconciler) getSpecialResources(ctx context.Context, req ctrl.Request) (*srov1beta1.SpecialResource, *srov1beta1.SpecialResourceList, error) {
-	specialresources := &srov1beta1.SpecialResourceList{}
+// getSpecialResource returns the SpecialResource of the request, or nil if it
+// does not exist anymore.
+func (r *SpecialResourceReconciler) getSpecialResource(ctx context.Context, req ctrl.Request) (*srov1beta1.SpecialResource, error) {
+	sr := &srov1beta1.SpecialResource{}
 
-	opts := []client.ListOption{}
-	err := r.KubeClient.List(ctx, specialresources, opts...)
+	err := r.KubeClient.Get(ctx, types.NamespacedName{Name: req.Name}, sr)
+	if err == nil {
+		return sr, nil
+	} else if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	// If we do not find the specialresource it might be deleted,
+	// if it is a depdendency of another specialresource assign the
+	// parent specialresource for processing.
+	obj := types.NamespacedName{
+		Namespace: os.Getenv("OPERATOR_NAMESPACE"),
+		Name:      "special-resource-dependencies",
+	}
+	parent, err := r.Storage.CheckConfigMapEntry(ctx, req.Name, obj)
 	if err != nil {
-		return nil, nil, err
-	}
-
-	var idx int
-	var found bool
-	if idx, found = FindSR(specialresources.Items, req.Name, "Name"); !found {
-		// If we do not find the specialresource it might be deleted,
-		// if it is a depdendency of another specialresource assign the
-		// parent specialresource for processing.
-		obj := types.NamespacedName{
-			Namespace: os.Getenv("OPERATOR_NAMESPACE"),
-			Name:      "special-resource-dependencies",
-		}
-		parent, err := r.Storage.CheckConfigMapEntry(ctx, req.Name, obj)
-		if err != nil {
-			return nil, nil, err
-		}
+		return nil, err
+	}
+
+	if parent == "" {
+		return nil, nil
+	}
 
-		idx, found = FindSR(specialresources.Items, parent, "Name")
-		if !found {
-			return nil, nil, nil
+	if err = r.KubeClient.Get(ctx, types.NamespacedName{Name: parent}, sr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
 		}
+		return nil, err
+	}
+
+	return sr, nil
+}
+
+// countSpecialResources updates the number of SpecialResources in the cluster,
+// only their metadata is read.
+func (r *SpecialResourceReconciler) countSpecialResources(ctx context.Context) error {
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(srov1beta1.GroupVersion.WithKind("SpecialResourceList"))
+
+	if err := r.KubeClient.List(ctx, list); err != nil {
+		return err
 	}
 
-	return &specialresources.Items[idx], specialresources, nil
+	r.Metrics.SetSpecialResourcesCreated(len(list.Items))
+
+	return nil
 }
 
 // SetupWithManager main initalization for manager
@@ -155,8 +268,18 @@ func (r *SpecialResourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	watches := []schema.GroupVersionKind{srov1beta1.GroupVersion.WithKind(ownership.KindSpecialResource)}
+	for _, gvk := range ownership.OwnedKinds {
+		if platform == "OCP" || !strings.HasSuffix(gvk.Group, ".openshift.io") {
+			watches = append(watches, gvk)
+		}
+	}
+	watches = append(watches, v1.SchemeGroupVersion.WithKind("Node"))
+
+	var c controller.Controller
+
 	if platform == "OCP" {
-		return ctrl.NewControllerManagedBy(mgr).
+		c, err = ctrl.NewControllerManagedBy(mgr).
 			For(&srov1beta1.SpecialResource{}).
 			Owns(&v1.Pod{}).
 			Owns(&appsv1.DaemonSet{}).
@@ -176,10 +299,10 @@ func (r *SpecialResourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				MaxConcurrentReconciles: 1,
 			}).
 			WithEventFilter(r.Filter.GetPredicates()).
-			Complete(r)
+			Build(r)
 	} else {
 		log.Info("Warning: assuming vanilla K8s. Manager will own a limited set of resources.")
-		return ctrl.NewControllerManagedBy(mgr).
+		c, err = ctrl.NewControllerManagedBy(mgr).
 			For(&srov1beta1.SpecialResource{}).
 			Owns(&v1.Pod{}).
 			Owns(&appsv1.DaemonSet{}).
@@ -196,6 +319,52 @@ func (r *SpecialResourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				MaxConcurrentReconciles: 1,
 			}).
 			WithEventFilter(r.Filter.GetPredicates()).
-			Complete(r)
+			Build(r)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The kinds created by the charts that are not owned statically, e.g.
+	// Routes, are watched once the first object of the kind is applied
+	r.Watcher.SetController(c, r.Filter.GetPredicates(), watches)
+
+	if err = c.Watch(&source.Kind{Type: &srov1beta1.SpecialResource{}}, r.inFlight.handler()); err != nil {
+		return err
+	}
+
+	// Nodes are not owned by a SpecialResource, their watch is added to the
+	// controller directly so that it is not subject to the event filter.
+	if err = c.Watch(
+		&source.Kind{Type: &v1.Node{}},
+		handler.EnqueueRequestsFromMapFunc(r.specialResourcesForNode),
+		filter.KernelChanged()); err != nil {
+		return err
+	}
+
+	if err = c.Watch(
+		&source.Kind{Type: &v1.Node{}},
+		handler.EnqueueRequestsFromMapFunc(r.specialResourcesForNode),
+		filter.RebootChanged()); err != nil {
+		return err
 	}
+
+	if err = c.Watch(
+		&source.Kind{Type: &v1.Node{}},
+		handler.EnqueueRequestsFromMapFunc(r.specialResourcesForNodeReadiness),
+		filter.ReadinessChanged()); err != nil {
+		return err
+	}
+
+	// The upgrades are followed from the ClusterVersion cached by this watch
+	// rather than by resyncs, every SpecialResource is reconciled when one
+	// starts or completes
+	if platform == "OCP" {
+		return c.Watch(
+			&source.Kind{Type: &configv1.ClusterVersion{}},
+			handler.EnqueueRequestsFromMapFunc(r.specialResourcesForUpgrade),
+			filter.UpgradeChanged())
+	}
+
+	return nil
 }
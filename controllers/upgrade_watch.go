@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"context"
+
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// specialResourcesForUpgrade returns a request for every SpecialResource, an
+// upgrade of the cluster may bring new kernels to all of them.
+func (r *SpecialResourceReconciler) specialResourcesForUpgrade(obj client.Object) []reconcile.Request {
+	if cv, ok := obj.(*configv1.ClusterVersion); ok && len(cv.Status.History) > 0 {
+		r.Log.Info("Cluster upgrade", "version", cv.Status.History[0].Version, "state", cv.Status.History[0].State)
+	}
+
+	list := &srov1beta1.SpecialResourceList{}
+
+	if err := r.KubeClient.List(context.Background(), list); err != nil {
+		r.Log.Error(err, "Could not list the SpecialResources of an upgrade")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+
+	for _, sr := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: sr.Name}})
+	}
+
+	return requests
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/openshift-psap/special-resource-operator/pkg/runtime"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
 	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/postrender"
 )
 
 // WorkItem stores values required for current reconciliation
@@ -16,22 +17,28 @@ type WorkItem struct {
 	// SpecialResource is currently reconciled object
 	SpecialResource *srov1beta1.SpecialResource
 
-	// AllSRs stores all of currently existing SpecialResources in the cluster.
-	// It is used for resolving SpecialResource dependencies.
-	AllSRs *srov1beta1.SpecialResourceList
-
 	// Chart stores SpecialResource's chart
 	Chart *chart.Chart
 
 	// RunInfo contains information about the cluster.
 	RunInfo *runtime.RuntimeInformation
+
+	// NodeSelector is the nodeSelector of the workloads: the one of the
+	// SpecialResource merged with the cluster default, see nodeSelector.
+	NodeSelector map[string]string
+
+	// PostRenderer transforms the rendered manifests of the chart, if set.
+	PostRenderer postrender.PostRenderer
+
+	// Secrets are the values fetched from secret providers, they are redacted
+	// from the logs and from the recorded manifests.
+	Secrets []string
 }
 
 func (wi *WorkItem) CreateForChild(child *srov1beta1.SpecialResource, c *chart.Chart) *WorkItem {
 	return &WorkItem{
 		Log:             wi.Log.WithName(utils.Print(child.GetName(), utils.Purple)),
 		SpecialResource: child,
-		AllSRs:          wi.AllSRs,
 		Chart:           c,
 	}
 }
@@ -0,0 +1,175 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/internal/controllers/finalizers"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	drainInterval = 2 * time.Second
+
+	// nodeLabelPrefix is the prefix of the labels SRO sets on nodes
+	nodeLabelPrefix = "specialresource.openshift.io/"
+)
+
+// Report lists what is left of SRO once all SpecialResources are deleted.
+type Report struct {
+	// Objects are the owned objects left, as "Kind namespace/name".
+	Objects []string
+	// NodeLabels are the SRO labels left on nodes, as "node: label".
+	NodeLabels []string
+}
+
+func (r *Report) Empty() bool {
+	return len(r.Objects) == 0 && len(r.NodeLabels) == 0
+}
+
+//go:generate mockgen -source=cleanup.go -package=cleanup -destination=mock_cleanup_api.go
+
+type Cleaner interface {
+	Run(ctx context.Context) (*Report, error)
+}
+
+type cleaner struct {
+	kubeClient clients.ClientsInterface
+	finalizer  finalizers.SpecialResourceFinalizer
+	log        logr.Logger
+	timeout    time.Duration
+}
+
+// New returns a Cleaner that waits up to timeout for the owned objects to be
+// garbage collected.
+func New(kubeClient clients.ClientsInterface, finalizer finalizers.SpecialResourceFinalizer, timeout time.Duration) Cleaner {
+	return &cleaner{
+		kubeClient: kubeClient,
+		finalizer:  finalizer,
+		log:        zap.New(zap.UseDevMode(true)).WithName(utils.Print("cleanup", utils.Red)),
+		timeout:    timeout,
+	}
+}
+
+// Run deletes all SpecialResources and runs their finalizer, the operator is
+// expected to be scaled down. It then waits for the owned objects to drain and
+// reports anything left behind.
+func (c *cleaner) Run(ctx context.Context) (*Report, error) {
+	list := &v1beta1.SpecialResourceList{}
+
+	if err := c.kubeClient.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("could not list SpecialResources: %w", err)
+	}
+
+	for i := range list.Items {
+		if err := c.deleteSpecialResource(ctx, list.Items[i].Name); err != nil {
+			return nil, err
+		}
+	}
+
+	var objects []string
+
+	err := wait.PollImmediate(drainInterval, c.timeout, func() (bool, error) {
+		var err error
+		objects, err = c.ownedObjects(ctx)
+		return len(objects) == 0, err
+	})
+	if err != nil && !errors.Is(err, wait.ErrWaitTimeout) {
+		return nil, err
+	}
+
+	nodeLabels, err := c.nodeLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{Objects: objects, NodeLabels: nodeLabels}, nil
+}
+
+func (c *cleaner) deleteSpecialResource(ctx context.Context, name string) error {
+	c.log.Info("Deleting SpecialResource", "name", name)
+
+	sr := &v1beta1.SpecialResource{}
+	sr.SetName(name)
+
+	if err := c.kubeClient.Delete(ctx, sr); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("could not delete SpecialResource %s: %w", name, err)
+	}
+
+	// The deletion changed the resourceVersion
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: name}, sr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("could not get SpecialResource %s: %w", name, err)
+	}
+
+	if err := c.finalizer.Finalize(ctx, sr); err != nil {
+		return fmt.Errorf("could not finalize SpecialResource %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// ownedObjects returns the objects still carrying the owned label.
+func (c *cleaner) ownedObjects(ctx context.Context) ([]string, error) {
+	objects := make([]string, 0)
+
+	for _, gvk := range ownership.OwnedKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		if err := c.kubeClient.List(ctx, list, client.HasLabels{ownership.SpecialResourceOwnedLabel}); err != nil {
+			// OpenShift kinds on vanilla Kubernetes
+			var noMatch *meta.NoKindMatchError
+			if errors.As(err, &noMatch) {
+				continue
+			}
+			return nil, fmt.Errorf("could not list %s: %w", gvk.Kind, err)
+		}
+
+		for _, obj := range list.Items {
+			objects = append(objects, gvk.Kind+" "+strings.TrimPrefix(obj.GetNamespace()+"/"+obj.GetName(), "/"))
+		}
+	}
+
+	return objects, nil
+}
+
+func (c *cleaner) nodeLabels(ctx context.Context) ([]string, error) {
+	nodes := &corev1.NodeList{}
+
+	if err := c.kubeClient.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("could not list nodes: %w", err)
+	}
+
+	labels := make([]string, 0)
+
+	for _, node := range nodes.Items {
+		for label := range node.GetLabels() {
+			if strings.HasPrefix(label, nodeLabelPrefix) {
+				labels = append(labels, node.Name+": "+label)
+			}
+		}
+	}
+
+	sort.Strings(labels)
+
+	return labels, nil
+}
@@ -0,0 +1,121 @@
+package cleanup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/internal/cleanup"
+	"github.com/openshift-psap/special-resource-operator/internal/controllers/finalizers"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	ctrl          *gomock.Controller
+	mockClient    *clients.MockClientsInterface
+	mockFinalizer *finalizers.MockSpecialResourceFinalizer
+)
+
+func TestCleanup(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = clients.NewMockClientsInterface(ctrl)
+		mockFinalizer = finalizers.NewMockSpecialResourceFinalizer(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "Cleanup Suite")
+}
+
+var _ = Describe("Run", func() {
+	const name = "simple-kmod"
+
+	srList := gomock.AssignableToTypeOf(&v1beta1.SpecialResourceList{})
+	srObj := gomock.AssignableToTypeOf(&v1beta1.SpecialResource{})
+	ownedList := gomock.AssignableToTypeOf(&unstructured.UnstructuredList{})
+	nodeList := gomock.AssignableToTypeOf(&v1.NodeList{})
+
+	expectDeletion := func() {
+		mockClient.EXPECT().
+			List(gomock.Any(), srList).
+			Do(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) {
+				list.(*v1beta1.SpecialResourceList).Items = []v1beta1.SpecialResource{
+					{ObjectMeta: metav1.ObjectMeta{Name: name}},
+				}
+			})
+		mockClient.EXPECT().Delete(gomock.Any(), srObj)
+		mockClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: name}, srObj)
+	}
+
+	It("should delete and finalize the SpecialResources and report the node labels left", func() {
+		expectDeletion()
+		mockFinalizer.EXPECT().Finalize(gomock.Any(), srObj)
+		mockClient.EXPECT().List(gomock.Any(), ownedList, gomock.Any()).AnyTimes()
+		mockClient.EXPECT().
+			List(gomock.Any(), nodeList).
+			Do(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) {
+				list.(*v1.NodeList).Items = []v1.Node{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "node1",
+							Labels: map[string]string{
+								"specialresource.openshift.io/unload-pending": "true",
+								"kubernetes.io/hostname":                      "node1",
+							},
+						},
+					},
+				}
+			})
+
+		report, err := cleanup.New(mockClient, mockFinalizer, time.Minute).Run(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(report.Objects).To(BeEmpty())
+		Expect(report.NodeLabels).To(Equal([]string{"node1: specialresource.openshift.io/unload-pending"}))
+		Expect(report.Empty()).To(BeFalse())
+	})
+
+	It("should report the owned objects that did not drain", func() {
+		expectDeletion()
+		mockFinalizer.EXPECT().Finalize(gomock.Any(), srObj)
+		mockClient.EXPECT().
+			List(gomock.Any(), ownedList, gomock.Any()).
+			Do(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) {
+				obj := unstructured.Unstructured{}
+				obj.SetNamespace(name)
+				obj.SetName("leftover")
+				list.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{obj}
+			}).
+			AnyTimes()
+		mockClient.EXPECT().List(gomock.Any(), nodeList)
+
+		report, err := cleanup.New(mockClient, mockFinalizer, time.Millisecond).Run(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(report.Objects).To(ContainElement("Pod simple-kmod/leftover"))
+		Expect(report.NodeLabels).To(BeEmpty())
+	})
+
+	It("should fail if a SpecialResource cannot be finalized", func() {
+		expectDeletion()
+		mockFinalizer.EXPECT().Finalize(gomock.Any(), srObj).Return(errors.New("random error"))
+
+		_, err := cleanup.New(mockClient, mockFinalizer, time.Minute).Run(context.Background())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -8,10 +8,13 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
 	"github.com/openshift-psap/special-resource-operator/pkg/poll"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,6 +23,8 @@ import (
 
 const FinalizerString = "sro.openshift.io/finalizer"
 
+//go:generate mockgen -source=specialresource.go -package=finalizers -destination=mock_specialresource_api.go
+
 type SpecialResourceFinalizer interface {
 	AddToSpecialResource(ctx context.Context, sr *v1beta1.SpecialResource) error
 	Finalize(ctx context.Context, sr *v1beta1.SpecialResource) error
@@ -29,16 +34,24 @@ type specialResourceFinalizer struct {
 	kubeClient  clients.ClientsInterface
 	log         logr.Logger
 	pollActions poll.PollActions
+	ownership   ownership.Ownership
+	helmer      helmer.Helmer
 }
 
+// NewSpecialResourceFinalizer returns a finalizer that runs the pre-delete hooks
+// of the chart through helmer, if it is not nil, before cleaning up.
 func NewSpecialResourceFinalizer(
 	kubeClient clients.ClientsInterface,
 	pollActions poll.PollActions,
+	ownership ownership.Ownership,
+	helmer helmer.Helmer,
 ) SpecialResourceFinalizer {
 	return &specialResourceFinalizer{
 		kubeClient:  kubeClient,
 		log:         ctrl.Log.WithName("finalizers"),
 		pollActions: pollActions,
+		ownership:   ownership,
+		helmer:      helmer,
 	}
 }
 
@@ -109,6 +122,14 @@ func (srf *specialResourceFinalizer) finalizeSpecialResource(ctx context.Context
 	// of finalizers include performing backups and deleting
 	// resources that are not owned by this CR, like a PVC.
 
+	// The pre-delete hooks run first, e.g. Jobs unloading the kernel modules
+	// need the DriverContainers that are still around.
+	if srf.helmer != nil {
+		if err := srf.helmer.RunDeleteHooks(ctx, releaseName(sr), sr, sr.Name, sr.Spec.Namespace); err != nil {
+			return fmt.Errorf("could not run the delete hooks: %w", err)
+		}
+	}
+
 	if err := srf.finalizeNodes(ctx, sr, "specialresource.openshift.io/state-"+sr.Name); err != nil {
 		return err
 	}
@@ -130,22 +151,107 @@ func (srf *specialResourceFinalizer) finalizeSpecialResource(ctx context.Context
 		}
 	}
 
-	for _, owner := range ns.GetOwnerReferences() {
-		if owner.Kind == "SpecialResource" {
-			srf.log.Info("Namespaces is owned by SpecialResource deleting")
+	// Deleting the namespace would delete the retained objects in it as well
+	retained := ownership.Retained(&ns)
+	if !retained && srf.ownership.IsOwnedBy(&ns, sr) {
+		obj, err := srf.retainedObject(ctx, sr)
+		if err != nil {
+			srf.log.Error(err, "Failed to look for retained objects", "namespace", sr.Spec.Namespace)
+			return err
+		}
+		if obj != "" {
+			srf.log.Info("Namespace holds a retained object, keeping it", "namespace", sr.Spec.Namespace, "object", obj)
+			retained = true
+		}
+	}
 
-			if err := srf.kubeClient.Delete(ctx, &ns); err != nil {
-				srf.log.Error(err, "Failed to delete namespace", "namespace", sr.Spec.Namespace)
-				return err
-			}
+	if srf.ownership.IsOwnedBy(&ns, sr) && retained {
+		srf.log.Info("Namespace is retained, removing its owner reference", "namespace", sr.Spec.Namespace)
 
-			if err := srf.pollActions.ForResourceUnavailability(ctx, &ns); err != nil {
-				srf.log.Error(err, "Failed to delete namespace", "namespace", sr.Spec.Namespace)
-				return err
-			}
+		if err := srf.orphan(ctx, &ns, sr); err != nil {
+			srf.log.Error(err, "Failed to orphan namespace", "namespace", sr.Spec.Namespace)
+			return err
+		}
+
+		srf.log.Info("Successfully finalized", "SpecialResource:", sr.Name)
+		return nil
+	}
+
+	if srf.ownership.IsOwnedBy(&ns, sr) {
+		srf.log.Info("Namespaces is owned by SpecialResource deleting")
+
+		if err := srf.kubeClient.Delete(ctx, &ns); err != nil {
+			srf.log.Error(err, "Failed to delete namespace", "namespace", sr.Spec.Namespace)
+			return err
+		}
+
+		if err := srf.pollActions.ForResourceUnavailability(ctx, &ns); err != nil {
+			srf.log.Error(err, "Failed to delete namespace", "namespace", sr.Spec.Namespace)
+			return err
 		}
 	}
 
 	srf.log.Info("Successfully finalized", "SpecialResource:", sr.Name)
 	return nil
 }
+
+// retainedObject returns the first object applied for sr in its namespace
+// that is retained, an empty string if none. The applied objects are read
+// from the status of sr.
+func (srf *specialResourceFinalizer) retainedObject(ctx context.Context, sr *v1beta1.SpecialResource) (string, error) {
+	for _, refs := range sr.Status.Objects {
+		for _, ref := range refs {
+			if ref.Namespace != sr.Spec.Namespace {
+				continue
+			}
+
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(ref.APIVersion)
+			obj.SetKind(ref.Kind)
+
+			if err := srf.kubeClient.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return "", fmt.Errorf("could not get %s %s/%s: %w", ref.Kind, ref.Namespace, ref.Name, err)
+			}
+
+			if ownership.Retained(obj) {
+				return ref.Kind + " " + ref.Namespace + "/" + ref.Name, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// orphan removes the owner references to sr from obj, so that the garbage
+// collector does not delete it with sr.
+func (srf *specialResourceFinalizer) orphan(ctx context.Context, obj *unstructured.Unstructured, sr *v1beta1.SpecialResource) error {
+	refs := make([]metav1.OwnerReference, 0)
+
+	for _, ref := range obj.GetOwnerReferences() {
+		if (sr.GetUID() != "" && ref.UID == sr.GetUID()) || (ref.Kind == ownership.KindSpecialResource && ref.Name == sr.Name) {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+
+	if len(refs) == len(obj.GetOwnerReferences()) {
+		return nil
+	}
+
+	obj.SetOwnerReferences(refs)
+
+	return srf.kubeClient.Update(ctx, obj)
+}
+
+// releaseName returns the name of the Helm release of sr, which is the name of
+// its chart.
+func releaseName(sr *v1beta1.SpecialResource) string {
+	if sr.Spec.Manifests != nil {
+		return sr.Name
+	}
+
+	return sr.Spec.Chart.Name
+}
@@ -11,8 +11,12 @@ import (
 	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/internal/controllers/finalizers"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
 	"github.com/openshift-psap/special-resource-operator/pkg/poll"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -23,6 +27,7 @@ import (
 var (
 	mockKubeClient  *clients.MockClientsInterface
 	mockPollActions *poll.MockPollActions
+	mockHelmer      *helmer.MockHelmer
 )
 
 func TestFinalizers(t *testing.T) {
@@ -32,6 +37,7 @@ func TestFinalizers(t *testing.T) {
 		ctrl := gomock.NewController(GinkgoT())
 		mockKubeClient = clients.NewMockClientsInterface(ctrl)
 		mockPollActions = poll.NewMockPollActions(ctrl)
+		mockHelmer = helmer.NewMockHelmer(ctrl)
 	})
 
 	RunSpecs(t, "Finalizers Suite")
@@ -43,7 +49,7 @@ var _ = Describe("specialResourceFinalizer_AddToSpecialResource", func() {
 
 		mockKubeClient.EXPECT().Update(context.TODO(), sr)
 
-		err := finalizers.NewSpecialResourceFinalizer(mockKubeClient, nil).AddToSpecialResource(context.TODO(), sr)
+		err := finalizers.NewSpecialResourceFinalizer(mockKubeClient, nil, ownership.New(mockKubeClient), mockHelmer).AddToSpecialResource(context.TODO(), sr)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(controllerutil.ContainsFinalizer(sr, finalizers.FinalizerString)).To(BeTrue())
 	})
@@ -55,7 +61,7 @@ var _ = Describe("specialResourceFinalizer_AddToSpecialResource", func() {
 
 		mockKubeClient.EXPECT().Update(context.TODO(), sr).Return(randomError)
 
-		err := finalizers.NewSpecialResourceFinalizer(mockKubeClient, nil).AddToSpecialResource(context.TODO(), sr)
+		err := finalizers.NewSpecialResourceFinalizer(mockKubeClient, nil, ownership.New(mockKubeClient), mockHelmer).AddToSpecialResource(context.TODO(), sr)
 		Expect(err).To(Equal(randomError))
 	})
 })
@@ -64,7 +70,7 @@ var _ = Describe("specialResourceFinalizer_Finalize", func() {
 	It("should do nothing if the CR does not have the finalizer", func() {
 		sr := &v1beta1.SpecialResource{}
 
-		err := finalizers.NewSpecialResourceFinalizer(mockKubeClient, nil).Finalize(context.TODO(), sr)
+		err := finalizers.NewSpecialResourceFinalizer(mockKubeClient, nil, ownership.New(mockKubeClient), mockHelmer).Finalize(context.TODO(), sr)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -116,6 +122,7 @@ var _ = Describe("specialResourceFinalizer_Finalize", func() {
 			{
 				APIVersion: "v1beta1",
 				Kind:       "SpecialResource",
+				Name:       srName,
 			},
 		}
 
@@ -123,6 +130,7 @@ var _ = Describe("specialResourceFinalizer_Finalize", func() {
 		nsWithOwnerReference.SetOwnerReferences(refs)
 
 		gomock.InOrder(
+			mockHelmer.EXPECT().RunDeleteHooks(context.TODO(), "", sr, srName, srNamespace),
 			mockKubeClient.
 				EXPECT().
 				GetNodesByLabels(context.TODO(), nodeSelector).
@@ -139,9 +147,144 @@ var _ = Describe("specialResourceFinalizer_Finalize", func() {
 			mockKubeClient.EXPECT().Update(context.TODO(), srWithoutFinalizer),
 		)
 
-		f := finalizers.NewSpecialResourceFinalizer(mockKubeClient, mockPollActions)
+		f := finalizers.NewSpecialResourceFinalizer(mockKubeClient, mockPollActions, ownership.New(mockKubeClient), mockHelmer)
 
 		err := f.Finalize(context.TODO(), sr)
 		Expect(err).NotTo(HaveOccurred())
 	})
+
+	It("should orphan a retained namespace instead of deleting it", func() {
+		const (
+			srName      = "sr-name"
+			srNamespace = "sr-namespace"
+		)
+
+		sr := &v1beta1.SpecialResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       srName,
+				Finalizers: []string{finalizers.FinalizerString},
+			},
+			Spec: v1beta1.SpecialResourceSpec{Namespace: srNamespace},
+		}
+
+		other := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other"}
+
+		mockHelmer.EXPECT().RunDeleteHooks(context.TODO(), gomock.Any(), sr, srName, srNamespace)
+		mockKubeClient.EXPECT().GetNodesByLabels(context.TODO(), gomock.Any()).Return(&v1.NodeList{}, nil)
+		mockKubeClient.
+			EXPECT().
+			Get(context.TODO(), types.NamespacedName{Name: srNamespace}, gomock.Any()).
+			Do(func(_ context.Context, _ types.NamespacedName, obj client.Object) {
+				obj.SetAnnotations(map[string]string{ownership.DeletionPolicyAnnotation: ownership.DeletionPolicyRetain})
+				obj.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "v1beta1", Kind: "SpecialResource", Name: srName}, other})
+			})
+		gomock.InOrder(
+			mockKubeClient.EXPECT().Update(context.TODO(), gomock.AssignableToTypeOf(&unstructured.Unstructured{})).
+				Do(func(_ context.Context, obj client.Object) {
+					Expect(obj.GetOwnerReferences()).To(Equal([]metav1.OwnerReference{other}))
+				}),
+			mockKubeClient.EXPECT().Update(context.TODO(), gomock.AssignableToTypeOf(&v1beta1.SpecialResource{})),
+		)
+
+		f := finalizers.NewSpecialResourceFinalizer(mockKubeClient, mockPollActions, ownership.New(mockKubeClient), mockHelmer)
+
+		Expect(f.Finalize(context.TODO(), sr)).To(Succeed())
+	})
+
+	It("should keep the namespace if it holds a retained object", func() {
+		const (
+			srName      = "sr-name"
+			srNamespace = "sr-namespace"
+		)
+
+		sr := &v1beta1.SpecialResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       srName,
+				Finalizers: []string{finalizers.FinalizerString},
+			},
+			Spec: v1beta1.SpecialResourceSpec{Namespace: srNamespace},
+			Status: v1beta1.SpecialResourceStatus{
+				Objects: map[string][]v1beta1.ObjectReference{
+					"0000-ccache": {
+						{APIVersion: "v1", Kind: "ConfigMap", Namespace: srNamespace, Name: "config"},
+						{APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: "other", Name: "ccache"},
+						{APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: srNamespace, Name: "ccache"},
+					},
+				},
+			},
+		}
+
+		mockHelmer.EXPECT().RunDeleteHooks(context.TODO(), gomock.Any(), sr, srName, srNamespace)
+		mockKubeClient.EXPECT().GetNodesByLabels(context.TODO(), gomock.Any()).Return(&v1.NodeList{}, nil)
+		gomock.InOrder(
+			mockKubeClient.
+				EXPECT().
+				Get(context.TODO(), types.NamespacedName{Name: srNamespace}, gomock.Any()).
+				Do(func(_ context.Context, _ types.NamespacedName, obj client.Object) {
+					obj.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "v1beta1", Kind: "SpecialResource", Name: srName}})
+				}),
+			mockKubeClient.
+				EXPECT().
+				Get(context.TODO(), types.NamespacedName{Namespace: srNamespace, Name: "config"}, gomock.Any()),
+			mockKubeClient.
+				EXPECT().
+				Get(context.TODO(), types.NamespacedName{Namespace: srNamespace, Name: "ccache"}, gomock.Any()).
+				Do(func(_ context.Context, _ types.NamespacedName, obj client.Object) {
+					obj.SetAnnotations(map[string]string{ownership.DeletionPolicyAnnotation: ownership.DeletionPolicyRetain})
+				}),
+			mockKubeClient.EXPECT().Update(context.TODO(), gomock.AssignableToTypeOf(&unstructured.Unstructured{})).
+				Do(func(_ context.Context, obj client.Object) {
+					Expect(obj.GetOwnerReferences()).To(BeEmpty())
+				}),
+			mockKubeClient.EXPECT().Update(context.TODO(), gomock.AssignableToTypeOf(&v1beta1.SpecialResource{})),
+		)
+
+		f := finalizers.NewSpecialResourceFinalizer(mockKubeClient, mockPollActions, ownership.New(mockKubeClient), mockHelmer)
+
+		Expect(f.Finalize(context.TODO(), sr)).To(Succeed())
+	})
+
+	It("should run the delete hooks of the chart release", func() {
+		sr := &v1beta1.SpecialResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "sr-name",
+				Finalizers: []string{finalizers.FinalizerString},
+			},
+			Spec: v1beta1.SpecialResourceSpec{
+				Namespace: "sr-namespace",
+				Chart:     helmerv1beta1.HelmChart{Name: "chart-name"},
+			},
+		}
+
+		mockHelmer.EXPECT().RunDeleteHooks(context.TODO(), "chart-name", sr, "sr-name", "sr-namespace")
+		mockKubeClient.EXPECT().GetNodesByLabels(context.TODO(), gomock.Any()).Return(&v1.NodeList{}, nil)
+		mockKubeClient.
+			EXPECT().
+			Get(context.TODO(), types.NamespacedName{Name: "sr-namespace"}, gomock.Any()).
+			Return(apierrors.NewNotFound(v1.Resource("namespaces"), "sr-namespace"))
+		mockKubeClient.EXPECT().Update(context.TODO(), sr)
+
+		f := finalizers.NewSpecialResourceFinalizer(mockKubeClient, mockPollActions, ownership.New(mockKubeClient), mockHelmer)
+
+		Expect(f.Finalize(context.TODO(), sr)).To(Succeed())
+	})
+
+	It("should keep the finalizer if a delete hook fails", func() {
+		sr := &v1beta1.SpecialResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "sr-name",
+				Finalizers: []string{finalizers.FinalizerString},
+			},
+			Spec: v1beta1.SpecialResourceSpec{Namespace: "sr-namespace"},
+		}
+
+		randomError := errors.New("random error")
+
+		mockHelmer.EXPECT().RunDeleteHooks(context.TODO(), gomock.Any(), sr, "sr-name", "sr-namespace").Return(randomError)
+
+		f := finalizers.NewSpecialResourceFinalizer(mockKubeClient, mockPollActions, ownership.New(mockKubeClient), mockHelmer)
+
+		Expect(f.Finalize(context.TODO(), sr)).To(MatchError(randomError))
+		Expect(sr.GetFinalizers()).To(ContainElement(finalizers.FinalizerString))
+	})
 })
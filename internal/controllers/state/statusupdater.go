@@ -8,6 +8,8 @@ import (
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -26,6 +28,11 @@ const (
 	FailedToCreateDependencySR    = "FailedToCreateDependencySR"
 	FailedToDeployDependencyChart = "FailedToDeployDependencyChart"
 	FailedToDeployChart           = "FailedToDeployChart"
+	UnsupportedKernel             = "UnsupportedKernel"
+	Forbidden                     = "Forbidden"
+	PreApplyPolicyFailure         = "PreApplyPolicyFailure"
+	MachineConfigRollout          = "MachineConfigRollout"
+	PrerequisitesNotMet           = "PrerequisitesNotMet"
 )
 
 //go:generate mockgen -source=statusupdater.go -package=state -destination=mock_statusupdater_api.go
@@ -46,6 +53,28 @@ func NewStatusUpdater(kubeClient clients.ClientsInterface) StatusUpdater {
 	}
 }
 
+// patchStatus merge-patches the status of the SpecialResource in the API with
+// the one of sr. The patch is computed against the cached object and carries
+// no resourceVersion, so concurrent writes do not conflict.
+func (su *statusUpdater) patchStatus(ctx context.Context, sr *v1beta1.SpecialResource) error {
+	current := &v1beta1.SpecialResource{}
+	if err := su.kubeClient.Get(ctx, types.NamespacedName{Name: sr.Name, Namespace: sr.Namespace}, current); err != nil {
+		return fmt.Errorf("could not get SpecialResource %s: %w", sr.Name, err)
+	}
+
+	patch := client.MergeFrom(current.DeepCopy())
+	current.Status = *sr.Status.DeepCopy()
+
+	if err := su.kubeClient.StatusPatch(ctx, current, patch); err != nil {
+		return err
+	}
+
+	// Callers may update the object afterwards
+	sr.ResourceVersion = current.ResourceVersion
+
+	return nil
+}
+
 // SetAsProgressing changes SpecialResource's Progressing condition as true and changes Ready and Errored conditions to false, and updates the status in the API.
 func (su *statusUpdater) SetAsProgressing(ctx context.Context, sr *v1beta1.SpecialResource, reason, message string) error {
 	meta.SetStatusCondition(&sr.Status.Conditions, metav1.Condition{Type: v1beta1.SpecialResourceProgressing, Status: metav1.ConditionTrue, Reason: reason, Message: message})
@@ -54,7 +83,7 @@ func (su *statusUpdater) SetAsProgressing(ctx context.Context, sr *v1beta1.Speci
 
 	sr.Status.State = fmt.Sprintf("Progressing: %s", message)
 
-	return su.kubeClient.StatusUpdate(ctx, sr)
+	return su.patchStatus(ctx, sr)
 }
 
 // SetAsReady changes SpecialResource's Ready condition as true and changes Progressing and Errored conditions to false, and updates the status in the API.
@@ -65,7 +94,7 @@ func (su *statusUpdater) SetAsReady(ctx context.Context, sr *v1beta1.SpecialReso
 
 	sr.Status.State = fmt.Sprintf("Ready: %s", message)
 
-	return su.kubeClient.StatusUpdate(ctx, sr)
+	return su.patchStatus(ctx, sr)
 }
 
 // SetAsErrored changes SpecialResource's Errored condition as true and changes Ready and Progressing conditions to false, and updates the status in the API.
@@ -76,5 +105,5 @@ func (su *statusUpdater) SetAsErrored(ctx context.Context, sr *v1beta1.SpecialRe
 
 	sr.Status.State = fmt.Sprintf("Errored: %s", message)
 
-	return su.kubeClient.StatusUpdate(ctx, sr)
+	return su.patchStatus(ctx, sr)
 }
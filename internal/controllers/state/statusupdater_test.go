@@ -11,6 +11,8 @@ import (
 	"github.com/openshift-psap/special-resource-operator/internal/controllers/state"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type legacyStatusMatcher struct {
@@ -74,11 +76,19 @@ var _ = Describe("SetAs{Ready,Progressing,Errored}", func() {
 		func(expectedType string, call func(state.StatusUpdater) error) {
 			gomock.InOrder(
 				kubeClient.EXPECT().
-					StatusUpdate(context.TODO(), gomock.All(conditionExclusivityMatcher{expectedType}, legacyStatusMatcher{expectedType})).
-					Return(nil),
+					Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, gomock.AssignableToTypeOf(&v1beta1.SpecialResource{})).
+					Do(func(_ context.Context, _ types.NamespacedName, obj client.Object) {
+						obj.SetResourceVersion("1")
+					}),
+				kubeClient.EXPECT().
+					StatusPatch(context.Background(), gomock.All(conditionExclusivityMatcher{expectedType}, legacyStatusMatcher{expectedType}), gomock.Any()).
+					Do(func(_ context.Context, obj client.Object, _ client.Patch) {
+						obj.SetResourceVersion("2")
+					}),
 			)
 
 			Expect(call(state.NewStatusUpdater(kubeClient))).To(Succeed())
+			Expect(sr.ResourceVersion).To(Equal("2"))
 
 			// Make sure Conditions are set for object that was passed in and visible outside
 			Expect(sr.Status.Conditions).To(HaveLen(3))
@@ -4,7 +4,12 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 var (
@@ -42,9 +47,40 @@ var (
 		"ValidatingWebhookConfiguration": true,
 		"Deployment":                     true,
 		"ImagePolicy":                    true,
+		"MachineConfig":                  true,
 	}
 )
 
+// BuildAnnotation marks the Jobs of a chart that build the driver, the build
+// overrides only apply to those and to BuildConfigs.
+const BuildAnnotation = "specialresource.openshift.io/build"
+
+const (
+	ccacheVolumeName = "ccache"
+	ccacheMountPath  = "/var/cache/ccache"
+)
+
+const (
+	// EntitlementSecretName is the name of the cluster entitlement Secret and of its copy in the build namespace.
+	EntitlementSecretName = "etc-pki-entitlement"
+	entitlementMountPath  = "/etc/pki/entitlement"
+)
+
+var ccacheDefaultSize = resource.MustParse("5Gi")
+
+// CcacheClaimName returns the name of the ccache PersistentVolumeClaim of a SpecialResource.
+func CcacheClaimName(name string) string {
+	return name + "-ccache"
+}
+
+// CcacheSize returns the size of the ccache volume, defaulted if not set.
+func CcacheSize(ccache v1beta1.SpecialResourceCcache) resource.Quantity {
+	if ccache.Size == nil {
+		return ccacheDefaultSize.DeepCopy()
+	}
+	return ccache.Size.DeepCopy()
+}
+
 //go:generate mockgen -source=helper.go -package=resourcehelper -destination=mock_helper_api.go
 
 type Helper interface {
@@ -56,6 +92,10 @@ type Helper interface {
 	IsOneTimer(obj *unstructured.Unstructured) (bool, error)
 	SetLabel(obj *unstructured.Unstructured, label string) error
 	SetMetaData(obj *unstructured.Unstructured, nm string, ns string)
+	SetPodOverrides(obj *unstructured.Unstructured, overrides v1beta1.SpecialResourcePodOverrides) error
+	SetBuildOverrides(obj *unstructured.Unstructured, overrides v1beta1.SpecialResourceBuildOverrides) error
+	SetCcacheVolume(obj *unstructured.Unstructured, claimName string, size resource.Quantity) error
+	SetEntitlementVolume(obj *unstructured.Unstructured) error
 }
 
 func New() Helper {
@@ -109,16 +149,13 @@ func (rh *resourceHelper) UpdateResourceVersion(req *unstructured.Unstructured,
 }
 
 func (rh *resourceHelper) SetNodeSelectorTerms(obj *unstructured.Unstructured, terms map[string]string) error {
-	switch obj.GetKind() {
-	case "DaemonSet", "Deployment", "Statefulset": // TODO(qbarrand) should this be StatefulSet?:
-		if err := rh.nodeSelectorTerms(terms, obj, "spec", "template", "spec", "nodeSelector"); err != nil {
-			return fmt.Errorf("cannot setup %s nodeSelector: %w", obj.GetKind(), err)
-		}
+	fields, ok := utils.NodeSelectorFields[obj.GetKind()]
+	if !ok {
+		return nil
+	}
 
-	case "Pod", "BuildConfig":
-		if err := rh.nodeSelectorTerms(terms, obj, "spec", "nodeSelector"); err != nil {
-			return fmt.Errorf("cannot setup %s nodeSelector: %w", obj.GetKind(), err)
-		}
+	if err := rh.nodeSelectorTerms(terms, obj, fields...); err != nil {
+		return fmt.Errorf("cannot setup %s nodeSelector: %w", obj.GetKind(), err)
 	}
 
 	return nil
@@ -126,6 +163,11 @@ func (rh *resourceHelper) SetNodeSelectorTerms(obj *unstructured.Unstructured, t
 
 func (rh *resourceHelper) nodeSelectorTerms(terms map[string]string, obj *unstructured.Unstructured, fields ...string) error {
 
+	// A null nodeSelector, e.g. the one of a Build, is an empty one
+	if v, found, _ := unstructured.NestedFieldNoCopy(obj.Object, fields...); found && v == nil {
+		unstructured.RemoveNestedField(obj.Object, fields...)
+	}
+
 	nodeSelector, found, err := unstructured.NestedMap(obj.Object, fields...)
 	if err != nil {
 		return err
@@ -245,3 +287,291 @@ func (rh *resourceHelper) SetMetaData(obj *unstructured.Unstructured, nm string,
 
 	obj.SetLabels(labels)
 }
+
+// SetPodOverrides applies the SpecialResource's pod overrides to the pod template of DaemonSets and Deployments.
+func (rh *resourceHelper) SetPodOverrides(obj *unstructured.Unstructured, overrides v1beta1.SpecialResourcePodOverrides) error {
+	switch obj.GetKind() {
+	case "DaemonSet", "Deployment":
+	default:
+		return nil
+	}
+
+	if len(overrides.Labels) > 0 {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+
+		for k, v := range overrides.Labels {
+			labels[k] = v
+		}
+		obj.SetLabels(labels)
+
+		for k, v := range overrides.Labels {
+			if err := unstructured.SetNestedField(obj.Object, v, "spec", "template", "metadata", "labels", k); err != nil {
+				return fmt.Errorf("cannot set pod template label %s: %w", k, err)
+			}
+		}
+	}
+
+	if overrides.PriorityClassName != "" {
+		if err := unstructured.SetNestedField(obj.Object, overrides.PriorityClassName, "spec", "template", "spec", "priorityClassName"); err != nil {
+			return fmt.Errorf("cannot set priorityClassName: %w", err)
+		}
+	}
+
+	if err := rh.appendTolerations(obj, overrides.Tolerations, "spec", "template", "spec", "tolerations"); err != nil {
+		return err
+	}
+
+	if err := rh.setContainersResources(obj, overrides.Resources); err != nil {
+		return fmt.Errorf("cannot set container resources: %w", err)
+	}
+
+	return nil
+}
+
+// SetBuildOverrides applies the SpecialResource's build overrides to BuildConfigs and to the Jobs annotated with
+// BuildAnnotation. The nodeSelector of the overrides replaces the one of the object, so that builds are not
+// restricted to the nodes running the driver.
+func (rh *resourceHelper) SetBuildOverrides(obj *unstructured.Unstructured, overrides v1beta1.SpecialResourceBuildOverrides) error {
+	if obj.GetKind() == "Job" && obj.GetAnnotations()[BuildAnnotation] != "true" {
+		return nil
+	}
+
+	switch obj.GetKind() {
+	case "BuildConfig":
+		if len(overrides.NodeSelector) > 0 {
+			if err := unstructured.SetNestedStringMap(obj.Object, overrides.NodeSelector, "spec", "nodeSelector"); err != nil {
+				return fmt.Errorf("cannot set BuildConfig nodeSelector: %w", err)
+			}
+		}
+
+		// BuildConfigs have no tolerations, those can only be set cluster-wide
+		// through the build.config.openshift.io defaults.
+		if err := rh.mergeResources(obj.Object, overrides.Resources, "spec", "resources"); err != nil {
+			return fmt.Errorf("cannot set BuildConfig resources: %w", err)
+		}
+
+	case "Job":
+		if len(overrides.NodeSelector) > 0 {
+			if err := unstructured.SetNestedStringMap(obj.Object, overrides.NodeSelector, "spec", "template", "spec", "nodeSelector"); err != nil {
+				return fmt.Errorf("cannot set Job nodeSelector: %w", err)
+			}
+		}
+
+		if err := rh.appendTolerations(obj, overrides.Tolerations, "spec", "template", "spec", "tolerations"); err != nil {
+			return err
+		}
+
+		if err := rh.setContainersResources(obj, overrides.Resources); err != nil {
+			return fmt.Errorf("cannot set container resources: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SetCcacheVolume mounts the ccache PersistentVolumeClaim into every container of a Job annotated with
+// BuildAnnotation and points ccache to it. BuildConfigs cannot mount PersistentVolumeClaims and are left untouched.
+func (rh *resourceHelper) SetCcacheVolume(obj *unstructured.Unstructured, claimName string, size resource.Quantity) error {
+
+	if obj.GetKind() != "Job" || obj.GetAnnotations()[BuildAnnotation] != "true" {
+		return nil
+	}
+
+	volumes, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "volumes")
+	if err != nil {
+		return err
+	}
+
+	volumes = append(volumes, map[string]interface{}{
+		"name": ccacheVolumeName,
+		"persistentVolumeClaim": map[string]interface{}{
+			"claimName": claimName,
+		},
+	})
+
+	if err = unstructured.SetNestedSlice(obj.Object, volumes, "spec", "template", "spec", "volumes"); err != nil {
+		return fmt.Errorf("cannot set volumes: %w", err)
+	}
+
+	containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return errors.New("containers not found")
+	}
+
+	// Leave some headroom on the volume, ccache only evicts after a write
+	maxSize := fmt.Sprintf("%dKi", size.Value()*9/10/1024)
+
+	for _, container := range containers {
+		c, ok := container.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		mounts, _, err := unstructured.NestedSlice(c, "volumeMounts")
+		if err != nil {
+			return err
+		}
+
+		mounts = append(mounts, map[string]interface{}{
+			"name":      ccacheVolumeName,
+			"mountPath": ccacheMountPath,
+		})
+
+		if err = unstructured.SetNestedSlice(c, mounts, "volumeMounts"); err != nil {
+			return err
+		}
+
+		env, _, err := unstructured.NestedSlice(c, "env")
+		if err != nil {
+			return err
+		}
+
+		env = append(env,
+			map[string]interface{}{"name": "CCACHE_DIR", "value": ccacheMountPath},
+			map[string]interface{}{"name": "CCACHE_MAXSIZE", "value": maxSize},
+		)
+
+		if err = unstructured.SetNestedSlice(c, env, "env"); err != nil {
+			return err
+		}
+	}
+
+	return unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+}
+
+// SetEntitlementVolume mounts the entitlement Secret into the build of a BuildConfig, so that the
+// Dockerfile can install packages from the RHEL subscription.
+func (rh *resourceHelper) SetEntitlementVolume(obj *unstructured.Unstructured) error {
+
+	if obj.GetKind() != "BuildConfig" {
+		return nil
+	}
+
+	strategy := ""
+	for _, s := range []string{"dockerStrategy", "sourceStrategy"} {
+		if _, found, _ := unstructured.NestedMap(obj.Object, "spec", "strategy", s); found {
+			strategy = s
+			break
+		}
+	}
+
+	if strategy == "" {
+		return errors.New("entitlement can only be mounted for Docker and Source builds")
+	}
+
+	volumes, _, err := unstructured.NestedSlice(obj.Object, "spec", "strategy", strategy, "volumes")
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		if v, ok := volume.(map[string]interface{}); ok && v["name"] == EntitlementSecretName {
+			return nil
+		}
+	}
+
+	volumes = append(volumes, map[string]interface{}{
+		"name": EntitlementSecretName,
+		"mounts": []interface{}{
+			map[string]interface{}{"destinationPath": entitlementMountPath},
+		},
+		"source": map[string]interface{}{
+			"type": "Secret",
+			"secret": map[string]interface{}{
+				"secretName": EntitlementSecretName,
+			},
+		},
+	})
+
+	if err = unstructured.SetNestedSlice(obj.Object, volumes, "spec", "strategy", strategy, "volumes"); err != nil {
+		return fmt.Errorf("cannot set build volumes: %w", err)
+	}
+
+	return nil
+}
+
+func (rh *resourceHelper) appendTolerations(obj *unstructured.Unstructured, required []corev1.Toleration, fields ...string) error {
+
+	if len(required) == 0 {
+		return nil
+	}
+
+	tolerations, _, err := unstructured.NestedSlice(obj.Object, fields...)
+	if err != nil {
+		return err
+	}
+
+	for i := range required {
+		t, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&required[i])
+		if err != nil {
+			return fmt.Errorf("cannot convert toleration: %w", err)
+		}
+		tolerations = append(tolerations, t)
+	}
+
+	if err = unstructured.SetNestedSlice(obj.Object, tolerations, fields...); err != nil {
+		return fmt.Errorf("cannot set tolerations: %w", err)
+	}
+
+	return nil
+}
+
+func (rh *resourceHelper) setContainersResources(obj *unstructured.Unstructured, resources corev1.ResourceRequirements) error {
+
+	if len(resources.Requests) == 0 && len(resources.Limits) == 0 {
+		return nil
+	}
+
+	containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return errors.New("containers not found")
+	}
+
+	for _, container := range containers {
+		c, ok := container.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if err = rh.mergeResources(c, resources, "resources"); err != nil {
+			return err
+		}
+	}
+
+	return unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+}
+
+// mergeResources sets the requests and limits of resources at fields in obj, keeping the other ones.
+func (rh *resourceHelper) mergeResources(obj map[string]interface{}, resources corev1.ResourceRequirements, fields ...string) error {
+
+	required, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&resources)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range []string{"requests", "limits"} {
+		values, ok := required[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for name, value := range values {
+			if err = unstructured.SetNestedField(obj, value, append(fields, field, name)...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
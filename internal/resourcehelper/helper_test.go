@@ -6,11 +6,14 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/internal/resourcehelper"
 
 	buildv1 "github.com/openshift/api/build/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -217,8 +220,7 @@ var _ = Describe("SetNodeSelectorTerms", func() {
 		Expect(d.Spec.Template.Spec.NodeSelector).To(Equal(terms))
 	})
 
-	// TODO(qbarrand) this bugs because the code checks if the kind is Statefulset (no capital S)
-	PIt("should work for a StatefulSet", func() {
+	It("should work for a StatefulSet", func() {
 		statefulSet := appsv1.StatefulSet{
 			TypeMeta: metav1.TypeMeta{Kind: "StatefulSet"},
 		}
@@ -283,6 +285,83 @@ var _ = Describe("SetNodeSelectorTerms", func() {
 
 		Expect(d.Spec.NodeSelector).To(Equal(buildv1.OptionalNodeSelector(terms)))
 	})
+
+	It("should be idempotent", func() {
+		uo := unstructured.Unstructured{Object: map[string]interface{}{"kind": "DaemonSet"}}
+		Expect(unstructured.SetNestedStringMap(uo.Object, map[string]string{"chart": "value"}, "spec", "template", "spec", "nodeSelector")).To(Succeed())
+
+		terms := map[string]string{"key": "value"}
+
+		Expect(rh.SetNodeSelectorTerms(&uo, terms)).To(Succeed())
+		once := uo.DeepCopy()
+
+		Expect(rh.SetNodeSelectorTerms(&uo, terms)).To(Succeed())
+		Expect(uo.Object).To(Equal(once.Object))
+
+		nodeSelector, _, err := unstructured.NestedStringMap(uo.Object, "spec", "template", "spec", "nodeSelector")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodeSelector).To(Equal(map[string]string{"chart": "value", "key": "value"}))
+	})
+
+	It("should work for a Build", func() {
+		b := buildv1.Build{
+			TypeMeta: metav1.TypeMeta{Kind: "Build"},
+		}
+
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&b)
+		Expect(err).NotTo(HaveOccurred())
+
+		terms := map[string]string{"key": "value"}
+		uo := unstructured.Unstructured{Object: m}
+
+		err = rh.SetNodeSelectorTerms(&uo, terms)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(uo.Object, &b)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(b.Spec.NodeSelector).To(Equal(buildv1.OptionalNodeSelector(terms)))
+	})
+
+	It("should work for a Job", func() {
+		j := batchv1.Job{
+			TypeMeta: metav1.TypeMeta{Kind: "Job"},
+		}
+
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&j)
+		Expect(err).NotTo(HaveOccurred())
+
+		terms := map[string]string{"key": "value"}
+		uo := unstructured.Unstructured{Object: m}
+
+		err = rh.SetNodeSelectorTerms(&uo, terms)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(uo.Object, &j)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(j.Spec.Template.Spec.NodeSelector).To(Equal(terms))
+	})
+
+	It("should work for a CronJob", func() {
+		cj := batchv1.CronJob{
+			TypeMeta: metav1.TypeMeta{Kind: "CronJob"},
+		}
+
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&cj)
+		Expect(err).NotTo(HaveOccurred())
+
+		terms := map[string]string{"key": "value"}
+		uo := unstructured.Unstructured{Object: m}
+
+		err = rh.SetNodeSelectorTerms(&uo, terms)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(uo.Object, &cj)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cj.Spec.JobTemplate.Spec.Template.Spec.NodeSelector).To(Equal(terms))
+	})
 })
 
 var _ = Describe("TestIsOneTimer", func() {
@@ -421,3 +500,280 @@ var _ = Describe("SetLabel", func() {
 		Expect(uo.GetLabels()).To(HaveKeyWithValue(ownedLabel, "true"))
 	})
 })
+
+var _ = Describe("SetPodOverrides", func() {
+	rh := resourcehelper.New()
+
+	overrides := v1beta1.SpecialResourcePodOverrides{
+		Tolerations: []v1.Toleration{
+			{Key: "nvidia.com/gpu", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+		},
+		PriorityClassName: "system-node-critical",
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+		Labels: map[string]string{"team": "accelerators"},
+	}
+
+	It("should not modify a Pod", func() {
+		uo := unstructured.Unstructured{Object: make(map[string]interface{})}
+		uo.SetKind("Pod")
+
+		err := rh.SetPodOverrides(&uo, overrides)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(uo.Object).To(HaveLen(1))
+	})
+
+	It("should apply all overrides to a DaemonSet", func() {
+		ds := appsv1.DaemonSet{
+			TypeMeta: metav1.TypeMeta{Kind: "DaemonSet"},
+			Spec: appsv1.DaemonSetSpec{
+				Template: v1.PodTemplateSpec{
+					Spec: v1.PodSpec{
+						Tolerations: []v1.Toleration{{Key: "existing", Operator: v1.TolerationOpExists}},
+						Containers: []v1.Container{
+							{
+								Name: "driver",
+								Resources: v1.ResourceRequirements{
+									Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		mo, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&ds)
+		Expect(err).NotTo(HaveOccurred())
+
+		uo := unstructured.Unstructured{Object: mo}
+
+		err = rh.SetPodOverrides(&uo, overrides)
+		Expect(err).NotTo(HaveOccurred())
+
+		res := appsv1.DaemonSet{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(uo.Object, &res)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(res.GetLabels()).To(HaveKeyWithValue("team", "accelerators"))
+		Expect(res.Spec.Template.GetLabels()).To(HaveKeyWithValue("team", "accelerators"))
+		Expect(res.Spec.Template.Spec.PriorityClassName).To(Equal("system-node-critical"))
+		Expect(res.Spec.Template.Spec.Tolerations).To(HaveLen(2))
+		Expect(res.Spec.Template.Spec.Tolerations[1].Key).To(Equal("nvidia.com/gpu"))
+
+		resources := res.Spec.Template.Spec.Containers[0].Resources
+		Expect(resources.Requests.Memory().String()).To(Equal("1Gi"))
+		Expect(resources.Limits.Cpu().String()).To(Equal("2"))
+	})
+})
+
+var _ = Describe("SetBuildOverrides", func() {
+	rh := resourcehelper.New()
+
+	overrides := v1beta1.SpecialResourceBuildOverrides{
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("8")},
+		},
+		NodeSelector: map[string]string{"node-role.kubernetes.io/build": ""},
+		Tolerations: []v1.Toleration{
+			{Key: "build", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+		},
+	}
+
+	It("should not modify a DaemonSet", func() {
+		uo := unstructured.Unstructured{Object: make(map[string]interface{})}
+		uo.SetKind("DaemonSet")
+
+		err := rh.SetBuildOverrides(&uo, overrides)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(uo.Object).To(HaveLen(1))
+	})
+
+	It("should replace the nodeSelector and set the resources of a BuildConfig", func() {
+		bc := buildv1.BuildConfig{
+			TypeMeta: metav1.TypeMeta{Kind: "BuildConfig"},
+			Spec: buildv1.BuildConfigSpec{
+				CommonSpec: buildv1.CommonSpec{
+					NodeSelector: buildv1.OptionalNodeSelector{"feature.node.kubernetes.io/pci-10de.present": "true"},
+					Resources: v1.ResourceRequirements{
+						Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+					},
+				},
+			},
+		}
+
+		mo, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&bc)
+		Expect(err).NotTo(HaveOccurred())
+
+		uo := unstructured.Unstructured{Object: mo}
+
+		err = rh.SetBuildOverrides(&uo, overrides)
+		Expect(err).NotTo(HaveOccurred())
+
+		res := buildv1.BuildConfig{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(uo.Object, &res)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(res.Spec.NodeSelector).To(Equal(buildv1.OptionalNodeSelector{"node-role.kubernetes.io/build": ""}))
+		Expect(res.Spec.Resources.Requests.Cpu().String()).To(Equal("8"))
+		Expect(res.Spec.Resources.Limits.Memory().String()).To(Equal("4Gi"))
+	})
+
+	It("should not modify a Job that does not build the driver", func() {
+		uo := unstructured.Unstructured{Object: make(map[string]interface{})}
+		uo.SetKind("Job")
+
+		err := rh.SetBuildOverrides(&uo, overrides)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(uo.Object).To(HaveLen(1))
+	})
+
+	It("should apply all overrides to a build Job", func() {
+		job := batchv1.Job{
+			TypeMeta: metav1.TypeMeta{Kind: "Job"},
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{resourcehelper.BuildAnnotation: "true"},
+			},
+			Spec: batchv1.JobSpec{
+				Template: v1.PodTemplateSpec{
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{{Name: "build"}},
+					},
+				},
+			},
+		}
+
+		mo, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&job)
+		Expect(err).NotTo(HaveOccurred())
+
+		uo := unstructured.Unstructured{Object: mo}
+
+		err = rh.SetBuildOverrides(&uo, overrides)
+		Expect(err).NotTo(HaveOccurred())
+
+		res := batchv1.Job{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(uo.Object, &res)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(res.Spec.Template.Spec.NodeSelector).To(HaveKey("node-role.kubernetes.io/build"))
+		Expect(res.Spec.Template.Spec.Tolerations).To(HaveLen(1))
+		Expect(res.Spec.Template.Spec.Containers[0].Resources.Requests.Cpu().String()).To(Equal("8"))
+	})
+})
+
+var _ = Describe("SetCcacheVolume", func() {
+	rh := resourcehelper.New()
+
+	It("should not modify a BuildConfig", func() {
+		uo := unstructured.Unstructured{Object: make(map[string]interface{})}
+		uo.SetKind("BuildConfig")
+
+		err := rh.SetCcacheVolume(&uo, "simple-kmod-ccache", resource.MustParse("10Gi"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(uo.Object).To(HaveLen(1))
+	})
+
+	It("should not modify a Job that does not build the driver", func() {
+		uo := unstructured.Unstructured{Object: make(map[string]interface{})}
+		uo.SetKind("Job")
+
+		err := rh.SetCcacheVolume(&uo, "simple-kmod-ccache", resource.MustParse("10Gi"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(uo.Object).To(HaveLen(1))
+	})
+
+	It("should mount the claim into every container of a build Job", func() {
+		job := batchv1.Job{
+			TypeMeta: metav1.TypeMeta{Kind: "Job"},
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{resourcehelper.BuildAnnotation: "true"},
+			},
+			Spec: batchv1.JobSpec{
+				Template: v1.PodTemplateSpec{
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{
+							{Name: "build", Env: []v1.EnvVar{{Name: "KVER", Value: "4.18.0"}}},
+						},
+					},
+				},
+			},
+		}
+
+		mo, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&job)
+		Expect(err).NotTo(HaveOccurred())
+
+		uo := unstructured.Unstructured{Object: mo}
+
+		err = rh.SetCcacheVolume(&uo, "simple-kmod-ccache", resource.MustParse("10Gi"))
+		Expect(err).NotTo(HaveOccurred())
+
+		res := batchv1.Job{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(uo.Object, &res)
+		Expect(err).NotTo(HaveOccurred())
+
+		volumes := res.Spec.Template.Spec.Volumes
+		Expect(volumes).To(HaveLen(1))
+		Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("simple-kmod-ccache"))
+
+		container := res.Spec.Template.Spec.Containers[0]
+		Expect(container.VolumeMounts).To(ConsistOf(v1.VolumeMount{Name: "ccache", MountPath: "/var/cache/ccache"}))
+		Expect(container.Env).To(ConsistOf(
+			v1.EnvVar{Name: "KVER", Value: "4.18.0"},
+			v1.EnvVar{Name: "CCACHE_DIR", Value: "/var/cache/ccache"},
+			v1.EnvVar{Name: "CCACHE_MAXSIZE", Value: "9437184Ki"},
+		))
+	})
+})
+
+var _ = Describe("SetEntitlementVolume", func() {
+	rh := resourcehelper.New()
+
+	It("should not modify a Job", func() {
+		uo := unstructured.Unstructured{Object: make(map[string]interface{})}
+		uo.SetKind("Job")
+
+		err := rh.SetEntitlementVolume(&uo)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(uo.Object).To(HaveLen(1))
+	})
+
+	It("should fail for a BuildConfig without Docker or Source strategy", func() {
+		uo := unstructured.Unstructured{Object: make(map[string]interface{})}
+		uo.SetKind("BuildConfig")
+
+		err := rh.SetEntitlementVolume(&uo)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should mount the entitlement into a Docker build once", func() {
+		bc := buildv1.BuildConfig{
+			TypeMeta: metav1.TypeMeta{Kind: "BuildConfig"},
+			Spec: buildv1.BuildConfigSpec{
+				CommonSpec: buildv1.CommonSpec{
+					Strategy: buildv1.BuildStrategy{
+						Type:           buildv1.DockerBuildStrategyType,
+						DockerStrategy: &buildv1.DockerBuildStrategy{},
+					},
+				},
+			},
+		}
+
+		mo, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&bc)
+		Expect(err).NotTo(HaveOccurred())
+
+		uo := unstructured.Unstructured{Object: mo}
+
+		Expect(rh.SetEntitlementVolume(&uo)).To(Succeed())
+		Expect(rh.SetEntitlementVolume(&uo)).To(Succeed())
+
+		res := buildv1.BuildConfig{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(uo.Object, &res)
+		Expect(err).NotTo(HaveOccurred())
+
+		volumes := res.Spec.Strategy.DockerStrategy.Volumes
+		Expect(volumes).To(HaveLen(1))
+		Expect(volumes[0].Source.Secret.SecretName).To(Equal(resourcehelper.EntitlementSecretName))
+		Expect(volumes[0].Mounts).To(ConsistOf(buildv1.BuildVolumeMount{DestinationPath: "/etc/pki/entitlement"}))
+	})
+})
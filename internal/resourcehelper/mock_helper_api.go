@@ -8,6 +8,8 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	v1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
 	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -91,6 +93,48 @@ func (mr *MockHelperMockRecorder) NeedsResourceVersionUpdate(kind interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsResourceVersionUpdate", reflect.TypeOf((*MockHelper)(nil).NeedsResourceVersionUpdate), kind)
 }
 
+// SetBuildOverrides mocks base method.
+func (m *MockHelper) SetBuildOverrides(obj *unstructured.Unstructured, overrides v1beta1.SpecialResourceBuildOverrides) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBuildOverrides", obj, overrides)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBuildOverrides indicates an expected call of SetBuildOverrides.
+func (mr *MockHelperMockRecorder) SetBuildOverrides(obj, overrides interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBuildOverrides", reflect.TypeOf((*MockHelper)(nil).SetBuildOverrides), obj, overrides)
+}
+
+// SetCcacheVolume mocks base method.
+func (m *MockHelper) SetCcacheVolume(obj *unstructured.Unstructured, claimName string, size resource.Quantity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCcacheVolume", obj, claimName, size)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCcacheVolume indicates an expected call of SetCcacheVolume.
+func (mr *MockHelperMockRecorder) SetCcacheVolume(obj, claimName, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCcacheVolume", reflect.TypeOf((*MockHelper)(nil).SetCcacheVolume), obj, claimName, size)
+}
+
+// SetEntitlementVolume mocks base method.
+func (m *MockHelper) SetEntitlementVolume(obj *unstructured.Unstructured) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEntitlementVolume", obj)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEntitlementVolume indicates an expected call of SetEntitlementVolume.
+func (mr *MockHelperMockRecorder) SetEntitlementVolume(obj interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntitlementVolume", reflect.TypeOf((*MockHelper)(nil).SetEntitlementVolume), obj)
+}
+
 // SetLabel mocks base method.
 func (m *MockHelper) SetLabel(obj *unstructured.Unstructured, label string) error {
 	m.ctrl.T.Helper()
@@ -131,6 +175,20 @@ func (mr *MockHelperMockRecorder) SetNodeSelectorTerms(obj, terms interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNodeSelectorTerms", reflect.TypeOf((*MockHelper)(nil).SetNodeSelectorTerms), obj, terms)
 }
 
+// SetPodOverrides mocks base method.
+func (m *MockHelper) SetPodOverrides(obj *unstructured.Unstructured, overrides v1beta1.SpecialResourcePodOverrides) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPodOverrides", obj, overrides)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPodOverrides indicates an expected call of SetPodOverrides.
+func (mr *MockHelperMockRecorder) SetPodOverrides(obj, overrides interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPodOverrides", reflect.TypeOf((*MockHelper)(nil).SetPodOverrides), obj, overrides)
+}
+
 // UpdateResourceVersion mocks base method.
 func (m *MockHelper) UpdateResourceVersion(req, found *unstructured.Unstructured) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,130 @@
+package resourcehelper
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// workloadPodSpecFields is the path of the Pod spec of the workload kinds.
+var workloadPodSpecFields = map[string][]string{
+	"Pod":         {"spec"},
+	"DaemonSet":   {"spec", "template", "spec"},
+	"Deployment":  {"spec", "template", "spec"},
+	"StatefulSet": {"spec", "template", "spec"},
+	"Job":         {"spec", "template", "spec"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// IsWorkload returns true if the objects of kind run Pods.
+func IsWorkload(kind string) bool {
+	_, ok := workloadPodSpecFields[kind]
+	return ok
+}
+
+// MatchingNodes returns how many of nodes match the nodeSelector and the
+// required node affinity of the Pods of the workload obj.
+func MatchingNodes(obj *unstructured.Unstructured, nodes []corev1.Node) (int, error) {
+	fields, ok := workloadPodSpecFields[obj.GetKind()]
+	if !ok {
+		return 0, fmt.Errorf("%s is not a workload", obj.GetKind())
+	}
+
+	raw, _, err := unstructured.NestedMap(obj.Object, fields...)
+	if err != nil {
+		return 0, err
+	}
+
+	spec := corev1.PodSpec{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &spec); err != nil {
+		return 0, fmt.Errorf("invalid Pod spec: %w", err)
+	}
+
+	nodeSelector := labels.SelectorFromSet(spec.NodeSelector)
+
+	var terms []corev1.NodeSelectorTerm
+	if a := spec.Affinity; a != nil && a.NodeAffinity != nil && a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		terms = a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	}
+
+	count := 0
+	for i := range nodes {
+		if !nodeSelector.Matches(labels.Set(nodes[i].Labels)) {
+			continue
+		}
+
+		matches, err := matchesTerms(&nodes[i], terms)
+		if err != nil {
+			return 0, err
+		}
+		if matches {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// matchesTerms returns true if node matches one of the node selector terms,
+// or if there are none.
+func matchesTerms(node *corev1.Node, terms []corev1.NodeSelectorTerm) (bool, error) {
+	if len(terms) == 0 {
+		return true, nil
+	}
+
+	for _, term := range terms {
+		// A term without requirements matches no node
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+
+		labelsMatch, err := matchesRequirements(labels.Set(node.Labels), term.MatchExpressions)
+		if err != nil {
+			return false, err
+		}
+
+		fieldsMatch, err := matchesRequirements(labels.Set{"metadata.name": node.Name}, term.MatchFields)
+		if err != nil {
+			return false, err
+		}
+
+		if labelsMatch && fieldsMatch {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func matchesRequirements(set labels.Set, requirements []corev1.NodeSelectorRequirement) (bool, error) {
+	selector := labels.NewSelector()
+
+	for _, r := range requirements {
+		op, ok := nodeSelectorOperators[r.Operator]
+		if !ok {
+			return false, fmt.Errorf("invalid node selector operator %q", r.Operator)
+		}
+
+		req, err := labels.NewRequirement(r.Key, op, r.Values)
+		if err != nil {
+			return false, fmt.Errorf("invalid node selector requirement: %w", err)
+		}
+
+		selector = selector.Add(*req)
+	}
+
+	return selector.Matches(set), nil
+}
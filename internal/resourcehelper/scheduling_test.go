@@ -0,0 +1,86 @@
+package resourcehelper_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/internal/resourcehelper"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("MatchingNodes", func() {
+	node := func(name string, labels map[string]string) v1.Node {
+		return v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	nodes := []v1.Node{
+		node("node1", map[string]string{"node-role.kubernetes.io/worker": "", "gpu": "a100"}),
+		node("node2", map[string]string{"node-role.kubernetes.io/worker": "", "gpu": "t4"}),
+		node("node3", map[string]string{"node-role.kubernetes.io/worker": ""}),
+	}
+
+	daemonSet := func(spec v1.PodSpec) *unstructured.Unstructured {
+		ds := &appsv1.DaemonSet{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSet"},
+			Spec:     appsv1.DaemonSetSpec{Template: v1.PodTemplateSpec{Spec: spec}},
+		}
+
+		raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ds)
+		Expect(err).NotTo(HaveOccurred())
+
+		return &unstructured.Unstructured{Object: raw}
+	}
+
+	affinity := func(terms ...v1.NodeSelectorTerm) *v1.Affinity {
+		return &v1.Affinity{
+			NodeAffinity: &v1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: terms},
+			},
+		}
+	}
+
+	DescribeTable("should count the nodes the Pods can run on",
+		func(spec v1.PodSpec, expected int) {
+			count, err := resourcehelper.MatchingNodes(daemonSet(spec), nodes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(expected))
+		},
+		Entry("no constraint", v1.PodSpec{}, 3),
+		Entry("nodeSelector", v1.PodSpec{NodeSelector: map[string]string{"gpu": "a100"}}, 1),
+		Entry("nodeSelector matching no node", v1.PodSpec{NodeSelector: map[string]string{"gpu": "h100"}}, 0),
+		Entry("node affinity", v1.PodSpec{
+			Affinity: affinity(v1.NodeSelectorTerm{
+				MatchExpressions: []v1.NodeSelectorRequirement{{Key: "gpu", Operator: v1.NodeSelectorOpExists}},
+			}),
+		}, 2),
+		Entry("node affinity terms are ORed", v1.PodSpec{
+			Affinity: affinity(
+				v1.NodeSelectorTerm{
+					MatchExpressions: []v1.NodeSelectorRequirement{{Key: "gpu", Operator: v1.NodeSelectorOpIn, Values: []string{"t4"}}},
+				},
+				v1.NodeSelectorTerm{
+					MatchFields: []v1.NodeSelectorRequirement{{Key: "metadata.name", Operator: v1.NodeSelectorOpIn, Values: []string{"node3"}}},
+				},
+			),
+		}, 2),
+		Entry("nodeSelector and node affinity", v1.PodSpec{
+			NodeSelector: map[string]string{"gpu": "a100"},
+			Affinity: affinity(v1.NodeSelectorTerm{
+				MatchExpressions: []v1.NodeSelectorRequirement{{Key: "gpu", Operator: v1.NodeSelectorOpNotIn, Values: []string{"a100"}}},
+			}),
+		}, 0),
+	)
+
+	It("should fail for the objects that are not workloads", func() {
+		cm := &unstructured.Unstructured{}
+		cm.SetKind("ConfigMap")
+
+		Expect(resourcehelper.IsWorkload("ConfigMap")).To(BeFalse())
+		_, err := resourcehelper.MatchingNodes(cm, nodes)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,196 @@
+package selftest
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/internal/controllers/state"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	// Name is the name of the self-test SpecialResource, of its namespace and
+	// of the ConfigMap holding its manifests.
+	Name = "sro-self-test"
+
+	pollInterval = 5 * time.Second
+)
+
+//go:embed manifests
+var manifests embed.FS
+
+//go:generate mockgen -source=selftest.go -package=selftest -destination=mock_selftest_api.go
+
+// Tester deploys a trivial kernel module through the whole pipeline of the
+// operator: it is built against the Driver Toolkit, loaded by a DaemonSet and
+// checked to be loaded, so that the operator can be validated after an install
+// or an upgrade.
+type Tester interface {
+	Run(ctx context.Context) error
+}
+
+type tester struct {
+	kubeClient        clients.ClientsInterface
+	log               logr.Logger
+	operatorNamespace string
+	timeout           time.Duration
+}
+
+// New returns a Tester that waits up to timeout for the self-test
+// SpecialResource to be ready, and as long again for it to be deleted.
+func New(kubeClient clients.ClientsInterface, operatorNamespace string, timeout time.Duration) Tester {
+	return &tester{
+		kubeClient:        kubeClient,
+		log:               zap.New(zap.UseDevMode(true)).WithName(utils.Print("selftest", utils.Green)),
+		operatorNamespace: operatorNamespace,
+		timeout:           timeout,
+	}
+}
+
+// Run creates the self-test SpecialResource from the embedded manifests and
+// waits for the running operator to reconcile it. It returns nil if it became
+// ready, the last error of the reconciles otherwise. The self-test is removed
+// before and after the run.
+func (t *tester) Run(ctx context.Context) error {
+	// A leftover of a previous run would already be ready
+	if err := t.deleteSpecialResource(ctx); err != nil {
+		return err
+	}
+
+	if err := t.applyManifests(ctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := t.deleteSpecialResource(ctx); err != nil {
+			t.log.Error(err, "Could not remove the self-test")
+		}
+		if err := t.kubeClient.Delete(ctx, t.configMap()); err != nil && !apierrors.IsNotFound(err) {
+			t.log.Error(err, "Could not remove the self-test manifests")
+		}
+	}()
+
+	sr := &v1beta1.SpecialResource{}
+	sr.SetName(Name)
+	sr.Spec.Namespace = Name
+	sr.Spec.Manifests = &v1beta1.SpecialResourceManifests{ConfigMap: Name}
+
+	t.log.Info("Creating the self-test SpecialResource")
+
+	if err := t.kubeClient.Create(ctx, sr); err != nil {
+		return fmt.Errorf("could not create SpecialResource %s: %w", Name, err)
+	}
+
+	var lastError string
+
+	err := wait.PollImmediate(pollInterval, t.timeout, func() (bool, error) {
+		if err := t.kubeClient.Get(ctx, types.NamespacedName{Name: Name}, sr); err != nil {
+			return false, fmt.Errorf("could not get SpecialResource %s: %w", Name, err)
+		}
+
+		// The operator retries the failed reconciles, e.g. a build that is
+		// still running, only the readiness ends the run
+		if cond := meta.FindStatusCondition(sr.Status.Conditions, state.Errored); cond != nil && cond.Status == "True" {
+			if cond.Message != lastError {
+				t.log.Info("Reconcile failed", "reason", cond.Reason, "message", cond.Message)
+			}
+			lastError = cond.Message
+		}
+
+		return meta.IsStatusConditionTrue(sr.Status.Conditions, state.Ready), nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		if lastError == "" {
+			return fmt.Errorf("SpecialResource %s was not ready after %s", Name, t.timeout)
+		}
+		return fmt.Errorf("SpecialResource %s was not ready after %s: %s", Name, t.timeout, lastError)
+	} else if err != nil {
+		return err
+	}
+
+	t.log.Info("The self-test SpecialResource is ready")
+
+	return nil
+}
+
+func (t *tester) configMap() *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	cm.SetNamespace(t.operatorNamespace)
+	cm.SetName(Name)
+
+	return cm
+}
+
+// applyManifests writes the embedded manifests to the ConfigMap the self-test
+// SpecialResource is deployed from.
+func (t *tester) applyManifests(ctx context.Context) error {
+	data := make(map[string]string)
+
+	entries, err := manifests.ReadDir("manifests")
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		content, err := fs.ReadFile(manifests, path.Join("manifests", e.Name()))
+		if err != nil {
+			return err
+		}
+		data[e.Name()] = string(content)
+	}
+
+	cm := t.configMap()
+
+	_, err = t.kubeClient.CreateOrUpdate(ctx, cm, func() error {
+		cm.Data = data
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not write the self-test manifests to ConfigMap %s/%s: %w", t.operatorNamespace, Name, err)
+	}
+
+	return nil
+}
+
+// deleteSpecialResource deletes the self-test SpecialResource and waits for the
+// operator to finalize it.
+func (t *tester) deleteSpecialResource(ctx context.Context) error {
+	sr := &v1beta1.SpecialResource{}
+	sr.SetName(Name)
+
+	if err := t.kubeClient.Delete(ctx, sr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("could not delete SpecialResource %s: %w", Name, err)
+	}
+
+	t.log.Info("Waiting for the self-test SpecialResource to be deleted")
+
+	err := wait.PollImmediate(pollInterval, t.timeout, func() (bool, error) {
+		err := t.kubeClient.Get(ctx, types.NamespacedName{Name: Name}, sr)
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("SpecialResource %s was not deleted: %w", Name, err)
+	}
+
+	return nil
+}
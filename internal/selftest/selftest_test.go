@@ -0,0 +1,121 @@
+package selftest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/internal/controllers/state"
+	"github.com/openshift-psap/special-resource-operator/internal/selftest"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var (
+	ctrl       *gomock.Controller
+	mockClient *clients.MockClientsInterface
+)
+
+func TestSelfTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = clients.NewMockClientsInterface(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "SelfTest Suite")
+}
+
+var _ = Describe("Run", func() {
+	const operatorNamespace = "openshift-special-resource-operator"
+
+	srObj := gomock.AssignableToTypeOf(&v1beta1.SpecialResource{})
+	cmObj := gomock.AssignableToTypeOf(&v1.ConfigMap{})
+	key := types.NamespacedName{Name: selftest.Name}
+	notFound := k8serrors.NewNotFound(v1beta1.GroupVersion.WithResource("specialresources").GroupResource(), selftest.Name)
+
+	withConditions := func(conditions ...metav1.Condition) func(context.Context, types.NamespacedName, client.Object) error {
+		return func(_ context.Context, _ types.NamespacedName, obj client.Object) error {
+			obj.(*v1beta1.SpecialResource).Status.Conditions = conditions
+			return nil
+		}
+	}
+
+	// expectSetup expects no leftover of a previous run and the manifests to
+	// be written, then the SpecialResource to be created.
+	expectSetup := func() {
+		gomock.InOrder(
+			mockClient.EXPECT().Delete(gomock.Any(), srObj).Return(notFound),
+			mockClient.EXPECT().
+				CreateOrUpdate(gomock.Any(), cmObj, gomock.Any()).
+				DoAndReturn(func(_ context.Context, obj client.Object, fn controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+					Expect(fn()).To(Succeed())
+
+					cm := obj.(*v1.ConfigMap)
+					Expect(cm.Namespace).To(Equal(operatorNamespace))
+					Expect(cm.Data).To(HaveKey("0000-build.yaml"))
+					Expect(cm.Data).To(HaveKey("1000-driver-container.yaml"))
+
+					return controllerutil.OperationResultCreated, nil
+				}),
+			mockClient.EXPECT().
+				Create(gomock.Any(), srObj).
+				Do(func(_ context.Context, obj client.Object, _ ...client.CreateOption) {
+					sr := obj.(*v1beta1.SpecialResource)
+					Expect(sr.Name).To(Equal(selftest.Name))
+					Expect(sr.Spec.Manifests.ConfigMap).To(Equal(selftest.Name))
+				}),
+		)
+	}
+
+	// expectTeardown expects the SpecialResource and the manifests to be
+	// removed once the run ended with poll.
+	expectTeardown := func(poll *gomock.Call) {
+		gomock.InOrder(
+			poll,
+			mockClient.EXPECT().Delete(gomock.Any(), srObj),
+			mockClient.EXPECT().Get(gomock.Any(), key, srObj).Return(notFound),
+			mockClient.EXPECT().Delete(gomock.Any(), cmObj),
+		)
+	}
+
+	It("should pass once the SpecialResource is ready", func() {
+		expectSetup()
+		expectTeardown(mockClient.EXPECT().
+			Get(gomock.Any(), key, srObj).
+			DoAndReturn(withConditions(metav1.Condition{Type: state.Ready, Status: metav1.ConditionTrue})))
+
+		Expect(selftest.New(mockClient, operatorNamespace, time.Minute).Run(context.Background())).To(Succeed())
+	})
+
+	It("should report the last reconcile error on timeout", func() {
+		expectSetup()
+		expectTeardown(mockClient.EXPECT().
+			Get(gomock.Any(), key, srObj).
+			DoAndReturn(withConditions(metav1.Condition{
+				Type:    state.Errored,
+				Status:  metav1.ConditionTrue,
+				Reason:  "WaitTimeout",
+				Message: "BuildConfig sro-self-test/sro-self-test: build failed",
+			})).
+			MinTimes(1))
+
+		err := selftest.New(mockClient, operatorNamespace, 10*time.Millisecond).Run(context.Background())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("build failed"))
+	})
+})
@@ -17,38 +17,41 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"errors"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/cmd/cli"
 	"github.com/openshift-psap/special-resource-operator/controllers"
+	"github.com/openshift-psap/special-resource-operator/internal/cleanup"
 	"github.com/openshift-psap/special-resource-operator/internal/controllers/finalizers"
-	"github.com/openshift-psap/special-resource-operator/internal/controllers/state"
-	"github.com/openshift-psap/special-resource-operator/internal/resourcehelper"
-	"github.com/openshift-psap/special-resource-operator/pkg/assets"
+	"github.com/openshift-psap/special-resource-operator/internal/selftest"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
-	"github.com/openshift-psap/special-resource-operator/pkg/cluster"
-	"github.com/openshift-psap/special-resource-operator/pkg/filter"
+	srodebug "github.com/openshift-psap/special-resource-operator/pkg/debug"
+	"github.com/openshift-psap/special-resource-operator/pkg/health"
 	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
-	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
 	"github.com/openshift-psap/special-resource-operator/pkg/lifecycle"
-	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
 	"github.com/openshift-psap/special-resource-operator/pkg/poll"
-	"github.com/openshift-psap/special-resource-operator/pkg/proxy"
-	"github.com/openshift-psap/special-resource-operator/pkg/registry"
-	"github.com/openshift-psap/special-resource-operator/pkg/resource"
-	"github.com/openshift-psap/special-resource-operator/pkg/runtime"
+	"github.com/openshift-psap/special-resource-operator/pkg/redact"
+	"github.com/openshift-psap/special-resource-operator/pkg/scaffold"
 	sroscheme "github.com/openshift-psap/special-resource-operator/pkg/scheme"
 	"github.com/openshift-psap/special-resource-operator/pkg/storage"
-	"github.com/openshift-psap/special-resource-operator/pkg/upgrade"
+	"github.com/openshift-psap/special-resource-operator/pkg/summary"
+	"github.com/openshift-psap/special-resource-operator/pkg/tracing"
+	"github.com/openshift-psap/special-resource-operator/pkg/webhook"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	// +kubebuilder:scaffold:imports
 )
@@ -73,13 +76,24 @@ func main() {
 		os.Exit(1)
 	}
 
-	helmSettings, err := helmer.DefaultSettings()
+	libraryCharts, err := helmer.ParseLibraryCharts(cl.LibraryCharts)
 	if err != nil {
-		setupLog.Error(err, "failed to create Helm settings")
+		setupLog.Error(err, "invalid library charts")
 		os.Exit(1)
 	}
 
-	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	chartVerification, err := helmer.ParseChartVerification(cl.ChartVerification, cl.ChartKeyring)
+	if err != nil {
+		setupLog.Error(err, "invalid chart verification")
+		os.Exit(1)
+	}
+
+	if err = redact.Configure(cl.LogRedaction, cl.LogRedactionPatterns); err != nil {
+		setupLog.Error(err, "invalid log redaction")
+		os.Exit(1)
+	}
+
+	ctrl.SetLogger(redact.Logger(zap.New(zap.UseDevMode(true))))
 
 	vcsData, err := vcsBuildSettingsToLogArgs()
 	if err != nil {
@@ -88,12 +102,30 @@ func main() {
 		setupLog.Info("VCS build settings", vcsData...)
 	}
 
+	if cl.LogSpans {
+		tracing.Enable()
+	}
+
+	if cl.Uninstall {
+		os.Exit(uninstall(cl))
+	}
+
+	if cl.Scaffold.Template != "" {
+		os.Exit(writeScaffold(cl))
+	}
+
+	if cl.SelfTest {
+		os.Exit(selfTest(cl))
+	}
+
 	opts := &ctrl.Options{
-		LeaderElection:     cl.EnableLeaderElection,
-		LeaderElectionID:   "sro.sigs.k8s.io",
-		MetricsBindAddress: cl.MetricsAddr,
-		Port:               9443,
-		Scheme:             scheme,
+		HealthProbeBindAddress: cl.HealthProbeAddr,
+		LeaderElection:         cl.EnableLeaderElection,
+		LeaderElectionID:       "sro.sigs.k8s.io",
+		MetricsBindAddress:     cl.MetricsAddr,
+		NewClient:              clients.NewClientFunc(cl.APICacheUnstructured),
+		Port:                   9443,
+		Scheme:                 scheme,
 	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), *opts)
@@ -102,58 +134,65 @@ func main() {
 		os.Exit(1)
 	}
 
-	kubeClient, err := clients.NewClients(mgr.GetClient(), mgr.GetConfig(), mgr.GetEventRecorderFor("specialresource"))
+	reconciler, registryAPI, err := controllers.NewSpecialResourceReconciler(mgr, scheme, controllers.Options{
+		API: clients.LimitOptions{
+			QPS:               cl.APIQPS,
+			Burst:             cl.APIBurst,
+			CacheUnstructured: cl.APICacheUnstructured,
+		},
+		AuditLog:             cl.AuditLog,
+		ChartUpgradeInterval: cl.ChartUpgradeInterval,
+		ChartVerification:    chartVerification,
+		DecisionPlugin:       cl.DecisionPlugin,
+		FeatureGates:         cl.FeatureGates,
+		LibraryCharts:        libraryCharts,
+		RecordManifests:      cl.RecordManifests,
+		RegistryBurst:        cl.RegistryBurst,
+		RegistryQPS:          cl.RegistryQPS,
+		ResourceBudget:       cl.ResourceBudget,
+		StorageBackend:       cl.StorageBackend,
+	})
 	if err != nil {
-		setupLog.Error(err, "unable to create k8s clients")
+		setupLog.Error(err, "unable to create controller", "controller", "SpecialResource")
 		os.Exit(1)
 	}
-	clusterAPI := cluster.NewCluster(kubeClient)
-
-	metricsClient := metrics.New()
-
-	st := storage.NewStorage(kubeClient)
-	lc := lifecycle.New(kubeClient, st)
-	pollActions := poll.New(kubeClient, lc, st)
-	kernelAPI := kernel.NewKernelData()
-	proxyAPI := proxy.NewProxyAPI(kubeClient)
-
-	creator := resource.NewCreator(
-		kubeClient,
-		metricsClient,
-		pollActions,
-		kernelAPI,
-		scheme,
-		lc,
-		proxyAPI,
-		resourcehelper.New())
-
-	clusterInfoAPI := upgrade.NewClusterInfo(registry.NewRegistry(kubeClient), clusterAPI)
-	runtimeAPI := runtime.NewRuntimeAPI(kubeClient, clusterAPI, kernelAPI, clusterInfoAPI, proxyAPI)
-
-	if err = (&controllers.SpecialResourceReconciler{
-		Cluster:       clusterAPI,
-		ClusterInfo:   clusterInfoAPI,
-		Creator:       creator,
-		PollActions:   pollActions,
-		Filter:        filter.NewFilter(lc, st, kernelAPI),
-		Finalizer:     finalizers.NewSpecialResourceFinalizer(kubeClient, pollActions),
-		StatusUpdater: state.NewStatusUpdater(kubeClient),
-		Storage:       st,
-		Helmer:        helmer.NewHelmer(creator, helmSettings, kubeClient),
-		Assets:        assets.NewAssets(),
-		KernelData:    kernelAPI,
-		Log:           ctrl.Log,
-		Metrics:       metricsClient,
-		Scheme:        scheme,
-		ProxyAPI:      proxyAPI,
-		RuntimeAPI:    runtimeAPI,
-		KubeClient:    kubeClient,
-	}).SetupWithManager(mgr); err != nil {
+
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SpecialResource")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 
+	if cl.EnableWebhook {
+		mgr.GetWebhookServer().Register(webhook.Path, webhook.NewWebhook(webhook.NewDefaulter(reconciler.Helmer, registryAPI)))
+	}
+
+	if cl.DebugAddr != "" {
+		if err = mgr.Add(srodebug.NewServer(cl.DebugAddr, reconciler.Debug.Handler())); err != nil {
+			setupLog.Error(err, "unable to set up the debug server")
+			os.Exit(1)
+		}
+	}
+
+	if err = mgr.AddMetricsExtraHandler(summary.Path, reconciler.Summary.Handler()); err != nil {
+		setupLog.Error(err, "unable to serve the summary")
+		os.Exit(1)
+	}
+
+	checks := health.New(reconciler.KubeClient, registryAPI)
+	if err = mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	if err = mgr.AddReadyzCheck("reconcile", checks.Reconcile); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	if err = mgr.AddReadyzCheck("registry", checks.Registry); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -161,6 +200,118 @@ func main() {
 	}
 }
 
+// uninstall removes the footprint of the operator from the cluster and returns
+// the exit code. It does not need a manager, the operator itself is expected to
+// be scaled down.
+func uninstall(cl *cli.CommandLine) int {
+	cfg := ctrl.GetConfigOrDie()
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		return 1
+	}
+
+	kubeClient, err := clients.NewClients(c, cfg, nil)
+	if err != nil {
+		setupLog.Error(err, "unable to create k8s clients")
+		return 1
+	}
+
+	st, err := storage.New(cl.StorageBackend, kubeClient)
+	if err != nil {
+		setupLog.Error(err, "unable to create storage")
+		return 1
+	}
+
+	pollActions := poll.New(kubeClient, lifecycle.New(kubeClient, st), st)
+	// The delete hooks of the charts are not run, they need the resource
+	// creator of a running operator.
+	finalizer := finalizers.NewSpecialResourceFinalizer(kubeClient, pollActions, ownership.New(kubeClient), nil)
+
+	report, err := cleanup.New(kubeClient, finalizer, 5*time.Minute).Run(context.Background())
+	if err != nil {
+		setupLog.Error(err, "uninstall failed")
+		return 1
+	}
+
+	for _, obj := range report.Objects {
+		setupLog.Info("Object left behind", "object", obj)
+	}
+	for _, label := range report.NodeLabels {
+		setupLog.Info("Node label left behind", "label", label)
+	}
+
+	if !report.Empty() {
+		return 1
+	}
+
+	setupLog.Info("uninstall complete")
+	return 0
+}
+
+func selfTest(cl *cli.CommandLine) int {
+	cfg := ctrl.GetConfigOrDie()
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		return 1
+	}
+
+	kubeClient, err := clients.NewClients(c, cfg, nil)
+	if err != nil {
+		setupLog.Error(err, "unable to create k8s clients")
+		return 1
+	}
+
+	if err = selftest.New(kubeClient, os.Getenv("OPERATOR_NAMESPACE"), cl.SelfTestTimeout).Run(context.Background()); err != nil {
+		setupLog.Error(err, "self-test failed")
+		return 1
+	}
+
+	setupLog.Info("self-test passed")
+	return 0
+}
+
+// writeScaffold writes a starter recipe to the output directory, existing files
+// are not overwritten.
+func writeScaffold(cl *cli.CommandLine) int {
+	files, err := scaffold.New().Generate(cl.Scaffold)
+	if err != nil {
+		setupLog.Error(err, "could not generate the recipe")
+		return 1
+	}
+
+	for _, f := range files {
+		p := filepath.Join(cl.ScaffoldOutput, f.Path)
+
+		if err = os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			setupLog.Error(err, "could not create the recipe directory")
+			return 1
+		}
+
+		out, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			setupLog.Error(err, "could not create the recipe file")
+			return 1
+		}
+
+		_, err = out.Write(f.Content)
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			setupLog.Error(err, "could not write the recipe file", "path", p)
+			return 1
+		}
+
+		setupLog.Info("Written", "path", p)
+	}
+
+	return 0
+}
+
 func vcsBuildSettingsToLogArgs() ([]any, error) {
 	bi, ok := debug.ReadBuildInfo()
 	if !ok {
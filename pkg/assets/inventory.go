@@ -0,0 +1,43 @@
+package assets
+
+import (
+	_ "embed"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/states"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// InventoryStateName is the name of the built-in device inventory state, as
+// listed in spec.disableStates.
+const InventoryStateName = "device-inventory"
+
+// inventoryTemplate is the path of the built-in state in the chart, it runs
+// before the states of the chart.
+const inventoryTemplate = "templates/0000-" + InventoryStateName + ".yaml"
+
+// InventoryLabelPrefix prefixes the pci-<class>_<vendor>.present labels the
+// device inventory sets on the nodes.
+const InventoryLabelPrefix = "inventory.specialresource.openshift.io/"
+
+// inventory labels the nodes with their PCI devices if
+// .Values.deviceInventory.enabled, and renders nothing otherwise.
+//
+//go:embed inventory.yaml
+var inventory []byte
+
+// WithInventory returns a copy of ch with the built-in device-inventory state
+// if its values or set, the values of the SpecialResource, enable it. ch is
+// returned as is otherwise or if it already has a state of that name.
+func WithInventory(ch *chart.Chart, set map[string]interface{}) *chart.Chart {
+	if !inventoryEnabled(ch.Values) && !inventoryEnabled(set) {
+		return ch
+	}
+
+	return states.Add(ch, inventoryTemplate, inventory)
+}
+
+func inventoryEnabled(values map[string]interface{}) bool {
+	enabled, _, _ := unstructured.NestedBool(values, "deviceInventory", "enabled")
+	return enabled
+}
@@ -0,0 +1,69 @@
+package assets_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/assets"
+	"github.com/openshift-psap/special-resource-operator/pkg/states"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var enabled = map[string]interface{}{"deviceInventory": map[string]interface{}{"enabled": true}}
+
+func newChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "simple-kmod", Version: "0.0.1", APIVersion: chart.APIVersionV2},
+		Templates: []*chart.File{{Name: "templates/1000-driver-container.yaml"}},
+	}
+}
+
+var _ = Describe("WithInventory", func() {
+	It("should not add the state unless enabled", func() {
+		ch := newChart()
+		Expect(assets.WithInventory(ch, nil)).To(BeIdenticalTo(ch))
+		Expect(assets.WithInventory(ch, map[string]interface{}{"deviceInventory": map[string]interface{}{"enabled": false}})).To(BeIdenticalTo(ch))
+	})
+
+	It("should add the state before the states of the chart", func() {
+		ch := newChart()
+
+		stateYAMLS, _ := states.Split(assets.WithInventory(ch, enabled), func(string) bool { return true })
+
+		Expect(ch.Templates).To(HaveLen(1))
+		Expect(stateYAMLS).To(HaveLen(2))
+		Expect(states.Name(stateYAMLS[0].Name)).To(Equal(assets.InventoryStateName))
+	})
+
+	It("should label the selected nodes with their PCI devices", func() {
+		values := chartutil.CoalesceTables(map[string]interface{}{
+			"specialresource": map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "simple-kmod"},
+				"spec": map[string]interface{}{
+					"namespace":    "simple-kmod",
+					"nodeSelector": map[string]interface{}{"node-role.kubernetes.io/worker": ""},
+				},
+			},
+		}, enabled)
+
+		manifest, err := states.Render(assets.WithInventory(newChart(), enabled), values, "templates/0000-device-inventory.yaml")
+		Expect(err).NotTo(HaveOccurred())
+
+		var ds appsv1.DaemonSet
+		for _, doc := range releaseutil.SplitManifests(manifest) {
+			if strings.Contains(doc, "kind: DaemonSet") {
+				Expect(yaml.UnmarshalStrict([]byte(doc), &ds)).To(Succeed())
+			}
+		}
+
+		Expect(ds.Name).To(Equal("simple-kmod-device-inventory"))
+		Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"node-role.kubernetes.io/worker": ""}))
+		Expect(manifest).To(ContainSubstring("PREFIX=" + strings.TrimSuffix(assets.InventoryLabelPrefix, "/")))
+		Expect(manifest).To(ContainSubstring("sleep 300"))
+	})
+})
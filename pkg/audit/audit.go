@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/storage"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	// limit is the number of decisions kept per SpecialResource, the
+	// oldest ones are dropped first.
+	limit = 100
+
+	storeName = "special-resource-audit"
+)
+
+// Action is a decision of the operator.
+type Action string
+
+const (
+	ObjectCreated   Action = "ObjectCreated"
+	ObjectUpdated   Action = "ObjectUpdated"
+	ObjectRecreated Action = "ObjectRecreated"
+	ObjectAdopted   Action = "ObjectAdopted"
+	StateApplied    Action = "StateApplied"
+	StateSkipped    Action = "StateSkipped"
+	StateFailed     Action = "StateFailed"
+	KernelAdded     Action = "KernelAdded"
+	KernelRemoved   Action = "KernelRemoved"
+	ChartUpgraded   Action = "ChartUpgraded"
+)
+
+// Entry is a decision taken for a SpecialResource. Entries are only ever
+// appended to the log.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Action  Action    `json:"action"`
+	Subject string    `json:"subject"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// record is the log of a SpecialResource in the store, along with the kernels
+// it was last reconciled for.
+type record struct {
+	Kernels []string `json:"kernels"`
+	Entries []Entry  `json:"entries"`
+}
+
+//go:generate mockgen -source=audit.go -package=audit -destination=mock_audit_api.go
+
+type Log interface {
+	Record(specialResource string, action Action, subject, reason string)
+	Flush(ctx context.Context, specialResource string, kernels []string) error
+}
+
+type auditLog struct {
+	kubeClient clients.ClientsInterface
+	storage    storage.Storage
+	log        logr.Logger
+	enabled    bool
+
+	mutex   sync.Mutex
+	pending map[string][]Entry
+}
+
+// New returns a Log keeping the last decisions taken for every SpecialResource
+// in the storage. A disabled Log records nothing.
+func New(kubeClient clients.ClientsInterface, storage storage.Storage, enabled bool) Log {
+	return &auditLog{
+		kubeClient: kubeClient,
+		storage:    storage,
+		log:        zap.New(zap.UseDevMode(true)).WithName(utils.Print("audit", utils.Brown)),
+		enabled:    enabled,
+		pending:    make(map[string][]Entry),
+	}
+}
+
+// Record logs a decision and buffers it until the next Flush.
+func (l *auditLog) Record(specialResource string, action Action, subject, reason string) {
+	if !l.enabled {
+		return
+	}
+
+	l.log.Info("Decision", "specialresource", specialResource, "action", action, "subject", subject, "reason", reason)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.pending[specialResource] = append(l.pending[specialResource],
+		Entry{Time: time.Now().UTC(), Action: action, Subject: subject, Reason: reason})
+}
+
+// Flush appends the decisions buffered for the SpecialResource to its log,
+// preceded by the kernels added and removed since the last Flush.
+func (l *auditLog) Flush(ctx context.Context, specialResource string, kernels []string) error {
+	if !l.enabled {
+		return nil
+	}
+
+	l.mutex.Lock()
+	entries := l.pending[specialResource]
+	delete(l.pending, specialResource)
+	l.mutex.Unlock()
+
+	value, err := l.storage.CheckConfigMapEntry(ctx, specialResource, storeNamespacedName())
+	if apierrors.IsNotFound(err) {
+		// The store is not shipped with the operator
+		value, err = "", storage.CreateConfigMap(ctx, l.kubeClient, storeNamespacedName())
+	}
+	if err != nil {
+		return fmt.Errorf("could not read the audit log of %s: %w", specialResource, err)
+	}
+
+	rec := record{}
+	if value != "" {
+		if err = json.Unmarshal([]byte(value), &rec); err != nil {
+			return fmt.Errorf("could not unmarshal the audit log of %s: %w", specialResource, err)
+		}
+	}
+
+	kernels = append([]string(nil), kernels...)
+	sort.Strings(kernels)
+
+	now := time.Now().UTC()
+	changes := make([]Entry, 0)
+	for _, kernel := range difference(kernels, rec.Kernels) {
+		changes = append(changes, Entry{Time: now, Action: KernelAdded, Subject: kernel})
+	}
+	for _, kernel := range difference(rec.Kernels, kernels) {
+		changes = append(changes, Entry{Time: now, Action: KernelRemoved, Subject: kernel})
+	}
+
+	if len(changes) == 0 && len(entries) == 0 {
+		return nil
+	}
+
+	rec.Kernels = kernels
+	rec.Entries = append(append(rec.Entries, changes...), entries...)
+	if len(rec.Entries) > limit {
+		rec.Entries = rec.Entries[len(rec.Entries)-limit:]
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	return l.storage.UpdateConfigMapEntry(ctx, specialResource, string(data), storeNamespacedName())
+}
+
+// difference returns the items of a that are not in b.
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+
+	diff := make([]string, 0)
+	for _, s := range a {
+		if !in[s] {
+			diff = append(diff, s)
+		}
+	}
+
+	return diff
+}
+
+func storeNamespacedName() types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: os.Getenv("OPERATOR_NAMESPACE"),
+		Name:      storeName,
+	}
+}
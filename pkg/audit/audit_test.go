@@ -0,0 +1,155 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/audit"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/storage"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	srName  = "simple-kmod"
+	kernel1 = "4.18.0-305.19.1.el8_4.x86_64"
+	kernel2 = "4.18.0-305.25.1.el8_4.x86_64"
+)
+
+var (
+	ctrl        *gomock.Controller
+	mockClient  *clients.MockClientsInterface
+	mockStorage *storage.MockStorage
+	store       = types.NamespacedName{Namespace: "operator-ns", Name: "special-resource-audit"}
+)
+
+func TestAudit(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		GinkgoT().Setenv("OPERATOR_NAMESPACE", store.Namespace)
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = clients.NewMockClientsInterface(ctrl)
+		mockStorage = storage.NewMockStorage(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "Audit Suite")
+}
+
+type stored struct {
+	Kernels []string      `json:"kernels"`
+	Entries []audit.Entry `json:"entries"`
+}
+
+// flush flushes the log on top of previous and returns what was stored.
+func flush(l audit.Log, previous string, kernels []string) stored {
+	var value string
+
+	mockStorage.EXPECT().CheckConfigMapEntry(context.TODO(), srName, store).Return(previous, nil)
+	mockStorage.EXPECT().
+		UpdateConfigMapEntry(context.TODO(), srName, gomock.Any(), store).
+		Do(func(_ context.Context, _, v string, _ types.NamespacedName) {
+			value = v
+		})
+
+	Expect(l.Flush(context.TODO(), srName, kernels)).To(Succeed())
+
+	s := stored{}
+	Expect(json.Unmarshal([]byte(value), &s)).To(Succeed())
+
+	return s
+}
+
+var _ = Describe("Log", func() {
+	It("should record nothing when disabled", func() {
+		l := audit.New(mockClient, mockStorage, false)
+		l.Record(srName, audit.StateApplied, "0000-state", "")
+
+		Expect(l.Flush(context.TODO(), srName, []string{kernel1})).To(Succeed())
+	})
+
+	It("should append the recorded decisions after the kernels added", func() {
+		l := audit.New(mockClient, mockStorage, true)
+		l.Record(srName, audit.ObjectCreated, "DaemonSet simple-kmod/driver", "not found")
+		l.Record(srName, audit.StateApplied, "0000-state", "")
+
+		s := flush(l, "", []string{kernel1})
+
+		Expect(s.Kernels).To(Equal([]string{kernel1}))
+		Expect(s.Entries).To(HaveLen(3))
+		Expect(s.Entries[0].Action).To(Equal(audit.KernelAdded))
+		Expect(s.Entries[0].Subject).To(Equal(kernel1))
+		Expect(s.Entries[1].Action).To(Equal(audit.ObjectCreated))
+		Expect(s.Entries[1].Reason).To(Equal("not found"))
+		Expect(s.Entries[2].Action).To(Equal(audit.StateApplied))
+	})
+
+	It("should record the kernels added and removed since the last flush", func() {
+		l := audit.New(mockClient, mockStorage, true)
+		first := flush(l, "", []string{kernel1})
+
+		previous, err := json.Marshal(first)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := flush(l, string(previous), []string{kernel2})
+
+		Expect(s.Kernels).To(Equal([]string{kernel2}))
+		Expect(s.Entries).To(HaveLen(3))
+		Expect(s.Entries[1].Action).To(Equal(audit.KernelAdded))
+		Expect(s.Entries[1].Subject).To(Equal(kernel2))
+		Expect(s.Entries[2].Action).To(Equal(audit.KernelRemoved))
+		Expect(s.Entries[2].Subject).To(Equal(kernel1))
+	})
+
+	It("should not write the store when nothing happened", func() {
+		l := audit.New(mockClient, mockStorage, true)
+
+		previous, err := json.Marshal(stored{Kernels: []string{kernel1}})
+		Expect(err).NotTo(HaveOccurred())
+
+		mockStorage.EXPECT().CheckConfigMapEntry(context.TODO(), srName, store).Return(string(previous), nil)
+
+		Expect(l.Flush(context.TODO(), srName, []string{kernel1})).To(Succeed())
+	})
+
+	It("should only keep the last decisions", func() {
+		l := audit.New(mockClient, mockStorage, true)
+		for i := 0; i < 150; i++ {
+			l.Record(srName, audit.StateSkipped, "0000-state", "unchanged")
+		}
+
+		s := flush(l, "", nil)
+
+		Expect(s.Entries).To(HaveLen(100))
+	})
+
+	It("should create the store if it does not exist", func() {
+		l := audit.New(mockClient, mockStorage, true)
+		l.Record(srName, audit.StateApplied, "0000-state", "")
+
+		gomock.InOrder(
+			mockStorage.EXPECT().
+				CheckConfigMapEntry(context.TODO(), srName, store).
+				Return("", k8serrors.NewNotFound(v1.Resource("configmap"), store.Name)),
+			mockClient.EXPECT().
+				Create(context.TODO(), gomock.AssignableToTypeOf(&v1.ConfigMap{})).
+				Do(func(_ context.Context, cm *v1.ConfigMap) {
+					Expect(cm.GetName()).To(Equal(store.Name))
+					Expect(cm.GetNamespace()).To(Equal(store.Namespace))
+				}),
+			mockStorage.EXPECT().UpdateConfigMapEntry(context.TODO(), srName, gomock.Any(), store),
+		)
+
+		Expect(l.Flush(context.TODO(), srName, nil)).To(Succeed())
+	})
+})
@@ -0,0 +1,200 @@
+package budget
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+//go:generate mockgen -source=budget.go -package=budget -destination=mock_budget_api.go
+
+// Budget limits the number of objects of a kind a single SpecialResource,
+// along with its dependencies, may create, so that a recipe cannot e.g. bind
+// cluster roles on a shared cluster.
+type Budget interface {
+	Allow(specialResource string, obj *unstructured.Unstructured) error
+	Reset(specialResource string)
+	Share(dependency, specialResource string)
+	Forget(specialResource string)
+}
+
+type budget struct {
+	limits map[string]int
+
+	mutex sync.Mutex
+	// accounts are the SpecialResources the dependencies share the budget of
+	accounts map[string]string
+	// applied are the objects counted per account and kind, along with the
+	// SpecialResource they were counted for
+	applied map[string]map[string]map[string]string
+}
+
+// New returns the budget of every SpecialResource, spec is a comma separated
+// list of kind=max pairs, e.g. "ClusterRoleBinding=0,DaemonSet=2". Kinds that
+// are not listed are not limited.
+func New(spec string) (Budget, error) {
+	limits, err := parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &budget{
+		limits:   limits,
+		accounts: make(map[string]string),
+		applied:  make(map[string]map[string]map[string]string),
+	}, nil
+}
+
+func parse(spec string) (map[string]int, error) {
+	limits := make(map[string]int)
+
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kind, max, ok := strings.Cut(pair, "=")
+		kind = strings.TrimSpace(kind)
+		if !ok || kind == "" {
+			return nil, fmt.Errorf("resource budget %q is not of the form kind=max", pair)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(max))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid maximum of kind %q: %q", kind, max)
+		}
+
+		limits[kind] = n
+	}
+
+	return limits, nil
+}
+
+// Allow counts obj against the budget of specialResource, shared with the
+// SpecialResource it is a dependency of, and returns a Blocked error if it
+// would exceed it. An object already counted since the last Reset is always
+// allowed.
+func (b *budget) Allow(specialResource string, obj *unstructured.Unstructured) error {
+	max, limited := b.limits[obj.GetKind()]
+	if !limited {
+		return nil
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	kinds, ok := b.applied[b.account(specialResource)]
+	if !ok {
+		kinds = make(map[string]map[string]string)
+		b.applied[b.account(specialResource)] = kinds
+	}
+
+	objs, ok := kinds[obj.GetKind()]
+	if !ok {
+		objs = make(map[string]string)
+		kinds[obj.GetKind()] = objs
+	}
+
+	key := obj.GetNamespace() + "/" + obj.GetName()
+	if _, ok = objs[key]; ok {
+		return nil
+	}
+
+	if len(objs) >= max {
+		return sroerrors.Wrap(
+			sroerrors.Blocked,
+			fmt.Errorf("%s %s exceeds the resource budget of %d %s objects per SpecialResource", obj.GetKind(), key, max, obj.GetKind()),
+		)
+	}
+
+	objs[key] = specialResource
+
+	return nil
+}
+
+// Reset forgets the objects counted for specialResource, it is called before
+// its states are applied again. The objects counted for the other
+// SpecialResources sharing its budget are kept.
+func (b *budget) Reset(specialResource string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.reset(b.account(specialResource), specialResource)
+}
+
+// Share counts the objects of dependency against the budget of
+// specialResource, the objects counted for dependency so far included.
+func (b *budget) Share(dependency, specialResource string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	account := b.account(specialResource)
+	previous := b.account(dependency)
+	if account == previous {
+		return
+	}
+
+	for kind, objs := range b.applied[previous] {
+		for key, owner := range objs {
+			if owner != dependency {
+				continue
+			}
+			if _, ok := b.applied[account]; !ok {
+				b.applied[account] = make(map[string]map[string]string)
+			}
+			if _, ok := b.applied[account][kind]; !ok {
+				b.applied[account][kind] = make(map[string]string)
+			}
+			b.applied[account][kind][key] = owner
+		}
+	}
+	b.reset(previous, dependency)
+
+	b.accounts[dependency] = account
+}
+
+// Forget drops everything known about specialResource, once it is deleted.
+// Its dependencies get a budget of their own again.
+func (b *budget) Forget(specialResource string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.reset(b.account(specialResource), specialResource)
+	delete(b.accounts, specialResource)
+
+	for dependency, account := range b.accounts {
+		if account == specialResource {
+			delete(b.accounts, dependency)
+		}
+	}
+	delete(b.applied, specialResource)
+}
+
+// account returns the SpecialResource whose budget specialResource uses.
+func (b *budget) account(specialResource string) string {
+	if account, ok := b.accounts[specialResource]; ok {
+		return account
+	}
+	return specialResource
+}
+
+func (b *budget) reset(account, specialResource string) {
+	for kind, objs := range b.applied[account] {
+		for key, owner := range objs {
+			if owner == specialResource {
+				delete(objs, key)
+			}
+		}
+		if len(objs) == 0 {
+			delete(b.applied[account], kind)
+		}
+	}
+	if len(b.applied[account]) == 0 {
+		delete(b.applied, account)
+	}
+}
@@ -0,0 +1,123 @@
+package budget_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/budget"
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestBudget(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Budget Suite")
+}
+
+func object(kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetKind(kind)
+	obj.SetNamespace("ns")
+	obj.SetName(name)
+
+	return obj
+}
+
+var _ = Describe("New", func() {
+	DescribeTable("should reject an invalid spec",
+		func(spec string) {
+			_, err := budget.New(spec)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("no maximum", "DaemonSet"),
+		Entry("no kind", "=2"),
+		Entry("not a number", "DaemonSet=two"),
+		Entry("negative", "DaemonSet=-1"),
+	)
+
+	It("should not limit anything with an empty spec", func() {
+		b, err := budget.New("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.Allow("sr", object("ClusterRoleBinding", "crb"))).To(Succeed())
+	})
+})
+
+var _ = Describe("Allow", func() {
+	var b budget.Budget
+
+	BeforeEach(func() {
+		var err error
+		b, err = budget.New("ClusterRoleBinding=0, DaemonSet=2")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should block a forbidden kind", func() {
+		err := b.Allow("sr", object("ClusterRoleBinding", "crb"))
+		Expect(err).To(HaveOccurred())
+		Expect(sroerrors.CategoryOf(err)).To(Equal(sroerrors.Blocked))
+	})
+
+	It("should count the objects per SpecialResource", func() {
+		Expect(b.Allow("sr", object("DaemonSet", "a"))).To(Succeed())
+		Expect(b.Allow("sr", object("DaemonSet", "b"))).To(Succeed())
+		Expect(b.Allow("sr", object("DaemonSet", "a"))).To(Succeed())
+		Expect(b.Allow("sr", object("DaemonSet", "c"))).NotTo(Succeed())
+
+		Expect(b.Allow("other", object("DaemonSet", "c"))).To(Succeed())
+		Expect(b.Allow("sr", object("ConfigMap", "c"))).To(Succeed())
+	})
+
+	It("should count again after a reset", func() {
+		Expect(b.Allow("sr", object("DaemonSet", "a"))).To(Succeed())
+		Expect(b.Allow("sr", object("DaemonSet", "b"))).To(Succeed())
+
+		b.Reset("sr")
+
+		Expect(b.Allow("sr", object("DaemonSet", "c"))).To(Succeed())
+	})
+})
+
+var _ = Describe("Share", func() {
+	var b budget.Budget
+
+	BeforeEach(func() {
+		var err error
+		b, err = budget.New("DaemonSet=2")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should count the objects of the dependencies against the SpecialResource", func() {
+		Expect(b.Allow("dependency", object("DaemonSet", "a"))).To(Succeed())
+
+		b.Share("dependency", "sr")
+
+		Expect(b.Allow("sr", object("DaemonSet", "b"))).To(Succeed())
+		Expect(b.Allow("sr", object("DaemonSet", "c"))).NotTo(Succeed())
+		Expect(b.Allow("dependency", object("DaemonSet", "c"))).NotTo(Succeed())
+	})
+
+	It("should only reset the objects of the reset SpecialResource", func() {
+		b.Share("dependency", "sr")
+
+		Expect(b.Allow("dependency", object("DaemonSet", "a"))).To(Succeed())
+		Expect(b.Allow("sr", object("DaemonSet", "b"))).To(Succeed())
+
+		b.Reset("sr")
+
+		Expect(b.Allow("sr", object("DaemonSet", "c"))).To(Succeed())
+		Expect(b.Allow("sr", object("DaemonSet", "d"))).NotTo(Succeed())
+	})
+
+	It("should give the dependencies a budget of their own once the SpecialResource is forgotten", func() {
+		b.Share("dependency", "sr")
+
+		Expect(b.Allow("sr", object("DaemonSet", "a"))).To(Succeed())
+		Expect(b.Allow("sr", object("DaemonSet", "b"))).To(Succeed())
+
+		b.Forget("sr")
+
+		Expect(b.Allow("dependency", object("DaemonSet", "c"))).To(Succeed())
+		Expect(b.Allow("dependency", object("DaemonSet", "d"))).To(Succeed())
+	})
+})
@@ -3,7 +3,9 @@ package clients
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	"github.com/openshift-psap/special-resource-operator/pkg/redact"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
 	buildv1 "github.com/openshift/api/build/v1"
 	configv1 "github.com/openshift/api/config/v1"
@@ -13,6 +15,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
@@ -31,26 +34,30 @@ const (
 )
 
 var (
-	log = zap.New(zap.UseDevMode(true)).WithName(utils.Print("clients", utils.Brown))
+	log = redact.Logger(zap.New(zap.UseDevMode(true)).WithName(utils.Print("clients", utils.Brown)))
 	// TODO need to remove this global variable
 	Namespace string
 )
 
 type ClientsInterface interface {
-	Update(ctx context.Context, obj client.Object) error
+	Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error
 	Get(ctx context.Context, key client.ObjectKey, obj client.Object) error
 	Delete(ctx context.Context, obj client.Object) error
 	List(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error
-	Create(ctx context.Context, obj client.Object) error
+	Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error
+	Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error
 	GetPodLogs(namespace, podName string, podLogOpts *v1.PodLogOptions) *restclient.Request
 	GetNamespace(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Namespace, error)
 	GetSecret(ctx context.Context, namespace, name string, opts metav1.GetOptions) (*v1.Secret, error)
 	ClusterVersionGet(ctx context.Context, opts metav1.GetOptions) (result *configv1.ClusterVersion, err error)
+	Event(object runtime.Object, eventtype, reason, message string)
 	Invalidate()
 	ServerGroups() (*metav1.APIGroupList, error)
 	StatusUpdate(ctx context.Context, obj client.Object) error
+	StatusPatch(ctx context.Context, obj client.Object, patch client.Patch) error
 	CreateOrUpdate(ctx context.Context, obj client.Object, fn controllerutil.MutateFn) (controllerutil.OperationResult, error)
 	HasResource(resource schema.GroupVersionResource) (bool, error)
+	Impersonate(namespace, serviceAccount string) (ClientsInterface, error)
 	GetNodesByLabels(ctx context.Context, matchingLabels map[string]string) (*v1.NodeList, error)
 	GetPlatform() (string, error)
 }
@@ -62,6 +69,9 @@ type k8sClients struct {
 	eventRecorder   record.EventRecorder
 	cachedDiscovery discovery.CachedDiscoveryInterface
 	restConfig      *restclient.Config
+
+	impersonatedMu sync.Mutex
+	impersonated   map[string]ClientsInterface
 }
 
 func NewClients(runtimeClient client.Client, restConfig *restclient.Config, eventRecorder record.EventRecorder) (ClientsInterface, error) {
@@ -84,11 +94,12 @@ func NewClients(runtimeClient client.Client, restConfig *restclient.Config, even
 		eventRecorder:   eventRecorder,
 		cachedDiscovery: cachedDiscoveryClient,
 		restConfig:      restConfig,
+		impersonated:    make(map[string]ClientsInterface),
 	}, nil
 }
 
-func (k *k8sClients) Update(ctx context.Context, obj client.Object) error {
-	return k.runtimeClient.Update(ctx, obj)
+func (k *k8sClients) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return k.runtimeClient.Update(ctx, obj, opts...)
 }
 
 func (k *k8sClients) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
@@ -103,8 +114,12 @@ func (k *k8sClients) List(ctx context.Context, obj client.ObjectList, opts ...cl
 	return k.runtimeClient.List(ctx, obj, opts...)
 }
 
-func (k *k8sClients) Create(ctx context.Context, obj client.Object) error {
-	return k.runtimeClient.Create(ctx, obj)
+func (k *k8sClients) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return k.runtimeClient.Patch(ctx, obj, patch, opts...)
+}
+
+func (k *k8sClients) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return k.runtimeClient.Create(ctx, obj, opts...)
 }
 
 func (k *k8sClients) GetPodLogs(namespace, podName string, podLogOpts *v1.PodLogOptions) *restclient.Request {
@@ -123,6 +138,11 @@ func (k *k8sClients) ClusterVersionGet(ctx context.Context, opts metav1.GetOptio
 	return k.configV1Client.ClusterVersions().Get(ctx, clusterVersionName, opts)
 }
 
+// Event records an event for object with the event recorder of the manager.
+func (k *k8sClients) Event(object runtime.Object, eventtype, reason, message string) {
+	k.eventRecorder.Event(object, eventtype, reason, message)
+}
+
 func (k *k8sClients) Invalidate() {
 	k.cachedDiscovery.Invalidate()
 }
@@ -135,6 +155,10 @@ func (k *k8sClients) StatusUpdate(ctx context.Context, obj client.Object) error
 	return k.runtimeClient.Status().Update(ctx, obj)
 }
 
+func (k *k8sClients) StatusPatch(ctx context.Context, obj client.Object, patch client.Patch) error {
+	return k.runtimeClient.Status().Patch(ctx, obj, patch)
+}
+
 func (k *k8sClients) CreateOrUpdate(ctx context.Context, obj client.Object, fn controllerutil.MutateFn) (controllerutil.OperationResult, error) {
 	return controllerruntime.CreateOrUpdate(ctx, k.runtimeClient, obj, fn)
 }
@@ -170,6 +194,37 @@ func (k *k8sClients) HasResource(resource schema.GroupVersionResource) (bool, er
 	return false, nil
 }
 
+// Impersonate returns clients acting as the ServiceAccount, the requests are
+// authorized against its Roles instead of the ones of the operator. The clients
+// do not cache the objects they read.
+func (k *k8sClients) Impersonate(namespace, serviceAccount string) (ClientsInterface, error) {
+	userName := fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount)
+
+	k.impersonatedMu.Lock()
+	defer k.impersonatedMu.Unlock()
+
+	if c, ok := k.impersonated[userName]; ok {
+		return c, nil
+	}
+
+	cfg := restclient.CopyConfig(k.restConfig)
+	cfg.Impersonate = restclient.ImpersonationConfig{UserName: userName}
+
+	runtimeClient, err := client.New(cfg, client.Options{Scheme: k.runtimeClient.Scheme(), Mapper: k.runtimeClient.RESTMapper()})
+	if err != nil {
+		return nil, fmt.Errorf("could not create a client for %s: %w", userName, err)
+	}
+
+	c, err := NewClients(runtimeClient, cfg, k.eventRecorder)
+	if err != nil {
+		return nil, fmt.Errorf("could not create clients for %s: %w", userName, err)
+	}
+
+	k.impersonated[userName] = c
+
+	return c, nil
+}
+
 func (k *k8sClients) GetPlatform() (string, error) {
 	clusterIsOCP, err := k.HasResource(buildv1.SchemeGroupVersion.WithResource("buildconfigs"))
 	if err != nil {
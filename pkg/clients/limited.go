@@ -0,0 +1,283 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	configv1 "github.com/openshift/api/config/v1"
+	"golang.org/x/time/rate"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	restclient "k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// LimitOptions are the limits of the requests to the API server.
+type LimitOptions struct {
+	// QPS is the maximum number of requests per second, 0 disables the limit.
+	QPS   float64
+	Burst int
+	// CacheUnstructured is whether the reads of unstructured objects are
+	// served by the cache of the manager, see NewClientFunc.
+	CacheUnstructured bool
+}
+
+// NewClientFunc returns the function creating the client of the manager. The
+// typed objects are read from its cache, and so are the unstructured ones if
+// cacheUnstructured is true: an informer is then started for every kind the
+// operator reads, e.g. the kinds created by the charts.
+func NewClientFunc(cacheUnstructured bool) cluster.NewClientFunc {
+	return func(cache cache.Cache, config *restclient.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+		c, err := client.New(config, options)
+		if err != nil {
+			return nil, err
+		}
+
+		return client.NewDelegatingClient(client.NewDelegatingClientInput{
+			CacheReader:       cache,
+			Client:            c,
+			UncachedObjects:   uncachedObjects,
+			CacheUnstructured: cacheUnstructured,
+		})
+	}
+}
+
+type limitedClients struct {
+	ClientsInterface
+	limiter *rate.Limiter
+	metrics metrics.Metrics
+	scheme  *runtime.Scheme
+
+	// cacheReads is whether the reads are served by the cache of the manager,
+	// the impersonated clients have no cache
+	cacheReads        bool
+	cacheUnstructured bool
+}
+
+// NewLimitedClients wraps c, created with the client of the manager: the
+// requests that reach the API server are rate limited and their latency is
+// observed per verb and kind. The reads served by the cache are neither. The
+// clients impersonating a ServiceAccount share the limit.
+func NewLimitedClients(c ClientsInterface, scheme *runtime.Scheme, m metrics.Metrics, opts LimitOptions) ClientsInterface {
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if opts.QPS > 0 {
+		burst := opts.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(opts.QPS), burst)
+	}
+
+	return &limitedClients{
+		ClientsInterface:  c,
+		limiter:           limiter,
+		metrics:           m,
+		scheme:            scheme,
+		cacheReads:        true,
+		cacheUnstructured: opts.CacheUnstructured,
+	}
+}
+
+// request waits for the limiter and observes the latency of fn.
+func (l *limitedClients) request(ctx context.Context, verb, kind string, fn func() error) error {
+	if err := l.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("API request rate limit: %w", err)
+	}
+
+	start := time.Now()
+	err := fn()
+	l.metrics.ObserveAPIRequest(verb, kind, time.Since(start))
+
+	return err
+}
+
+// read is a request unless obj is served by the cache.
+func (l *limitedClients) read(ctx context.Context, verb string, obj runtime.Object, fn func() error) error {
+	if l.cached(obj) {
+		return fn()
+	}
+	return l.request(ctx, verb, l.kind(obj), fn)
+}
+
+func (l *limitedClients) cached(obj runtime.Object) bool {
+	if !l.cacheReads {
+		return false
+	}
+
+	switch obj.(type) {
+	case *unstructured.Unstructured, *unstructured.UnstructuredList:
+		return l.cacheUnstructured
+	}
+
+	return true
+}
+
+// kind returns the kind of obj, the one of the items for a list.
+func (l *limitedClients) kind(obj runtime.Object) string {
+	gvk, err := apiutil.GVKForObject(obj, l.scheme)
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSuffix(gvk.Kind, "List")
+}
+
+func (l *limitedClients) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return l.request(ctx, "update", l.kind(obj), func() error {
+		return l.ClientsInterface.Update(ctx, obj, opts...)
+	})
+}
+
+func (l *limitedClients) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	return l.read(ctx, "get", obj, func() error {
+		return l.ClientsInterface.Get(ctx, key, obj)
+	})
+}
+
+func (l *limitedClients) Delete(ctx context.Context, obj client.Object) error {
+	return l.request(ctx, "delete", l.kind(obj), func() error {
+		return l.ClientsInterface.Delete(ctx, obj)
+	})
+}
+
+func (l *limitedClients) List(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+	return l.read(ctx, "list", obj, func() error {
+		return l.ClientsInterface.List(ctx, obj, opts...)
+	})
+}
+
+func (l *limitedClients) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return l.request(ctx, "patch", l.kind(obj), func() error {
+		return l.ClientsInterface.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+func (l *limitedClients) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return l.request(ctx, "create", l.kind(obj), func() error {
+		return l.ClientsInterface.Create(ctx, obj, opts...)
+	})
+}
+
+func (l *limitedClients) GetNamespace(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Namespace, error) {
+	var ns *v1.Namespace
+
+	err := l.request(ctx, "get", "Namespace", func() error {
+		var err error
+		ns, err = l.ClientsInterface.GetNamespace(ctx, name, opts)
+		return err
+	})
+
+	return ns, err
+}
+
+func (l *limitedClients) GetSecret(ctx context.Context, namespace, name string, opts metav1.GetOptions) (*v1.Secret, error) {
+	var secret *v1.Secret
+
+	err := l.request(ctx, "get", "Secret", func() error {
+		var err error
+		secret, err = l.ClientsInterface.GetSecret(ctx, namespace, name, opts)
+		return err
+	})
+
+	return secret, err
+}
+
+func (l *limitedClients) ClusterVersionGet(ctx context.Context, opts metav1.GetOptions) (*configv1.ClusterVersion, error) {
+	var version *configv1.ClusterVersion
+
+	err := l.request(ctx, "get", "ClusterVersion", func() error {
+		var err error
+		version, err = l.ClientsInterface.ClusterVersionGet(ctx, opts)
+		return err
+	})
+
+	return version, err
+}
+
+func (l *limitedClients) StatusUpdate(ctx context.Context, obj client.Object) error {
+	return l.request(ctx, "update", l.kind(obj), func() error {
+		return l.ClientsInterface.StatusUpdate(ctx, obj)
+	})
+}
+
+func (l *limitedClients) StatusPatch(ctx context.Context, obj client.Object, patch client.Patch) error {
+	return l.request(ctx, "patch", l.kind(obj), func() error {
+		return l.ClientsInterface.StatusPatch(ctx, obj, patch)
+	})
+}
+
+// CreateOrUpdate is limited as a single request, its read may be served by
+// the cache.
+func (l *limitedClients) CreateOrUpdate(ctx context.Context, obj client.Object, fn controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+	var result controllerutil.OperationResult
+
+	err := l.request(ctx, "createorupdate", l.kind(obj), func() error {
+		var err error
+		result, err = l.ClientsInterface.CreateOrUpdate(ctx, obj, fn)
+		return err
+	})
+
+	return result, err
+}
+
+func (l *limitedClients) HasResource(resource schema.GroupVersionResource) (bool, error) {
+	var found bool
+
+	err := l.request(context.Background(), "discovery", resource.Resource, func() error {
+		var err error
+		found, err = l.ClientsInterface.HasResource(resource)
+		return err
+	})
+
+	return found, err
+}
+
+// Impersonate returns the impersonated clients wrapped with the same limit,
+// they have no cache.
+func (l *limitedClients) Impersonate(namespace, serviceAccount string) (ClientsInterface, error) {
+	c, err := l.ClientsInterface.Impersonate(namespace, serviceAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &limitedClients{
+		ClientsInterface: c,
+		limiter:          l.limiter,
+		metrics:          l.metrics,
+		scheme:           l.scheme,
+		cacheReads:       false,
+	}, nil
+}
+
+func (l *limitedClients) GetNodesByLabels(ctx context.Context, matchingLabels map[string]string) (*v1.NodeList, error) {
+	var nodes *v1.NodeList
+
+	err := l.read(ctx, "list", &v1.NodeList{}, func() error {
+		var err error
+		nodes, err = l.ClientsInterface.GetNodesByLabels(ctx, matchingLabels)
+		return err
+	})
+
+	return nodes, err
+}
+
+func (l *limitedClients) GetPlatform() (string, error) {
+	var platform string
+
+	err := l.request(context.Background(), "discovery", "buildconfigs", func() error {
+		var err error
+		platform, err = l.ClientsInterface.GetPlatform()
+		return err
+	})
+
+	return platform, err
+}
@@ -0,0 +1,128 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("limitedClients", func() {
+	var (
+		ctrl        *gomock.Controller
+		mockClients *MockClientsInterface
+		mockMetrics *metrics.MockMetrics
+		scheme      *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClients = NewMockClientsInterface(ctrl)
+		mockMetrics = metrics.NewMockMetrics(ctrl)
+
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	})
+
+	unstructuredDaemonSet := func() *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"})
+		return u
+	}
+
+	It("should not observe the reads served by the cache", func() {
+		lc := NewLimitedClients(mockClients, scheme, mockMetrics, LimitOptions{QPS: 1, Burst: 1})
+
+		gomock.InOrder(
+			mockClients.EXPECT().Get(context.TODO(), client.ObjectKey{Name: "a"}, gomock.Any()),
+			mockClients.EXPECT().List(context.TODO(), gomock.Any()),
+		)
+
+		Expect(lc.Get(context.TODO(), client.ObjectKey{Name: "a"}, &appsv1.DaemonSet{})).To(Succeed())
+		Expect(lc.List(context.TODO(), &appsv1.DaemonSetList{})).To(Succeed())
+	})
+
+	It("should observe the unstructured reads unless they are cached", func() {
+		lc := NewLimitedClients(mockClients, scheme, mockMetrics, LimitOptions{})
+
+		gomock.InOrder(
+			mockClients.EXPECT().Get(context.TODO(), client.ObjectKey{Name: "a"}, gomock.Any()),
+			mockMetrics.EXPECT().ObserveAPIRequest("get", "DaemonSet", gomock.Any()),
+		)
+
+		Expect(lc.Get(context.TODO(), client.ObjectKey{Name: "a"}, unstructuredDaemonSet())).To(Succeed())
+
+		lc = NewLimitedClients(mockClients, scheme, mockMetrics, LimitOptions{CacheUnstructured: true})
+
+		mockClients.EXPECT().Get(context.TODO(), client.ObjectKey{Name: "a"}, gomock.Any())
+
+		Expect(lc.Get(context.TODO(), client.ObjectKey{Name: "a"}, unstructuredDaemonSet())).To(Succeed())
+	})
+
+	It("should observe the writes by verb and kind", func() {
+		lc := NewLimitedClients(mockClients, scheme, mockMetrics, LimitOptions{})
+
+		randomError := errors.New("random error")
+		ds := &appsv1.DaemonSet{}
+
+		gomock.InOrder(
+			mockClients.EXPECT().Create(context.TODO(), ds),
+			mockMetrics.EXPECT().ObserveAPIRequest("create", "DaemonSet", gomock.Any()),
+			mockClients.EXPECT().Delete(context.TODO(), ds).Return(randomError),
+			mockMetrics.EXPECT().ObserveAPIRequest("delete", "DaemonSet", gomock.Any()),
+			mockClients.EXPECT().StatusUpdate(context.TODO(), unstructuredDaemonSet()),
+			mockMetrics.EXPECT().ObserveAPIRequest("update", "DaemonSet", gomock.Any()),
+		)
+
+		Expect(lc.Create(context.TODO(), ds)).To(Succeed())
+		Expect(lc.Delete(context.TODO(), ds)).To(Equal(randomError))
+		Expect(lc.StatusUpdate(context.TODO(), unstructuredDaemonSet())).To(Succeed())
+	})
+
+	It("should observe every read of the impersonated clients", func() {
+		lc := NewLimitedClients(mockClients, scheme, mockMetrics, LimitOptions{CacheUnstructured: true})
+
+		mockImpersonated := NewMockClientsInterface(ctrl)
+		nodes := &v1.NodeList{}
+
+		gomock.InOrder(
+			mockClients.EXPECT().Impersonate("ns", "sa").Return(mockImpersonated, nil),
+			mockImpersonated.EXPECT().GetNodesByLabels(context.TODO(), nil).Return(nodes, nil),
+			mockMetrics.EXPECT().ObserveAPIRequest("list", "Node", gomock.Any()),
+		)
+
+		ic, err := lc.Impersonate("ns", "sa")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ic.GetNodesByLabels(context.TODO(), nil)).To(Equal(nodes))
+	})
+
+	It("should limit the rate of the requests", func() {
+		lc := NewLimitedClients(mockClients, scheme, mockMetrics, LimitOptions{QPS: 0.1, Burst: 1})
+
+		ds := &appsv1.DaemonSet{}
+
+		gomock.InOrder(
+			mockClients.EXPECT().Update(gomock.Any(), ds),
+			mockMetrics.EXPECT().ObserveAPIRequest("update", "DaemonSet", gomock.Any()),
+		)
+
+		Expect(lc.Update(context.TODO(), ds)).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		Expect(lc.Update(ctx, ds)).To(MatchError(ContainSubstring("API request rate limit")))
+	})
+})
@@ -12,6 +12,7 @@ import (
 	v1 "github.com/openshift/api/config/v1"
 	v10 "k8s.io/api/core/v1"
 	v11 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	rest "k8s.io/client-go/rest"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
@@ -57,17 +58,22 @@ func (mr *MockClientsInterfaceMockRecorder) ClusterVersionGet(ctx, opts interfac
 }
 
 // Create mocks base method.
-func (m *MockClientsInterface) Create(ctx context.Context, obj client.Object) error {
+func (m *MockClientsInterface) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, obj)
+	varargs := []interface{}{ctx, obj}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Create", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockClientsInterfaceMockRecorder) Create(ctx, obj interface{}) *gomock.Call {
+func (mr *MockClientsInterfaceMockRecorder) Create(ctx, obj interface{}, opts ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockClientsInterface)(nil).Create), ctx, obj)
+	varargs := append([]interface{}{ctx, obj}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockClientsInterface)(nil).Create), varargs...)
 }
 
 // CreateOrUpdate mocks base method.
@@ -99,6 +105,18 @@ func (mr *MockClientsInterfaceMockRecorder) Delete(ctx, obj interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClientsInterface)(nil).Delete), ctx, obj)
 }
 
+// Event mocks base method.
+func (m *MockClientsInterface) Event(object runtime.Object, eventtype, reason, message string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Event", object, eventtype, reason, message)
+}
+
+// Event indicates an expected call of Event.
+func (mr *MockClientsInterfaceMockRecorder) Event(object, eventtype, reason, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Event", reflect.TypeOf((*MockClientsInterface)(nil).Event), object, eventtype, reason, message)
+}
+
 // Get mocks base method.
 func (m *MockClientsInterface) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
 	m.ctrl.T.Helper()
@@ -202,6 +220,21 @@ func (mr *MockClientsInterfaceMockRecorder) HasResource(resource interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasResource", reflect.TypeOf((*MockClientsInterface)(nil).HasResource), resource)
 }
 
+// Impersonate mocks base method.
+func (m *MockClientsInterface) Impersonate(namespace, serviceAccount string) (ClientsInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Impersonate", namespace, serviceAccount)
+	ret0, _ := ret[0].(ClientsInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Impersonate indicates an expected call of Impersonate.
+func (mr *MockClientsInterfaceMockRecorder) Impersonate(namespace, serviceAccount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Impersonate", reflect.TypeOf((*MockClientsInterface)(nil).Impersonate), namespace, serviceAccount)
+}
+
 // Invalidate mocks base method.
 func (m *MockClientsInterface) Invalidate() {
 	m.ctrl.T.Helper()
@@ -233,6 +266,25 @@ func (mr *MockClientsInterfaceMockRecorder) List(ctx, obj interface{}, opts ...i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockClientsInterface)(nil).List), varargs...)
 }
 
+// Patch mocks base method.
+func (m *MockClientsInterface) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, obj, patch}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Patch", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockClientsInterfaceMockRecorder) Patch(ctx, obj, patch interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, obj, patch}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockClientsInterface)(nil).Patch), varargs...)
+}
+
 // ServerGroups mocks base method.
 func (m *MockClientsInterface) ServerGroups() (*v11.APIGroupList, error) {
 	m.ctrl.T.Helper()
@@ -248,6 +300,20 @@ func (mr *MockClientsInterfaceMockRecorder) ServerGroups() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerGroups", reflect.TypeOf((*MockClientsInterface)(nil).ServerGroups))
 }
 
+// StatusPatch mocks base method.
+func (m *MockClientsInterface) StatusPatch(ctx context.Context, obj client.Object, patch client.Patch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatusPatch", ctx, obj, patch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StatusPatch indicates an expected call of StatusPatch.
+func (mr *MockClientsInterfaceMockRecorder) StatusPatch(ctx, obj, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusPatch", reflect.TypeOf((*MockClientsInterface)(nil).StatusPatch), ctx, obj, patch)
+}
+
 // StatusUpdate mocks base method.
 func (m *MockClientsInterface) StatusUpdate(ctx context.Context, obj client.Object) error {
 	m.ctrl.T.Helper()
@@ -263,15 +329,20 @@ func (mr *MockClientsInterfaceMockRecorder) StatusUpdate(ctx, obj interface{}) *
 }
 
 // Update mocks base method.
-func (m *MockClientsInterface) Update(ctx context.Context, obj client.Object) error {
+func (m *MockClientsInterface) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, obj)
+	varargs := []interface{}{ctx, obj}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Update", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockClientsInterfaceMockRecorder) Update(ctx, obj interface{}) *gomock.Call {
+func (mr *MockClientsInterfaceMockRecorder) Update(ctx, obj interface{}, opts ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockClientsInterface)(nil).Update), ctx, obj)
+	varargs := append([]interface{}{ctx, obj}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockClientsInterface)(nil).Update), varargs...)
 }
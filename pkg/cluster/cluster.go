@@ -14,19 +14,42 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
+// ClusterVersionName is the name of the ClusterVersion of an OpenShift cluster.
+const ClusterVersionName = "version"
+
+// controllerVersionAnnotation is set by the Machine Config Operator on the
+// MachineConfigs it renders, to the version of the controller that rendered
+// them.
+const controllerVersionAnnotation = "machineconfiguration.openshift.io/generated-by-controller-version"
+
+// MachineConfigPool is the OS the machines of a MachineConfigPool run: the one
+// of the rendered MachineConfig the pool last completed an update to. The pools
+// of a cluster run different builds while an upgrade rolls out, or as long as a
+// pool is paused.
+type MachineConfigPool struct {
+	OSImageURL        string            `json:"osImageURL"`
+	RenderedConfig    string            `json:"renderedConfig"`
+	ControllerVersion string            `json:"controllerVersion,omitempty"`
+	KernelType        string            `json:"kernelType,omitempty"`
+	Paused            bool              `json:"paused"`
+	NodeSelector      map[string]string `json:"nodeSelector,omitempty"`
+}
+
 //go:generate mockgen -source=cluster.go -package=cluster -destination=mock_cluster_api.go
 
 type Cluster interface {
 	Version(context.Context) (string, string, error)
 	VersionHistory(context.Context) ([]string, error)
 	OSImageURL(context.Context) (string, error)
+	MachineConfigPools(context.Context) (map[string]MachineConfigPool, error)
 	OperatingSystem(*corev1.NodeList) (string, string, string, error)
+	DefaultNodeSelector(context.Context) (map[string]string, error)
 }
 
 func NewCluster(clients clients.ClientsInterface) Cluster {
@@ -36,6 +59,8 @@ func NewCluster(clients clients.ClientsInterface) Cluster {
 	}
 }
 
+var errNotRendered = errors.New("no rendered MachineConfig in the status")
+
 type cluster struct {
 	log     logr.Logger
 	clients clients.ClientsInterface
@@ -51,9 +76,9 @@ func (c *cluster) Version(ctx context.Context) (string, string, error) {
 		return "", "", nil
 	}
 
-	version, err := c.clients.ClusterVersionGet(ctx, metav1.GetOptions{})
+	version, err := c.clusterVersion(ctx)
 	if err != nil {
-		return "", "", fmt.Errorf("ConfigClient unable to get ClusterVersions: %w", err)
+		return "", "", err
 	}
 
 	var majorMinor string
@@ -88,9 +113,9 @@ func (c *cluster) VersionHistory(ctx context.Context) ([]string, error) {
 		return stat, nil
 	}
 
-	version, err := c.clients.ClusterVersionGet(ctx, metav1.GetOptions{})
+	version, err := c.clusterVersion(ctx)
 	if err != nil {
-		return stat, fmt.Errorf("ConfigClient unable to get ClusterVersions: %w", err)
+		return stat, err
 	}
 
 	stat = append(stat, version.Status.Desired.Image)
@@ -136,6 +161,94 @@ func (c *cluster) OSImageURL(ctx context.Context) (string, error) {
 	return osImageURL, nil
 }
 
+// MachineConfigPools returns the OS of every MachineConfigPool by name. It is
+// empty on vanilla k8s.
+func (c *cluster) MachineConfigPools(ctx context.Context) (map[string]MachineConfigPool, error) {
+
+	poolsAvailable, err := c.clients.HasResource(machinev1.SchemeGroupVersion.WithResource("machineconfigpools"))
+	if err != nil {
+		return nil, fmt.Errorf("Error discovering machineconfigpool API resource: %w", err)
+	}
+	if !poolsAvailable {
+		c.log.Info("Warning: Could not find machineconfigpool API resource. Can be ignored on vanilla k8s.")
+		return nil, nil
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion(machinev1.SchemeGroupVersion.String())
+	list.SetKind("MachineConfigPoolList")
+
+	if err = c.clients.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("could not list the MachineConfigPools: %w", err)
+	}
+
+	pools := make(map[string]MachineConfigPool, len(list.Items))
+
+	for _, item := range list.Items {
+		pool, err := c.machineConfigPool(ctx, item)
+		if errors.Is(err, errNotRendered) {
+			// A pool that was just created has no rendered MachineConfig yet,
+			// it has no nodes to build for until the MCO renders one
+			c.log.Info("Skipping MachineConfigPool without a rendered MachineConfig", "pool", item.GetName())
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("MachineConfigPool %s: %w", item.GetName(), err)
+		}
+		pools[item.GetName()] = pool
+	}
+
+	return pools, nil
+}
+
+func (c *cluster) machineConfigPool(ctx context.Context, item unstructured.Unstructured) (MachineConfigPool, error) {
+
+	pool := MachineConfigPool{}
+
+	paused, _, err := unstructured.NestedBool(item.Object, "spec", "paused")
+	if err != nil {
+		return pool, err
+	}
+	pool.Paused = paused
+
+	selector, _, err := unstructured.NestedStringMap(item.Object, "spec", "nodeSelector", "matchLabels")
+	if err != nil {
+		return pool, err
+	}
+	pool.NodeSelector = selector
+
+	// The status follows the updates the pool completed, the spec the ones it
+	// was asked to
+	rendered, found, err := unstructured.NestedString(item.Object, "status", "configuration", "name")
+	if err != nil {
+		return pool, err
+	}
+	if !found || rendered == "" {
+		return pool, errNotRendered
+	}
+	pool.RenderedConfig = rendered
+
+	mc := &unstructured.Unstructured{}
+	mc.SetAPIVersion(machinev1.SchemeGroupVersion.String())
+	mc.SetKind("MachineConfig")
+
+	if err = c.clients.Get(ctx, types.NamespacedName{Name: rendered}, mc); err != nil {
+		return pool, fmt.Errorf("could not get MachineConfig %s: %w", rendered, err)
+	}
+
+	if pool.OSImageURL, _, err = unstructured.NestedString(mc.Object, "spec", "osImageURL"); err != nil {
+		return pool, err
+	}
+
+	if pool.KernelType, _, err = unstructured.NestedString(mc.Object, "spec", "kernelType"); err != nil {
+		return pool, err
+	}
+
+	pool.ControllerVersion = mc.GetAnnotations()[controllerVersionAnnotation]
+
+	return pool, nil
+}
+
 // Assumes all nodes have the same OS.
 // Returns the os in the following forms:
 // rhelx.y, rhelx, x.y
@@ -144,23 +257,23 @@ func (c *cluster) OperatingSystem(nodeList *corev1.NodeList) (string, string, st
 	var nodeOSrel string
 	var nodeOSmaj string
 	var nodeOSmin string
-	var labels map[string]string
+	var nodeLabels map[string]string
 
 	// Assuming all nodes are running the same os
 	os := "feature.node.kubernetes.io/system-os_release"
 
 	for _, node := range nodeList.Items {
-		labels = node.GetLabels()
-		nodeOSrel = labels[os+".ID"]
-		nodeOSmaj = labels[os+".VERSION_ID.major"]
-		nodeOSmin = labels[os+".VERSION_ID.minor"]
+		nodeLabels = node.GetLabels()
+		nodeOSrel = nodeLabels[os+".ID"]
+		nodeOSmaj = nodeLabels[os+".VERSION_ID.major"]
+		nodeOSmin = nodeLabels[os+".VERSION_ID.minor"]
 
 		if len(nodeOSrel) == 0 || len(nodeOSmaj) == 0 {
 			return "", "", "", fmt.Errorf("Cannot extract %s.*, is NFD running? Check node labels", os)
 		}
 	}
 	// On OCP >4.7, we can use the NFD label  feature.node.kubernetes.io/system-os_release.RHEL_VERSION label.
-	if rhelVersion, found := labels[os+".RHEL_VERSION"]; found && len(rhelVersion) == 3 {
+	if rhelVersion, found := nodeLabels[os+".RHEL_VERSION"]; found && len(rhelVersion) == 3 {
 		rhelMaj := rhelVersion[0:1]
 		rhelMin := rhelVersion[2:]
 		return "rhel" + rhelMaj, "rhel" + rhelVersion, rhelMaj + "." + rhelMin, nil
@@ -170,6 +283,53 @@ func (c *cluster) OperatingSystem(nodeList *corev1.NodeList) (string, string, st
 	return utils.RenderOperatingSystem(nodeOSrel, nodeOSmaj, nodeOSmin)
 }
 
+// DefaultNodeSelector returns the default node selector of the cluster
+// Scheduler config, the one OpenShift adds to the pods of the namespaces
+// without an openshift.io/node-selector annotation. It is empty on vanilla
+// k8s and when no default is set.
+func (c *cluster) DefaultNodeSelector(ctx context.Context) (map[string]string, error) {
+
+	schedulerAvailable, err := c.clients.HasResource(configv1.SchemeGroupVersion.WithResource("schedulers"))
+	if err != nil {
+		return nil, fmt.Errorf("Error discovering scheduler API resource: %w", err)
+	}
+	if !schedulerAvailable {
+		return nil, nil
+	}
+
+	scheduler := &configv1.Scheduler{}
+	err = c.clients.Get(ctx, types.NamespacedName{Name: "cluster"}, scheduler)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get the cluster Scheduler config: %w", err)
+	}
+
+	if scheduler.Spec.DefaultNodeSelector == "" {
+		return nil, nil
+	}
+
+	selector, err := labels.ConvertSelectorToLabelsMap(scheduler.Spec.DefaultNodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default node selector %q: %w", scheduler.Spec.DefaultNodeSelector, err)
+	}
+
+	return selector, nil
+}
+
+// clusterVersion returns the ClusterVersion from the cache of the manager, the
+// controller watches it so that the cache follows the upgrades.
+func (c *cluster) clusterVersion(ctx context.Context) (*configv1.ClusterVersion, error) {
+	version := &configv1.ClusterVersion{}
+
+	if err := c.clients.Get(ctx, types.NamespacedName{Name: ClusterVersionName}, version); err != nil {
+		return nil, fmt.Errorf("unable to get ClusterVersion %s: %w", ClusterVersionName, err)
+	}
+
+	return version, nil
+}
+
 func (c *cluster) clusterVersionAvailable() (bool, error) {
 
 	clusterVersionAvailable, err := c.clients.HasResource(configv1.SchemeGroupVersion.WithResource("clusterversions"))
@@ -15,7 +15,6 @@ import (
 	machinev1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	v1 "k8s.io/api/apps/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -72,8 +71,7 @@ var _ = Describe("cluster_Version", func() {
 				Return(true, nil),
 			mockKubeClients.
 				EXPECT().
-				ClusterVersionGet(context.TODO(), metav1.GetOptions{}).
-				Return(&configv1.ClusterVersion{}, nil),
+				Get(context.TODO(), types.NamespacedName{Name: cluster.ClusterVersionName}, &configv1.ClusterVersion{}),
 		)
 
 		_, _, err := cluster.NewCluster(mockKubeClients).Version(context.TODO())
@@ -101,8 +99,8 @@ var _ = Describe("cluster_Version", func() {
 					Return(true, nil),
 				mockKubeClients.
 					EXPECT().
-					ClusterVersionGet(context.TODO(), metav1.GetOptions{}).
-					Return(cv, nil),
+					Get(context.TODO(), types.NamespacedName{Name: cluster.ClusterVersionName}, &configv1.ClusterVersion{}).
+					SetArg(2, *cv),
 			)
 
 			cvv, v, err := cluster.NewCluster(mockKubeClients).Version(context.TODO())
@@ -137,7 +135,7 @@ var _ = Describe("cluster_VersionHistory", func() {
 		Expect(s).To(BeEmpty())
 	})
 
-	It("should return an error when ClusterVersionGet fails", func() {
+	It("should return an error when the ClusterVersion cannot be read", func() {
 		gomock.InOrder(
 			mockKubeClients.
 				EXPECT().
@@ -145,8 +143,8 @@ var _ = Describe("cluster_VersionHistory", func() {
 				Return(true, nil),
 			mockKubeClients.
 				EXPECT().
-				ClusterVersionGet(context.TODO(), metav1.GetOptions{}).
-				Return(nil, randomError),
+				Get(context.TODO(), types.NamespacedName{Name: cluster.ClusterVersionName}, &configv1.ClusterVersion{}).
+				Return(randomError),
 		)
 
 		_, err := cluster.NewCluster(mockKubeClients).VersionHistory(context.TODO())
@@ -183,8 +181,8 @@ var _ = Describe("cluster_VersionHistory", func() {
 				Return(true, nil),
 			mockKubeClients.
 				EXPECT().
-				ClusterVersionGet(context.TODO(), metav1.GetOptions{}).
-				Return(&cv, nil),
+				Get(context.TODO(), types.NamespacedName{Name: cluster.ClusterVersionName}, &configv1.ClusterVersion{}).
+				SetArg(2, cv),
 		)
 
 		s, err := cluster.NewCluster(mockKubeClients).VersionHistory(context.TODO())
@@ -294,6 +292,120 @@ var _ = Describe("cluster_OSImageURL", func() {
 	})
 })
 
+var _ = Describe("cluster_MachineConfigPools", func() {
+	mcpResource := machinev1.SchemeGroupVersion.WithResource("machineconfigpools")
+	mcpList := gomock.AssignableToTypeOf(&unstructured.UnstructuredList{})
+	mcObj := gomock.AssignableToTypeOf(&unstructured.Unstructured{})
+
+	pool := func(name, rendered string, paused bool) unstructured.Unstructured {
+		mcp := unstructured.Unstructured{Object: map[string]interface{}{}}
+		mcp.SetName(name)
+		Expect(unstructured.SetNestedField(mcp.Object, paused, "spec", "paused")).To(Succeed())
+		Expect(
+			unstructured.SetNestedStringMap(mcp.Object, map[string]string{"node-role.kubernetes.io/" + name: ""}, "spec", "nodeSelector", "matchLabels"),
+		).To(Succeed())
+		if rendered != "" {
+			Expect(unstructured.SetNestedField(mcp.Object, rendered, "status", "configuration", "name")).To(Succeed())
+		}
+		return mcp
+	}
+
+	listPools := func(pools ...unstructured.Unstructured) func(context.Context, *unstructured.UnstructuredList, ...interface{}) {
+		return func(_ context.Context, list *unstructured.UnstructuredList, _ ...interface{}) {
+			list.Items = pools
+		}
+	}
+
+	It("should return nothing when MachineConfigPool is not available", func() {
+		mockKubeClients.
+			EXPECT().
+			HasResource(mcpResource).
+			Return(false, nil)
+
+		pools, err := cluster.NewCluster(mockKubeClients).MachineConfigPools(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pools).To(BeEmpty())
+	})
+
+	It("should return an error when the MachineConfigPools cannot be listed", func() {
+		gomock.InOrder(
+			mockKubeClients.EXPECT().HasResource(mcpResource).Return(true, nil),
+			mockKubeClients.EXPECT().List(context.TODO(), mcpList).Return(randomError),
+		)
+
+		_, err := cluster.NewCluster(mockKubeClients).MachineConfigPools(context.TODO())
+		Expect(errors.Is(err, randomError)).To(BeTrue())
+	})
+
+	It("should skip the pools that have not rendered a MachineConfig yet", func() {
+		gomock.InOrder(
+			mockKubeClients.EXPECT().HasResource(mcpResource).Return(true, nil),
+			mockKubeClients.EXPECT().List(context.TODO(), mcpList).Do(listPools(pool("worker", "", false))),
+		)
+
+		pools, err := cluster.NewCluster(mockKubeClients).MachineConfigPools(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pools).To(BeEmpty())
+	})
+
+	It("should return the OS of the rendered MachineConfig of every pool", func() {
+		const (
+			masterImage = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:new"
+			workerImage = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:old"
+		)
+
+		renderedConfigs := map[string]*unstructured.Unstructured{
+			"rendered-master-new": {Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{
+						"machineconfiguration.openshift.io/generated-by-controller-version": "4.10.0",
+					},
+				},
+				"spec": map[string]interface{}{"osImageURL": masterImage},
+			}},
+			"rendered-worker-old": {Object: map[string]interface{}{
+				"spec": map[string]interface{}{"osImageURL": workerImage, "kernelType": "realtime"},
+			}},
+		}
+
+		gomock.InOrder(
+			mockKubeClients.EXPECT().HasResource(mcpResource).Return(true, nil),
+			mockKubeClients.
+				EXPECT().
+				List(context.TODO(), mcpList).
+				Do(listPools(pool("master", "rendered-master-new", false), pool("worker", "rendered-worker-old", true))),
+		)
+
+		mockKubeClients.
+			EXPECT().
+			Get(context.TODO(), gomock.Any(), mcObj).
+			Do(func(_ context.Context, key types.NamespacedName, mc *unstructured.Unstructured) {
+				Expect(mc.GetKind()).To(Equal("MachineConfig"))
+				Expect(renderedConfigs).To(HaveKey(key.Name))
+				mc.Object = renderedConfigs[key.Name].Object
+			}).
+			Times(2)
+
+		pools, err := cluster.NewCluster(mockKubeClients).MachineConfigPools(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pools).To(Equal(map[string]cluster.MachineConfigPool{
+			"master": {
+				OSImageURL:        masterImage,
+				RenderedConfig:    "rendered-master-new",
+				ControllerVersion: "4.10.0",
+				NodeSelector:      map[string]string{"node-role.kubernetes.io/master": ""},
+			},
+			"worker": {
+				OSImageURL:     workerImage,
+				RenderedConfig: "rendered-worker-old",
+				KernelType:     "realtime",
+				Paused:         true,
+				NodeSelector:   map[string]string{"node-role.kubernetes.io/worker": ""},
+			},
+		}))
+	})
+})
+
 var _ = Describe("cluster_OperatingSystem", func() {
 
 	It("should return an error when feature.node.kubernetes.io/system-os_release.ID is empty", func() {
@@ -338,3 +450,70 @@ var _ = Describe("cluster_OperatingSystem", func() {
 		Expect(o2).To(Equal("456.789"))
 	})
 })
+
+var _ = Describe("cluster_DefaultNodeSelector", func() {
+	nsn := types.NamespacedName{Name: "cluster"}
+
+	It("should return nothing when the Scheduler config is not available", func() {
+		mockKubeClients.
+			EXPECT().
+			HasResource(configv1.SchemeGroupVersion.WithResource("schedulers")).
+			Return(false, nil)
+
+		selector, err := cluster.NewCluster(mockKubeClients).DefaultNodeSelector(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector).To(BeEmpty())
+	})
+
+	It("should return nothing when the cluster Scheduler config does not exist", func() {
+		gomock.InOrder(
+			mockKubeClients.
+				EXPECT().
+				HasResource(configv1.SchemeGroupVersion.WithResource("schedulers")).
+				Return(true, nil),
+			mockKubeClients.
+				EXPECT().
+				Get(context.TODO(), nsn, &configv1.Scheduler{}).
+				Return(k8serrors.NewNotFound(configv1.Resource("schedulers"), "cluster")),
+		)
+
+		selector, err := cluster.NewCluster(mockKubeClients).DefaultNodeSelector(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector).To(BeEmpty())
+	})
+
+	It("should return an error when the cluster Scheduler config cannot be read", func() {
+		gomock.InOrder(
+			mockKubeClients.
+				EXPECT().
+				HasResource(configv1.SchemeGroupVersion.WithResource("schedulers")).
+				Return(true, nil),
+			mockKubeClients.
+				EXPECT().
+				Get(context.TODO(), nsn, &configv1.Scheduler{}).
+				Return(randomError),
+		)
+
+		_, err := cluster.NewCluster(mockKubeClients).DefaultNodeSelector(context.TODO())
+		Expect(errors.Is(err, randomError)).To(BeTrue())
+	})
+
+	It("should return the parsed default node selector", func() {
+		gomock.InOrder(
+			mockKubeClients.
+				EXPECT().
+				HasResource(configv1.SchemeGroupVersion.WithResource("schedulers")).
+				Return(true, nil),
+			mockKubeClients.
+				EXPECT().
+				Get(context.TODO(), nsn, &configv1.Scheduler{}).
+				Do(func(_ context.Context, _ types.NamespacedName, s *configv1.Scheduler) {
+					s.Spec.DefaultNodeSelector = "type=user-node,region=east"
+				}),
+		)
+
+		selector, err := cluster.NewCluster(mockKubeClients).DefaultNodeSelector(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector).To(Equal(map[string]string{"type": "user-node", "region": "east"}))
+	})
+})
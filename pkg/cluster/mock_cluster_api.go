@@ -35,6 +35,36 @@ func (m *MockCluster) EXPECT() *MockClusterMockRecorder {
 	return m.recorder
 }
 
+// DefaultNodeSelector mocks base method.
+func (m *MockCluster) DefaultNodeSelector(arg0 context.Context) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultNodeSelector", arg0)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DefaultNodeSelector indicates an expected call of DefaultNodeSelector.
+func (mr *MockClusterMockRecorder) DefaultNodeSelector(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultNodeSelector", reflect.TypeOf((*MockCluster)(nil).DefaultNodeSelector), arg0)
+}
+
+// MachineConfigPools mocks base method.
+func (m *MockCluster) MachineConfigPools(arg0 context.Context) (map[string]MachineConfigPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MachineConfigPools", arg0)
+	ret0, _ := ret[0].(map[string]MachineConfigPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MachineConfigPools indicates an expected call of MachineConfigPools.
+func (mr *MockClusterMockRecorder) MachineConfigPools(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MachineConfigPools", reflect.TypeOf((*MockCluster)(nil).MachineConfigPools), arg0)
+}
+
 // OSImageURL mocks base method.
 func (m *MockCluster) OSImageURL(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,150 @@
+package csi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// Sidecars are the images of the CSI sidecar containers the csi-driver state
+// runs next to the driver, as .Values.csiSidecars.
+type Sidecars struct {
+	Attacher            string `json:"attacher"`
+	NodeDriverRegistrar string `json:"nodeDriverRegistrar"`
+	Provisioner         string `json:"provisioner"`
+	Resizer             string `json:"resizer"`
+	Snapshotter         string `json:"snapshotter"`
+}
+
+const registry = "registry.k8s.io/sig-storage/"
+
+// releases are the sidecars tested with a Kubernetes minor version, see the
+// compatibility tables of https://kubernetes-csi.github.io/docs/sidecar-containers.html.
+var releases = []struct {
+	minor    uint
+	sidecars Sidecars
+}{
+	{19, Sidecars{"csi-attacher:v3.0.2", "csi-node-driver-registrar:v2.0.1", "csi-provisioner:v2.0.4", "csi-resizer:v1.0.1", "csi-snapshotter:v3.0.3"}},
+	{20, Sidecars{"csi-attacher:v3.1.0", "csi-node-driver-registrar:v2.1.0", "csi-provisioner:v2.1.0", "csi-resizer:v1.1.0", "csi-snapshotter:v4.0.0"}},
+	{21, Sidecars{"csi-attacher:v3.2.1", "csi-node-driver-registrar:v2.2.0", "csi-provisioner:v2.2.2", "csi-resizer:v1.2.0", "csi-snapshotter:v4.1.1"}},
+	{22, Sidecars{"csi-attacher:v3.3.0", "csi-node-driver-registrar:v2.3.0", "csi-provisioner:v3.0.0", "csi-resizer:v1.3.0", "csi-snapshotter:v4.2.1"}},
+	{23, Sidecars{"csi-attacher:v3.4.0", "csi-node-driver-registrar:v2.4.0", "csi-provisioner:v3.1.0", "csi-resizer:v1.4.0", "csi-snapshotter:v5.0.1"}},
+	{24, Sidecars{"csi-attacher:v3.5.0", "csi-node-driver-registrar:v2.5.1", "csi-provisioner:v3.2.1", "csi-resizer:v1.5.0", "csi-snapshotter:v6.0.1"}},
+}
+
+// KubernetesVersion returns the oldest kubelet version of the nodes, the
+// sidecars have to work with every one of them.
+func KubernetesVersion(nodeList *corev1.NodeList) (*version.Version, error) {
+	var oldest *version.Version
+
+	for _, node := range nodeList.Items {
+		kubelet := node.Status.NodeInfo.KubeletVersion
+		if kubelet == "" {
+			continue
+		}
+
+		v, err := version.ParseGeneric(kubelet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubelet version %s of node %s: %w", kubelet, node.GetName(), err)
+		}
+
+		if oldest == nil || v.LessThan(oldest) {
+			oldest = v
+		}
+	}
+
+	return oldest, nil
+}
+
+// SidecarsFor returns the sidecars for kubernetes overridden by the ones
+// pinned in set, the values of the SpecialResource, as csiSidecars. The
+// sidecars of the newest known release are used for a newer Kubernetes, there
+// are none for an older or unknown one. A pinned sidecar is rejected when it
+// is newer than the one Kubernetes was tested with, or of another major
+// version.
+func SidecarsFor(kubernetes *version.Version, set map[string]interface{}) (Sidecars, error) {
+	var (
+		sidecars Sidecars
+		tested   bool
+	)
+
+	if kubernetes != nil && kubernetes.Minor() >= releases[0].minor {
+		i := sort.Search(len(releases), func(i int) bool { return releases[i].minor > kubernetes.Minor() }) - 1
+		sidecars = releases[i].sidecars
+		tested = i < len(releases)-1 || releases[i].minor == kubernetes.Minor()
+	}
+
+	fields := []struct {
+		name  string
+		image *string
+	}{
+		{"attacher", &sidecars.Attacher},
+		{"nodeDriverRegistrar", &sidecars.NodeDriverRegistrar},
+		{"provisioner", &sidecars.Provisioner},
+		{"resizer", &sidecars.Resizer},
+		{"snapshotter", &sidecars.Snapshotter},
+	}
+
+	for _, field := range fields {
+		if *field.image != "" {
+			*field.image = registry + *field.image
+		}
+
+		pinned, found, err := unstructured.NestedString(set, "csiSidecars", field.name)
+		if err != nil {
+			return Sidecars{}, fmt.Errorf("invalid csiSidecars.%s: %w", field.name, err)
+		}
+		if !found || pinned == "" {
+			continue
+		}
+
+		if tested {
+			if err := compatible(pinned, *field.image); err != nil {
+				return Sidecars{}, fmt.Errorf("csiSidecars.%s is not compatible with Kubernetes %d.%d: %w",
+					field.name, kubernetes.Major(), kubernetes.Minor(), err)
+			}
+		}
+
+		*field.image = pinned
+	}
+
+	return sidecars, nil
+}
+
+// compatible returns an error unless the tag of pinned is a version of the
+// major version of the tested image, and not newer than it.
+func compatible(pinned string, tested string) error {
+	pinnedVersion, err := tagVersion(pinned)
+	if err != nil {
+		return err
+	}
+
+	testedVersion, err := tagVersion(tested)
+	if err != nil {
+		return err
+	}
+
+	if pinnedVersion.Major() != testedVersion.Major() || testedVersion.LessThan(pinnedVersion) {
+		return fmt.Errorf("%s is neither %s nor an older release of its major version", pinned, tested)
+	}
+
+	return nil
+}
+
+func tagVersion(image string) (*version.Version, error) {
+	tag, err := name.NewTag(image, name.StrictValidation)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not pinned to a version tag: %w", image, err)
+	}
+
+	v, err := version.ParseGeneric(tag.TagStr())
+	if err != nil {
+		return nil, fmt.Errorf("%s is not pinned to a version tag: %w", image, err)
+	}
+
+	return v, nil
+}
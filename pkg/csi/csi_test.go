@@ -0,0 +1,98 @@
+package csi_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/csi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestCSI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CSI Suite")
+}
+
+func pin(sidecar, image string) map[string]interface{} {
+	return map[string]interface{}{"csiSidecars": map[string]interface{}{sidecar: image}}
+}
+
+var _ = Describe("KubernetesVersion", func() {
+	It("should return the oldest kubelet version", func() {
+		nodeList := &corev1.NodeList{}
+		for _, kubelet := range []string{"v1.23.3+e419edf", "v1.22.5+5c84e52", ""} {
+			node := corev1.Node{}
+			node.Status.NodeInfo.KubeletVersion = kubelet
+			nodeList.Items = append(nodeList.Items, node)
+		}
+
+		v, err := csi.KubernetesVersion(nodeList)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v.String()).To(Equal("1.22.5"))
+	})
+
+	It("should return nil without nodes", func() {
+		Expect(csi.KubernetesVersion(&corev1.NodeList{})).To(BeNil())
+	})
+
+	It("should reject an invalid kubelet version", func() {
+		node := corev1.Node{}
+		node.Status.NodeInfo.KubeletVersion = "unknown"
+
+		_, err := csi.KubernetesVersion(&corev1.NodeList{Items: []corev1.Node{node}})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("SidecarsFor", func() {
+	It("should return the sidecars tested with the Kubernetes version", func() {
+		sidecars, err := csi.SidecarsFor(version.MustParseGeneric("1.22.5"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sidecars).To(Equal(csi.Sidecars{
+			Attacher:            "registry.k8s.io/sig-storage/csi-attacher:v3.3.0",
+			NodeDriverRegistrar: "registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.3.0",
+			Provisioner:         "registry.k8s.io/sig-storage/csi-provisioner:v3.0.0",
+			Resizer:             "registry.k8s.io/sig-storage/csi-resizer:v1.3.0",
+			Snapshotter:         "registry.k8s.io/sig-storage/csi-snapshotter:v4.2.1",
+		}))
+	})
+
+	It("should return the newest sidecars for a newer Kubernetes", func() {
+		sidecars, err := csi.SidecarsFor(version.MustParseGeneric("1.30.0"), pin("provisioner", "quay.io/acme/csi-provisioner:v5.0.1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sidecars.Attacher).To(Equal("registry.k8s.io/sig-storage/csi-attacher:v3.5.0"))
+		Expect(sidecars.Provisioner).To(Equal("quay.io/acme/csi-provisioner:v5.0.1"))
+	})
+
+	It("should only return the pinned sidecars for an unknown Kubernetes", func() {
+		sidecars, err := csi.SidecarsFor(nil, pin("resizer", "quay.io/acme/csi-resizer:v1.0.0"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sidecars).To(Equal(csi.Sidecars{Resizer: "quay.io/acme/csi-resizer:v1.0.0"}))
+	})
+
+	DescribeTable("should validate the pinned sidecars",
+		func(sidecar, image string, valid bool) {
+			sidecars, err := csi.SidecarsFor(version.MustParseGeneric("1.22.5"), pin(sidecar, image))
+			if !valid {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sidecars.Provisioner).To(Equal(image))
+		},
+		Entry("the tested release", "provisioner", "registry.k8s.io/sig-storage/csi-provisioner:v3.0.0", true),
+		Entry("the tested release from a mirror", "provisioner", "mirror.acme.com/sig-storage/csi-provisioner:v3.0.0", true),
+		Entry("a newer release", "provisioner", "registry.k8s.io/sig-storage/csi-provisioner:v3.2.1", false),
+		Entry("an older major release", "provisioner", "registry.k8s.io/sig-storage/csi-provisioner:v2.2.2", false),
+		Entry("no version tag", "provisioner", "registry.k8s.io/sig-storage/csi-provisioner:latest", false),
+		Entry("a digest", "provisioner", "registry.k8s.io/sig-storage/csi-provisioner@sha256:4ff8f292fc4f65e812c99b023204eff84d6737ac42dcd198e4792213a1471873", false),
+	)
+
+	It("should reject an invalid pin", func() {
+		_, err := csi.SidecarsFor(version.MustParseGeneric("1.22.5"), map[string]interface{}{"csiSidecars": map[string]interface{}{"attacher": 3}})
+		Expect(err).To(HaveOccurred())
+	})
+})
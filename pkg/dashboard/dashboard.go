@@ -0,0 +1,41 @@
+package dashboard
+
+import (
+	_ "embed"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/states"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// StateName is the name of the built-in state, as listed in
+// spec.disableStates.
+const StateName = "device-dashboard"
+
+// stateTemplate is the path of the built-in state in the chart, it runs after
+// the states of the chart.
+const stateTemplate = "templates/9999-" + StateName + ".yaml"
+
+// state renders an OpenShift console dashboard of the metrics listed in
+// .Values.deviceDashboard.metrics, and nothing without them.
+//
+//go:embed state.yaml
+var state []byte
+
+// WithState returns a copy of ch with the built-in device-dashboard state if
+// its values or set, the values of the SpecialResource, list metrics. ch is
+// returned as is without metrics or if it already has a state of that name, it
+// is never modified, it may be shared with other reconciles.
+func WithState(ch *chart.Chart, set map[string]interface{}) *chart.Chart {
+	if !hasMetrics(ch.Values) && !hasMetrics(set) {
+		return ch
+	}
+
+	return states.Add(ch, stateTemplate, state)
+}
+
+func hasMetrics(values map[string]interface{}) bool {
+	metrics, _, _ := unstructured.NestedFieldNoCopy(values, "deviceDashboard", "metrics")
+	list, ok := metrics.([]interface{})
+	return ok && len(list) > 0
+}
@@ -0,0 +1,123 @@
+package dashboard_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/dashboard"
+	"github.com/openshift-psap/special-resource-operator/pkg/states"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestDashboard(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Dashboard Suite")
+}
+
+var metrics = map[string]interface{}{
+	"deviceDashboard": map[string]interface{}{
+		"title": "Simple KMOD",
+		"metrics": []interface{}{
+			map[string]interface{}{"name": "simple_kmod_loaded", "title": "Loaded modules"},
+			map[string]interface{}{"name": "simple_kmod_errors_total", "query": "rate(simple_kmod_errors_total[5m])", "unit": "ops"},
+		},
+	},
+}
+
+func newChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "simple-kmod", Version: "0.0.1", APIVersion: chart.APIVersionV2},
+		Templates: []*chart.File{{Name: "templates/0000-driver-container.yaml"}},
+	}
+}
+
+// render renders the dashboard state of ch on platform.
+func render(ch *chart.Chart, set map[string]interface{}, platform string) string {
+	values := chartutil.CoalesceTables(map[string]interface{}{
+		"platform":        platform,
+		"groupName":       map[string]interface{}{"deviceDashboard": "device-dashboard"},
+		"specialresource": map[string]interface{}{"metadata": map[string]interface{}{"name": "simple-kmod"}},
+	}, set)
+
+	manifest, err := states.Render(ch, values, "templates/9999-device-dashboard.yaml")
+	Expect(err).NotTo(HaveOccurred())
+
+	return manifest
+}
+
+var _ = Describe("WithState", func() {
+	It("should not add the state without metrics", func() {
+		ch := newChart()
+		Expect(dashboard.WithState(ch, nil)).To(BeIdenticalTo(ch))
+	})
+
+	It("should not replace the state of the chart", func() {
+		ch := newChart()
+		ch.Templates = append(ch.Templates, &chart.File{Name: "templates/5000-device-dashboard.yaml"})
+
+		Expect(dashboard.WithState(ch, metrics)).To(BeIdenticalTo(ch))
+	})
+
+	It("should add the state to a copy of the chart", func() {
+		ch := newChart()
+
+		withState := dashboard.WithState(ch, metrics)
+
+		Expect(ch.Templates).To(HaveLen(1))
+		Expect(withState.Templates).To(HaveLen(2))
+		Expect(states.Name(withState.Templates[1].Name)).To(Equal(dashboard.StateName))
+	})
+
+	It("should add the state for the metrics of the chart values", func() {
+		ch := newChart()
+		ch.Values = metrics
+
+		Expect(dashboard.WithState(ch, nil).Templates).To(HaveLen(2))
+	})
+
+	It("should render a console dashboard of the metrics", func() {
+		manifest := render(dashboard.WithState(newChart(), metrics), metrics, "OCP")
+
+		cm := corev1.ConfigMap{}
+		Expect(yaml.UnmarshalStrict([]byte(manifest), &cm)).To(Succeed())
+		Expect(cm.Name).To(Equal("simple-kmod-device-dashboard"))
+		Expect(cm.Namespace).To(Equal("openshift-config-managed"))
+		Expect(cm.Labels).To(HaveKeyWithValue("console.openshift.io/dashboard", "true"))
+
+		board := struct {
+			Title  string
+			Panels []struct {
+				Title   string
+				Targets []struct {
+					Expr         string
+					LegendFormat string
+				}
+				Yaxes []struct {
+					Format string
+				}
+			}
+		}{}
+		Expect(json.Unmarshal([]byte(cm.Data["simple-kmod-device-dashboard.json"]), &board)).To(Succeed())
+
+		Expect(board.Title).To(Equal("Simple KMOD"))
+		Expect(board.Panels).To(HaveLen(2))
+		Expect(board.Panels[0].Title).To(Equal("Loaded modules"))
+		Expect(board.Panels[0].Targets[0].Expr).To(Equal("simple_kmod_loaded"))
+		Expect(board.Panels[0].Targets[0].LegendFormat).To(Equal("{{instance}}"))
+		Expect(board.Panels[0].Yaxes[0].Format).To(Equal("short"))
+		Expect(board.Panels[1].Title).To(Equal("simple_kmod_errors_total"))
+		Expect(board.Panels[1].Targets[0].Expr).To(Equal("rate(simple_kmod_errors_total[5m])"))
+		Expect(board.Panels[1].Yaxes[0].Format).To(Equal("ops"))
+	})
+
+	It("should render nothing on vanilla Kubernetes", func() {
+		manifest := render(dashboard.WithState(newChart(), metrics), metrics, "K8S")
+		Expect(strings.TrimSpace(manifest)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,164 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const DumpPath = "/debug/sro"
+
+//go:generate mockgen -source=debug.go -package=debug -destination=mock_debug_api.go
+
+// Debug keeps what the controller is doing and serves it along with pprof.
+type Debug interface {
+	Forget(specialResource string)
+	Handler() http.Handler
+	SetRuntimeInformation(specialResource string, info interface{})
+	SetState(specialResource, state string)
+	SetWatches(kinds []string)
+}
+
+type specialResourceDump struct {
+	State              string      `json:"state"`
+	Since              time.Time   `json:"since"`
+	RuntimeInformation interface{} `json:"runtimeInformation,omitempty"`
+}
+
+type dump struct {
+	Watches          []string                        `json:"watches"`
+	SpecialResources map[string]*specialResourceDump `json:"specialResources"`
+}
+
+type debug struct {
+	mu               sync.Mutex
+	watches          []string
+	specialResources map[string]*specialResourceDump
+}
+
+func New() Debug {
+	return &debug{
+		specialResources: make(map[string]*specialResourceDump),
+	}
+}
+
+func (d *debug) get(specialResource string) *specialResourceDump {
+	sr, ok := d.specialResources[specialResource]
+	if !ok {
+		sr = &specialResourceDump{}
+		d.specialResources[specialResource] = sr
+	}
+	return sr
+}
+
+// Forget drops a deleted SpecialResource.
+func (d *debug) Forget(specialResource string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.specialResources, specialResource)
+}
+
+// SetRuntimeInformation keeps the cluster information the last reconcile of
+// the SpecialResource worked with.
+func (d *debug) SetRuntimeInformation(specialResource string, info interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.get(specialResource).RuntimeInformation = info
+}
+
+// SetState records the state the SpecialResource is working on and since when,
+// a reconcile stuck in a state keeps an old timestamp.
+func (d *debug) SetState(specialResource, state string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sr := d.get(specialResource)
+	if sr.State != state {
+		sr.State = state
+		sr.Since = time.Now()
+	}
+}
+
+func (d *debug) SetWatches(kinds []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.watches = append([]string{}, kinds...)
+	sort.Strings(d.watches)
+}
+
+func (d *debug) serveDump(w http.ResponseWriter, _ *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(dump{Watches: d.watches, SpecialResources: d.specialResources}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Handler serves the pprof profiles under /debug/pprof/ and the dump of the
+// controller under DumpPath.
+func (d *debug) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc(DumpPath, d.serveDump)
+
+	return mux
+}
+
+type server struct {
+	log    logr.Logger
+	server *http.Server
+}
+
+// NewServer returns a Runnable serving handler on addr. It runs on every
+// replica, not only on the leader.
+func NewServer(addr string, handler http.Handler) manager.Runnable {
+	return &server{
+		log:    zap.New(zap.UseDevMode(true)).WithName(utils.Print("debug", utils.Brown)),
+		server: &http.Server{Addr: addr, Handler: handler},
+	}
+}
+
+func (s *server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *server) Start(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		if err := s.server.Shutdown(context.Background()); err != nil {
+			s.log.Error(err, "could not shut down the debug server")
+		}
+	}()
+
+	s.log.Info("Serving debug endpoints", "addr", s.server.Addr)
+
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
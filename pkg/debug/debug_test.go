@@ -0,0 +1,55 @@
+package debug_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/debug"
+)
+
+func TestDebug(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Debug Suite")
+}
+
+var _ = Describe("Handler", func() {
+	get := func(d debug.Debug, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		d.Handler().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec
+	}
+
+	It("should dump the watches and the SpecialResources", func() {
+		d := debug.New()
+		d.SetWatches([]string{"Pod", "DaemonSet"})
+		d.SetState("simple-kmod", "templates/0000-buildconfig.yaml")
+		d.SetRuntimeInformation("simple-kmod", map[string]string{"kernelFullVersion": "4.18.0-305.el8.x86_64"})
+		d.SetState("other", "templates/0001-driver-container.yaml")
+		d.Forget("other")
+
+		rec := get(d, debug.DumpPath)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var dump struct {
+			Watches          []string
+			SpecialResources map[string]struct {
+				State              string
+				RuntimeInformation map[string]string
+			}
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &dump)).To(Succeed())
+
+		Expect(dump.Watches).To(Equal([]string{"DaemonSet", "Pod"}))
+		Expect(dump.SpecialResources).To(HaveLen(1))
+		Expect(dump.SpecialResources["simple-kmod"].State).To(Equal("templates/0000-buildconfig.yaml"))
+		Expect(dump.SpecialResources["simple-kmod"].RuntimeInformation).To(HaveKeyWithValue("kernelFullVersion", "4.18.0-305.el8.x86_64"))
+	})
+
+	It("should serve the pprof index", func() {
+		Expect(get(debug.New(), "/debug/pprof/").Code).To(Equal(http.StatusOK))
+	})
+})
@@ -0,0 +1,132 @@
+package dtk
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ReleaseFile is the path of the metadata in the Driver Toolkit image.
+const ReleaseFile = "etc/driver-toolkit-release.json"
+
+// Schema versions of the metadata, each one adds fields to the previous one.
+const (
+	// SchemaKernel only has the kernel and the RHEL versions.
+	SchemaKernel = 1
+	// SchemaRTKernel adds the version of the real-time kernel.
+	SchemaRTKernel = 2
+	// SchemaOCP adds the version of OpenShift.
+	SchemaOCP = 3
+)
+
+// ErrNotDriverToolkit is returned for a layer that has no metadata, it is not
+// the one of a Driver Toolkit image.
+var ErrNotDriverToolkit = errors.New("not a Driver Toolkit layer: missing " + ReleaseFile)
+
+// CorruptError is metadata that cannot be read, or lacks a required field.
+type CorruptError struct {
+	Reason string
+	Err    error
+}
+
+func (e *CorruptError) Error() string {
+	if e.Err == nil {
+		return "corrupt Driver Toolkit metadata: " + e.Reason
+	}
+	return fmt.Sprintf("corrupt Driver Toolkit metadata: %s: %v", e.Reason, e.Err)
+}
+
+func (e *CorruptError) Unwrap() error {
+	return e.Err
+}
+
+// Release is the metadata of a Driver Toolkit image.
+type Release struct {
+	// SchemaVersion is the version of the format the metadata was read from.
+	SchemaVersion int
+	// KernelFullVersion is the version of the kernel the image builds for.
+	KernelFullVersion string
+	// RTKernelFullVersion is the version of the real-time kernel, empty
+	// before SchemaRTKernel.
+	RTKernelFullVersion string
+	// OSVersion is the version of RHEL the image is based on, e.g. 8.4.
+	OSVersion string
+	// OCPVersion is the version of OpenShift the image was released with,
+	// empty before SchemaOCP.
+	OCPVersion string
+}
+
+// raw is the metadata as written in the image, fields missing from an older
+// schema are nil.
+type raw struct {
+	KernelVersion   *string `json:"KERNEL_VERSION"`
+	RTKernelVersion *string `json:"RT_KERNEL_VERSION"`
+	RHELVersion     *string `json:"RHEL_VERSION"`
+	OCPVersion      *string `json:"OCP_VERSION"`
+}
+
+// Parse parses the metadata of any schema version.
+func Parse(data []byte) (Release, error) {
+	var r raw
+
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Release{}, &CorruptError{Reason: "invalid JSON", Err: err}
+	}
+
+	if r.KernelVersion == nil || *r.KernelVersion == "" {
+		return Release{}, &CorruptError{Reason: "missing KERNEL_VERSION"}
+	}
+
+	if r.RHELVersion == nil || *r.RHELVersion == "" {
+		return Release{}, &CorruptError{Reason: "missing RHEL_VERSION"}
+	}
+
+	rel := Release{
+		SchemaVersion:     SchemaKernel,
+		KernelFullVersion: *r.KernelVersion,
+		OSVersion:         *r.RHELVersion,
+	}
+
+	if r.RTKernelVersion != nil {
+		rel.SchemaVersion = SchemaRTKernel
+		rel.RTKernelFullVersion = *r.RTKernelVersion
+	}
+
+	if r.OCPVersion != nil {
+		rel.SchemaVersion = SchemaOCP
+		rel.OCPVersion = *r.OCPVersion
+	}
+
+	return rel, nil
+}
+
+// Read parses the metadata from the tar archive of a layer. It returns
+// ErrNotDriverToolkit if the archive does not hold ReleaseFile.
+func Read(archive io.Reader) (Release, error) {
+	tr := tar.NewReader(archive)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return Release{}, ErrNotDriverToolkit
+		} else if err != nil {
+			return Release{}, fmt.Errorf("could not read the layer: %w", err)
+		}
+
+		// Some layers are archived with absolute or ./ relative paths
+		if strings.TrimPrefix(path.Clean(header.Name), "/") != ReleaseFile {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return Release{}, &CorruptError{Reason: "could not read " + ReleaseFile, Err: err}
+		}
+
+		return Parse(data)
+	}
+}
@@ -0,0 +1,95 @@
+package dtk_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/dtk"
+)
+
+func TestDTK(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "DTK Suite")
+}
+
+var _ = Describe("Parse", func() {
+	DescribeTable("should parse every schema version",
+		func(data string, expected dtk.Release) {
+			rel, err := dtk.Parse([]byte(data))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rel).To(Equal(expected))
+		},
+		Entry("kernel only",
+			`{"KERNEL_VERSION": "4.18.0-240.el8.x86_64", "RHEL_VERSION": "8.3"}`,
+			dtk.Release{SchemaVersion: dtk.SchemaKernel, KernelFullVersion: "4.18.0-240.el8.x86_64", OSVersion: "8.3"}),
+		Entry("real-time kernel",
+			`{"KERNEL_VERSION": "4.18.0-305.el8.x86_64", "RT_KERNEL_VERSION": "4.18.0-305.rt7.72.el8.x86_64", "RHEL_VERSION": "8.4"}`,
+			dtk.Release{
+				SchemaVersion:       dtk.SchemaRTKernel,
+				KernelFullVersion:   "4.18.0-305.el8.x86_64",
+				RTKernelFullVersion: "4.18.0-305.rt7.72.el8.x86_64",
+				OSVersion:           "8.4",
+			}),
+		Entry("OpenShift version",
+			`{"KERNEL_VERSION": "4.18.0-372.el8.x86_64", "RT_KERNEL_VERSION": "4.18.0-372.rt7.129.el8.x86_64", "RHEL_VERSION": "8.6", "OCP_VERSION": "4.11"}`,
+			dtk.Release{
+				SchemaVersion:       dtk.SchemaOCP,
+				KernelFullVersion:   "4.18.0-372.el8.x86_64",
+				RTKernelFullVersion: "4.18.0-372.rt7.129.el8.x86_64",
+				OSVersion:           "8.6",
+				OCPVersion:          "4.11",
+			}),
+	)
+
+	DescribeTable("should report corrupt metadata",
+		func(data string) {
+			_, err := dtk.Parse([]byte(data))
+
+			var corrupt *dtk.CorruptError
+			Expect(errors.As(err, &corrupt)).To(BeTrue())
+		},
+		Entry("invalid JSON", `{"KERNEL_VERSION": `),
+		Entry("kernel version of the wrong type", `{"KERNEL_VERSION": 4, "RHEL_VERSION": "8.4"}`),
+		Entry("no kernel version", `{"RHEL_VERSION": "8.4"}`),
+		Entry("no RHEL version", `{"KERNEL_VERSION": "4.18.0-305.el8.x86_64"}`),
+	)
+})
+
+var _ = Describe("Read", func() {
+	archive := func(files map[string]string) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for name, content := range files {
+			Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))})).To(Succeed())
+			_, err := tw.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+
+		return &buf
+	}
+
+	It("should read the metadata of the layer", func() {
+		rel, err := dtk.Read(archive(map[string]string{
+			"./etc/driver-toolkit-release.json": `{"KERNEL_VERSION": "4.18.0-240.el8.x86_64", "RHEL_VERSION": "8.3"}`,
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rel.KernelFullVersion).To(Equal("4.18.0-240.el8.x86_64"))
+	})
+
+	It("should tell a layer that is not the one of a Driver Toolkit", func() {
+		_, err := dtk.Read(archive(map[string]string{"etc/os-release": "ID=rhel"}))
+		Expect(err).To(MatchError(dtk.ErrNotDriverToolkit))
+	})
+
+	It("should tell corrupt metadata", func() {
+		_, err := dtk.Read(archive(map[string]string{"etc/driver-toolkit-release.json": "{"}))
+
+		var corrupt *dtk.CorruptError
+		Expect(errors.As(err, &corrupt)).To(BeTrue())
+	})
+})
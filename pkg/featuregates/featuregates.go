@@ -0,0 +1,152 @@
+package featuregates
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+type Gate string
+
+// Experimental behaviors, all of them are disabled per default.
+const (
+	ServerSideApply     Gate = "ServerSideApply"
+	GracefulUnload      Gate = "GracefulUnload"
+	StateHashes         Gate = "StateHashes"
+	PreApplyPolicyCheck Gate = "PreApplyPolicyCheck"
+)
+
+// Annotation overrides the gates of the operator for a single SpecialResource,
+// with the same syntax as the --feature-gates flag.
+const Annotation = "specialresource.openshift.io/feature-gates"
+
+var defaults = map[Gate]bool{
+	ServerSideApply:     false,
+	GracefulUnload:      false,
+	StateHashes:         false,
+	PreApplyPolicyCheck: false,
+}
+
+//go:generate mockgen -source=featuregates.go -package=featuregates -destination=mock_featuregates_api.go
+
+type FeatureGates interface {
+	Enabled(gate Gate, obj metav1.Object) bool
+	Report(obj metav1.Object)
+}
+
+type featureGates struct {
+	log     logr.Logger
+	metrics metrics.Metrics
+	gates   map[Gate]bool
+}
+
+// New returns the feature gates of the operator, spec is a comma separated
+// list of gate=bool pairs, e.g. "StateHashes=true,ServerSideApply=false".
+func New(spec string, metrics metrics.Metrics) (FeatureGates, error) {
+	gates := make(map[Gate]bool, len(defaults))
+	for gate, enabled := range defaults {
+		gates[gate] = enabled
+	}
+
+	overrides, err := parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	for gate, enabled := range overrides {
+		gates[gate] = enabled
+	}
+
+	fg := &featureGates{
+		log:     zap.New(zap.UseDevMode(true)).WithName(utils.Print("featuregates", utils.Green)),
+		metrics: metrics,
+		gates:   gates,
+	}
+
+	fg.Report(nil)
+
+	return fg, nil
+}
+
+func parse(spec string) (map[Gate]bool, error) {
+	gates := make(map[Gate]bool)
+
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("feature gate %q is not of the form gate=bool", pair)
+		}
+
+		gate := Gate(strings.TrimSpace(kv[0]))
+		if _, ok := defaults[gate]; !ok {
+			return nil, fmt.Errorf("unknown feature gate %q", gate)
+		}
+
+		enabled, err := strconv.ParseBool(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of feature gate %q: %w", gate, err)
+		}
+
+		gates[gate] = enabled
+	}
+
+	return gates, nil
+}
+
+// Enabled returns whether gate is enabled for obj. The annotation of obj takes
+// precedence over the gates of the operator; obj may be nil.
+func (f *featureGates) Enabled(gate Gate, obj metav1.Object) bool {
+	enabled := f.gates[gate]
+
+	if obj == nil {
+		return enabled
+	}
+
+	spec, ok := obj.GetAnnotations()[Annotation]
+	if !ok {
+		return enabled
+	}
+
+	overrides, err := parse(spec)
+	if err != nil {
+		f.log.Info("Ignoring invalid feature gates annotation", "name", obj.GetName(), "error", err)
+		return enabled
+	}
+
+	if override, ok := overrides[gate]; ok {
+		return override
+	}
+
+	return enabled
+}
+
+// Report exports the state of the gates for obj as a metric, the gates of the
+// operator if obj is nil.
+func (f *featureGates) Report(obj metav1.Object) {
+	name := ""
+	if obj != nil {
+		name = obj.GetName()
+	}
+
+	gates := make([]string, 0, len(defaults))
+	for gate := range defaults {
+		gates = append(gates, string(gate))
+	}
+	sort.Strings(gates)
+
+	for _, gate := range gates {
+		f.metrics.SetFeatureGate(name, gate, f.Enabled(Gate(gate), obj))
+	}
+}
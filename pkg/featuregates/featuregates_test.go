@@ -0,0 +1,98 @@
+package featuregates_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/featuregates"
+	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	ctrl        *gomock.Controller
+	mockMetrics *metrics.MockMetrics
+)
+
+func TestFeatureGates(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockMetrics = metrics.NewMockMetrics(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "FeatureGates Suite")
+}
+
+var _ = Describe("New", func() {
+	It("should disable all gates per default and report them", func() {
+		mockMetrics.EXPECT().SetFeatureGate("", gomock.Any(), false).Times(4)
+
+		fg, err := featuregates.New("", mockMetrics)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fg.Enabled(featuregates.StateHashes, nil)).To(BeFalse())
+	})
+
+	It("should enable the gates of the spec", func() {
+		mockMetrics.EXPECT().SetFeatureGate("", string(featuregates.GracefulUnload), true)
+		mockMetrics.EXPECT().SetFeatureGate("", gomock.Any(), false).Times(3)
+
+		fg, err := featuregates.New(" GracefulUnload=true, ServerSideApply=false", mockMetrics)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fg.Enabled(featuregates.GracefulUnload, nil)).To(BeTrue())
+		Expect(fg.Enabled(featuregates.ServerSideApply, nil)).To(BeFalse())
+	})
+
+	DescribeTable("should reject invalid specs",
+		func(spec string) {
+			_, err := featuregates.New(spec, mockMetrics)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unknown gate", "Unknown=true"),
+		Entry("missing value", "StateHashes"),
+		Entry("invalid value", "StateHashes=maybe"),
+	)
+})
+
+var _ = Describe("Enabled", func() {
+	var fg featuregates.FeatureGates
+
+	BeforeEach(func() {
+		mockMetrics.EXPECT().SetFeatureGate(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+		var err error
+		fg, err = featuregates.New("StateHashes=true", mockMetrics)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	sr := func(annotation string) *v1beta1.SpecialResource {
+		return &v1beta1.SpecialResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test",
+				Annotations: map[string]string{featuregates.Annotation: annotation},
+			},
+		}
+	}
+
+	It("should let the annotation override the operator", func() {
+		obj := sr("StateHashes=false,ServerSideApply=true")
+
+		Expect(fg.Enabled(featuregates.StateHashes, obj)).To(BeFalse())
+		Expect(fg.Enabled(featuregates.ServerSideApply, obj)).To(BeTrue())
+		Expect(fg.Enabled(featuregates.GracefulUnload, obj)).To(BeFalse())
+	})
+
+	It("should ignore an invalid annotation", func() {
+		Expect(fg.Enabled(featuregates.StateHashes, sr("StateHashes=maybe"))).To(BeTrue())
+	})
+})
@@ -0,0 +1,41 @@
+package filter
+
+import (
+	configv1 "github.com/openshift/api/config/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// UpgradeChanged returns the predicates of the ClusterVersion watch: only an
+// upgrade that starts, i.e. a new entry in the history, or that completes is
+// of interest.
+func UpgradeChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return latestUpdate(e.ObjectOld) != latestUpdate(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// latestUpdate returns the newest entry of the history of a ClusterVersion,
+// the history is sorted from the newest to the oldest.
+func latestUpdate(obj client.Object) configv1.UpdateHistory {
+	cv, ok := obj.(*configv1.ClusterVersion)
+	if !ok || len(cv.Status.History) == 0 {
+		return configv1.UpdateHistory{}
+	}
+
+	latest := cv.Status.History[0]
+
+	return configv1.UpdateHistory{State: latest.State, Version: latest.Version, Image: latest.Image}
+}
@@ -2,18 +2,24 @@ package filter
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"reflect"
-	"strings"
+	"sync/atomic"
 
 	"github.com/go-logr/logr"
 	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
 	"github.com/openshift-psap/special-resource-operator/pkg/lifecycle"
+	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
 	"github.com/openshift-psap/special-resource-operator/pkg/storage"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
 	operatorv1 "github.com/openshift/api/operator/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -22,8 +28,8 @@ import (
 )
 
 const (
-	Kind       = "SpecialResource"
-	OwnedLabel = "specialresource.openshift.io/owned"
+	Kind       = ownership.KindSpecialResource
+	OwnedLabel = ownership.SpecialResourceOwnedLabel
 )
 
 type Filter interface {
@@ -31,26 +37,39 @@ type Filter interface {
 	GetMode() string
 }
 
-func NewFilter(lifecycle lifecycle.Lifecycle, storage storage.Storage, kernelData kernel.KernelData) Filter {
+// NewFilter returns the predicates of a single controller. kind is the Kind of
+// the custom resource the controller reconciles and ownedLabel the label it sets
+// on the objects it creates, so that each controller only reacts to its own objects.
+func NewFilter(kind, ownedLabel string, scheme *runtime.Scheme, lifecycle lifecycle.Lifecycle, storage storage.Storage, kernelData kernel.KernelData, metricsClient metrics.Metrics) Filter {
 	return &filter{
 		log:        zap.New(zap.UseDevMode(true)).WithName(utils.Print("filter", utils.Purple)),
+		kind:       kind,
+		ownedLabel: ownedLabel,
+		scheme:     scheme,
 		lifecycle:  lifecycle,
 		storage:    storage,
 		kernelData: kernelData,
+		metrics:    metricsClient,
 	}
 }
 
 type filter struct {
 	log        logr.Logger
+	kind       string
+	ownedLabel string
+	scheme     *runtime.Scheme
 	lifecycle  lifecycle.Lifecycle
 	storage    storage.Storage
 	kernelData kernel.KernelData
+	metrics    metrics.Metrics
 
-	mode string
+	// mode is the type of the last event seen, predicates run concurrently
+	mode atomic.Value
 }
 
 func (f *filter) GetMode() string {
-	return f.mode
+	mode, _ := f.mode.Load().(string)
+	return mode
 }
 
 func (f *filter) isSpecialResourceUnmanaged(obj client.Object) bool {
@@ -61,39 +80,24 @@ func (f *filter) isSpecialResourceUnmanaged(obj client.Object) bool {
 	return sr.Spec.ManagementState == operatorv1.Unmanaged
 }
 
-func (f *filter) isSpecialResource(obj client.Object) bool {
+// isSpecialResource returns true if obj is of the kind the controller
+// reconciles, in any version of the sro.openshift.io API group. Typed objects,
+// e.g. a newly created SpecialResource, do not always have their GVK set, it
+// is then looked up in the scheme.
+func (f *filter) isSpecialResource(mode string, obj client.Object) bool {
 
-	kind := obj.GetObjectKind().GroupVersionKind().Kind
-
-	if kind == Kind {
-		f.log.Info(f.mode+" IsSpecialResource (sroGVK)", "Name", obj.GetName(), "Type", reflect.TypeOf(obj).String())
-		return true
-	}
-
-	t := reflect.TypeOf(obj).String()
-
-	if strings.Contains(t, Kind) {
-		f.log.Info(f.mode+" IsSpecialResource (reflect)", "Name", obj.GetName(), "Type", reflect.TypeOf(obj).String())
-		return true
-
-	}
+	gvks := []schema.GroupVersionKind{obj.GetObjectKind().GroupVersionKind()}
 
-	// If SRO owns the resource then it cannot be a SpecialResource
-	if f.owned(obj) {
-		return false
+	if gvks[0].Empty() {
+		var err error
+		if gvks, _, err = f.scheme.ObjectKinds(obj); err != nil {
+			return false
+		}
 	}
 
-	// We need this because a newly created SpecialResource will not yet
-	// have a GVK
-	selfLink := obj.GetSelfLink()
-	if strings.Contains(selfLink, "/apis/sro.openshift.io/v") {
-		f.log.Info(f.mode+" IsSpecialResource (selflink)", "Name", obj.GetName(), "Type", reflect.TypeOf(obj).String())
-		return true
-	}
-	if kind == "" {
-		objstr := fmt.Sprintf("%+v", obj)
-		if strings.Contains(objstr, "sro.openshift.io/v") {
-			f.log.Info(f.mode+" IsSpecialResource (contains)", "Name", obj.GetName(), "Type", reflect.TypeOf(obj).String())
+	for _, gvk := range gvks {
+		if gvk.Kind == f.kind && gvk.Group == v1beta1.GroupVersion.Group {
+			f.log.Info(mode+" IsSpecialResource", "Name", obj.GetName(), "GVK", gvk.String())
 			return true
 		}
 	}
@@ -101,21 +105,29 @@ func (f *filter) isSpecialResource(obj client.Object) bool {
 	return false
 }
 
-func (f *filter) owned(obj client.Object) bool {
+func (f *filter) owned(mode string, obj client.Object) bool {
 
 	for _, owner := range obj.GetOwnerReferences() {
-		if owner.Kind == Kind {
-			f.log.Info(f.mode+" Owned (sroGVK)", "Name", obj.GetName(),
+		if owner.Kind == f.kind && inGroup(owner.APIVersion) {
+			f.log.Info(mode+" Owned (sroGVK)", "Name", obj.GetName(),
 				"Type", reflect.TypeOf(obj).String())
 			return true
 		}
 	}
 
+	// Namespaced objects carry a controller ownerReference, garbage collection
+	// relies on it so we do as well. The label is only honored for cluster-scoped
+	// objects and for Pods, which are owned by a workload and only inherit the
+	// label from its template.
+	if _, isPod := obj.(*corev1.Pod); obj.GetNamespace() != "" && !isPod {
+		return false
+	}
+
 	var labels map[string]string
 
 	if labels = obj.GetLabels(); labels != nil {
-		if _, found := labels[OwnedLabel]; found {
-			f.log.Info(f.mode+" Owned (label)", "Name", obj.GetName(),
+		if _, found := labels[f.ownedLabel]; found {
+			f.log.Info(mode+" Owned (label)", "Name", obj.GetName(),
 				"Type", reflect.TypeOf(obj).String())
 			return true
 		}
@@ -123,20 +135,89 @@ func (f *filter) owned(obj client.Object) bool {
 	return false
 }
 
+// inGroup returns true if apiVersion is of the sro.openshift.io API group,
+// other operators may define kinds of the same name.
+func inGroup(apiVersion string) bool {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	return err == nil && gv.Group == v1beta1.GroupVersion.Group
+}
+
+// isStatusOnlyUpdate returns true if old and new are DaemonSets or Deployments
+// that only differ in their status.
+func isStatusOnlyUpdate(old client.Object, new client.Object) bool {
+
+	if old.GetGeneration() != new.GetGeneration() ||
+		!equality.Semantic.DeepEqual(old.GetLabels(), new.GetLabels()) ||
+		!equality.Semantic.DeepEqual(old.GetAnnotations(), new.GetAnnotations()) ||
+		!equality.Semantic.DeepEqual(old.GetDeletionTimestamp(), new.GetDeletionTimestamp()) {
+		return false
+	}
+
+	switch o := old.(type) {
+	case *appsv1.DaemonSet:
+		n, ok := new.(*appsv1.DaemonSet)
+		return ok && equality.Semantic.DeepEqual(o.Spec, n.Spec)
+	case *appsv1.Deployment:
+		n, ok := new.(*appsv1.Deployment)
+		return ok && equality.Semantic.DeepEqual(o.Spec, n.Spec)
+	}
+
+	return false
+}
+
+// GetPredicates returns the predicates of the controller, every event is
+// counted in the watch events metric.
 func (f *filter) GetPredicates() predicate.Predicate {
+	p := f.predicates()
+
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
+			return f.count(e.Object, "create", p.Create(e))
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return f.count(e.ObjectNew, "update", p.Update(e))
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return f.count(e.Object, "delete", p.Delete(e))
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return f.count(e.Object, "generic", p.Generic(e))
+		},
+	}
+}
+
+// count records whether the event of obj is enqueued and returns enqueued.
+func (f *filter) count(obj client.Object, event string, enqueued bool) bool {
+	result := metrics.WatchEventFiltered
+	if enqueued {
+		result = metrics.WatchEventEnqueued
+	}
+
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
 
-			f.mode = "CREATE"
+	f.metrics.IncWatchEvent(kind, event, result)
+
+	return enqueued
+}
+
+func (f *filter) predicates() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+
+			mode := "CREATE"
+			f.mode.Store(mode)
 			// If a specialresource dependency is deleted we
 			/* want to recreate it so handle the delete event */
 			obj := e.Object
 
-			if f.isSpecialResource(obj) {
+			if f.isSpecialResource(mode, obj) {
 				return !f.isSpecialResourceUnmanaged(obj)
 			}
 
-			if f.owned(obj) {
+			if f.owned(mode, obj) {
 				return true
 			}
 
@@ -152,35 +233,43 @@ func (f *filter) GetPredicates() predicate.Predicate {
 			if e.MetaOld.GetResourceVersion() == e.MetaNew.GetResourceVersion() {
 				return false
 			}*/
-			f.mode = "UPDATE"
+			mode := "UPDATE"
+			f.mode.Store(mode)
 
 			e.ObjectOld.GetGeneration()
 			e.ObjectOld.GetOwnerReferences()
 
 			obj := e.ObjectNew
 
+			// Status churn of owned workloads, e.g. numberReady on large
+			// clusters, does not need a reconcile
+			if f.owned(mode, obj) && isStatusOnlyUpdate(e.ObjectOld, obj) {
+				return false
+			}
+
 			// Required for the case when pods are deleted due to OS upgrade
 
-			if f.owned(obj) && f.kernelData.IsObjectAffine(obj) {
+			if f.owned(mode, obj) && f.kernelData.IsObjectAffine(obj) {
 				if e.ObjectOld.GetGeneration() == e.ObjectNew.GetGeneration() &&
 					e.ObjectOld.GetResourceVersion() == e.ObjectNew.GetResourceVersion() {
 					return false
 				} else {
-					f.log.Info(f.mode+" Owned Generation or resourceVersion Changed for kernel affine object",
+					f.log.Info(mode+" Owned Generation or resourceVersion Changed for kernel affine object",
 						"Name", obj.GetName(), "Type", reflect.TypeOf(obj).String())
 					if reflect.TypeOf(obj).String() == "*v1.DaemonSet" && e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() {
 						err := f.lifecycle.UpdateDaemonSetPods(context.TODO(), obj)
 						utils.WarnOnError(err)
 					}
-					if f.isSpecialResource(obj) && f.isSpecialResourceUnmanaged(obj) {
+					if f.isSpecialResource(mode, obj) && f.isSpecialResourceUnmanaged(obj) {
 						return false
 					}
 					return true
 				}
 			}
 
-			// Ignore updates to CR status in which case metadata.Generation does not change
-			if e.ObjectOld.GetGeneration() == e.ObjectNew.GetGeneration() {
+			// Ignore updates to CR status in which case metadata.Generation
+			// does not change, as well as resyncs and metadata churn
+			if !(predicate.GenerationChangedPredicate{}).Update(e) {
 				return false
 			}
 			// Some objects will increase generation on Update SRO sets the
@@ -193,19 +282,19 @@ func (f *filter) GetPredicates() predicate.Predicate {
 			// If a specialresource dependency is updated we
 			// want to reconcile it, handle the update event
 
-			if f.isSpecialResource(obj) {
+			if f.isSpecialResource(mode, obj) {
 				if f.isSpecialResourceUnmanaged(obj) {
 					return false
 				}
-				f.log.Info(f.mode+" IsSpecialResource GenerationChanged",
+				f.log.Info(mode+" IsSpecialResource GenerationChanged",
 					"Name", obj.GetName(), "Type", reflect.TypeOf(obj).String())
 				return true
 			}
 
 			// If we do not own the object, do not care
-			if f.owned(obj) {
+			if f.owned(mode, obj) {
 
-				f.log.Info(f.mode+" Owned GenerationChanged",
+				f.log.Info(mode+" Owned GenerationChanged",
 					"Name", obj.GetName(), "Type", reflect.TypeOf(obj).String())
 
 				if reflect.TypeOf(obj).String() == "*v1.DaemonSet" {
@@ -220,16 +309,17 @@ func (f *filter) GetPredicates() predicate.Predicate {
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
 
-			f.mode = "DELETE"
+			mode := "DELETE"
+			f.mode.Store(mode)
 			// If a specialresource dependency is deleted we
 			/* want to recreate it so handle the delete event */
 			obj := e.Object
-			if f.isSpecialResource(obj) {
+			if f.isSpecialResource(mode, obj) {
 				return true
 			}
 
 			// If we do not own the object, do not care
-			if f.owned(obj) {
+			if f.owned(mode, obj) {
 
 				ins := types.NamespacedName{
 					Namespace: os.Getenv("OPERATOR_NAMESPACE"),
@@ -243,22 +333,27 @@ func (f *filter) GetPredicates() predicate.Predicate {
 				err = f.storage.DeleteConfigMapEntry(context.TODO(), key, ins)
 				utils.WarnOnError(err)
 
+				// Let drains proceed once the replaced driver Pods are gone
+				err = f.lifecycle.ReleaseNode(context.TODO(), obj)
+				utils.WarnOnError(err)
+
 				return true
 			}
 			return false
 		},
 		GenericFunc: func(e event.GenericEvent) bool {
 
-			f.mode = "GENERIC"
+			mode := "GENERIC"
+			f.mode.Store(mode)
 
 			// If a specialresource dependency is updated we
 			// want to reconcile it, handle the update event
 			obj := e.Object
-			if f.isSpecialResource(obj) {
+			if f.isSpecialResource(mode, obj) {
 				return !f.isSpecialResourceUnmanaged(obj)
 			}
 			// If we do not own the object, do not care
-			if f.owned(obj) {
+			if f.owned(mode, obj) {
 				return true
 			}
 			return false
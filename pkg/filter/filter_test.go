@@ -16,11 +16,16 @@ import (
 	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
 	"github.com/openshift-psap/special-resource-operator/pkg/lifecycle"
+	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
 	"github.com/openshift-psap/special-resource-operator/pkg/storage"
+	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -29,22 +34,33 @@ var (
 	mockLifecycle *lifecycle.MockLifecycle
 	mockStorage   *storage.MockStorage
 	mockKernel    *kernel.MockKernelData
-	f             filter
+	mockMetrics   *metrics.MockMetrics
+	f             *filter
+	scheme        = runtime.NewScheme()
 )
 
 func TestFilter(t *testing.T) {
 	RegisterFailHandler(Fail)
 
+	utilruntime.Must(v1beta1.AddToScheme(scheme))
+	utilruntime.Must(corev1.AddToScheme(scheme))
+
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockLifecycle = lifecycle.NewMockLifecycle(ctrl)
 		mockStorage = storage.NewMockStorage(ctrl)
 		mockKernel = kernel.NewMockKernelData(ctrl)
-		f = filter{
+		mockMetrics = metrics.NewMockMetrics(ctrl)
+		mockMetrics.EXPECT().IncWatchEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		f = &filter{
 			log:        zap.New(zap.WriteTo(ioutil.Discard)),
+			kind:       Kind,
+			ownedLabel: OwnedLabel,
+			scheme:     scheme,
 			lifecycle:  mockLifecycle,
 			storage:    mockStorage,
 			kernelData: mockKernel,
+			metrics:    mockMetrics,
 		}
 	})
 
@@ -59,12 +75,12 @@ var _ = Describe("IsSpecialResource", func() {
 	DescribeTable(
 		"should return the correct value",
 		func(obj client.Object, m types.GomegaMatcher) {
-			Expect(f.isSpecialResource(obj)).To(m)
+			Expect(f.isSpecialResource("TEST", obj)).To(m)
 		},
 		Entry(
 			Kind,
 			&v1beta1.SpecialResource{
-				TypeMeta: metav1.TypeMeta{Kind: Kind},
+				TypeMeta: metav1.TypeMeta{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 			},
 			BeTrue(),
 		),
@@ -78,27 +94,44 @@ var _ = Describe("IsSpecialResource", func() {
 			BeFalse(),
 		),
 		Entry(
-			"valid selflink",
+			"SpecialResource without TypeMeta",
+			&v1beta1.SpecialResource{},
+			BeTrue(),
+		),
+		Entry(
+			"unstructured SpecialResource of another version",
 			func() *unstructured.Unstructured {
 				uo := &unstructured.Unstructured{}
-				uo.SetSelfLink("/apis/sro.openshift.io/v1")
+				uo.SetAPIVersion("sro.openshift.io/v1")
+				uo.SetKind(Kind)
 
 				return uo
 			}(),
 			BeTrue(),
 		),
 		Entry(
-			"selflink in Label",
+			"SpecialResource of another API group",
+			func() *unstructured.Unstructured {
+				uo := &unstructured.Unstructured{}
+				uo.SetAPIVersion("example.com/v1")
+				uo.SetKind(Kind)
+
+				return uo
+			}(),
+			BeFalse(),
+		),
+		Entry(
+			"sro.openshift.io in a label",
 			func() *unstructured.Unstructured {
 				uo := &unstructured.Unstructured{}
 				uo.SetLabels(map[string]string{"some-label": "/apis/sro.openshift.io/v1"})
 
 				return uo
 			}(),
-			BeTrue(),
+			BeFalse(),
 		),
 		Entry(
-			"no selflink",
+			"no GVK",
 			&unstructured.Unstructured{},
 			BeFalse(),
 		),
@@ -109,19 +142,30 @@ var _ = Describe("Owned", func() {
 	DescribeTable(
 		"should return the expected value",
 		func(obj client.Object, m types.GomegaMatcher) {
-			Expect(f.owned(obj)).To(m)
+			Expect(f.owned("TEST", obj)).To(m)
 		},
 		Entry(
 			"via ownerReferences",
 			&corev1.Pod{
 				ObjectMeta: metav1.ObjectMeta{
 					OwnerReferences: []metav1.OwnerReference{
-						{Kind: Kind},
+						{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 					},
 				},
 			},
 			BeTrue(),
 		),
+		Entry(
+			"via ownerReferences of another API group",
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: "example.com/v1", Kind: Kind},
+					},
+				},
+			},
+			BeFalse(),
+		),
 		Entry(
 			"via labels",
 			&corev1.Pod{
@@ -136,6 +180,25 @@ var _ = Describe("Owned", func() {
 			&corev1.Pod{},
 			BeFalse(),
 		),
+		Entry(
+			"namespaced object via labels only",
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns",
+					Labels:    map[string]string{OwnedLabel: "true"},
+				},
+			},
+			BeFalse(),
+		),
+		Entry(
+			"cluster-scoped object via labels",
+			&rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{OwnedLabel: "true"},
+				},
+			},
+			BeTrue(),
+		),
 	)
 })
 
@@ -159,7 +222,7 @@ var _ = Describe("Predicate", func() {
 				&corev1.Pod{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 					},
 				},
@@ -173,7 +236,7 @@ var _ = Describe("Predicate", func() {
 			Entry(
 				"unmanaged special resource",
 				&v1beta1.SpecialResource{
-					TypeMeta: metav1.TypeMeta{Kind: Kind},
+					TypeMeta: metav1.TypeMeta{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 					Spec: v1beta1.SpecialResourceSpec{
 						ManagementState: operatorv1.Unmanaged,
 					},
@@ -205,7 +268,7 @@ var _ = Describe("Predicate", func() {
 				&corev1.Pod{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Generation:      1,
 						ResourceVersion: "dummy1",
@@ -214,7 +277,7 @@ var _ = Describe("Predicate", func() {
 				&corev1.Pod{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Generation:      1,
 						ResourceVersion: "dummy1",
@@ -222,6 +285,32 @@ var _ = Describe("Predicate", func() {
 				},
 				BeFalse(),
 			),
+			Entry(
+				"Object's metadata changed, no change to Generation",
+				func() {
+					mockKernel.EXPECT().IsObjectAffine(gomock.Any()).Return(false)
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
+						},
+						Generation:      1,
+						ResourceVersion: "dummy1",
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
+						},
+						Generation:      1,
+						ResourceVersion: "dummy2",
+						ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+					},
+				},
+				BeFalse(),
+			),
 			Entry(
 				"Object's Generation changed, no change to ResourceVersion",
 				func() {
@@ -230,7 +319,7 @@ var _ = Describe("Predicate", func() {
 				&corev1.Pod{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Generation:      1,
 						ResourceVersion: "dummy1",
@@ -239,7 +328,7 @@ var _ = Describe("Predicate", func() {
 				&corev1.Pod{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Generation:      2,
 						ResourceVersion: "dummy1",
@@ -273,7 +362,7 @@ var _ = Describe("Predicate", func() {
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Generation:      1,
 						ResourceVersion: "dummy1",
@@ -282,7 +371,7 @@ var _ = Describe("Predicate", func() {
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Generation:      2,
 						ResourceVersion: "dummy2",
@@ -292,13 +381,11 @@ var _ = Describe("Predicate", func() {
 			),
 			Entry(
 				"Object is a SRO owned & kernel affine DaemonSet, but did not change",
-				func() {
-					mockKernel.EXPECT().IsObjectAffine(gomock.Any()).Return(true)
-				},
+				func() {},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Annotations: map[string]string{
 							"specialresource.openshift.io/kernel-affine": "true",
@@ -310,7 +397,7 @@ var _ = Describe("Predicate", func() {
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Annotations: map[string]string{
 							"specialresource.openshift.io/kernel-affine": "true",
@@ -321,6 +408,37 @@ var _ = Describe("Predicate", func() {
 				},
 				BeFalse(),
 			),
+			Entry(
+				"Object is a SRO owned DaemonSet and only its status changed",
+				func() {},
+				&appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
+						},
+						Annotations: map[string]string{
+							"specialresource.openshift.io/kernel-affine": "true",
+						},
+						Generation:      1,
+						ResourceVersion: "dummy1",
+					},
+					Status: appsv1.DaemonSetStatus{NumberReady: 1},
+				},
+				&appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
+						},
+						Annotations: map[string]string{
+							"specialresource.openshift.io/kernel-affine": "true",
+						},
+						Generation:      1,
+						ResourceVersion: "dummy2",
+					},
+					Status: appsv1.DaemonSetStatus{NumberReady: 2},
+				},
+				BeFalse(),
+			),
 			Entry(
 				"Object is a SRO owned & kernel affine DaemonSet",
 				func() {
@@ -330,7 +448,7 @@ var _ = Describe("Predicate", func() {
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Annotations: map[string]string{
 							"specialresource.openshift.io/kernel-affine": "true",
@@ -342,7 +460,7 @@ var _ = Describe("Predicate", func() {
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Annotations: map[string]string{
 							"specialresource.openshift.io/kernel-affine": "true",
@@ -361,7 +479,7 @@ var _ = Describe("Predicate", func() {
 				&v1beta1.SpecialResource{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Generation:      1,
 						ResourceVersion: "dummy1",
@@ -370,7 +488,7 @@ var _ = Describe("Predicate", func() {
 				&v1beta1.SpecialResource{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Generation:      2,
 						ResourceVersion: "dummy2",
@@ -386,7 +504,7 @@ var _ = Describe("Predicate", func() {
 				&v1beta1.SpecialResource{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Generation:      1,
 						ResourceVersion: "dummy1",
@@ -395,7 +513,7 @@ var _ = Describe("Predicate", func() {
 				&v1beta1.SpecialResource{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 						Generation:      2,
 						ResourceVersion: "dummy2",
@@ -452,7 +570,7 @@ var _ = Describe("Predicate", func() {
 				&corev1.Pod{
 					ObjectMeta: metav1.ObjectMeta{
 						OwnerReferences: []metav1.OwnerReference{
-							{Kind: Kind},
+							{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 						},
 					},
 				},
@@ -466,7 +584,7 @@ var _ = Describe("Predicate", func() {
 			Entry(
 				"unmanaged special resource",
 				&v1beta1.SpecialResource{
-					TypeMeta: metav1.TypeMeta{Kind: Kind},
+					TypeMeta: metav1.TypeMeta{APIVersion: v1beta1.GroupVersion.String(), Kind: Kind},
 					Spec: v1beta1.SpecialResourceSpec{
 						ManagementState: operatorv1.Unmanaged,
 					},
@@ -476,3 +594,138 @@ var _ = Describe("Predicate", func() {
 		)
 	})
 })
+
+var _ = Describe("KernelChanged", func() {
+	node := func(kernelVersion string) *corev1.Node {
+		n := &corev1.Node{}
+		if kernelVersion != "" {
+			n.SetLabels(map[string]string{kernel.FullVersionLabel: kernelVersion})
+		}
+		return n
+	}
+
+	It("should accept new nodes with a kernel version", func() {
+		Expect(KernelChanged().Create(event.CreateEvent{Object: node("4.18.0-305.19.1.el8_4.x86_64")})).To(BeTrue())
+		Expect(KernelChanged().Create(event.CreateEvent{Object: node("")})).To(BeFalse())
+	})
+
+	DescribeTable("should only accept updates of the kernel version",
+		func(oldVersion, newVersion string, m types.GomegaMatcher) {
+			Expect(KernelChanged().Update(event.UpdateEvent{ObjectOld: node(oldVersion), ObjectNew: node(newVersion)})).To(m)
+		},
+		Entry("new kernel", "4.18.0-305.19.1.el8_4.x86_64", "4.18.0-372.9.1.el8.x86_64", BeTrue()),
+		Entry("label added", "", "4.18.0-305.19.1.el8_4.x86_64", BeTrue()),
+		Entry("same kernel", "4.18.0-305.19.1.el8_4.x86_64", "4.18.0-305.19.1.el8_4.x86_64", BeFalse()),
+	)
+
+	It("should ignore deleted nodes", func() {
+		Expect(KernelChanged().Delete(event.DeleteEvent{Object: node("4.18.0-305.19.1.el8_4.x86_64")})).To(BeFalse())
+	})
+})
+
+var _ = Describe("ReadinessChanged", func() {
+	node := func(ready corev1.ConditionStatus, unschedulable bool) *corev1.Node {
+		n := &corev1.Node{}
+		n.Spec.Unschedulable = unschedulable
+		n.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}}
+		return n
+	}
+
+	DescribeTable("should only accept nodes becoming or ceasing to be ready",
+		func(oldNode, newNode *corev1.Node, m types.GomegaMatcher) {
+			Expect(ReadinessChanged().Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).To(m)
+		},
+		Entry("became ready", node(corev1.ConditionFalse, false), node(corev1.ConditionTrue, false), BeTrue()),
+		Entry("uncordoned", node(corev1.ConditionTrue, true), node(corev1.ConditionTrue, false), BeTrue()),
+		Entry("cordoned", node(corev1.ConditionTrue, false), node(corev1.ConditionTrue, true), BeTrue()),
+		Entry("still ready", node(corev1.ConditionTrue, false), node(corev1.ConditionTrue, false), BeFalse()),
+		Entry("still not ready", node(corev1.ConditionUnknown, false), node(corev1.ConditionFalse, false), BeFalse()),
+	)
+
+	It("should ignore new and deleted nodes", func() {
+		Expect(ReadinessChanged().Create(event.CreateEvent{Object: node(corev1.ConditionTrue, false)})).To(BeFalse())
+		Expect(ReadinessChanged().Delete(event.DeleteEvent{Object: node(corev1.ConditionTrue, false)})).To(BeFalse())
+	})
+})
+
+var _ = Describe("RebootChanged", func() {
+	node := func(current, desired string) *corev1.Node {
+		n := &corev1.Node{}
+		n.SetAnnotations(map[string]string{
+			"machineconfiguration.openshift.io/currentConfig": current,
+			"machineconfiguration.openshift.io/desiredConfig": desired,
+		})
+		return n
+	}
+
+	DescribeTable("should only accept reboot requests and completions",
+		func(oldNode, newNode *corev1.Node, m types.GomegaMatcher) {
+			Expect(RebootChanged().Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).To(m)
+		},
+		Entry("reboot requested", node("rendered-1", "rendered-1"), node("rendered-1", "rendered-2"), BeTrue()),
+		Entry("reboot completed", node("rendered-1", "rendered-2"), node("rendered-2", "rendered-2"), BeTrue()),
+		Entry("no reboot", node("rendered-1", "rendered-1"), node("rendered-1", "rendered-1"), BeFalse()),
+	)
+})
+
+var _ = Describe("NewFilter", func() {
+	const (
+		otherKind       = "OtherResource"
+		otherOwnedLabel = "otherresource.openshift.io/owned"
+	)
+
+	It("should only match objects of its own kind and owned label", func() {
+		other := NewFilter(otherKind, otherOwnedLabel, scheme, mockLifecycle, mockStorage, mockKernel, mockMetrics).(*filter)
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{OwnedLabel: "true"},
+			},
+		}
+
+		Expect(other.owned("TEST", pod)).To(BeFalse())
+		Expect(other.isSpecialResource("TEST", &v1beta1.SpecialResource{})).To(BeFalse())
+		Expect(f.owned("TEST", pod)).To(BeTrue())
+	})
+
+	It("should only match the owner references of its own kind", func() {
+		other := NewFilter(otherKind, otherOwnedLabel, scheme, mockLifecycle, mockStorage, mockKernel, mockMetrics).(*filter)
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: v1beta1.GroupVersion.String(), Kind: otherKind},
+				},
+			},
+		}
+
+		Expect(other.owned("TEST", pod)).To(BeTrue())
+		Expect(f.owned("TEST", pod)).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpgradeChanged", func() {
+	clusterVersion := func(history ...configv1.UpdateHistory) *configv1.ClusterVersion {
+		cv := &configv1.ClusterVersion{}
+		cv.Status.History = history
+		return cv
+	}
+
+	completed := configv1.UpdateHistory{State: configv1.CompletedUpdate, Version: "4.9.10", Image: "release:4.9.10"}
+	started := configv1.UpdateHistory{State: configv1.PartialUpdate, Version: "4.10.3", Image: "release:4.10.3"}
+	finished := configv1.UpdateHistory{State: configv1.CompletedUpdate, Version: "4.10.3", Image: "release:4.10.3"}
+
+	DescribeTable("should only accept upgrades that start or complete",
+		func(oldCV, newCV *configv1.ClusterVersion, m types.GomegaMatcher) {
+			Expect(UpgradeChanged().Update(event.UpdateEvent{ObjectOld: oldCV, ObjectNew: newCV})).To(m)
+		},
+		Entry("upgrade started", clusterVersion(completed), clusterVersion(started, completed), BeTrue()),
+		Entry("upgrade completed", clusterVersion(started, completed), clusterVersion(finished, completed), BeTrue()),
+		Entry("no upgrade", clusterVersion(completed), clusterVersion(completed), BeFalse()),
+	)
+
+	It("should ignore the creation of the ClusterVersion", func() {
+		Expect(UpgradeChanged().Create(event.CreateEvent{Object: clusterVersion(completed)})).To(BeFalse())
+	})
+})
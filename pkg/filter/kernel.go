@@ -0,0 +1,28 @@
+package filter
+
+import (
+	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// KernelChanged returns the predicates of the Node watch: only a node joining
+// with a kernel version, or a node reporting a new one after an upgrade, is of
+// interest. Removed nodes leave their replicas in place until the next resync.
+func KernelChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			_, found := e.Object.GetLabels()[kernel.FullVersionLabel]
+			return found
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetLabels()[kernel.FullVersionLabel] != e.ObjectNew.GetLabels()[kernel.FullVersionLabel]
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
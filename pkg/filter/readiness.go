@@ -0,0 +1,33 @@
+package filter
+
+import (
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ReadinessChanged returns the predicates of the Node watch of the
+// SpecialResources computing their runtime values from the ready nodes only: a
+// node became ready and schedulable, or stopped being so.
+func ReadinessChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, okOld := e.ObjectOld.(*corev1.Node)
+			newNode, okNew := e.ObjectNew.(*corev1.Node)
+			if !okOld || !okNew {
+				return false
+			}
+			return utils.NodeReady(oldNode) != utils.NodeReady(newNode)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package filter
+
+import (
+	"github.com/openshift-psap/special-resource-operator/pkg/unload"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// RebootChanged returns the predicates of the Node watch of the graceful
+// unload: a reboot was requested for a node, or the node completed it.
+func RebootChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return unload.RebootPending(e.ObjectOld) != unload.RebootPending(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
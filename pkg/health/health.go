@@ -0,0 +1,68 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/registry"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// erroredThreshold is how long a SpecialResource can stay Errored before the
+// operator reports itself as not ready.
+const erroredThreshold = 10 * time.Minute
+
+//go:generate mockgen -source=health.go -package=health -destination=mock_health_api.go
+
+// Checks are the readiness checks of the operator, they match healthz.Checker.
+type Checks interface {
+	Reconcile(req *http.Request) error
+	Registry(req *http.Request) error
+}
+
+type checks struct {
+	kubeClient clients.ClientsInterface
+	registry   registry.Registry
+}
+
+func New(kubeClient clients.ClientsInterface, registry registry.Registry) Checks {
+	return &checks{
+		kubeClient: kubeClient,
+		registry:   registry,
+	}
+}
+
+// Reconcile fails if a SpecialResource has been Errored for longer than erroredThreshold.
+func (c *checks) Reconcile(req *http.Request) error {
+	list := &srov1beta1.SpecialResourceList{}
+
+	if err := c.kubeClient.List(req.Context(), list); err != nil {
+		return fmt.Errorf("could not list SpecialResources: %w", err)
+	}
+
+	for _, sr := range list.Items {
+		cond := meta.FindStatusCondition(sr.Status.Conditions, srov1beta1.SpecialResourceErrored)
+		if cond == nil || cond.Status != metav1.ConditionTrue {
+			continue
+		}
+
+		if since := time.Since(cond.LastTransitionTime.Time); since > erroredThreshold {
+			return fmt.Errorf("SpecialResource %s errored for %s: %s", sr.Name, since.Round(time.Second), cond.Message)
+		}
+	}
+
+	return nil
+}
+
+// Registry fails if the last image lookup could not reach its registry.
+func (c *checks) Registry(_ *http.Request) error {
+	if err := c.registry.Reachable(); err != nil {
+		return fmt.Errorf("registry unreachable: %w", err)
+	}
+
+	return nil
+}
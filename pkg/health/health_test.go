@@ -0,0 +1,110 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/health"
+	"github.com/openshift-psap/special-resource-operator/pkg/registry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	ctrl         *gomock.Controller
+	mockClient   *clients.MockClientsInterface
+	mockRegistry *registry.MockRegistry
+)
+
+func TestHealth(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = clients.NewMockClientsInterface(ctrl)
+		mockRegistry = registry.NewMockRegistry(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "Health Suite")
+}
+
+var _ = Describe("Reconcile", func() {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	listWith := func(status metav1.ConditionStatus, since time.Duration) {
+		mockClient.
+			EXPECT().
+			List(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.SpecialResourceList{})).
+			Do(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) {
+				list.(*v1beta1.SpecialResourceList).Items = []v1beta1.SpecialResource{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "test"},
+						Status: v1beta1.SpecialResourceStatus{
+							Conditions: []metav1.Condition{
+								{
+									Type:               v1beta1.SpecialResourceErrored,
+									Status:             status,
+									LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+								},
+							},
+						},
+					},
+				}
+			})
+	}
+
+	It("should succeed if a SpecialResource just errored", func() {
+		listWith(metav1.ConditionTrue, time.Minute)
+
+		Expect(health.New(mockClient, mockRegistry).Reconcile(req)).To(Succeed())
+	})
+
+	It("should succeed if a SpecialResource recovered", func() {
+		listWith(metav1.ConditionFalse, time.Hour)
+
+		Expect(health.New(mockClient, mockRegistry).Reconcile(req)).To(Succeed())
+	})
+
+	It("should fail if a SpecialResource errored for too long", func() {
+		listWith(metav1.ConditionTrue, time.Hour)
+
+		Expect(health.New(mockClient, mockRegistry).Reconcile(req)).NotTo(Succeed())
+	})
+
+	It("should fail if the SpecialResources cannot be listed", func() {
+		mockClient.
+			EXPECT().
+			List(gomock.Any(), gomock.Any()).
+			Return(errors.New("random error"))
+
+		Expect(health.New(mockClient, mockRegistry).Reconcile(req)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Registry", func() {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	It("should succeed if the registry is reachable", func() {
+		mockRegistry.EXPECT().Reachable().Return(nil)
+
+		Expect(health.New(mockClient, mockRegistry).Registry(req)).To(Succeed())
+	})
+
+	It("should fail if the registry is unreachable", func() {
+		mockRegistry.EXPECT().Reachable().Return(errors.New("random error"))
+
+		Expect(health.New(mockClient, mockRegistry).Registry(req)).NotTo(Succeed())
+	})
+})
@@ -52,6 +52,19 @@ type HelmRepo struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=false
 	InsecureSkipTLSverify bool `json:"insecure_skip_tls_verify"`
+
+	// SecretRef references a Secret of the operator namespace holding the credentials of the Helm repository.
+	// They are read every time a chart is loaded and take precedence over the other credentials.
+	// +kubebuilder:validation:Optional
+	SecretRef *HelmRepoSecretRef `json:"secretRef,omitempty"`
+}
+
+// HelmRepoSecretRef references a Secret holding the credentials of a Helm repository: a username and password,
+// or a token, and a client certificate under the usual keys of a TLS Secret.
+type HelmRepoSecretRef struct {
+	// Name is the name of the Secret.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
 }
 
 // HelmChart describes a Helm Chart.
@@ -62,6 +75,18 @@ type HelmChart struct {
 	// Version is the chart's version.
 	Version string `json:"version"`
 
+	// Digest pins the chart to the sha256 digest of its archive, e.g. sha256:<hex>. A chart whose archive has
+	// another digest is not loaded. Version must be exact when Digest is set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest,omitempty"`
+
+	// UpgradePolicy selects the newer versions of the chart it is upgraded to: Pinned, the default, Patch, Minor
+	// or Latest. It is ignored when Digest is set, and for the dependencies of a SpecialResource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Pinned;Patch;Minor;Latest
+	UpgradePolicy UpgradePolicy `json:"upgradePolicy,omitempty"`
+
 	// Repository is the chart's repository information.
 	// +kubebuilder:validation:Required
 	Repository HelmRepo `json:"repository"`
@@ -69,16 +94,47 @@ type HelmChart struct {
 	// Tags is a list of tags for this chart.
 	// +kubebuilder:validation:Optional
 	Tags []string `json:"tags"`
+
+	// PostRenderer transforms the rendered manifests of the chart before they are applied.
+	// +kubebuilder:validation:Optional
+	PostRenderer *HelmPostRenderer `json:"postRenderer,omitempty"`
+}
+
+// UpgradePolicy selects the newer versions of a chart it is upgraded to.
+type UpgradePolicy string
+
+const (
+	// UpgradePinned keeps the version of the chart.
+	UpgradePinned UpgradePolicy = "Pinned"
+	// UpgradePatch upgrades the chart to its newer patch versions.
+	UpgradePatch UpgradePolicy = "Patch"
+	// UpgradeMinor upgrades the chart to its newer minor and patch versions.
+	UpgradeMinor UpgradePolicy = "Minor"
+	// UpgradeLatest upgrades the chart to its latest version.
+	UpgradeLatest UpgradePolicy = "Latest"
+)
+
+// HelmPostRenderer describes a kustomize overlay applied to the rendered manifests of a chart.
+type HelmPostRenderer struct {
+	// ConfigMap is the name of the ConfigMap of the operator namespace holding the kustomization.yaml
+	// of the overlay and the files it refers to. The rendered manifests are its rendered.yaml resource.
+	// +kubebuilder:validation:Required
+	ConfigMap string `json:"configMap"`
 }
 
 func (in *HelmChart) DeepCopyInto(out *HelmChart) {
 	*out = *in
-	out.Repository = in.Repository
+	in.Repository.DeepCopyInto(&out.Repository)
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PostRenderer != nil {
+		in, out := &in.PostRenderer, &out.PostRenderer
+		*out = new(HelmPostRenderer)
+		**out = **in
+	}
 }
 
 // DeepCopy is a manually created deepcopy function, copying the receiver, creating a new HelmChart.
@@ -91,9 +147,29 @@ func (in *HelmChart) DeepCopy() *HelmChart {
 	return out
 }
 
+// DeepCopyInto is a manually created deepcopy function, copying the receiver, writing into out. in must be nonnil.
+func (in *HelmPostRenderer) DeepCopyInto(out *HelmPostRenderer) {
+	*out = *in
+}
+
+// DeepCopy is a manually created deepcopy function, copying the receiver, creating a new HelmPostRenderer.
+func (in *HelmPostRenderer) DeepCopy() *HelmPostRenderer {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmPostRenderer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is a manually created deepcopy function, copying the receiver, writing into out. in must be nonnil.
 func (in *HelmRepo) DeepCopyInto(out *HelmRepo) {
 	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(HelmRepoSecretRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is a manually created deepcopy function, copying the receiver, creating a new HelmRepo.
@@ -0,0 +1,152 @@
+package helmer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"helm.sh/helm/v3/pkg/getter"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Keys of the Secret referenced by a HelmRepo.
+const (
+	SecretUsername = "username"
+	SecretPassword = "password"
+	SecretToken    = "token"
+	SecretCA       = "ca.crt"
+)
+
+// RepositoryHostsAnnotation lists, comma separated, the hosts of the chart
+// repositories the credentials of a Secret may be sent to. Secrets without it
+// are never used: any SpecialResource can name a Secret of the operator
+// namespace, and it chooses the repository URL as well.
+const RepositoryHostsAnnotation = "specialresource.openshift.io/chart-repository-hosts"
+
+// isHTTP returns true if the repository at repoURL is served over HTTP(S).
+func isHTTP(repoURL string) bool {
+	u, err := url.Parse(repoURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// credentials returns the credentials of repo, the ones of its Secret if it
+// has one. The Secret is read every time, so that rotated credentials are
+// used without a restart; they are kept in memory only.
+func (h *helmer) credentials(ctx context.Context, repo helmerv1beta1.HelmRepo) (httpCredentials, error) {
+	creds := httpCredentials{insecureSkipTLSverify: repo.InsecureSkipTLSverify}
+
+	if repo.SecretRef == nil {
+		creds.username = repo.Username
+		creds.password = repo.Password
+
+		for _, f := range []struct {
+			path string
+			data *[]byte
+		}{
+			{repo.CertFile, &creds.cert},
+			{repo.KeyFile, &creds.key},
+			{repo.CAFile, &creds.ca},
+		} {
+			if f.path == "" {
+				continue
+			}
+
+			data, err := os.ReadFile(f.path)
+			if err != nil {
+				return creds, fmt.Errorf("could not read the credentials of repository %s: %w", repo.Name, err)
+			}
+			*f.data = data
+		}
+
+		return creds, nil
+	}
+
+	secret, err := h.kubeClient.GetSecret(ctx, os.Getenv("OPERATOR_NAMESPACE"), repo.SecretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return creds, fmt.Errorf("could not get the credentials of repository %s: %w", repo.Name, err)
+	}
+
+	if err = allowedFor(secret, repo.URL); err != nil {
+		return creds, fmt.Errorf("could not use the credentials of repository %s: %w", repo.Name, err)
+	}
+
+	_, hasCert := secret.Data[v1.TLSCertKey]
+	_, hasKey := secret.Data[v1.TLSPrivateKeyKey]
+	if hasCert != hasKey {
+		return creds, fmt.Errorf("Secret %s: both %s and %s are required for a client certificate", secret.Name, v1.TLSCertKey, v1.TLSPrivateKeyKey)
+	}
+
+	creds.username = string(secret.Data[SecretUsername])
+	creds.password = string(secret.Data[SecretPassword])
+	creds.token = string(secret.Data[SecretToken])
+	creds.cert = secret.Data[v1.TLSCertKey]
+	creds.key = secret.Data[v1.TLSPrivateKeyKey]
+	creds.ca = secret.Data[SecretCA]
+
+	return creds, nil
+}
+
+// allowedFor returns an error unless the RepositoryHostsAnnotation of secret
+// lists the host of repoURL.
+func allowedFor(secret *v1.Secret, repoURL string) error {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return fmt.Errorf("invalid repository URL %s: %w", repoURL, err)
+	}
+
+	hosts, ok := secret.GetAnnotations()[RepositoryHostsAnnotation]
+	if !ok {
+		return fmt.Errorf("Secret %s has no %s annotation", secret.Name, RepositoryHostsAnnotation)
+	}
+
+	for _, host := range strings.Split(hosts, ",") {
+		if strings.EqualFold(strings.TrimSpace(host), u.Host) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Secret %s is not allowed for host %s by its %s annotation", secret.Name, u.Host, RepositoryHostsAnnotation)
+}
+
+// getterProvidersFor returns the getters of the repository: the ones of the
+// operator, with the http and https ones replaced by a caching getter using
+// the credentials of the repository.
+func (h *helmer) getterProvidersFor(ctx context.Context, repo helmerv1beta1.HelmRepo) (getter.Providers, error) {
+	if !isHTTP(repo.URL) {
+		if repo.SecretRef != nil {
+			return nil, fmt.Errorf("repository %s: credentials Secrets are only supported for HTTP repositories", repo.Name)
+		}
+		return h.getterProviders, nil
+	}
+
+	creds, err := h.credentials(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := newHTTPGetter(h.log, h.cache, repo.URL, creds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials of repository %s: %w", repo.Name, err)
+	}
+
+	providers := getter.Providers{
+		{
+			Schemes: []string{"http", "https"},
+			New: func(...getter.Option) (getter.Getter, error) {
+				return g, nil
+			},
+		},
+	}
+
+	for _, p := range h.getterProviders {
+		if !p.Provides("http") && !p.Provides("https") {
+			providers = append(providers, p)
+		}
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,167 @@
+package helmer_test
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"helm.sh/helm/v3/pkg/cli"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("helmer_Load with a credentials Secret", func() {
+	const (
+		operatorNamespace = "operator-ns"
+		secretName        = "repo-credentials"
+		token             = "s3cr3t"
+	)
+
+	var (
+		h        helmer.Helmer
+		server   *httptest.Server
+		requests []string
+	)
+
+	load := func() error {
+		_, _, err := h.Load(context.TODO(), helmerv1beta1.HelmChart{
+			Name:    "test-chart",
+			Version: "0.1.0",
+			Repository: helmerv1beta1.HelmRepo{
+				Name:      "private",
+				URL:       server.URL,
+				SecretRef: &helmerv1beta1.HelmRepoSecretRef{Name: secretName},
+			},
+		})
+
+		return err
+	}
+
+	BeforeEach(func() {
+		GinkgoT().Setenv("OPERATOR_NAMESPACE", operatorNamespace)
+
+		requests = nil
+		files := http.FileServer(http.Dir("testdata"))
+
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.Path)
+
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			files.ServeHTTP(w, r)
+		}))
+		DeferCleanup(server.Close)
+
+		settings := cli.New()
+		settings.RepositoryConfig = filepath.Join(GinkgoT().TempDir(), "repositories.yaml")
+		settings.RepositoryCache = GinkgoT().TempDir()
+
+		h = helmer.NewHelmer(mockCreator, settings, mockKubeClient, nil, helmer.ChartVerification{})
+	})
+
+	secret := func(data map[string][]byte) *v1.Secret {
+		data[helmer.SecretCA] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        secretName,
+				Namespace:   operatorNamespace,
+				Annotations: map[string]string{helmer.RepositoryHostsAnnotation: "charts.example.com, " + strings.TrimPrefix(server.URL, "https://")},
+			},
+			Data: data,
+		}
+	}
+
+	It("should fetch the index and the chart with the token and CA of the Secret", func() {
+		mockKubeClient.EXPECT().
+			GetSecret(gomock.Any(), operatorNamespace, secretName, metav1.GetOptions{}).
+			Return(secret(map[string][]byte{helmer.SecretToken: []byte(token)}), nil)
+
+		Expect(load()).To(Succeed())
+		Expect(requests).To(Equal([]string{"/index.yaml", "/test-chart-0.1.0.tgz"}))
+	})
+
+	It("should read the Secret again on every load", func() {
+		gomock.InOrder(
+			mockKubeClient.EXPECT().
+				GetSecret(gomock.Any(), operatorNamespace, secretName, metav1.GetOptions{}).
+				Return(secret(map[string][]byte{helmer.SecretToken: []byte("expired")}), nil),
+			mockKubeClient.EXPECT().
+				GetSecret(gomock.Any(), operatorNamespace, secretName, metav1.GetOptions{}).
+				Return(secret(map[string][]byte{helmer.SecretToken: []byte(token)}), nil),
+		)
+
+		Expect(load()).NotTo(Succeed())
+		Expect(load()).To(Succeed())
+	})
+
+	It("should not serve the cached copy fetched with other credentials", func() {
+		gomock.InOrder(
+			mockKubeClient.EXPECT().
+				GetSecret(gomock.Any(), operatorNamespace, secretName, metav1.GetOptions{}).
+				Return(secret(map[string][]byte{helmer.SecretToken: []byte(token)}), nil),
+			mockKubeClient.EXPECT().
+				GetSecret(gomock.Any(), operatorNamespace, secretName, metav1.GetOptions{}).
+				Return(secret(map[string][]byte{helmer.SecretToken: []byte("other")}), nil),
+		)
+
+		Expect(load()).To(Succeed())
+
+		server.Close()
+
+		Expect(load()).NotTo(Succeed())
+	})
+
+	It("should fail if the Secret cannot be read", func() {
+		mockKubeClient.EXPECT().
+			GetSecret(gomock.Any(), operatorNamespace, secretName, metav1.GetOptions{}).
+			Return(nil, errors.New("random error"))
+
+		Expect(load()).To(HaveOccurred())
+		Expect(requests).To(BeEmpty())
+	})
+
+	It("should not use a Secret that is not bound to a repository", func() {
+		s := secret(map[string][]byte{helmer.SecretToken: []byte(token)})
+		s.Annotations = nil
+
+		mockKubeClient.EXPECT().
+			GetSecret(gomock.Any(), operatorNamespace, secretName, metav1.GetOptions{}).
+			Return(s, nil)
+
+		Expect(load()).To(HaveOccurred())
+		Expect(requests).To(BeEmpty())
+	})
+
+	It("should not use a Secret bound to another repository", func() {
+		s := secret(map[string][]byte{helmer.SecretToken: []byte(token)})
+		s.Annotations[helmer.RepositoryHostsAnnotation] = "charts.example.com"
+
+		mockKubeClient.EXPECT().
+			GetSecret(gomock.Any(), operatorNamespace, secretName, metav1.GetOptions{}).
+			Return(s, nil)
+
+		Expect(load()).To(HaveOccurred())
+		Expect(requests).To(BeEmpty())
+	})
+
+	It("should fail with a client certificate without its key", func() {
+		mockKubeClient.EXPECT().
+			GetSecret(gomock.Any(), operatorNamespace, secretName, metav1.GetOptions{}).
+			Return(secret(map[string][]byte{v1.TLSCertKey: []byte("cert")}), nil)
+
+		Expect(load()).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,80 @@
+package helmer_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+var _ = Describe("helmer_Load with a digest", func() {
+	var (
+		h      helmer.Helmer
+		server *httptest.Server
+		digest string
+	)
+
+	loadVersion := func(version string, pinned string) (string, error) {
+		_, d, err := h.Load(context.TODO(), helmerv1beta1.HelmChart{
+			Name:       "test-chart",
+			Version:    version,
+			Digest:     pinned,
+			Repository: helmerv1beta1.HelmRepo{Name: "pinned", URL: server.URL},
+		})
+
+		return d, err
+	}
+
+	load := func(pinned string) (string, error) {
+		return loadVersion("0.1.0", pinned)
+	}
+
+	BeforeEach(func() {
+		archive, err := os.ReadFile(filepath.Join("testdata", "test-chart-0.1.0.tgz"))
+		Expect(err).NotTo(HaveOccurred())
+
+		sum := sha256.Sum256(archive)
+		digest = "sha256:" + hex.EncodeToString(sum[:])
+
+		server = httptest.NewServer(http.FileServer(http.Dir("testdata")))
+		DeferCleanup(server.Close)
+
+		settings := cli.New()
+		settings.RepositoryConfig = filepath.Join(GinkgoT().TempDir(), "repositories.yaml")
+		settings.RepositoryCache = GinkgoT().TempDir()
+
+		h = helmer.NewHelmer(mockCreator, settings, mockKubeClient, nil, helmer.ChartVerification{})
+	})
+
+	It("should return the digest of the chart archive", func() {
+		Expect(load("")).To(Equal(digest))
+	})
+
+	It("should load a chart matching the pinned digest", func() {
+		Expect(load(digest)).To(Equal(digest))
+	})
+
+	It("should fail if the chart does not match the pinned digest", func() {
+		_, err := load("sha256:0000000000000000000000000000000000000000000000000000000000000000")
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("should fail if the digest is pinned without an exact version",
+		func(version string) {
+			_, err := loadVersion(version, digest)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("no version", ""),
+		Entry("a range", "~0.1.0"),
+		Entry("a partial version", "0.1"),
+	)
+})
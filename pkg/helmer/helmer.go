@@ -3,28 +3,41 @@ package helmer
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/go-logr/logr"
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
 	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/redact"
 	"github.com/openshift-psap/special-resource-operator/pkg/resource"
+	"github.com/openshift-psap/special-resource-operator/pkg/secrets"
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
+	"github.com/openshift-psap/special-resource-operator/pkg/tracing"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
 	"github.com/pkg/errors"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/downloader"
 	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/postrender"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/releaseutil"
 	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
 	helmtime "helm.sh/helm/v3/pkg/time"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,6 +46,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
+// releaseStorage is the Helm storage driver of the releases. Their config holds
+// the values the charts were rendered with, including the ones fetched from
+// secret providers, which must not end up in ConfigMaps.
+const releaseStorage = "secrets"
+
+// legacyReleaseStorage is the storage driver the releases were kept in before,
+// they are read to run their delete hooks and purged once superseded.
+const legacyReleaseStorage = "configmaps"
+
 func DefaultSettings() (*cli.EnvSettings, error) {
 	s := cli.New()
 
@@ -60,33 +82,59 @@ func OpenShiftInstallOrder() {
 	releaseutil.InstallOrder = utils.StringSliceInsert(releaseutil.InstallOrder, idx, "Certificates")
 }
 
+//go:generate mockgen -source=helmer.go -package=helmer -destination=mock_helmer_api.go
+
 type Helmer interface {
-	Load(helmerv1beta1.HelmChart) (*chart.Chart, error)
-	Run(context.Context, chart.Chart, map[string]interface{}, v1.Object, string, string, map[string]string, string, string, bool) error
+	LatestVersion(context.Context, helmerv1beta1.HelmChart) (string, error)
+	Load(context.Context, helmerv1beta1.HelmChart) (*chart.Chart, string, error)
+	Run(context.Context, chart.Chart, map[string]interface{}, v1.Object, string, string, map[string]string, string, string, bool, postrender.PostRenderer) error
+	RunDeleteHooks(context.Context, string, v1.Object, string, string) error
+}
+
+// HookPostRenderer is a post-renderer transforming the hooks of a release as
+// well, Helm only post-renders its manifests.
+type HookPostRenderer interface {
+	postrender.PostRenderer
+	RunHook(path string, rendered *bytes.Buffer) (*bytes.Buffer, error)
 }
 
 type helmer struct {
 	actionConfig    *action.Configuration
+	cache           *httpCache
 	creator         resource.Creator
 	getterProviders getter.Providers
 	log             logr.Logger
 	kubeClient      clients.ClientsInterface
+	libraries       []helmerv1beta1.HelmChart
 	repoFile        *repo.File
 	settings        *cli.EnvSettings
+	verification    ChartVerification
 }
 
-func NewHelmer(creator resource.Creator, settings *cli.EnvSettings, kubeClient clients.ClientsInterface) *helmer {
+// NewHelmer returns a Helmer injecting the libraries as dependencies into
+// every chart it loads, and verifying the provenance of the charts as set by
+// verification.
+func NewHelmer(
+	creator resource.Creator,
+	settings *cli.EnvSettings,
+	kubeClient clients.ClientsInterface,
+	libraries []helmerv1beta1.HelmChart,
+	verification ChartVerification,
+) *helmer {
 	return &helmer{
+		cache:           newHTTPCache(filepath.Join(settings.RepositoryCache, "http")),
 		creator:         creator,
 		getterProviders: getter.All(settings),
-		log:             zap.New(zap.UseDevMode(true)).WithName(utils.Print("helmer", utils.Blue)),
+		log:             redact.Logger(zap.New(zap.UseDevMode(true)).WithName(utils.Print("helmer", utils.Blue))),
 		kubeClient:      kubeClient,
+		libraries:       libraries,
 		repoFile: &repo.File{
 			APIVersion:   "",
 			Generated:    time.Time{},
 			Repositories: []*repo.Entry{},
 		},
-		settings: settings,
+		settings:     settings,
+		verification: verification,
 	}
 }
 
@@ -94,9 +142,9 @@ func init() {
 	OpenShiftInstallOrder()
 }
 
-func (h *helmer) AddorUpdateRepo(entry *repo.Entry) error {
+func (h *helmer) AddorUpdateRepo(entry *repo.Entry, providers getter.Providers) error {
 
-	chartRepo, err := repo.NewChartRepository(entry, h.getterProviders)
+	chartRepo, err := repo.NewChartRepository(entry, providers)
 	if err != nil {
 		return fmt.Errorf("new chart repository failed: %w", err)
 
@@ -120,22 +168,72 @@ func (h *helmer) AddorUpdateRepo(entry *repo.Entry) error {
 	return nil
 }
 
-func (h *helmer) Load(spec helmerv1beta1.HelmChart) (*chart.Chart, error) {
+// Load loads the chart of spec and returns it along with the digest of its
+// archive, empty if the chart is not an archive.
+func (h *helmer) Load(ctx context.Context, spec helmerv1beta1.HelmChart) (*chart.Chart, string, error) {
+
+	loaded, digest, err := h.load(ctx, spec)
+	if err != nil {
+		return nil, "", sroerrors.Wrap(sroerrors.ChartLoad, err)
+	}
+
+	libraries := make([]*chart.Chart, 0, len(h.libraries))
+
+	for _, l := range h.libraries {
+		lib, _, err := h.load(ctx, l)
+		if err != nil {
+			return nil, "", sroerrors.Wrap(sroerrors.ChartLoad, fmt.Errorf("could not load library chart %s: %w", l.Name, err))
+		}
+		libraries = append(libraries, lib)
+	}
+
+	if err = AddLibraries(loaded, libraries); err != nil {
+		return nil, "", sroerrors.Wrap(sroerrors.ChartLoad, err)
+	}
+
+	return loaded, digest, nil
+}
 
+// addRepo adds the repository r, or updates its index, and returns its entry
+// along with the getters to use for it.
+func (h *helmer) addRepo(ctx context.Context, r helmerv1beta1.HelmRepo) (*repo.Entry, getter.Providers, error) {
 	entry := &repo.Entry{
-		Name:                  spec.Repository.Name,
-		URL:                   spec.Repository.URL,
-		Username:              spec.Repository.Username,
-		Password:              spec.Repository.Password,
-		CertFile:              spec.Repository.CertFile,
-		KeyFile:               spec.Repository.KeyFile,
-		CAFile:                spec.Repository.CAFile,
-		InsecureSkipTLSverify: spec.Repository.InsecureSkipTLSverify,
+		Name:                  r.Name,
+		URL:                   r.URL,
+		Username:              r.Username,
+		Password:              r.Password,
+		CertFile:              r.CertFile,
+		KeyFile:               r.KeyFile,
+		CAFile:                r.CAFile,
+		InsecureSkipTLSverify: r.InsecureSkipTLSverify,
+	}
+
+	providers, err := h.getterProvidersFor(ctx, r)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := h.AddorUpdateRepo(entry); err != nil {
+	if err = h.AddorUpdateRepo(entry, providers); err != nil {
 		utils.WarnOnError(err)
-		return nil, err
+		return nil, nil, err
+	}
+
+	return entry, providers, nil
+}
+
+func (h *helmer) load(ctx context.Context, spec helmerv1beta1.HelmChart) (*chart.Chart, string, error) {
+
+	// A range, or no version, resolves to the newer versions of the chart
+	// which cannot match the pinned digest
+	if spec.Digest != "" {
+		if _, err := semver.StrictNewVersion(spec.Version); err != nil {
+			return nil, "", fmt.Errorf("chart %s: the digest %s is pinned, the version %q must be exact: %w", spec.Name, spec.Digest, spec.Version, err)
+		}
+	}
+
+	entry, providers, err := h.addRepo(ctx, spec.Repository)
+	if err != nil {
+		return nil, "", err
 	}
 
 	act := action.ChartPathOptions{
@@ -155,19 +253,116 @@ func (h *helmer) Load(spec helmerv1beta1.HelmChart) (*chart.Chart, error) {
 	repoChartName := entry.Name + "/" + spec.Name
 	h.log.Info("Locating", "chart", repoChartName)
 
-	var err error
 	var path string
 
-	if path, err = act.LocateChart(repoChartName, h.settings); err != nil {
-		return nil, fmt.Errorf("Could not locate chart %s: %w", repoChartName, err)
+	mode := h.verification.Mode(entry.URL)
+	if mode != "" && strings.HasPrefix(entry.URL, "oci://") {
+		if mode == VerifyRequired {
+			return nil, "", fmt.Errorf("chart %s: the provenance of OCI charts cannot be verified", repoChartName)
+		}
+		h.log.Info("Not verifying the provenance of an OCI chart", "chart", repoChartName)
+		mode = ""
+	}
+
+	// Helm locates the charts with its own getters, the caching ones of the
+	// HTTP repositories require a downloader of ours
+	if mode != "" || isHTTP(entry.URL) {
+		if path, err = h.locateChart(entry, spec.Name, spec.Version, mode, providers); err != nil {
+			return nil, "", err
+		}
+	} else if path, err = act.LocateChart(repoChartName, h.settings); err != nil {
+		return nil, "", fmt.Errorf("Could not locate chart %s: %w", repoChartName, err)
+	}
+
+	digest, err := chartDigest(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not compute the digest of chart %s: %w", repoChartName, err)
+	}
+
+	if spec.Digest != "" && digest != spec.Digest {
+		return nil, "", fmt.Errorf("chart %s: digest %q does not match the pinned digest %s", repoChartName, digest, spec.Digest)
 	}
 
 	loaded, err := loader.Load(path)
 
-	return loaded, err
+	return loaded, digest, err
 
 }
 
+// chartDigest returns the sha256 digest of the chart archive at path, empty
+// if path is a directory.
+func chartDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if fi.IsDir() {
+		return "", nil
+	}
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// locateChart downloads the chart name of the repository entry to the
+// repository cache with the getters of providers, and verifies its provenance
+// file against the keyring as mode requires.
+func (h *helmer) locateChart(entry *repo.Entry, name, version string, mode VerificationMode, providers getter.Providers) (string, error) {
+	dl := downloader.ChartDownloader{
+		Out:     os.Stdout,
+		Keyring: h.verification.Keyring,
+		Getters: providers,
+		Options: []getter.Option{
+			getter.WithBasicAuth(entry.Username, entry.Password),
+			getter.WithTLSClientConfig(entry.CertFile, entry.KeyFile, entry.CAFile),
+			getter.WithInsecureSkipVerifyTLS(entry.InsecureSkipTLSverify),
+		},
+		RepositoryConfig: h.settings.RepositoryConfig,
+		RepositoryCache:  h.settings.RepositoryCache,
+	}
+
+	switch mode {
+	case VerifyRequired:
+		dl.Verify = downloader.VerifyAlways
+	case VerifyOptional:
+		dl.Verify = downloader.VerifyIfPossible
+	default:
+		dl.Verify = downloader.VerifyNever
+	}
+
+	if err := os.MkdirAll(h.settings.RepositoryCache, 0755); err != nil {
+		return "", err
+	}
+
+	path, ver, err := dl.DownloadTo(entry.Name+"/"+name, version, h.settings.RepositoryCache)
+	if err != nil {
+		return "", fmt.Errorf("could not download chart %s: %w", name, err)
+	}
+
+	if mode == "" {
+		return path, nil
+	}
+
+	if ver == nil || ver.SignedBy == nil {
+		h.log.Info("Chart is not signed", "chart", name, "repository", entry.URL)
+	} else {
+		h.log.Info("Chart provenance verified", "chart", name, "hash", ver.FileHash)
+	}
+
+	return path, nil
+}
+
 func (h *helmer) logWrap(format string, v ...interface{}) {
 	msg := fmt.Sprintf(format, v...)
 	h.log.Info("Helm", "internal", msg)
@@ -235,11 +430,12 @@ func (h *helmer) Run(
 	nodeSelector map[string]string,
 	kernelFullVersion string,
 	operatingSystemMajorMinor string,
-	debug bool) error {
+	debug bool,
+	postRenderer postrender.PostRenderer) error {
 
 	h.actionConfig = new(action.Configuration)
 
-	err := h.actionConfig.Init(h.settings.RESTClientGetter(), namespace, "configmaps", h.logWrap)
+	err := h.actionConfig.Init(h.settings.RESTClientGetter(), namespace, releaseStorage, h.logWrap)
 	if err != nil {
 		return fmt.Errorf("Cannot initialize helm action config: %w", err)
 	}
@@ -256,13 +452,14 @@ func (h *helmer) Run(
 	install.DisableHooks = false
 	install.IsUpgrade = false
 	install.Timeout = time.Second * 300
+	install.PostRenderer = postRenderer
 
 	if install.Version == "" {
 		install.Version = ">0.0.0-0"
 	}
 
 	if ch.Metadata.Type != "" && ch.Metadata.Type != "application" {
-		return fmt.Errorf("Chart has an unsupported type %s and can not be installed", ch.Metadata.Type)
+		return sroerrors.Wrap(sroerrors.ChartLoad, fmt.Errorf("Chart has an unsupported type %s and can not be installed", ch.Metadata.Type))
 	}
 
 	// Pre-install anything in the crd/ directory. We do this before Helm
@@ -272,30 +469,59 @@ func (h *helmer) Run(
 		h.log.Info("Release CRDs")
 		err := h.InstallCRDs(ctx, crds, owner, install.ReleaseName, install.Namespace)
 		if err != nil {
-			return fmt.Errorf("Cannot install CRDs: %w", err)
+			return sroerrors.Wrap(sroerrors.Apply, fmt.Errorf("Cannot install CRDs: %w", err))
 		}
 	}
 
+	_, span := tracing.Start(ctx, "render", "chart", ch.Metadata.Name)
 	rel, err := install.Run(&ch, vals)
+	span.End(err)
 	if err != nil {
 		utils.WarnOnError(err)
-		return err
+		return sroerrors.Wrap(sroerrors.Render, err)
+	}
+
+	if hookPostRenderer, ok := postRenderer.(HookPostRenderer); ok {
+		for _, hk := range rel.Hooks {
+			out, err := hookPostRenderer.RunHook(hk.Path, bytes.NewBufferString(hk.Manifest))
+			if err != nil {
+				return sroerrors.Wrap(sroerrors.Render, fmt.Errorf("could not post-render hook %s: %w", hk.Path, err))
+			}
+			hk.Manifest = out.String()
+		}
 	}
 
 	if debug {
-		json, err := json.MarshalIndent(vals, "", " ")
+		// The values fetched from secret providers may be rendered anywhere
+		var fetched []string
+		if sr, ok := owner.(*srov1beta1.SpecialResource); ok {
+			fetched = secrets.Fetched(sr, vals)
+		}
+
+		values, err := json.MarshalIndent(vals, "", " ")
 		if err != nil {
 			return err
 		}
-		h.log.Info("Debug active. Showing manifests", "json", json, "manifest", rel.Manifest)
+		h.log.Info("Debug active. Showing manifests", "json", redact.Literals(string(values), fetched), "manifest", redact.Literals(rel.Manifest, fetched))
 		for _, hook := range rel.Hooks {
-			h.log.Info("Debug active. Showing hooks", "name", hook.Name, "manifest", hook.Manifest)
+			h.log.Info("Debug active. Showing hooks", "name", hook.Name, "manifest", redact.Literals(hook.Manifest, fetched))
 		}
 	}
 
+	// Every state of the chart is released under the same name, the hooks of
+	// the states applied before are kept for the deletion but only the hooks
+	// of this state are run
+	current := rel.Hooks
+	rel.Hooks = append(current, h.otherStateHooks(rel.Name, ch, current)...)
+
 	// Store the release in history before continuing (new in Helm 3). We always know
 	// that this is a create operation.
 	if err = h.actionConfig.Releases.Create(rel); err != nil {
+		// The hooks of the stored release are run on deletion, keep them
+		// current
+		if errors.Is(err, driver.ErrReleaseExists) {
+			err = h.actionConfig.Releases.Update(rel)
+		}
 		// We could try to recover gracefully here, but since nothing has been installed
 		// yet, this is probably safer than trying to continue when we know storage is
 		// not working.
@@ -303,18 +529,21 @@ func (h *helmer) Run(
 		//return err
 	}
 
+	h.purgeLegacyReleases(rel.Name, namespace)
+
 	h.log.Info("Release pre-install hooks")
 	// pre-install hooks
 	if !install.DisableHooks {
-		if err := h.ExecHook(ctx, rel, release.HookPreInstall, owner, name, namespace); err != nil {
-			return h.failRelease(rel, fmt.Errorf("failed pre-install: %s", err))
+		if err := h.ExecHook(ctx, rel, current, release.HookPreInstall, owner, name, namespace); err != nil {
+			return h.failRelease(rel, fmt.Errorf("failed pre-install: %w", err))
 		}
 
 	}
 
 	h.log.Info("Release manifests")
+	applyCtx, span := tracing.Start(ctx, "apply", "chart", ch.Metadata.Name, "kernel", kernelFullVersion)
 	err = h.creator.CreateFromYAML(
-		ctx,
+		applyCtx,
 		[]byte(rel.Manifest),
 		h.ReleaseInstalled(name),
 		owner,
@@ -323,6 +552,7 @@ func (h *helmer) Run(
 		nodeSelector,
 		kernelFullVersion,
 		operatingSystemMajorMinor)
+	span.End(err)
 
 	if err != nil {
 		return h.failRelease(rel, err)
@@ -330,8 +560,8 @@ func (h *helmer) Run(
 
 	h.log.Info("Release post-install hooks")
 	if !install.DisableHooks {
-		if err := h.ExecHook(ctx, rel, release.HookPostInstall, owner, name, namespace); err != nil {
-			return h.failRelease(rel, fmt.Errorf("failed post-install: %s", err))
+		if err := h.ExecHook(ctx, rel, current, release.HookPostInstall, owner, name, namespace); err != nil {
+			return h.failRelease(rel, fmt.Errorf("failed post-install: %w", err))
 		}
 	}
 
@@ -360,7 +590,9 @@ func (x hookByWeight) Less(i, j int) bool {
 	return x[i].Weight < x[j].Weight
 }
 
-func (h *helmer) ExecHook(ctx context.Context, rl *release.Release, hook release.HookEvent, owner v1.Object, name string, namespace string) error {
+// ExecHook runs the hooks of candidates, all part of rl, bound to the event
+// hook. The status of the hooks is stored with rl.
+func (h *helmer) ExecHook(ctx context.Context, rl *release.Release, candidates []*release.Hook, hook release.HookEvent, owner v1.Object, name string, namespace string) error {
 
 	obj := unstructured.Unstructured{}
 	obj.SetKind("ConfigMap")
@@ -386,7 +618,7 @@ func (h *helmer) ExecHook(ctx context.Context, rl *release.Release, hook release
 
 	hooks := []*release.Hook{}
 
-	for _, h := range rl.Hooks {
+	for _, h := range candidates {
 		for _, e := range h.Events {
 			if e == hook {
 				hooks = append(hooks, h)
@@ -461,6 +693,103 @@ func (h *helmer) ExecHook(ctx context.Context, rl *release.Release, hook release
 	return nil
 }
 
+// RunDeleteHooks runs the pre-delete hooks of the last release of releaseName,
+// which holds the hooks of every state, in namespace, e.g. Jobs unloading the driver or cleaning files of the hosts,
+// before the objects of the release are deleted. Nothing is run if there is no
+// release.
+func (h *helmer) RunDeleteHooks(ctx context.Context, releaseName string, owner v1.Object, name string, namespace string) error {
+
+	var rel *release.Release
+
+	// The releases not reconciled since they moved to the secrets driver are
+	// still in ConfigMaps
+	for _, storage := range []string{releaseStorage, legacyReleaseStorage} {
+		h.actionConfig = new(action.Configuration)
+
+		if err := h.actionConfig.Init(h.settings.RESTClientGetter(), namespace, storage, h.logWrap); err != nil {
+			return fmt.Errorf("Cannot initialize helm action config: %w", err)
+		}
+
+		var err error
+		rel, err = h.actionConfig.Releases.Last(releaseName)
+		if err == nil {
+			break
+		} else if !errors.Is(err, driver.ErrReleaseNotFound) {
+			return fmt.Errorf("could not get the release %s: %w", releaseName, err)
+		}
+	}
+
+	if rel == nil {
+		h.log.Info("No release, skipping the pre-delete hooks", "release", releaseName)
+		return nil
+	}
+
+	h.log.Info("Release pre-delete hooks")
+	if err := h.ExecHook(ctx, rel, rel.Hooks, release.HookPreDelete, owner, name, namespace); err != nil {
+		return fmt.Errorf("failed pre-delete: %w", err)
+	}
+
+	return nil
+}
+
+// otherStateHooks returns the hooks of the last release of releaseName that
+// were not rendered from ch, i.e. the hooks of the other states of the chart.
+// The hooks rendered from the templates of ch, or again in current, are
+// superseded by current.
+func (h *helmer) otherStateHooks(releaseName string, ch chart.Chart, current []*release.Hook) []*release.Hook {
+	previous, err := h.actionConfig.Releases.Last(releaseName)
+	if err != nil {
+		if !errors.Is(err, driver.ErrReleaseNotFound) {
+			h.log.Info("Could not get the hooks of the other states", "release", releaseName, "error", err)
+		}
+		return nil
+	}
+
+	superseded := make(map[string]bool)
+	for _, t := range ch.Templates {
+		superseded[path.Join(ch.Name(), t.Name)] = true
+	}
+	for _, hk := range current {
+		superseded[hk.Path] = true
+	}
+
+	hooks := []*release.Hook{}
+	for _, hk := range previous.Hooks {
+		if !superseded[hk.Path] {
+			hooks = append(hooks, hk)
+		}
+	}
+	return hooks
+}
+
+// purgeLegacyReleases deletes the revisions of releaseName kept in ConfigMaps
+// by the legacy storage driver, they hold the values in plain text. Errors are
+// only logged, the release is current in the secrets driver.
+func (h *helmer) purgeLegacyReleases(releaseName string, namespace string) {
+	clientset, err := h.actionConfig.KubernetesClientSet()
+	if err != nil {
+		h.log.Info("Could not purge the legacy releases", "release", releaseName, "error", err)
+		return
+	}
+
+	legacy := driver.NewConfigMaps(clientset.CoreV1().ConfigMaps(namespace))
+	legacy.Log = h.logWrap
+
+	revisions, err := legacy.Query(map[string]string{"name": releaseName, "owner": "helm"})
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return
+	} else if err != nil {
+		h.log.Info("Could not purge the legacy releases", "release", releaseName, "error", err)
+		return
+	}
+
+	for _, rev := range revisions {
+		if _, err = legacy.Delete(fmt.Sprintf("sh.helm.release.v1.%s.v%d", rev.Name, rev.Version)); err != nil {
+			h.log.Info("Could not purge the legacy release", "release", releaseName, "version", rev.Version, "error", err)
+		}
+	}
+}
+
 func (h *helmer) ReleaseInstalled(releaseName string) bool {
 
 	hist, err := h.actionConfig.Releases.History(releaseName)
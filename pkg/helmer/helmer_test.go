@@ -50,7 +50,7 @@ var _ = Describe("helmer_InstallCRDs", func() {
 			CreateFromYAML(context.TODO(), nil, false, owner, name, namespace, nil, "", "").
 			Return(randomError)
 
-		err := helmer.NewHelmer(mockCreator, cli.New(), mockKubeClient).InstallCRDs(context.TODO(), nil, owner, name, namespace)
+		err := helmer.NewHelmer(mockCreator, cli.New(), mockKubeClient, nil, helmer.ChartVerification{}).InstallCRDs(context.TODO(), nil, owner, name, namespace)
 		Expect(err).To(Equal(randomError))
 	})
 
@@ -78,7 +78,7 @@ def
 			EXPECT().
 			CreateFromYAML(context.TODO(), manifests, false, owner, name, namespace, nil, "", "")
 
-		err := helmer.NewHelmer(mockCreator, cli.New(), mockKubeClient).InstallCRDs(context.TODO(), crds, owner, name, namespace)
+		err := helmer.NewHelmer(mockCreator, cli.New(), mockKubeClient, nil, helmer.ChartVerification{}).InstallCRDs(context.TODO(), crds, owner, name, namespace)
 		Expect(err).NotTo(HaveOccurred())
 	})
 })
@@ -100,8 +100,8 @@ var _ = Describe("helmer_Run", func() {
 		}
 
 		err := helmer.
-			NewHelmer(mockCreator, cli.New(), mockKubeClient).
-			Run(context.TODO(), ch, nil, owner, name, namespace, nil, "", "", false)
+			NewHelmer(mockCreator, cli.New(), mockKubeClient, nil, helmer.ChartVerification{}).
+			Run(context.TODO(), ch, nil, owner, name, namespace, nil, "", "", false, nil)
 		Expect(err).To(HaveOccurred())
 	})
 
@@ -127,8 +127,8 @@ var _ = Describe("helmer_Run", func() {
 			Return(randomError)
 
 		err := helmer.
-			NewHelmer(mockCreator, cli.New(), mockKubeClient).
-			Run(context.TODO(), ch, nil, owner, name, namespace, nil, "", "", false)
+			NewHelmer(mockCreator, cli.New(), mockKubeClient, nil, helmer.ChartVerification{}).
+			Run(context.TODO(), ch, nil, owner, name, namespace, nil, "", "", false, nil)
 		Expect(errors.Is(err, randomError)).To(BeTrue())
 	})
 })
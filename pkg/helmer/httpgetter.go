@@ -0,0 +1,331 @@
+package helmer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+const (
+	// httpTimeout bounds a request to a chart repository, so that a stalled
+	// repository fails the load and the cached copy is used instead.
+	httpTimeout = 2 * time.Minute
+
+	// maxHTTPCacheSize bounds the size of the cached responses, the least
+	// recently used ones are evicted above it.
+	maxHTTPCacheSize = 256 << 20
+)
+
+// httpCache keeps the index files and the charts fetched from the chart
+// repositories on disk, keyed by the digest of their content, along with the
+// validators of the response they came from.
+type httpCache struct {
+	dir     string
+	maxSize int64
+	mu      sync.Mutex
+}
+
+type cacheEntry struct {
+	Digest       string `json:"digest"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+func newHTTPCache(dir string) *httpCache {
+	return &httpCache{dir: dir, maxSize: maxHTTPCacheSize}
+}
+
+func (c *httpCache) entryPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, "urls", hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *httpCache) blobPath(digest string) string {
+	return filepath.Join(c.dir, "blobs", digest)
+}
+
+// lookup returns the cached response of key, nil if there is none.
+func (c *httpCache) lookup(key string) (*cacheEntry, []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	raw, err := os.ReadFile(c.entryPath(key))
+	if err != nil {
+		return nil, nil
+	}
+
+	entry := &cacheEntry{}
+	if err = json.Unmarshal(raw, entry); err != nil {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(c.blobPath(entry.Digest))
+	if err != nil {
+		return nil, nil
+	}
+
+	// The modification time orders the blobs for the eviction
+	now := time.Now()
+	_ = os.Chtimes(c.blobPath(entry.Digest), now, now)
+
+	return entry, data
+}
+
+// store caches data as the response of key.
+func (c *httpCache) store(key string, etag, lastModified string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sum := sha256.Sum256(data)
+	entry := cacheEntry{Digest: hex.EncodeToString(sum[:]), ETag: etag, LastModified: lastModified}
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{filepath.Dir(c.entryPath(key)), filepath.Dir(c.blobPath(entry.Digest))} {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	if err = os.WriteFile(c.blobPath(entry.Digest), data, 0644); err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(c.entryPath(key), raw, 0644); err != nil {
+		return err
+	}
+
+	return c.evict()
+}
+
+// evict removes the least recently used blobs until the cache fits in
+// maxSize, and the entries pointing to them.
+func (c *httpCache) evict() error {
+	blobs, err := os.ReadDir(filepath.Join(c.dir, "blobs"))
+	if err != nil {
+		return err
+	}
+
+	infos := make([]fs.FileInfo, 0, len(blobs))
+	size := int64(0)
+
+	for _, blob := range blobs {
+		info, err := blob.Info()
+		if err != nil {
+			continue
+		}
+		infos = append(infos, info)
+		size += info.Size()
+	}
+
+	if size <= c.maxSize {
+		return nil
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+
+	evicted := make(map[string]bool)
+
+	// The newest blob is the one just stored, it is kept even if it is
+	// larger than the cache
+	for _, info := range infos[:len(infos)-1] {
+		if size <= c.maxSize {
+			break
+		}
+		if err = os.Remove(c.blobPath(info.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		evicted[info.Name()] = true
+		size -= info.Size()
+	}
+
+	entries, err := os.ReadDir(filepath.Join(c.dir, "urls"))
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		path := filepath.Join(c.dir, "urls", e.Name())
+
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		entry := cacheEntry{}
+		if err = json.Unmarshal(raw, &entry); err != nil || evicted[entry.Digest] {
+			if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// httpGetter fetches the files of a chart repository over HTTP. The
+// responses are cached and revalidated with their ETag or Last-Modified
+// header, and the cached copy is served when the repository cannot be
+// reached, so that the charts already deployed can still be reconciled.
+type httpGetter struct {
+	log   logr.Logger
+	cache *httpCache
+
+	// scheme and host are the ones of the repository, the credentials are
+	// not sent to the other hosts its index may point to, nor in clear text.
+	scheme   string
+	host     string
+	username string
+	password string
+	token    string
+	client   *http.Client
+
+	// identity tells the credentials apart in the cache, so that a response
+	// fetched with some credentials is never served for other ones.
+	identity string
+}
+
+// httpCredentials are the credentials of a chart repository, the PEM encoded
+// certificates included.
+type httpCredentials struct {
+	username              string
+	password              string
+	token                 string
+	cert                  []byte
+	key                   []byte
+	ca                    []byte
+	insecureSkipTLSverify bool
+}
+
+func newHTTPGetter(log logr.Logger, cache *httpCache, repoURL string, creds httpCredentials) (*httpGetter, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL %s: %w", repoURL, err)
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: creds.insecureSkipTLSverify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if len(creds.cert) > 0 || len(creds.key) > 0 {
+		pair, err := tls.X509KeyPair(creds.cert, creds.key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	if len(creds.ca) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(creds.ca) {
+			return nil, errors.New("no CA certificate found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	identity := sha256.New()
+	for _, field := range [][]byte{[]byte(creds.username), []byte(creds.password), []byte(creds.token), creds.cert} {
+		identity.Write(field)
+		identity.Write([]byte{0})
+	}
+
+	return &httpGetter{
+		log:      log,
+		cache:    cache,
+		scheme:   u.Scheme,
+		host:     u.Host,
+		username: creds.username,
+		password: creds.password,
+		token:    creds.token,
+		client:   &http.Client{Transport: transport, Timeout: httpTimeout},
+		identity: hex.EncodeToString(identity.Sum(nil)),
+	}, nil
+}
+
+// Get implements getter.Getter, the options are ignored: the getter is
+// created with the credentials of the repository.
+func (g *httpGetter) Get(href string, _ ...getter.Option) (*bytes.Buffer, error) {
+	req, err := http.NewRequest(http.MethodGet, href, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The client certificate is sent to every host, the other credentials
+	// only to the one of the repository
+	key := href + "\x00" + g.identity
+
+	if req.URL.Scheme == g.scheme && req.URL.Host == g.host {
+		if g.token != "" {
+			req.Header.Set("Authorization", "Bearer "+g.token)
+		} else if g.username != "" || g.password != "" {
+			req.SetBasicAuth(g.username, g.password)
+		}
+	}
+
+	cached, data := g.cache.lookup(key)
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		if cached != nil {
+			g.log.Info("Repository not reachable, using the cached copy", "url", href, "error", err)
+			return bytes.NewBuffer(data), nil
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return bytes.NewBuffer(data), nil
+	case resp.StatusCode >= http.StatusInternalServerError && cached != nil:
+		g.log.Info("Repository not available, using the cached copy", "url", href, "status", resp.Status)
+		return bytes.NewBuffer(data), nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch %s : %s", href, resp.Status)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if _, err = io.Copy(buf, resp.Body); err != nil {
+		return nil, err
+	}
+
+	if err = g.cache.store(key, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), buf.Bytes()); err != nil {
+		g.log.Info("Could not cache", "url", href, "error", err)
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,79 @@
+package helmer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+var _ = Describe("helmer_Load from an HTTP repository", func() {
+	const etag = `"v1"`
+
+	var (
+		h           helmer.Helmer
+		server      *httptest.Server
+		revalidated []string
+	)
+
+	load := func() error {
+		_, _, err := h.Load(context.TODO(), helmerv1beta1.HelmChart{
+			Name:       "test-chart",
+			Version:    "0.1.0",
+			Repository: helmerv1beta1.HelmRepo{Name: "cached", URL: server.URL},
+		})
+
+		return err
+	}
+
+	BeforeEach(func() {
+		revalidated = nil
+		files := http.FileServer(http.Dir("testdata"))
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == etag {
+				revalidated = append(revalidated, r.URL.Path)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.Header().Set("ETag", etag)
+			files.ServeHTTP(w, r)
+		}))
+		DeferCleanup(server.Close)
+
+		settings := cli.New()
+		settings.RepositoryConfig = filepath.Join(GinkgoT().TempDir(), "repositories.yaml")
+		settings.RepositoryCache = GinkgoT().TempDir()
+
+		h = helmer.NewHelmer(mockCreator, settings, mockKubeClient, nil, helmer.ChartVerification{})
+	})
+
+	It("should revalidate the cached index and chart with their ETag", func() {
+		Expect(load()).To(Succeed())
+		Expect(revalidated).To(BeEmpty())
+
+		Expect(load()).To(Succeed())
+		Expect(revalidated).To(Equal([]string{"/index.yaml", "/test-chart-0.1.0.tgz"}))
+	})
+
+	It("should use the cached index and chart if the repository is not reachable", func() {
+		Expect(load()).To(Succeed())
+
+		server.Close()
+
+		Expect(load()).To(Succeed())
+	})
+
+	It("should fail if the repository is not reachable and nothing is cached", func() {
+		server.Close()
+
+		Expect(load()).To(HaveOccurred())
+	})
+})
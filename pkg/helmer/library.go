@@ -0,0 +1,71 @@
+package helmer
+
+import (
+	"fmt"
+	"strings"
+
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// ParseLibraryCharts parses a comma separated list of library charts, each one
+// given as <repository URL>#<name>@<version>, e.g.
+// file:///charts/library#sro-helpers@0.0.1.
+func ParseLibraryCharts(s string) ([]helmerv1beta1.HelmChart, error) {
+	libraries := make([]helmerv1beta1.HelmChart, 0)
+
+	if s == "" {
+		return libraries, nil
+	}
+
+	for _, entry := range strings.Split(s, ",") {
+		url, ref, ok := strings.Cut(entry, "#")
+		if !ok || url == "" {
+			return nil, fmt.Errorf("library chart %q: missing repository URL", entry)
+		}
+
+		name, version, ok := strings.Cut(ref, "@")
+		if !ok || name == "" || version == "" {
+			return nil, fmt.Errorf("library chart %q: expected <name>@<version> after the URL", entry)
+		}
+
+		libraries = append(libraries, helmerv1beta1.HelmChart{
+			Name:    name,
+			Version: version,
+			Repository: helmerv1beta1.HelmRepo{
+				Name: "library-" + name,
+				URL:  url,
+			},
+		})
+	}
+
+	return libraries, nil
+}
+
+// AddLibraries adds the library charts as dependencies of ch so that their
+// named templates can be included by its templates. A dependency the chart
+// already ships with takes precedence over the library of the same name.
+func AddLibraries(ch *chart.Chart, libraries []*chart.Chart) error {
+	for _, lib := range libraries {
+		if !strings.EqualFold(lib.Metadata.Type, "library") {
+			return fmt.Errorf("chart %s is not a library chart", lib.Name())
+		}
+
+		if hasDependency(ch, lib.Name()) {
+			continue
+		}
+
+		ch.AddDependency(lib)
+	}
+
+	return nil
+}
+
+func hasDependency(ch *chart.Chart, name string) bool {
+	for _, dep := range ch.Dependencies() {
+		if dep.Name() == name {
+			return true
+		}
+	}
+	return false
+}
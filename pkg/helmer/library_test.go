@@ -0,0 +1,83 @@
+package helmer_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+var _ = Describe("ParseLibraryCharts", func() {
+	It("should return no library for an empty string", func() {
+		libraries, err := helmer.ParseLibraryCharts("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(libraries).To(BeEmpty())
+	})
+
+	It("should parse all the library charts", func() {
+		libraries, err := helmer.ParseLibraryCharts("file:///charts/library#sro-helpers@0.0.1,https://example.com/charts#scc@1.2.3")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(libraries).To(Equal([]helmerv1beta1.HelmChart{
+			{
+				Name:       "sro-helpers",
+				Version:    "0.0.1",
+				Repository: helmerv1beta1.HelmRepo{Name: "library-sro-helpers", URL: "file:///charts/library"},
+			},
+			{
+				Name:       "scc",
+				Version:    "1.2.3",
+				Repository: helmerv1beta1.HelmRepo{Name: "library-scc", URL: "https://example.com/charts"},
+			},
+		}))
+	})
+
+	DescribeTable("should fail on invalid entries",
+		func(s string) {
+			_, err := helmer.ParseLibraryCharts(s)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("no URL", "sro-helpers@0.0.1"),
+		Entry("empty URL", "#sro-helpers@0.0.1"),
+		Entry("no version", "file:///charts/library#sro-helpers"),
+		Entry("empty name", "file:///charts/library#@0.0.1"),
+	)
+})
+
+var _ = Describe("AddLibraries", func() {
+	newChart := func(name, version, typ string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{Name: name, Version: version, Type: typ},
+		}
+	}
+
+	It("should add the libraries as dependencies", func() {
+		ch := newChart("simple-kmod", "0.0.1", "application")
+
+		err := helmer.AddLibraries(ch, []*chart.Chart{newChart("sro-helpers", "0.0.1", "library")})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ch.Dependencies()).To(HaveLen(1))
+		Expect(ch.Dependencies()[0].Name()).To(Equal("sro-helpers"))
+		Expect(ch.Dependencies()[0].Parent()).To(Equal(ch))
+	})
+
+	It("should keep the dependency shipped with the chart", func() {
+		ch := newChart("simple-kmod", "0.0.1", "application")
+		ch.AddDependency(newChart("sro-helpers", "0.0.2", "library"))
+
+		err := helmer.AddLibraries(ch, []*chart.Chart{newChart("sro-helpers", "0.0.1", "library")})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ch.Dependencies()).To(HaveLen(1))
+		Expect(ch.Dependencies()[0].Metadata.Version).To(Equal("0.0.2"))
+	})
+
+	It("should fail if a library is an application chart", func() {
+		ch := newChart("simple-kmod", "0.0.1", "application")
+
+		err := helmer.AddLibraries(ch, []*chart.Chart{newChart("ping-pong", "0.0.1", "application")})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,73 @@
+package helmer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VerificationMode tells whether the charts of a repository must be signed.
+type VerificationMode string
+
+const (
+	// VerifyRequired rejects the charts without a valid provenance file.
+	VerifyRequired VerificationMode = "Required"
+	// VerifyOptional rejects the charts with an invalid provenance file, the
+	// charts without one are loaded.
+	VerifyOptional VerificationMode = "Optional"
+)
+
+// ChartVerification is the provenance verification of the charts loaded from
+// the repositories it lists, the charts of the other repositories are not
+// verified.
+type ChartVerification struct {
+	// Keyring is the path of the keyring holding the public keys of the
+	// trusted chart signers.
+	Keyring string
+
+	// Repositories maps a repository URL to its verification mode.
+	Repositories map[string]VerificationMode
+}
+
+// ParseChartVerification parses a comma separated list of repositories, each
+// one given as <repository URL>=<Required|Optional>, e.g.
+// https://example.com/charts=Required.
+func ParseChartVerification(s, keyring string) (ChartVerification, error) {
+	v := ChartVerification{
+		Keyring:      keyring,
+		Repositories: make(map[string]VerificationMode),
+	}
+
+	if s == "" {
+		return v, nil
+	}
+
+	for _, entry := range strings.Split(s, ",") {
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 {
+			return v, fmt.Errorf("chart verification %q: expected <repository URL>=<mode>", entry)
+		}
+
+		mode := VerificationMode(entry[i+1:])
+		if mode != VerifyRequired && mode != VerifyOptional {
+			return v, fmt.Errorf("chart verification %q: mode must be %s or %s", entry, VerifyRequired, VerifyOptional)
+		}
+
+		v.Repositories[normalizeURL(entry[:i])] = mode
+	}
+
+	if len(v.Repositories) > 0 && keyring == "" {
+		return v, fmt.Errorf("a keyring is required to verify the charts")
+	}
+
+	return v, nil
+}
+
+// Mode returns the verification mode of the repository url, empty if its
+// charts are not verified.
+func (v ChartVerification) Mode(url string) VerificationMode {
+	return v.Repositories[normalizeURL(url)]
+}
+
+func normalizeURL(url string) string {
+	return strings.TrimSuffix(strings.TrimSpace(url), "/")
+}
@@ -0,0 +1,137 @@
+package helmer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"golang.org/x/crypto/openpgp"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/provenance"
+)
+
+var _ = Describe("ParseChartVerification", func() {
+	It("should verify nothing for an empty string", func() {
+		v, err := helmer.ParseChartVerification("", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v.Mode("https://example.com/charts")).To(BeEmpty())
+	})
+
+	It("should parse all the repositories", func() {
+		v, err := helmer.ParseChartVerification("https://example.com/charts/=Required,file:///charts/example=Optional", "/keyring.gpg")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(v.Keyring).To(Equal("/keyring.gpg"))
+		Expect(v.Mode("https://example.com/charts")).To(Equal(helmer.VerifyRequired))
+		Expect(v.Mode("file:///charts/example/")).To(Equal(helmer.VerifyOptional))
+		Expect(v.Mode("https://example.com/other")).To(BeEmpty())
+	})
+
+	DescribeTable("should fail on invalid entries",
+		func(s, keyring string) {
+			_, err := helmer.ParseChartVerification(s, keyring)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("no mode", "https://example.com/charts", "/keyring.gpg"),
+		Entry("no URL", "=Required", "/keyring.gpg"),
+		Entry("unknown mode", "https://example.com/charts=Always", "/keyring.gpg"),
+		Entry("no keyring", "https://example.com/charts=Required", ""),
+	)
+})
+
+var _ = Describe("helmer_Load", func() {
+	const chartFile = "test-chart-0.1.0.tgz"
+
+	var (
+		repoDir string
+		server  *httptest.Server
+		signer  *openpgp.Entity
+		keyring string
+	)
+
+	copyFile := func(src, dst string) {
+		data, err := os.ReadFile(src)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(dst, data, 0644)).To(Succeed())
+	}
+
+	writeKeyring := func(e *openpgp.Entity) string {
+		f, err := os.Create(filepath.Join(GinkgoT().TempDir(), "keyring.gpg"))
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+
+		Expect(e.Serialize(f)).To(Succeed())
+
+		return f.Name()
+	}
+
+	sign := func(e *openpgp.Entity) {
+		sig, err := (&provenance.Signatory{Entity: e}).ClearSign(filepath.Join(repoDir, chartFile))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(repoDir, chartFile+".prov"), []byte(sig), 0644)).To(Succeed())
+	}
+
+	load := func(mode helmer.VerificationMode) error {
+		settings := cli.New()
+		settings.RepositoryConfig = filepath.Join(GinkgoT().TempDir(), "repositories.yaml")
+		settings.RepositoryCache = GinkgoT().TempDir()
+
+		verification := helmer.ChartVerification{
+			Keyring:      keyring,
+			Repositories: map[string]helmer.VerificationMode{server.URL: mode},
+		}
+
+		_, _, err := helmer.NewHelmer(mockCreator, settings, mockKubeClient, nil, verification).
+			Load(context.TODO(), helmerv1beta1.HelmChart{
+				Name:       "test-chart",
+				Version:    "0.1.0",
+				Repository: helmerv1beta1.HelmRepo{Name: "test", URL: server.URL},
+			})
+
+		return err
+	}
+
+	BeforeEach(func() {
+		repoDir = GinkgoT().TempDir()
+		copyFile("testdata/index.yaml", filepath.Join(repoDir, "index.yaml"))
+		copyFile(filepath.Join("testdata", chartFile), filepath.Join(repoDir, chartFile))
+
+		server = httptest.NewServer(http.FileServer(http.Dir(repoDir)))
+		DeferCleanup(server.Close)
+
+		var err error
+		signer, err = openpgp.NewEntity("SRO Test", "", "sro@example.com", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		keyring = writeKeyring(signer)
+	})
+
+	It("should reject an unsigned chart if verification is required", func() {
+		Expect(load(helmer.VerifyRequired)).To(HaveOccurred())
+	})
+
+	It("should load an unsigned chart if verification is optional", func() {
+		Expect(load(helmer.VerifyOptional)).To(Succeed())
+	})
+
+	It("should load a chart signed by a trusted key", func() {
+		sign(signer)
+
+		Expect(load(helmer.VerifyRequired)).To(Succeed())
+	})
+
+	It("should reject a chart signed by an unknown key, even if verification is optional", func() {
+		other, err := openpgp.NewEntity("Someone Else", "", "else@example.com", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		sign(other)
+
+		Expect(load(helmer.VerifyOptional)).To(HaveOccurred())
+	})
+})
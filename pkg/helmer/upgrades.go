@@ -0,0 +1,75 @@
+package helmer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// upgradeConstraint returns the semver constraint the versions the chart at
+// version can be upgraded to with policy must satisfy, empty for any version.
+func upgradeConstraint(policy helmerv1beta1.UpgradePolicy, version string) (string, error) {
+	if version == "" {
+		if policy == helmerv1beta1.UpgradeLatest {
+			return "", nil
+		}
+		return "", fmt.Errorf("the %s upgrade policy requires a chart version", policy)
+	}
+
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("invalid chart version %s: %w", version, err)
+	}
+
+	switch policy {
+	case helmerv1beta1.UpgradePatch:
+		return fmt.Sprintf(">= %s, < %d.%d.0", v, v.Major(), v.Minor()+1), nil
+	case helmerv1beta1.UpgradeMinor:
+		return fmt.Sprintf(">= %s, < %d.0.0", v, v.Major()+1), nil
+	case helmerv1beta1.UpgradeLatest:
+		return fmt.Sprintf(">= %s", v), nil
+	}
+
+	return "", fmt.Errorf("unknown upgrade policy %q", policy)
+}
+
+// LatestVersion returns the newest version of the chart of spec in its
+// repository allowed by its upgrade policy, the version of spec if the chart
+// is pinned.
+func (h *helmer) LatestVersion(ctx context.Context, spec helmerv1beta1.HelmChart) (string, error) {
+	if spec.UpgradePolicy == "" || spec.UpgradePolicy == helmerv1beta1.UpgradePinned {
+		return spec.Version, nil
+	}
+
+	constraint, err := upgradeConstraint(spec.UpgradePolicy, spec.Version)
+	if err != nil {
+		return "", fmt.Errorf("chart %s: %w", spec.Name, err)
+	}
+
+	if strings.HasPrefix(spec.Repository.URL, "oci://") {
+		return "", fmt.Errorf("chart %s: OCI repositories have no index to look for upgrades in", spec.Name)
+	}
+
+	entry, _, err := h.addRepo(ctx, spec.Repository)
+	if err != nil {
+		return "", err
+	}
+
+	index, err := repo.LoadIndexFile(filepath.Join(h.settings.RepositoryCache, helmpath.CacheIndexFile(entry.Name)))
+	if err != nil {
+		return "", fmt.Errorf("could not load the index of repository %s: %w", entry.Name, err)
+	}
+
+	cv, err := index.Get(spec.Name, constraint)
+	if err != nil {
+		return "", fmt.Errorf("could not find a version of chart %s: %w", spec.Name, err)
+	}
+
+	return cv.Version, nil
+}
@@ -0,0 +1,72 @@
+package helmer_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+var _ = Describe("helmer_LatestVersion", func() {
+	var (
+		h      helmer.Helmer
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		var entries strings.Builder
+		for _, v := range []string{"2.0.0", "1.2.0-rc.1", "1.1.0", "1.0.1", "1.0.0"} {
+			fmt.Fprintf(&entries, "  - apiVersion: v2\n    name: test-chart\n    urls:\n    - test-chart-%s.tgz\n    version: %s\n", v, v)
+		}
+		index := "apiVersion: v1\nentries:\n  test-chart:\n" + entries.String()
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, index)
+		}))
+		DeferCleanup(server.Close)
+
+		settings := cli.New()
+		settings.RepositoryConfig = filepath.Join(GinkgoT().TempDir(), "repositories.yaml")
+		settings.RepositoryCache = GinkgoT().TempDir()
+
+		h = helmer.NewHelmer(mockCreator, settings, mockKubeClient, nil, helmer.ChartVerification{})
+	})
+
+	DescribeTable("should return the newest version allowed by the upgrade policy",
+		func(policy helmerv1beta1.UpgradePolicy, version, expected string) {
+			latest, err := h.LatestVersion(context.TODO(), helmerv1beta1.HelmChart{
+				Name:          "test-chart",
+				Version:       version,
+				UpgradePolicy: policy,
+				Repository:    helmerv1beta1.HelmRepo{Name: "upgrades", URL: server.URL},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(latest).To(Equal(expected))
+		},
+		Entry("no policy", helmerv1beta1.UpgradePolicy(""), "1.0.0", "1.0.0"),
+		Entry(nil, helmerv1beta1.UpgradePinned, "1.0.0", "1.0.0"),
+		Entry(nil, helmerv1beta1.UpgradePatch, "1.0.0", "1.0.1"),
+		Entry(nil, helmerv1beta1.UpgradeMinor, "1.0.0", "1.1.0"),
+		Entry(nil, helmerv1beta1.UpgradeLatest, "1.0.0", "2.0.0"),
+		Entry("Latest without a version", helmerv1beta1.UpgradeLatest, "", "2.0.0"),
+	)
+
+	It("should fail without a version for the Patch and Minor policies", func() {
+		_, err := h.LatestVersion(context.TODO(), helmerv1beta1.HelmChart{
+			Name:          "test-chart",
+			UpgradePolicy: helmerv1beta1.UpgradeMinor,
+			Repository:    helmerv1beta1.HelmRepo{Name: "upgrades", URL: server.URL},
+		})
+
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,6 +1,7 @@
 package kernel
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -12,6 +13,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
+// FullVersionLabel is the NFD label of a node holding its kernel version.
+const FullVersionLabel = "feature.node.kubernetes.io/kernel-version.full"
+
 //go:generate mockgen -source=kernel.go -package=kernel -destination=mock_kernel_api.go
 
 type KernelData interface {
@@ -19,6 +23,7 @@ type KernelData interface {
 	IsObjectAffine(obj client.Object) bool
 	FullVersion(*corev1.NodeList) (string, error)
 	PatchVersion(kernelFullVersion string) (string, error)
+	ParseVersion(kernelFullVersion string) (Version, error)
 }
 
 type kernelData struct {
@@ -75,22 +80,13 @@ func (k *kernelData) SetAffineAttributes(obj *unstructured.Unstructured,
 
 func (k *kernelData) setVersionNodeAffinity(obj *unstructured.Unstructured, kernelFullVersion string) error {
 
-	if strings.Compare(obj.GetKind(), "DaemonSet") == 0 ||
-		strings.Compare(obj.GetKind(), "Deployment") == 0 ||
-		strings.Compare(obj.GetKind(), "Statefulset") == 0 {
-		if err := k.versionNodeAffinity(kernelFullVersion, obj, "spec", "template", "spec", "nodeSelector"); err != nil {
-			return errors.Wrap(err, "Cannot setup DaemonSet kernel version affinity")
-		}
-	}
-	if strings.Compare(obj.GetKind(), "Pod") == 0 {
-		if err := k.versionNodeAffinity(kernelFullVersion, obj, "spec", "nodeSelector"); err != nil {
-			return errors.Wrap(err, "Cannot setup Pod kernel version affinity")
-		}
+	fields, ok := utils.NodeSelectorFields[obj.GetKind()]
+	if !ok {
+		return nil
 	}
-	if strings.Compare(obj.GetKind(), "BuildConfig") == 0 {
-		if err := k.versionNodeAffinity(kernelFullVersion, obj, "spec", "nodeSelector"); err != nil {
-			return errors.Wrap(err, "Cannot setup BuildConfig kernel version affinity")
-		}
+
+	if err := k.versionNodeAffinity(kernelFullVersion, obj, fields...); err != nil {
+		return errors.Wrap(err, "Cannot setup "+obj.GetKind()+" kernel version affinity")
 	}
 
 	return nil
@@ -98,6 +94,11 @@ func (k *kernelData) setVersionNodeAffinity(obj *unstructured.Unstructured, kern
 
 func (k *kernelData) versionNodeAffinity(kernelFullVersion string, obj *unstructured.Unstructured, fields ...string) error {
 
+	// A null nodeSelector, e.g. the one of a Build, is an empty one
+	if v, found, _ := unstructured.NestedFieldNoCopy(obj.Object, fields...); found && v == nil {
+		unstructured.RemoveNestedField(obj.Object, fields...)
+	}
+
 	nodeSelector, found, err := unstructured.NestedMap(obj.Object, fields...)
 	if err != nil {
 		return err
@@ -107,7 +108,7 @@ func (k *kernelData) versionNodeAffinity(kernelFullVersion string, obj *unstruct
 		nodeSelector = make(map[string]interface{})
 	}
 
-	nodeSelector["feature.node.kubernetes.io/kernel-version.full"] = kernelFullVersion
+	nodeSelector[FullVersionLabel] = kernelFullVersion
 
 	if err := unstructured.SetNestedMap(obj.Object, nodeSelector, fields...); err != nil {
 		return errors.Wrap(err, "Cannot update nodeSelector")
@@ -139,9 +140,8 @@ func (k *kernelData) FullVersion(nodeList *corev1.NodeList) (string, error) {
 
 		// We only need to check for the key, the value
 		// is available if the key is there
-		short := "feature.node.kubernetes.io/kernel-version.full"
-		if kernelFullVersion, found = labels[short]; !found {
-			return "", errors.New("Label " + short + " not found is NFD running? Check node labels")
+		if kernelFullVersion, found = labels[FullVersionLabel]; !found {
+			return "", errors.New("Label " + FullVersionLabel + " not found is NFD running? Check node labels")
 		}
 	}
 
@@ -155,14 +155,16 @@ func (k *kernelData) FullVersion(nodeList *corev1.NodeList) (string, error) {
 // zzz=Patch number = 45
 func (k *kernelData) PatchVersion(kernelFullVersion string) (string, error) {
 
-	version := strings.Split(kernelFullVersion, "-")
-	// Happens only if kernel full version has no patch version sep by "-"
-	if len(version) == 1 {
-		short := strings.Split(kernelFullVersion, ".")
-		return short[0] + "." + short[1] + "." + short[2], nil
+	v, err := k.ParseVersion(kernelFullVersion)
+	if err != nil {
+		return "", err
+	}
+
+	short := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Build == "" {
+		return short, nil
 	}
 
-	patch := strings.Split(version[1], ".")
 	// version.major.minor-patch
-	return version[0] + "-" + patch[0], nil
+	return short + "-" + strings.Split(v.Build, ".")[0], nil
 }
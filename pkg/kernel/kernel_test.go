@@ -71,6 +71,7 @@ var _ = Describe("AffineAttributes", func() {
 			Expect(v).To(Equal(expectedSelector))
 		},
 		Entry("Pod", "Pod"),
+		Entry("Build", "Build"),
 		Entry("BuildConfig", "BuildConfig"),
 	)
 
@@ -132,6 +133,7 @@ var _ = Describe("SetVersionNodeAffinity", func() {
 			Expect(v).To(Equal(expectedSelector))
 		},
 		Entry("Pod", "Pod"),
+		Entry("Build", "Build"),
 		Entry("BuildConfig", "BuildConfig"),
 	)
 
@@ -155,8 +157,20 @@ var _ = Describe("SetVersionNodeAffinity", func() {
 		},
 		Entry("DaemonSet", "DaemonSet"),
 		Entry("Deployment", "Deployment"),
-		Entry("Statefulset", "Statefulset"),
+		Entry("StatefulSet", "StatefulSet"),
+		Entry("Job", "Job"),
 	)
+
+	It("should work for CronJobs", func() {
+		obj := newObj("CronJob", "")
+
+		Expect(kernel.setVersionNodeAffinity(obj, kernelFullVersion)).To(Succeed())
+
+		m, ok, err := unstructured.NestedMap(obj.Object, "spec", "jobTemplate", "spec", "template", "spec", "nodeSelector")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(m).To(HaveKeyWithValue("feature.node.kubernetes.io/kernel-version.full", kernelFullVersion))
+	})
 })
 
 var _ = Describe("TestIsObjectAffine", func() {
@@ -188,5 +202,36 @@ var _ = Describe("PatchVersion", func() {
 		Entry(nil, kernelFullVersion, "4.18.0-305"),
 		Entry(nil, "4.18.0", "4.18.0"),
 		Entry(nil, "4.18.0-305", "4.18.0-305"),
+		Entry(nil, "5.15.0-91-generic", "5.15.0-91"),
+		Entry(nil, "5.10.0-26-amd64", "5.10.0-26"),
+		Entry(nil, "6.1", "6.1.0"),
+	)
+})
+
+var _ = Describe("ParseVersion", func() {
+	DescribeTable(
+		"should return the components",
+		func(input string, expected Version) {
+			v, err := kernel.ParseVersion(input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(Equal(expected))
+		},
+		EntryDescription("%q"),
+		Entry(nil, kernelFullVersion, Version{Major: 4, Minor: 18, Build: "305.19.1.el8_4", Arch: "x86_64"}),
+		Entry(nil, "5.15.0-91-generic", Version{Major: 5, Minor: 15, Build: "91", Flavor: "generic"}),
+		Entry(nil, "5.10.0-26-cloud-amd64", Version{Major: 5, Minor: 10, Build: "26", Flavor: "cloud", Arch: "amd64"}),
+		Entry(nil, "6.1.55", Version{Major: 6, Minor: 1, Patch: 55}),
+		Entry(nil, "6.1", Version{Major: 6, Minor: 1}),
+	)
+
+	DescribeTable(
+		"should fail on invalid versions",
+		func(input string) {
+			_, err := kernel.ParseVersion(input)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("no minor", "6"),
+		Entry("not a number", "6.x.1"),
+		Entry("too many numbers", "6.1.0.1"),
 	)
 })
@@ -65,6 +65,21 @@ func (mr *MockKernelDataMockRecorder) IsObjectAffine(obj interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsObjectAffine", reflect.TypeOf((*MockKernelData)(nil).IsObjectAffine), obj)
 }
 
+// ParseVersion mocks base method.
+func (m *MockKernelData) ParseVersion(kernelFullVersion string) (Version, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseVersion", kernelFullVersion)
+	ret0, _ := ret[0].(Version)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseVersion indicates an expected call of ParseVersion.
+func (mr *MockKernelDataMockRecorder) ParseVersion(kernelFullVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseVersion", reflect.TypeOf((*MockKernelData)(nil).ParseVersion), kernelFullVersion)
+}
+
 // PatchVersion mocks base method.
 func (m *MockKernelData) PatchVersion(kernelFullVersion string) (string, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,181 @@
+package kernel
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RangeAnnotation is the Chart.yaml annotation declaring the kernels a chart
+// supports, e.g. ">=4.18.0-305 <4.18.0-400".
+const RangeAnnotation = "specialresource.openshift.io/kernel-range"
+
+// ErrUnsupportedKernel is returned when a kernel is outside of the range of a chart.
+var ErrUnsupportedKernel = errors.New("unsupported kernel")
+
+var (
+	constraintRegex = regexp.MustCompile(`^(>=|<=|!=|>|<|=)?([0-9].*)$`)
+	numberRegex     = regexp.MustCompile(`^[0-9]+`)
+)
+
+type constraint struct {
+	op      string
+	version []int
+}
+
+// Range is a set of kernel versions. Constraints separated by spaces must all
+// be met, alternatives are separated by ||.
+type Range struct {
+	alternatives [][]constraint
+}
+
+// ParseRange parses a range like ">=4.18.0-305 <4.18.0-400 || >=5.14.0".
+// A constraint without operator means an equal version.
+func ParseRange(s string) (*Range, error) {
+	r := &Range{}
+
+	for _, alternative := range strings.Split(s, "||") {
+		fields := strings.Fields(alternative)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("kernel range %q: empty constraint", s)
+		}
+
+		constraints := make([]constraint, 0, len(fields))
+
+		for _, field := range fields {
+			match := constraintRegex.FindStringSubmatch(field)
+			if match == nil {
+				return nil, fmt.Errorf("kernel range %q: invalid constraint %q", s, field)
+			}
+
+			op := match[1]
+			if op == "" {
+				op = "="
+			}
+
+			constraints = append(constraints, constraint{op: op, version: versionNumbers(match[2])})
+		}
+
+		r.alternatives = append(r.alternatives, constraints)
+	}
+
+	return r, nil
+}
+
+// Contains returns true if the kernel is in the range. A constraint is only
+// compared up to its own precision, 4.18.0-305.19.1.el8_4.x86_64 is equal to
+// 4.18.0-305 and thus not lower than it.
+func (r *Range) Contains(kernelFullVersion string) bool {
+	version := versionNumbers(kernelFullVersion)
+
+	for _, constraints := range r.alternatives {
+		met := true
+
+		for _, c := range constraints {
+			if !c.matches(version) {
+				met = false
+				break
+			}
+		}
+
+		if met {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckRange returns an ErrUnsupportedKernel error listing the kernels that are
+// not in the range s. An empty range contains every kernel.
+func CheckRange(s string, kernels []string) error {
+	if s == "" {
+		return nil
+	}
+
+	r, err := ParseRange(s)
+	if err != nil {
+		return err
+	}
+
+	unsupported := make([]string, 0)
+
+	for _, k := range kernels {
+		if !r.Contains(k) {
+			unsupported = append(unsupported, k)
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("%w: %s not in %q", ErrUnsupportedKernel, strings.Join(unsupported, ", "), s)
+	}
+
+	return nil
+}
+
+func (c constraint) matches(version []int) bool {
+	cmp := compareVersions(version, c.version)
+
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case "!=":
+		return cmp != 0
+	default:
+		return cmp == 0
+	}
+}
+
+// compareVersions compares version with the len(bound) first numbers of it,
+// missing numbers count as 0.
+func compareVersions(version, bound []int) int {
+	for i, b := range bound {
+		var v int
+		if i < len(version) {
+			v = version[i]
+		}
+
+		if v < b {
+			return -1
+		}
+		if v > b {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// versionNumbers returns the leading numbers of a kernel version, separated by
+// dots, dashes or underscores: 4.18.0-305.19.1.el8_4.x86_64 gives
+// [4 18 0 305 19 1].
+func versionNumbers(s string) []int {
+	numbers := make([]int, 0)
+
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == '-' || r == '_' }) {
+		digits := numberRegex.FindString(part)
+		if digits == "" {
+			break
+		}
+
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+
+		if digits != part {
+			break
+		}
+	}
+
+	return numbers
+}
@@ -0,0 +1,48 @@
+package kernel
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Range", func() {
+	DescribeTable("should match kernels",
+		func(s, kernel string, contained bool) {
+			r, err := ParseRange(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Contains(kernel)).To(Equal(contained))
+		},
+		Entry("within bounds", ">=4.18.0-305 <4.18.0-400", kernelFullVersion, true),
+		Entry("lower bound", ">=4.18.0-305.19 <4.18.0-400", kernelFullVersion, true),
+		Entry("above the upper bound", ">=4.18.0-193 <4.18.0-305", kernelFullVersion, false),
+		Entry("below the lower bound", ">4.18.0-305", kernelFullVersion, false),
+		Entry("inclusive upper bound", "<=4.18.0-305", kernelFullVersion, true),
+		Entry("equal version", "4.18.0-305", kernelFullVersion, true),
+		Entry("excluded version", "!=4.18.0-305", kernelFullVersion, false),
+		Entry("second alternative", ">=5.14.0 || >=4.18.0-305 <4.18.0-400", kernelFullVersion, true),
+		Entry("no alternative", "<4.18.0 || >=5.14.0", kernelFullVersion, false),
+	)
+
+	DescribeTable("should reject invalid ranges",
+		func(s string) {
+			_, err := ParseRange(s)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty alternative", ">=4.18.0 ||"),
+		Entry("unknown operator", "~4.18.0"),
+		Entry("no version", ">="),
+	)
+
+	It("should list the unsupported kernels", func() {
+		err := CheckRange("<4.18.0-400", []string{kernelFullVersion, "4.18.0-425.3.1.el8.x86_64"})
+		Expect(errors.Is(err, ErrUnsupportedKernel)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("4.18.0-425.3.1.el8.x86_64"))
+		Expect(err.Error()).NotTo(ContainSubstring(kernelFullVersion))
+	})
+
+	It("should support every kernel without range", func() {
+		Expect(CheckRange("", []string{kernelFullVersion})).To(Succeed())
+	})
+})
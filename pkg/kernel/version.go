@@ -0,0 +1,63 @@
+package kernel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// architectures are the suffixes of a kernel release naming its architecture.
+var architectures = map[string]bool{
+	"aarch64": true,
+	"amd64":   true,
+	"arm64":   true,
+	"ppc64le": true,
+	"s390x":   true,
+	"x86_64":  true,
+}
+
+// Version holds the components of a kernel version, e.g.
+// 4.18.0-305.19.1.el8_4.x86_64 on RHEL, 5.15.0-91-generic on Ubuntu or a
+// vanilla 6.1.0.
+type Version struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+	Patch int `json:"patch"`
+	// Build is the distribution release, 305.19.1.el8_4 or 91
+	Build string `json:"build"`
+	// Flavor is the kernel flavor of Ubuntu and Debian, e.g. generic or aws
+	Flavor string `json:"flavor"`
+	Arch   string `json:"arch"`
+}
+
+// ParseVersion splits kernelFullVersion into its components. The patch level,
+// the build, the flavor and the architecture are optional.
+func (k *kernelData) ParseVersion(kernelFullVersion string) (Version, error) {
+	v := Version{}
+
+	upstream, release, _ := strings.Cut(kernelFullVersion, "-")
+
+	numbers := strings.Split(upstream, ".")
+	if len(numbers) < 2 || len(numbers) > 3 {
+		return v, fmt.Errorf("kernel version %q: expected major.minor[.patch]", kernelFullVersion)
+	}
+
+	components := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, n := range numbers {
+		number, err := strconv.Atoi(n)
+		if err != nil {
+			return v, fmt.Errorf("kernel version %q: %w", kernelFullVersion, err)
+		}
+		*components[i] = number
+	}
+
+	// RHEL appends .<arch>, Debian -<arch>
+	if i := strings.LastIndexAny(release, ".-"); i >= 0 && architectures[release[i+1:]] {
+		v.Arch = release[i+1:]
+		release = release[:i]
+	}
+
+	v.Build, v.Flavor, _ = strings.Cut(release, "-")
+
+	return v, nil
+}
@@ -2,10 +2,12 @@ package lifecycle
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/go-logr/logr"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
 	"github.com/openshift-psap/special-resource-operator/pkg/storage"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
 	appsv1 "k8s.io/api/apps/v1"
@@ -16,12 +18,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
+// UnloadPendingLabel is set on the nodes running driver Pods that are being
+// replaced, e.g. during an upgrade. Drains should wait until it is removed, the
+// old Pods are then gone and the driver unloaded.
+const UnloadPendingLabel = "specialresource.openshift.io/unload-pending"
+
 //go:generate mockgen -source=lifecycle.go -package=lifecycle -destination=mock_lifecycle_api.go
 
 type Lifecycle interface {
 	GetPodFromDaemonSet(context.Context, types.NamespacedName) *v1.PodList
 	GetPodFromDeployment(context.Context, types.NamespacedName) *v1.PodList
 	UpdateDaemonSetPods(context.Context, client.Object) error
+	IsNodeUnloadPending(context.Context, string) (bool, error)
+	ReleaseNode(context.Context, client.Object) error
 }
 
 type lifecycle struct {
@@ -105,6 +114,98 @@ func (l *lifecycle) UpdateDaemonSetPods(ctx context.Context, obj client.Object)
 			utils.WarnOnError(err)
 			return err
 		}
+
+		if pod.Spec.NodeName != "" {
+			if err = l.setUnloadPending(ctx, pod.Spec.NodeName, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsNodeUnloadPending returns true if driver Pods on the node are being replaced.
+func (l *lifecycle) IsNodeUnloadPending(ctx context.Context, nodeName string) (bool, error) {
+	node := &v1.Node{}
+
+	if err := l.kubeClient.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		return false, fmt.Errorf("could not get node %s: %w", nodeName, err)
+	}
+
+	_, found := node.GetLabels()[UnloadPendingLabel]
+	return found, nil
+}
+
+// ReleaseNode removes the UnloadPendingLabel from the node of a deleted Pod,
+// once no other Pod being replaced runs on it.
+func (l *lifecycle) ReleaseNode(ctx context.Context, obj client.Object) error {
+
+	pod, ok := obj.(*v1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil
+	}
+
+	ins := types.NamespacedName{
+		Namespace: os.Getenv("OPERATOR_NAMESPACE"),
+		Name:      "special-resource-lifecycle",
+	}
+
+	pl := &v1.PodList{}
+	if err := l.kubeClient.List(ctx, pl, client.HasLabels{ownership.SpecialResourceOwnedLabel}); err != nil {
+		return fmt.Errorf("could not list owned Pods: %w", err)
+	}
+
+	for _, p := range pl.Items {
+		if p.Spec.NodeName != pod.Spec.NodeName || p.GetUID() == pod.GetUID() {
+			continue
+		}
+
+		hs, err := utils.FNV64a(p.GetNamespace() + p.GetName())
+		if err != nil {
+			return err
+		}
+
+		value, err := l.storage.CheckConfigMapEntry(ctx, hs, ins)
+		if err != nil {
+			return err
+		}
+
+		if value != "" {
+			l.log.Info("Node still has Pods pending unload", "node", pod.Spec.NodeName, "pod", p.GetName())
+			return nil
+		}
+	}
+
+	return l.setUnloadPending(ctx, pod.Spec.NodeName, false)
+}
+
+func (l *lifecycle) setUnloadPending(ctx context.Context, nodeName string, pending bool) error {
+	node := &v1.Node{}
+
+	if err := l.kubeClient.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		return fmt.Errorf("could not get node %s: %w", nodeName, err)
+	}
+
+	if _, found := node.GetLabels()[UnloadPendingLabel]; found == pending {
+		return nil
+	}
+
+	labels := node.GetLabels()
+	if pending {
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[UnloadPendingLabel] = "true"
+	} else {
+		delete(labels, UnloadPendingLabel)
+	}
+	node.SetLabels(labels)
+
+	l.log.Info("Updating node", "node", nodeName, UnloadPendingLabel, pending)
+
+	if err := l.kubeClient.Update(ctx, node); err != nil {
+		return fmt.Errorf("could not update node %s: %w", nodeName, err)
 	}
 
 	return nil
@@ -196,3 +196,90 @@ var _ = Describe("UpdateDaemonSetPods", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 })
+
+var _ = Describe("IsNodeUnloadPending", func() {
+	nodeNsn := types.NamespacedName{Name: "node1"}
+
+	It("should return true when the node is labelled", func() {
+		mockClient.EXPECT().
+			Get(context.TODO(), nodeNsn, &v1.Node{}).
+			Do(func(_ context.Context, _ types.NamespacedName, node *v1.Node) {
+				node.SetLabels(map[string]string{lifecycle.UnloadPendingLabel: "true"})
+			})
+
+		pending, err := lifecycle.New(mockClient, mockStorage).IsNodeUnloadPending(context.TODO(), "node1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(BeTrue())
+	})
+
+	It("should return an error when the node does not exist", func() {
+		mockClient.EXPECT().
+			Get(context.TODO(), nodeNsn, &v1.Node{}).
+			Return(errors.NewNotFound(v1.Resource("nodes"), "node1"))
+
+		_, err := lifecycle.New(mockClient, mockStorage).IsNodeUnloadPending(context.TODO(), "node1")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ReleaseNode", func() {
+	nodeNsn := types.NamespacedName{Name: "node1"}
+
+	deleted := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: namespace, UID: "uid1"},
+		Spec:       v1.PodSpec{NodeName: "node1"},
+	}
+
+	other := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: namespace, UID: "uid2"},
+		Spec:       v1.PodSpec{NodeName: "node1"},
+	}
+
+	It("should ignore objects that are not Pods", func() {
+		err := lifecycle.New(mockClient, mockStorage).ReleaseNode(context.TODO(), &appsv1.DaemonSet{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should keep the label while another Pod on the node is pending", func() {
+		gomock.InOrder(
+			mockClient.EXPECT().
+				List(context.TODO(), &v1.PodList{}, gomock.Any()).
+				Do(func(_ context.Context, pl *v1.PodList, _ client.ListOption) {
+					pl.Items = []v1.Pod{*deleted, other}
+				}),
+			mockStorage.EXPECT().
+				CheckConfigMapEntry(context.TODO(), "39005d809548cba1", gomock.Any()).
+				Return("*v1.Pod", nil),
+		)
+
+		err := lifecycle.New(mockClient, mockStorage).ReleaseNode(context.TODO(), deleted)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should remove the label once no Pod on the node is pending", func() {
+		gomock.InOrder(
+			mockClient.EXPECT().
+				List(context.TODO(), &v1.PodList{}, gomock.Any()).
+				Do(func(_ context.Context, pl *v1.PodList, _ client.ListOption) {
+					pl.Items = []v1.Pod{*deleted, other}
+				}),
+			mockStorage.EXPECT().
+				CheckConfigMapEntry(context.TODO(), "39005d809548cba1", gomock.Any()).
+				Return("", nil),
+			mockClient.EXPECT().
+				Get(context.TODO(), nodeNsn, &v1.Node{}).
+				Do(func(_ context.Context, _ types.NamespacedName, node *v1.Node) {
+					node.SetLabels(map[string]string{lifecycle.UnloadPendingLabel: "true", "other": "label"})
+				}),
+			mockClient.EXPECT().
+				Update(context.TODO(), gomock.AssignableToTypeOf(&v1.Node{})).
+				Do(func(_ context.Context, node *v1.Node) {
+					Expect(node.GetLabels()).NotTo(HaveKey(lifecycle.UnloadPendingLabel))
+					Expect(node.GetLabels()).To(HaveKey("other"))
+				}),
+		)
+
+		err := lifecycle.New(mockClient, mockStorage).ReleaseNode(context.TODO(), deleted)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -65,6 +65,35 @@ func (mr *MockLifecycleMockRecorder) GetPodFromDeployment(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodFromDeployment", reflect.TypeOf((*MockLifecycle)(nil).GetPodFromDeployment), arg0, arg1)
 }
 
+// IsNodeUnloadPending mocks base method.
+func (m *MockLifecycle) IsNodeUnloadPending(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNodeUnloadPending", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsNodeUnloadPending indicates an expected call of IsNodeUnloadPending.
+func (mr *MockLifecycleMockRecorder) IsNodeUnloadPending(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNodeUnloadPending", reflect.TypeOf((*MockLifecycle)(nil).IsNodeUnloadPending), arg0, arg1)
+}
+
+// ReleaseNode mocks base method.
+func (m *MockLifecycle) ReleaseNode(arg0 context.Context, arg1 client.Object) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseNode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseNode indicates an expected call of ReleaseNode.
+func (mr *MockLifecycleMockRecorder) ReleaseNode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseNode", reflect.TypeOf((*MockLifecycle)(nil).ReleaseNode), arg0, arg1)
+}
+
 // UpdateDaemonSetPods mocks base method.
 func (m *MockLifecycle) UpdateDaemonSetPods(arg0 context.Context, arg1 client.Object) error {
 	m.ctrl.T.Helper()
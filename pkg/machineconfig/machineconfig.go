@@ -0,0 +1,277 @@
+package machineconfig
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	machinev1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultPool is the MachineConfigPool targeted when the SpecialResource
+	// does not name one.
+	DefaultPool = "worker"
+
+	// roleLabel selects the MachineConfigs of a pool, the custom pools
+	// select the ones of their name along with the worker ones.
+	roleLabel = "machineconfiguration.openshift.io/role"
+
+	ignitionVersion = "3.2.0"
+
+	currentConfigAnnotation = "machineconfiguration.openshift.io/currentConfig"
+	desiredConfigAnnotation = "machineconfiguration.openshift.io/desiredConfig"
+)
+
+// ErrPrerequisitesNotMet is returned while the prerequisites of a
+// SpecialResource are not in effect on its nodes.
+var ErrPrerequisitesNotMet = errors.New("prerequisites not met")
+
+var (
+	moduleName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	sysctlName = regexp.MustCompile(`^[a-zA-Z0-9_.*/-]+$`)
+)
+
+// hugepagesSizes are the kernel names of the huge page sizes, by resource
+// name suffix.
+var hugepagesSizes = map[string]string{"2Mi": "2M", "1Gi": "1G"}
+
+// Name returns the name of the MachineConfig of the SpecialResource sr.
+func Name(sr string) string {
+	return "50-sro-" + sr
+}
+
+// Pool returns the MachineConfigPool targeted by sr.
+func Pool(sr *srov1beta1.SpecialResource) string {
+	if sr.Spec.MachineConfigPool != "" {
+		return sr.Spec.MachineConfigPool
+	}
+	return DefaultPool
+}
+
+// Manifest returns the MachineConfig appending the kernelArguments of sr to the
+// kernel command line of the machines of its pool, keeping the modules of its
+// moduleBlacklist from being loaded and applying its prerequisites, nil if sr
+// has none of them.
+func Manifest(sr *srov1beta1.SpecialResource) ([]byte, error) {
+	kernelArguments := append([]string{}, sr.Spec.KernelArguments...)
+	blacklist := sr.Spec.ModuleBlacklist
+	prerequisites := sr.Spec.Prerequisites
+	if prerequisites == nil {
+		prerequisites = &srov1beta1.SpecialResourcePrerequisites{}
+	}
+
+	if len(kernelArguments) == 0 && len(blacklist) == 0 && len(prerequisites.Sysctls) == 0 &&
+		len(prerequisites.Hugepages) == 0 && len(prerequisites.UdevRules) == 0 {
+		return nil, nil
+	}
+
+	for _, arg := range kernelArguments {
+		if arg == "" || strings.ContainsAny(arg, " \t\n") {
+			return nil, fmt.Errorf("invalid kernel argument %q", arg)
+		}
+	}
+
+	var files []interface{}
+	if len(blacklist) > 0 {
+		conf := ""
+		for _, module := range blacklist {
+			if !moduleName.MatchString(module) {
+				return nil, fmt.Errorf("invalid module name %q", module)
+			}
+			conf += "blacklist " + module + "\n"
+		}
+
+		files = append(files, file("/etc/modprobe.d/sro-"+sr.Name+"-blacklist.conf", conf))
+
+		// The modprobe.d files are not read before the root filesystem
+		// is mounted, the modules the initramfs loads are blacklisted on
+		// the kernel command line
+		kernelArguments = append(kernelArguments, "modprobe.blacklist="+strings.Join(blacklist, ","))
+	}
+
+	if len(prerequisites.Sysctls) > 0 {
+		names := make([]string, 0, len(prerequisites.Sysctls))
+		for name := range prerequisites.Sysctls {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		conf := ""
+		for _, name := range names {
+			value := prerequisites.Sysctls[name]
+			if !sysctlName.MatchString(name) || strings.ContainsAny(value, "\n") {
+				return nil, fmt.Errorf("invalid sysctl %s = %q", name, value)
+			}
+			conf += name + " = " + value + "\n"
+		}
+
+		files = append(files, file("/etc/sysctl.d/50-sro-"+sr.Name+".conf", conf))
+	}
+
+	// The huge pages are reserved by the kernel on boot, before the memory is
+	// fragmented
+	for _, hugepages := range prerequisites.Hugepages {
+		size, ok := hugepagesSizes[hugepages.Size]
+		if !ok || hugepages.Count < 1 {
+			return nil, fmt.Errorf("invalid hugepages %d of size %s", hugepages.Count, hugepages.Size)
+		}
+		kernelArguments = append(kernelArguments, "hugepagesz="+size, fmt.Sprintf("hugepages=%d", hugepages.Count))
+	}
+
+	if len(prerequisites.UdevRules) > 0 {
+		rules := ""
+		for _, rule := range prerequisites.UdevRules {
+			if strings.Contains(rule, "\n") {
+				return nil, fmt.Errorf("invalid udev rule %q, one rule per line", rule)
+			}
+			rules += rule + "\n"
+		}
+
+		files = append(files, file("/etc/udev/rules.d/99-sro-"+sr.Name+".rules", rules))
+	}
+
+	config := map[string]interface{}{
+		"ignition": map[string]interface{}{"version": ignitionVersion},
+	}
+	if len(files) > 0 {
+		config["storage"] = map[string]interface{}{"files": files}
+	}
+
+	mc := map[string]interface{}{
+		"apiVersion": machinev1.SchemeGroupVersion.String(),
+		"kind":       "MachineConfig",
+		"metadata": map[string]interface{}{
+			"name":   Name(sr.Name),
+			"labels": map[string]interface{}{roleLabel: Pool(sr)},
+		},
+		"spec": map[string]interface{}{
+			"config":          config,
+			"kernelArguments": kernelArguments,
+		},
+	}
+
+	return yaml.Marshal(mc)
+}
+
+// file returns the Ignition file of contents at path.
+func file(path string, contents string) map[string]interface{} {
+	return map[string]interface{}{
+		"path":      path,
+		"mode":      420,
+		"overwrite": true,
+		"contents": map[string]interface{}{
+			"source": "data:text/plain;charset=utf-8;base64," + base64.StdEncoding.EncodeToString([]byte(contents)),
+		},
+	}
+}
+
+// Rollout returns the progress of the MachineConfig name in pool. It is done
+// once every machine of the pool runs the rendered config the pool targets and
+// that config includes it, i.e. once they rebooted with it. A MachineConfig
+// that was edited is part of the previous rendered config as well, which is
+// not enough.
+func Rollout(pool *unstructured.Unstructured, name string) srov1beta1.MachineConfigRollout {
+	rollout := srov1beta1.MachineConfigRollout{Name: name, Pool: pool.GetName()}
+
+	machines, _, _ := unstructured.NestedInt64(pool.Object, "status", "machineCount")
+	degraded, _, _ := unstructured.NestedInt64(pool.Object, "status", "degradedMachineCount")
+	rollout.MachineCount = int32(machines)
+	rollout.DegradedMachineCount = int32(degraded)
+	rollout.Paused, _, _ = unstructured.NestedBool(pool.Object, "spec", "paused")
+
+	// The updated machines run the config the pool is rolling out, which may
+	// not include the MachineConfig yet
+	if hasSource(pool, name, "spec", "configuration", "source") {
+		updated, _, _ := unstructured.NestedInt64(pool.Object, "status", "updatedMachineCount")
+		rollout.UpdatedMachineCount = int32(updated)
+	}
+
+	target, _, _ := unstructured.NestedString(pool.Object, "spec", "configuration", "name")
+	current, _, _ := unstructured.NestedString(pool.Object, "status", "configuration", "name")
+	updated, _, _ := unstructured.NestedInt64(pool.Object, "status", "updatedMachineCount")
+
+	rollout.Done = hasSource(pool, name, "spec", "configuration", "source") &&
+		target != "" && current == target && updated == machines
+
+	return rollout
+}
+
+func hasSource(pool *unstructured.Unstructured, name string, fields ...string) bool {
+	sources, _, _ := unstructured.NestedSlice(pool.Object, fields...)
+	for _, s := range sources {
+		source, ok := s.(map[string]interface{})
+		if ok && source["kind"] == "MachineConfig" && source["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// inPool returns false if node runs, or is being moved to, the rendered
+// configs of another pool than the named one. A node of a custom pool is
+// selected by the worker pool as well, since it keeps its worker role label.
+func inPool(node corev1.Node, pool string) bool {
+	config := node.GetAnnotations()[desiredConfigAnnotation]
+	if config == "" {
+		config = node.GetAnnotations()[currentConfigAnnotation]
+	}
+
+	return config == "" || strings.HasPrefix(config, "rendered-"+pool+"-")
+}
+
+// Verify returns an ErrPrerequisitesNotMet error unless the nodes of pool
+// among nodes run the latest rendered config of pool that includes the
+// MachineConfig of sr, and have the huge pages of its prerequisites. The nodes
+// of other pools selected by pool are skipped.
+func Verify(sr *srov1beta1.SpecialResource, pool *unstructured.Unstructured, nodes []corev1.Node) error {
+	if sr.Spec.Prerequisites == nil {
+		return nil
+	}
+
+	name := Name(sr.Name)
+	if !hasSource(pool, name, "spec", "configuration", "source") {
+		return fmt.Errorf("%w: MachineConfig %s is not rendered in MachineConfigPool %s yet", ErrPrerequisitesNotMet, name, pool.GetName())
+	}
+
+	rendered, _, _ := unstructured.NestedString(pool.Object, "spec", "configuration", "name")
+	matchLabels, _, _ := unstructured.NestedStringMap(pool.Object, "spec", "nodeSelector", "matchLabels")
+	selector := labels.SelectorFromSet(matchLabels)
+
+	for _, node := range nodes {
+		// The selected nodes of other pools do not get the MachineConfig
+		if !selector.Matches(labels.Set(node.GetLabels())) || !inPool(node, pool.GetName()) {
+			continue
+		}
+
+		if current := node.GetAnnotations()[currentConfigAnnotation]; current != rendered {
+			return fmt.Errorf("%w: node %s runs %s, not %s", ErrPrerequisitesNotMet, node.GetName(), current, rendered)
+		}
+
+		for _, hugepages := range sr.Spec.Prerequisites.Hugepages {
+			resourceName := corev1.ResourceName(corev1.ResourceHugePagesPrefix + hugepages.Size)
+			capacity := node.Status.Capacity[resourceName]
+
+			size, err := resource.ParseQuantity(hugepages.Size)
+			if err != nil {
+				return fmt.Errorf("invalid hugepages size %s: %w", hugepages.Size, err)
+			}
+			want := resource.NewQuantity(size.Value()*int64(hugepages.Count), resource.BinarySI)
+
+			if capacity.Cmp(*want) < 0 {
+				return fmt.Errorf("%w: node %s has %s of %s, not %s", ErrPrerequisitesNotMet, node.GetName(), capacity.String(), resourceName, want.String())
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,275 @@
+package machineconfig_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/machineconfig"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func TestMachineConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MachineConfig Suite")
+}
+
+func newSpecialResource(kernelArguments []string, blacklist []string) *srov1beta1.SpecialResource {
+	sr := &srov1beta1.SpecialResource{}
+	sr.Name = "simple-kmod"
+	sr.Spec.KernelArguments = kernelArguments
+	sr.Spec.ModuleBlacklist = blacklist
+	return sr
+}
+
+var _ = Describe("Manifest", func() {
+	It("should return nil without kernel arguments nor blacklist", func() {
+		Expect(machineconfig.Manifest(newSpecialResource(nil, nil))).To(BeNil())
+	})
+
+	It("should render the kernel arguments and the blacklist", func() {
+		sr := newSpecialResource([]string{"intel_iommu=on"}, []string{"nouveau", "simple_kmod"})
+		sr.Spec.MachineConfigPool = "gpu"
+
+		manifest, err := machineconfig.Manifest(sr)
+		Expect(err).NotTo(HaveOccurred())
+
+		mc := unstructured.Unstructured{}
+		Expect(yaml.Unmarshal(manifest, &mc.Object)).To(Succeed())
+
+		Expect(mc.GetKind()).To(Equal("MachineConfig"))
+		Expect(mc.GetName()).To(Equal("50-sro-simple-kmod"))
+		Expect(mc.GetLabels()).To(HaveKeyWithValue("machineconfiguration.openshift.io/role", "gpu"))
+
+		kernelArguments, _, _ := unstructured.NestedStringSlice(mc.Object, "spec", "kernelArguments")
+		Expect(kernelArguments).To(Equal([]string{"intel_iommu=on", "modprobe.blacklist=nouveau,simple_kmod"}))
+
+		files, _, _ := unstructured.NestedSlice(mc.Object, "spec", "config", "storage", "files")
+		Expect(files).To(HaveLen(1))
+
+		file := files[0].(map[string]interface{})
+		Expect(file["path"]).To(Equal("/etc/modprobe.d/sro-simple-kmod-blacklist.conf"))
+
+		source, _, _ := unstructured.NestedString(file, "contents", "source")
+		conf, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(source, "data:text/plain;charset=utf-8;base64,"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(conf)).To(Equal("blacklist nouveau\nblacklist simple_kmod\n"))
+	})
+
+	It("should target the worker pool and write no file without blacklist", func() {
+		manifest, err := machineconfig.Manifest(newSpecialResource([]string{"hugepages=16"}, nil))
+		Expect(err).NotTo(HaveOccurred())
+
+		mc := unstructured.Unstructured{}
+		Expect(yaml.Unmarshal(manifest, &mc.Object)).To(Succeed())
+
+		Expect(mc.GetLabels()).To(HaveKeyWithValue("machineconfiguration.openshift.io/role", machineconfig.DefaultPool))
+
+		_, found, _ := unstructured.NestedFieldNoCopy(mc.Object, "spec", "config", "storage")
+		Expect(found).To(BeFalse())
+	})
+
+	It("should render the prerequisites", func() {
+		sr := newSpecialResource(nil, nil)
+		sr.Spec.Prerequisites = &srov1beta1.SpecialResourcePrerequisites{
+			Sysctls:   map[string]string{"vm.nr_overcommit_hugepages": "0", "kernel.sched_rt_runtime_us": "-1"},
+			Hugepages: []srov1beta1.SpecialResourceHugepages{{Size: "1Gi", Count: 4}},
+			UdevRules: []string{`KERNEL=="simple-kmod*", MODE="0666"`},
+		}
+
+		manifest, err := machineconfig.Manifest(sr)
+		Expect(err).NotTo(HaveOccurred())
+
+		mc := unstructured.Unstructured{}
+		Expect(yaml.Unmarshal(manifest, &mc.Object)).To(Succeed())
+
+		kernelArguments, _, _ := unstructured.NestedStringSlice(mc.Object, "spec", "kernelArguments")
+		Expect(kernelArguments).To(Equal([]string{"hugepagesz=1G", "hugepages=4"}))
+
+		files, _, _ := unstructured.NestedSlice(mc.Object, "spec", "config", "storage", "files")
+		contents := make(map[string]string)
+		for _, f := range files {
+			file := f.(map[string]interface{})
+			source, _, _ := unstructured.NestedString(file, "contents", "source")
+			conf, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(source, "data:text/plain;charset=utf-8;base64,"))
+			Expect(err).NotTo(HaveOccurred())
+			contents[file["path"].(string)] = string(conf)
+		}
+
+		Expect(contents).To(Equal(map[string]string{
+			"/etc/sysctl.d/50-sro-simple-kmod.conf":      "kernel.sched_rt_runtime_us = -1\nvm.nr_overcommit_hugepages = 0\n",
+			"/etc/udev/rules.d/99-sro-simple-kmod.rules": "KERNEL==\"simple-kmod*\", MODE=\"0666\"\n",
+		}))
+	})
+
+	DescribeTable("should reject",
+		func(kernelArguments []string, blacklist []string) {
+			_, err := machineconfig.Manifest(newSpecialResource(kernelArguments, blacklist))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("an empty kernel argument", []string{""}, nil),
+		Entry("several kernel arguments in one", []string{"intel_iommu=on iommu=pt"}, nil),
+		Entry("an invalid module name", nil, []string{"nouveau\ninstall"}),
+	)
+
+	DescribeTable("should reject the prerequisites with",
+		func(prerequisites srov1beta1.SpecialResourcePrerequisites) {
+			sr := newSpecialResource(nil, nil)
+			sr.Spec.Prerequisites = &prerequisites
+
+			_, err := machineconfig.Manifest(sr)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("a sysctl value of several lines", srov1beta1.SpecialResourcePrerequisites{Sysctls: map[string]string{"vm.swappiness": "0\nkernel.panic = 1"}}),
+		Entry("an invalid sysctl name", srov1beta1.SpecialResourcePrerequisites{Sysctls: map[string]string{"vm swappiness": "0"}}),
+		Entry("an unknown huge page size", srov1beta1.SpecialResourcePrerequisites{Hugepages: []srov1beta1.SpecialResourceHugepages{{Size: "16Gi", Count: 1}}}),
+		Entry("no huge pages", srov1beta1.SpecialResourcePrerequisites{Hugepages: []srov1beta1.SpecialResourceHugepages{{Size: "2Mi"}}}),
+		Entry("several udev rules in one", srov1beta1.SpecialResourcePrerequisites{UdevRules: []string{"KERNEL==\"a\"\nKERNEL==\"b\""}}),
+	)
+})
+
+func sources(names []string) []interface{} {
+	s := []interface{}{}
+	for _, n := range names {
+		s = append(s, map[string]interface{}{"kind": "MachineConfig", "name": n})
+	}
+	return s
+}
+
+var _ = Describe("Rollout", func() {
+	const name = "50-sro-simple-kmod"
+
+	// newPool returns a pool rolling out the rendered config of spec over the
+	// one of status, with updated of its 3 machines running it.
+	newPool := func(paused bool, spec []string, status []string, updated int64) *unstructured.Unstructured {
+		rendered := func(sources []string) string {
+			return "rendered-worker-" + strings.Join(sources, "-")
+		}
+
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "worker"},
+			"spec": map[string]interface{}{
+				"paused":        paused,
+				"configuration": map[string]interface{}{"name": rendered(spec), "source": sources(spec)},
+			},
+			"status": map[string]interface{}{
+				"machineCount":         int64(3),
+				"updatedMachineCount":  updated,
+				"degradedMachineCount": int64(0),
+				"configuration":        map[string]interface{}{"name": rendered(status), "source": sources(status)},
+			},
+		}}
+	}
+
+	It("should not count the machines before the config is rendered", func() {
+		rollout := machineconfig.Rollout(newPool(false, []string{"00-worker"}, []string{"00-worker"}, 3), name)
+		Expect(rollout).To(Equal(srov1beta1.MachineConfigRollout{Name: name, Pool: "worker", MachineCount: 3}))
+	})
+
+	It("should count the updated machines while rolling out", func() {
+		rollout := machineconfig.Rollout(newPool(false, []string{"00-worker", name}, []string{"00-worker"}, 1), name)
+		Expect(rollout.UpdatedMachineCount).To(BeEquivalentTo(1))
+		Expect(rollout.Done).To(BeFalse())
+	})
+
+	It("should report a paused pool", func() {
+		rollout := machineconfig.Rollout(newPool(true, []string{"00-worker", name}, []string{"00-worker"}, 1), name)
+		Expect(rollout.Paused).To(BeTrue())
+		Expect(rollout.Done).To(BeFalse())
+	})
+
+	It("should not be done while an edited config rolls out", func() {
+		pool := newPool(false, []string{"00-worker", name}, []string{"00-worker", name}, 1)
+		Expect(unstructured.SetNestedField(pool.Object, "rendered-worker-3", "spec", "configuration", "name")).To(Succeed())
+
+		rollout := machineconfig.Rollout(pool, name)
+		Expect(rollout.Done).To(BeFalse())
+	})
+
+	It("should not be done until every machine is updated", func() {
+		rollout := machineconfig.Rollout(newPool(false, []string{"00-worker", name}, []string{"00-worker", name}, 2), name)
+		Expect(rollout.Done).To(BeFalse())
+	})
+
+	It("should be done once the machines run the config", func() {
+		rollout := machineconfig.Rollout(newPool(false, []string{"00-worker", name}, []string{"00-worker", name}, 3), name)
+		Expect(rollout.Done).To(BeTrue())
+	})
+})
+
+var _ = Describe("Verify", func() {
+	var sr *srov1beta1.SpecialResource
+
+	BeforeEach(func() {
+		sr = newSpecialResource(nil, nil)
+		sr.Spec.Prerequisites = &srov1beta1.SpecialResourcePrerequisites{
+			Hugepages: []srov1beta1.SpecialResourceHugepages{{Size: "1Gi", Count: 4}},
+		}
+	})
+
+	newPool := func(spec ...string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "worker"},
+			"spec": map[string]interface{}{
+				"nodeSelector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"node-role.kubernetes.io/worker": ""},
+				},
+				"configuration": map[string]interface{}{"name": "rendered-worker-2", "source": sources(spec)},
+			},
+		}}
+	}
+
+	// newNode returns a node of the pool role. Like with the MCO, the nodes of
+	// custom pools keep the worker role label.
+	newNode := func(role string, currentConfig string, hugepages string) corev1.Node {
+		node := corev1.Node{}
+		node.Name = "node-" + role
+		node.Labels = map[string]string{
+			"node-role.kubernetes.io/worker":  "",
+			"node-role.kubernetes.io/" + role: "",
+		}
+		node.Annotations = map[string]string{
+			"machineconfiguration.openshift.io/currentConfig": currentConfig,
+			"machineconfiguration.openshift.io/desiredConfig": currentConfig,
+		}
+		node.Status.Capacity = corev1.ResourceList{"hugepages-1Gi": resource.MustParse(hugepages)}
+		return node
+	}
+
+	It("should not verify a SpecialResource without prerequisites", func() {
+		sr.Spec.Prerequisites = nil
+		Expect(machineconfig.Verify(sr, newPool(), nil)).To(Succeed())
+	})
+
+	It("should succeed once the nodes of the pool run the config and have the huge pages", func() {
+		nodes := []corev1.Node{
+			newNode("worker", "rendered-worker-2", "4Gi"),
+			newNode("infra", "rendered-infra-1", "0"),
+		}
+		Expect(machineconfig.Verify(sr, newPool("00-worker", "50-sro-simple-kmod"), nodes)).To(Succeed())
+	})
+
+	It("should fail on a node moving to the pool", func() {
+		node := newNode("worker", "rendered-infra-1", "4Gi")
+		node.Annotations["machineconfiguration.openshift.io/desiredConfig"] = "rendered-worker-2"
+
+		err := machineconfig.Verify(sr, newPool("00-worker", "50-sro-simple-kmod"), []corev1.Node{node})
+		Expect(err).To(MatchError(machineconfig.ErrPrerequisitesNotMet))
+	})
+
+	DescribeTable("should not be met",
+		func(pool *unstructured.Unstructured, node corev1.Node) {
+			err := machineconfig.Verify(sr, pool, []corev1.Node{node})
+			Expect(err).To(MatchError(machineconfig.ErrPrerequisitesNotMet))
+		},
+		Entry("before the config is rendered", newPool("00-worker"), newNode("worker", "rendered-worker-2", "4Gi")),
+		Entry("on a node running an older config", newPool("00-worker", "50-sro-simple-kmod"), newNode("worker", "rendered-worker-1", "4Gi")),
+		Entry("on a node missing huge pages", newPool("00-worker", "50-sro-simple-kmod"), newNode("worker", "rendered-worker-2", "2Gi")),
+	)
+})
@@ -0,0 +1,203 @@
+package manifests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/postrender"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+)
+
+const (
+	// kustomizeDir is where the ConfigMap is written in the in-memory filesystem
+	kustomizeDir = "/manifests"
+
+	// kustomizeTemplate is the template holding the output of kustomize
+	kustomizeTemplate = "templates/kustomize.yaml"
+
+	// RenderedFile is the resource of a post-renderer kustomization holding
+	// the rendered manifests of the chart
+	RenderedFile = "rendered.yaml"
+)
+
+// variableRegex matches the ${name} and ${parent.child} variables of a manifest.
+var variableRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*)\}`)
+
+//go:generate mockgen -source=manifests.go -package=manifests -destination=mock_manifests_api.go
+
+type Manifests interface {
+	Load(ctx context.Context, name string, spec *v1beta1.SpecialResourceManifests) (*chart.Chart, error)
+	PostRenderer(ctx context.Context, spec *helmerv1beta1.HelmPostRenderer) (postrender.PostRenderer, error)
+}
+
+type manifests struct {
+	kubeClient clients.ClientsInterface
+}
+
+func New(kubeClient clients.ClientsInterface) Manifests {
+	return &manifests{kubeClient: kubeClient}
+}
+
+// Load returns a chart named name with the manifests of the ConfigMap as its
+// templates, so that they go through the same states as a Helm chart. The
+// ${variables} of the manifests are replaced by the runtime values of the same
+// name; Go templates are not evaluated.
+func (m *manifests) Load(ctx context.Context, name string, spec *v1beta1.SpecialResourceManifests) (*chart.Chart, error) {
+	cm, err := m.configMap(ctx, spec.ConfigMap)
+	if err != nil {
+		return nil, sroerrors.Wrap(sroerrors.ChartLoad, fmt.Errorf("could not get the manifests ConfigMap %s: %w", spec.ConfigMap, err))
+	}
+
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       name,
+			Version:    "0.0.0",
+			Type:       "application",
+		},
+		Values: make(map[string]interface{}),
+	}
+
+	if spec.Kustomize {
+		data, err := kustomize(cm.Data)
+		if err != nil {
+			return nil, sroerrors.Wrap(sroerrors.Render, fmt.Errorf("could not build the kustomization of %s: %w", spec.ConfigMap, err))
+		}
+
+		ch.Templates = []*chart.File{{Name: kustomizeTemplate, Data: Substitute(data)}}
+
+		return ch, nil
+	}
+
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ch.Templates = append(ch.Templates, &chart.File{
+			Name: "templates/" + k,
+			Data: Substitute([]byte(cm.Data[k])),
+		})
+	}
+
+	return ch, nil
+}
+
+// PostRenderer returns the Helm post-renderer applying the kustomization of
+// the ConfigMap of spec to the rendered manifests, nil without spec.
+func (m *manifests) PostRenderer(ctx context.Context, spec *helmerv1beta1.HelmPostRenderer) (postrender.PostRenderer, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	cm, err := m.configMap(ctx, spec.ConfigMap)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the post-renderer ConfigMap %s: %w", spec.ConfigMap, err)
+	}
+
+	if _, ok := cm.Data[RenderedFile]; ok {
+		return nil, fmt.Errorf("post-renderer ConfigMap %s: %s is reserved for the rendered manifests", spec.ConfigMap, RenderedFile)
+	}
+
+	return &kustomizePostRenderer{configMap: spec.ConfigMap, files: cm.Data}, nil
+}
+
+func (m *manifests) configMap(ctx context.Context, name string) (*v1.ConfigMap, error) {
+	cm := &v1.ConfigMap{}
+
+	key := types.NamespacedName{Namespace: os.Getenv("OPERATOR_NAMESPACE"), Name: name}
+	if err := m.kubeClient.Get(ctx, key, cm); err != nil {
+		return nil, err
+	}
+
+	return cm, nil
+}
+
+// kustomizePostRenderer builds the kustomization of a ConfigMap with the
+// rendered manifests as its rendered.yaml file.
+type kustomizePostRenderer struct {
+	configMap string
+	files     map[string]string
+}
+
+// String identifies the kustomization, it changes with the files of the
+// ConfigMap.
+func (k *kustomizePostRenderer) String() string {
+	names := make([]string, 0, len(k.files))
+	for name := range k.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sum := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(sum, "%s\x00%s\x00", name, k.files[name])
+	}
+
+	return fmt.Sprintf("kustomize %s sha256:%x", k.configMap, sum.Sum(nil))
+}
+
+func (k *kustomizePostRenderer) Run(rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	// A state may render nothing, e.g. when its template is disabled by a
+	// value, kustomize refuses empty resources.
+	if len(bytes.TrimSpace(rendered.Bytes())) == 0 {
+		return rendered, nil
+	}
+
+	files := make(map[string]string, len(k.files)+1)
+	for name, data := range k.files {
+		files[name] = data
+	}
+	files[RenderedFile] = rendered.String()
+
+	data, err := kustomize(files)
+	if err != nil {
+		return nil, fmt.Errorf("could not build the post-renderer kustomization of %s: %w", k.configMap, err)
+	}
+
+	return bytes.NewBuffer(data), nil
+}
+
+// kustomize builds the kustomization.yaml of files, all the resources it
+// refers to have to be part of files as well.
+func kustomize(files map[string]string) ([]byte, error) {
+	fs := filesys.MakeFsInMemory()
+
+	for name, data := range files {
+		if err := fs.WriteFile(filepath.Join(kustomizeDir, name), []byte(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, kustomizeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources.AsYaml()
+}
+
+// Substitute turns the ${variables} of a manifest into references to the
+// chart values and escapes anything else Helm would evaluate.
+func Substitute(manifest []byte) []byte {
+	escaped := strings.ReplaceAll(string(manifest), "{{", `{{ "{{" }}`)
+
+	return variableRegex.ReplaceAll([]byte(escaped), []byte("{{ .Values.$1 }}"))
+}
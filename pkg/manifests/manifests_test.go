@@ -0,0 +1,182 @@
+package manifests_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/manifests"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	cmName    = "simple-kmod-manifests"
+	namespace = "special-resource-operator"
+)
+
+var (
+	ctrl       *gomock.Controller
+	mockClient *clients.MockClientsInterface
+)
+
+func TestManifests(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = clients.NewMockClientsInterface(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "Manifests Suite")
+}
+
+func expectConfigMap(data map[string]string) {
+	mockClient.
+		EXPECT().
+		Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: cmName}, gomock.AssignableToTypeOf(&v1.ConfigMap{})).
+		Do(func(_ context.Context, _ types.NamespacedName, obj client.Object) {
+			obj.(*v1.ConfigMap).Data = data
+		})
+}
+
+var _ = Describe("Load", func() {
+	const namespaceEnvVar = "OPERATOR_NAMESPACE"
+
+	BeforeEach(func() {
+		err := os.Setenv(namespaceEnvVar, namespace)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		err := os.Unsetenv(namespaceEnvVar)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error if the ConfigMap cannot be read", func() {
+		mockClient.EXPECT().Get(context.Background(), gomock.Any(), gomock.Any()).Return(errors.New("random error"))
+
+		_, err := manifests.New(mockClient).Load(context.Background(), "simple-kmod", &v1beta1.SpecialResourceManifests{ConfigMap: cmName})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should turn every key into a template", func() {
+		expectConfigMap(map[string]string{
+			"0001-driver-container.yaml": "image: ${driverImage}",
+			"0000-buildconfig.yaml":      "kernel: ${clusterUpgradeInfo.kernel}",
+			"service.yaml":               "kind: Service",
+		})
+
+		ch, err := manifests.New(mockClient).Load(context.Background(), "simple-kmod", &v1beta1.SpecialResourceManifests{ConfigMap: cmName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ch.Metadata.Name).To(Equal("simple-kmod"))
+		Expect(ch.Validate()).To(Succeed())
+		Expect(ch.Templates).To(HaveLen(3))
+		Expect(ch.Templates[0].Name).To(Equal("templates/0000-buildconfig.yaml"))
+		Expect(string(ch.Templates[0].Data)).To(Equal("kernel: {{ .Values.clusterUpgradeInfo.kernel }}"))
+		Expect(ch.Templates[1].Name).To(Equal("templates/0001-driver-container.yaml"))
+		Expect(ch.Templates[2].Name).To(Equal("templates/service.yaml"))
+	})
+
+	It("should build the kustomization", func() {
+		expectConfigMap(map[string]string{
+			"kustomization.yaml": "namePrefix: sro-\nresources:\n- service.yaml\n",
+			"service.yaml":       "apiVersion: v1\nkind: Service\nmetadata:\n  name: ${name}\n",
+		})
+
+		ch, err := manifests.New(mockClient).Load(context.Background(), "simple-kmod", &v1beta1.SpecialResourceManifests{ConfigMap: cmName, Kustomize: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ch.Templates).To(HaveLen(1))
+		Expect(string(ch.Templates[0].Data)).To(ContainSubstring("name: sro-{{ .Values.name }}"))
+	})
+
+	It("should fail without a kustomization", func() {
+		expectConfigMap(map[string]string{"service.yaml": "kind: Service"})
+
+		_, err := manifests.New(mockClient).Load(context.Background(), "simple-kmod", &v1beta1.SpecialResourceManifests{ConfigMap: cmName, Kustomize: true})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("PostRenderer", func() {
+	const namespaceEnvVar = "OPERATOR_NAMESPACE"
+
+	BeforeEach(func() {
+		err := os.Setenv(namespaceEnvVar, namespace)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		err := os.Unsetenv(namespaceEnvVar)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not post-render without spec", func() {
+		pr, err := manifests.New(mockClient).PostRenderer(context.Background(), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pr).To(BeNil())
+	})
+
+	It("should apply the kustomization to the rendered manifests", func() {
+		expectConfigMap(map[string]string{
+			"kustomization.yaml": "resources:\n- rendered.yaml\ncommonLabels:\n  env: lab\n",
+		})
+
+		pr, err := manifests.New(mockClient).PostRenderer(context.Background(), &helmerv1beta1.HelmPostRenderer{ConfigMap: cmName})
+		Expect(err).NotTo(HaveOccurred())
+
+		out, err := pr.Run(bytes.NewBufferString("---\n# Source: simple-kmod/templates/0000-service.yaml\napiVersion: v1\nkind: Service\nmetadata:\n  name: simple-kmod\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(ContainSubstring("env: lab"))
+		Expect(out.String()).To(ContainSubstring("name: simple-kmod"))
+
+		empty, err := pr.Run(bytes.NewBufferString("\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(empty.String()).To(Equal("\n"))
+	})
+
+	It("should be identified by the files of the ConfigMap", func() {
+		postRenderer := func(files map[string]string) string {
+			expectConfigMap(files)
+
+			pr, err := manifests.New(mockClient).PostRenderer(context.Background(), &helmerv1beta1.HelmPostRenderer{ConfigMap: cmName})
+			Expect(err).NotTo(HaveOccurred())
+
+			return pr.(fmt.Stringer).String()
+		}
+
+		id := postRenderer(map[string]string{"kustomization.yaml": "resources:\n- rendered.yaml\n"})
+		Expect(postRenderer(map[string]string{"kustomization.yaml": "resources:\n- rendered.yaml\n"})).To(Equal(id))
+		Expect(postRenderer(map[string]string{"kustomization.yaml": "resources:\n- rendered.yaml\ncommonLabels:\n  env: lab\n"})).NotTo(Equal(id))
+	})
+
+	It("should reserve the rendered manifests file", func() {
+		expectConfigMap(map[string]string{"rendered.yaml": "kind: Service"})
+
+		_, err := manifests.New(mockClient).PostRenderer(context.Background(), &helmerv1beta1.HelmPostRenderer{ConfigMap: cmName})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Substitute", func() {
+	It("should escape Go templates", func() {
+		Expect(string(manifests.Substitute([]byte(`format: "{{.Name}}" kernel: ${kernelFullVersion} shell: $HOME`)))).
+			To(Equal(`format: "{{ "{{" }}.Name}}" kernel: {{ .Values.kernelFullVersion }} shell: $HOME`))
+	})
+})
@@ -1,6 +1,10 @@
 package metrics
 
 import (
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -11,8 +15,31 @@ const (
 	completedStatesQuery         = "sro_states_completed_info"
 	completedKindQuery           = "sro_kind_completed_info"
 	usedNodesQuery               = "sro_used_nodes"
+	ownedObjectsQuery            = "sro_owned_objects"
+	statesQuery                  = "sro_states"
+	kernelCoverageQuery          = "sro_kernel_coverage_info"
+	featureGateQuery             = "sro_feature_gate_info"
+	specialResourceInfoQuery     = "sro_specialresource_info"
+	watchEventsQuery             = "sro_watch_events_total"
+	reconcileErrorsQuery         = "sro_reconcile_errors_total"
+	apiRequestDurationQuery      = "sro_api_request_duration_seconds"
+)
+
+// Results of the watch events metric
+const (
+	WatchEventEnqueued = "enqueued"
+	WatchEventFiltered = "filtered"
+)
+
+// Statuses of the kernel coverage metric
+const (
+	CoverageBuilt      = "built"
+	CoverageInProgress = "in-progress"
+	CoverageFailed     = "failed"
 )
 
+var coverageStatuses = []string{CoverageBuilt, CoverageInProgress, CoverageFailed}
+
 var (
 	//#TODO set the metric
 	createdSpecialResources = prometheus.NewGauge(
@@ -42,8 +69,123 @@ var (
 		},
 		[]string{"cr", "kind", "name", "namespace", "nodes"},
 	)
+	ownedObjects = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: ownedObjectsQuery,
+			Help: "Number of objects owned by a specialresource, per kind and cluster version",
+		},
+		[]string{"specialresource", "kind", "cluster_version"},
+	)
+	states = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: statesQuery,
+			Help: "Number of states of a specialresource per status, completed or failed",
+		},
+		[]string{"specialresource", "status"},
+	)
+	kernelCoverage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: kernelCoverageQuery,
+			Help: "For a given specialresource, cluster version and kernel, 1 for the current status of the kernel affine states, 0 for the other ones.",
+		},
+		[]string{"specialresource", "cluster_version", "kernel", "status"},
+	)
+	featureGate = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: featureGateQuery,
+			Help: "For a given feature gate, 1 if it is enabled, 0 if it is not. The specialresource is empty for the gates of the operator.",
+		},
+		[]string{"specialresource", "gate"},
+	)
+	specialResourceInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: specialResourceInfoQuery,
+			Help: "Always 1, describes the chart, driver and kernel versions a specialresource currently manages.",
+		},
+		[]string{"specialresource", "namespace", "chart", "chart_version", "driver_version", "kernels"},
+	)
+	watchEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: watchEventsQuery,
+			Help: "Number of watch events per kind and type, enqueued for a reconcile or filtered out.",
+		},
+		[]string{"kind", "event", "result"},
+	)
+	reconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: reconcileErrorsQuery,
+			Help: "Number of failed reconciles per specialresource and error category, e.g. ChartLoadError or WaitTimeout.",
+		},
+		[]string{"specialresource", "category"},
+	)
+	apiRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    apiRequestDurationQuery,
+			Help:    "Latency of the requests of the operator to the API server per verb and kind, the reads served by the cache are not counted.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"verb", "kind"},
+	)
+
+	// specialResourceInfoLabels holds the current labels of the info metric
+	// per specialresource, so that a single series is kept for each of them.
+	specialResourceInfoLabels = make(map[string]prometheus.Labels)
+	specialResourceInfoMutex  sync.Mutex
+
+	// The series set per specialresource, so that they are deleted with it
+	completedStatesSeries = newSeries(completedStates)
+	ownedObjectsSeries    = newSeries(ownedObjects)
+	statesSeries          = newSeries(states)
+	kernelCoverageSeries  = newSeries(kernelCoverage)
+	featureGateSeries     = newSeries(featureGate)
 )
 
+// series keeps the label values of the series of a vector per
+// specialresource, its first label. The client cannot delete the series
+// matching some of their labels only.
+type series struct {
+	vec *prometheus.GaugeVec
+
+	mu     sync.Mutex
+	values map[string]map[string][]string
+}
+
+func newSeries(vec *prometheus.GaugeVec) *series {
+	return &series{vec: vec, values: make(map[string]map[string][]string)}
+}
+
+// set sets the series of the label values, the first one being the
+// specialresource.
+func (s *series) set(value float64, values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.values[values[0]] == nil {
+		s.values[values[0]] = make(map[string][]string)
+	}
+	s.values[values[0]][strings.Join(values, "\x00")] = values
+
+	s.vec.WithLabelValues(values...).Set(value)
+}
+
+// delete deletes the series of specialResource whose label values match, all
+// of them if match is nil.
+func (s *series) delete(specialResource string, match func(values []string) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, values := range s.values[specialResource] {
+		if match == nil || match(values) {
+			s.vec.DeleteLabelValues(values...)
+			delete(s.values[specialResource], key)
+		}
+	}
+
+	if len(s.values[specialResource]) == 0 {
+		delete(s.values, specialResource)
+	}
+}
+
 func init() {
 	// Register custom metrics with the global prometheus registry
 	metrics.Registry.MustRegister(
@@ -51,6 +193,14 @@ func init() {
 		createdSpecialResources,
 		completedKinds,
 		usedNodes,
+		ownedObjects,
+		states,
+		kernelCoverage,
+		featureGate,
+		specialResourceInfo,
+		watchEvents,
+		reconcileErrors,
+		apiRequestDuration,
 	)
 }
 
@@ -60,8 +210,22 @@ func init() {
 type Metrics interface {
 	SetSpecialResourcesCreated(value int)
 	SetCompletedState(specialResource, state string, value int)
+	DeleteCompletedStates(specialResource string)
 	SetCompletedKind(specialResource, kind, name, namespace string, value int)
 	SetUsedNodes(crName, kind, name, namespace, nodes string)
+	SetOwnedObjects(specialResource, kind, clusterVersion string, value int)
+	DeleteOwnedObjects(specialResource string)
+	SetStates(specialResource, status string, value int)
+	DeleteStates(specialResource string)
+	SetKernelCoverage(specialResource, clusterVersion, kernel, status string)
+	DeleteKernelCoverage(specialResource string, keep []string)
+	SetFeatureGate(specialResource, gate string, enabled bool)
+	DeleteFeatureGates(specialResource string)
+	SetSpecialResourceInfo(specialResource, namespace, chart, chartVersion, driverVersion, kernels string)
+	DeleteSpecialResourceInfo(specialResource string)
+	IncWatchEvent(kind, event, result string)
+	IncReconcileError(specialResource, category string)
+	ObserveAPIRequest(verb, kind string, duration time.Duration)
 }
 
 func New() Metrics {
@@ -75,7 +239,11 @@ func (m *metricsImpl) SetSpecialResourcesCreated(value int) {
 }
 
 func (m *metricsImpl) SetCompletedState(specialResource, state string, value int) {
-	completedStates.WithLabelValues(specialResource, state).Set(float64(value))
+	completedStatesSeries.set(float64(value), specialResource, state)
+}
+
+func (m *metricsImpl) DeleteCompletedStates(specialResource string) {
+	completedStatesSeries.delete(specialResource, nil)
 }
 
 func (m *metricsImpl) SetCompletedKind(specialResource, kind, name, namespace string, value int) {
@@ -85,3 +253,96 @@ func (m *metricsImpl) SetCompletedKind(specialResource, kind, name, namespace st
 func (m *metricsImpl) SetUsedNodes(crName, kind, name, namespace, nodes string) {
 	usedNodes.WithLabelValues(crName, kind, name, namespace, nodes).Set(float64(1))
 }
+
+func (m *metricsImpl) SetOwnedObjects(specialResource, kind, clusterVersion string, value int) {
+	ownedObjectsSeries.set(float64(value), specialResource, kind, clusterVersion)
+}
+
+func (m *metricsImpl) DeleteOwnedObjects(specialResource string) {
+	ownedObjectsSeries.delete(specialResource, nil)
+}
+
+func (m *metricsImpl) SetStates(specialResource, status string, value int) {
+	statesSeries.set(float64(value), specialResource, status)
+}
+
+func (m *metricsImpl) DeleteStates(specialResource string) {
+	statesSeries.delete(specialResource, nil)
+}
+
+func (m *metricsImpl) SetKernelCoverage(specialResource, clusterVersion, kernel, status string) {
+	for _, s := range coverageStatuses {
+		value := 0.0
+		if s == status {
+			value = 1
+		}
+		kernelCoverageSeries.set(value, specialResource, clusterVersion, kernel, s)
+	}
+}
+
+// DeleteKernelCoverage deletes the coverage of the kernels of specialResource
+// that are not in keep, e.g. the ones no node runs anymore.
+func (m *metricsImpl) DeleteKernelCoverage(specialResource string, keep []string) {
+	kept := make(map[string]bool, len(keep))
+	for _, kernel := range keep {
+		kept[kernel] = true
+	}
+
+	kernelCoverageSeries.delete(specialResource, func(values []string) bool {
+		return !kept[values[2]]
+	})
+}
+
+func (m *metricsImpl) SetFeatureGate(specialResource, gate string, enabled bool) {
+	value := 0.0
+	if enabled {
+		value = 1
+	}
+	featureGateSeries.set(value, specialResource, gate)
+}
+
+func (m *metricsImpl) DeleteFeatureGates(specialResource string) {
+	featureGateSeries.delete(specialResource, nil)
+}
+
+func (m *metricsImpl) SetSpecialResourceInfo(specialResource, namespace, chart, chartVersion, driverVersion, kernels string) {
+	labels := prometheus.Labels{
+		"specialresource": specialResource,
+		"namespace":       namespace,
+		"chart":           chart,
+		"chart_version":   chartVersion,
+		"driver_version":  driverVersion,
+		"kernels":         kernels,
+	}
+
+	specialResourceInfoMutex.Lock()
+	defer specialResourceInfoMutex.Unlock()
+
+	if old, ok := specialResourceInfoLabels[specialResource]; ok {
+		specialResourceInfo.Delete(old)
+	}
+	specialResourceInfoLabels[specialResource] = labels
+	specialResourceInfo.With(labels).Set(1)
+}
+
+func (m *metricsImpl) DeleteSpecialResourceInfo(specialResource string) {
+	specialResourceInfoMutex.Lock()
+	defer specialResourceInfoMutex.Unlock()
+
+	if old, ok := specialResourceInfoLabels[specialResource]; ok {
+		specialResourceInfo.Delete(old)
+		delete(specialResourceInfoLabels, specialResource)
+	}
+}
+
+func (m *metricsImpl) IncWatchEvent(kind, event, result string) {
+	watchEvents.WithLabelValues(kind, event, result).Inc()
+}
+
+func (m *metricsImpl) IncReconcileError(specialResource, category string) {
+	reconcileErrors.WithLabelValues(specialResource, category).Inc()
+}
+
+func (m *metricsImpl) ObserveAPIRequest(verb, kind string, duration time.Duration) {
+	apiRequestDuration.WithLabelValues(verb, kind).Observe(duration.Seconds())
+}
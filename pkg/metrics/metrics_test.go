@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -15,6 +16,8 @@ const (
 	completedStatesValue       = 2
 	completedKindValue         = 2
 	usedNodesValue             = 1
+	ownedObjectsValue          = 3
+	statesValue                = 4
 
 	sr         = "simple-kmod"
 	state      = "templates/0000-buildconfig.yaml"
@@ -44,6 +47,18 @@ var _ = Describe("Metrics", func() {
 	m.SetCompletedState(sr, state, completedStatesValue)
 	m.SetCompletedKind(sr, kind, name, namespace, completedKindValue)
 	m.SetUsedNodes(sr, kind, name, namespace, nodes_list)
+	m.SetOwnedObjects(sr, kind, "4.10.3", ownedObjectsValue)
+	m.SetStates(sr, "completed", statesValue)
+	m.SetKernelCoverage(sr, "4.10.3", "4.18.0-305.19.1.el8_4.x86_64", CoverageInProgress)
+	m.SetKernelCoverage(sr, "4.10.3", "4.18.0-305.19.1.el8_4.x86_64", CoverageBuilt)
+	m.SetFeatureGate(sr, "StateHashes", true)
+	m.SetSpecialResourceInfo(sr, namespace, sr, "0.0.1", "1.0.0", "4.18.0-305.19.1.el8_4.x86_64")
+	m.SetSpecialResourceInfo(sr, namespace, sr, "0.0.2", "1.0.1", "4.18.0-305.19.1.el8_4.x86_64")
+	m.IncWatchEvent("DaemonSet", "update", WatchEventFiltered)
+	m.IncWatchEvent("DaemonSet", "update", WatchEventFiltered)
+	m.IncReconcileError(sr, "WaitTimeout")
+	m.ObserveAPIRequest("get", "ConfigMap", 20*time.Millisecond)
+	m.ObserveAPIRequest("get", "ConfigMap", 40*time.Millisecond)
 
 	It("correctly passes calls to the collectors", func() {
 		expected := []struct {
@@ -54,11 +69,16 @@ var _ = Describe("Metrics", func() {
 			{completedStatesQuery, completedStatesValue},
 			{completedKindQuery, completedKindValue},
 			{usedNodesQuery, usedNodesValue},
+			{ownedObjectsQuery, ownedObjectsValue},
+			{statesQuery, statesValue},
+			{featureGateQuery, 1},
+			{specialResourceInfoQuery, 1},
 		}
 
 		data, err := metrics.Registry.Gather()
 		Expect(err).NotTo(HaveOccurred())
-		Expect(data).To(HaveLen(len(expected)))
+		// The kernel coverage, the counters and the histogram are checked below
+		Expect(data).To(HaveLen(len(expected) + 4))
 
 		for _, e := range expected {
 			m := findMetric(data, e.query)
@@ -69,4 +89,140 @@ var _ = Describe("Metrics", func() {
 			Expect(*m.Metric[0].Gauge.Value).To(BeEquivalentTo(e.value))
 		}
 	})
+
+	It("counts the watch events", func() {
+		data, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		m := findMetric(data, watchEventsQuery)
+		Expect(m).ToNot(BeNil())
+		Expect(m.Metric).To(HaveLen(1))
+		Expect(m.Metric[0].Counter.GetValue()).To(BeEquivalentTo(2))
+	})
+
+	It("counts the reconcile errors by category", func() {
+		data, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		m := findMetric(data, reconcileErrorsQuery)
+		Expect(m).ToNot(BeNil())
+		Expect(m.Metric).To(HaveLen(1))
+		Expect(m.Metric[0].Counter.GetValue()).To(BeEquivalentTo(1))
+	})
+
+	It("observes the latency of the API requests", func() {
+		data, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		m := findMetric(data, apiRequestDurationQuery)
+		Expect(m).ToNot(BeNil())
+		Expect(m.Metric).To(HaveLen(1))
+		Expect(m.Metric[0].Histogram.GetSampleCount()).To(BeEquivalentTo(2))
+		Expect(m.Metric[0].Histogram.GetSampleSum()).To(BeNumerically("~", 0.06, 0.001))
+	})
+
+	It("only keeps the current info of a specialresource", func() {
+		data, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		m := findMetric(data, specialResourceInfoQuery)
+		Expect(m).ToNot(BeNil())
+		Expect(m.Metric).To(HaveLen(1))
+
+		labels := make(map[string]string)
+		for _, l := range m.Metric[0].Label {
+			labels[l.GetName()] = l.GetValue()
+		}
+		Expect(labels).To(HaveKeyWithValue("chart_version", "0.0.2"))
+		Expect(labels).To(HaveKeyWithValue("driver_version", "1.0.1"))
+	})
+
+	It("only sets the current status of the kernel coverage", func() {
+		data, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		m := findMetric(data, kernelCoverageQuery)
+		Expect(m).ToNot(BeNil())
+		Expect(m.Metric).To(HaveLen(len(coverageStatuses)))
+
+		for _, metric := range m.Metric {
+			var status string
+			for _, l := range metric.Label {
+				if l.GetName() == "status" {
+					status = l.GetValue()
+				}
+			}
+
+			if status == CoverageBuilt {
+				Expect(metric.Gauge.GetValue()).To(BeEquivalentTo(1))
+			} else {
+				Expect(metric.Gauge.GetValue()).To(BeEquivalentTo(0))
+			}
+		}
+	})
+})
+
+// seriesOf returns the series of the metric query for the specialresource.
+func seriesOf(query, specialResource string) []*dto.Metric {
+	data, err := metrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	found := make([]*dto.Metric, 0)
+
+	if m := findMetric(data, query); m != nil {
+		for _, metric := range m.Metric {
+			for _, l := range metric.Label {
+				if l.GetName() == "specialresource" && l.GetValue() == specialResource {
+					found = append(found, metric)
+				}
+			}
+		}
+	}
+
+	return found
+}
+
+var _ = Describe("Delete", func() {
+	const deleted = "deleted-kmod"
+
+	It("deletes the series of a specialresource only", func() {
+		m := New()
+		m.SetCompletedState(deleted, state, 1)
+		m.SetOwnedObjects(deleted, kind, "4.10.3", 1)
+		m.SetStates(deleted, "completed", 1)
+		m.SetKernelCoverage(deleted, "4.10.3", "4.18.0-305.19.1.el8_4.x86_64", CoverageBuilt)
+		m.SetFeatureGate(deleted, "StateHashes", true)
+
+		m.DeleteCompletedStates(deleted)
+		m.DeleteOwnedObjects(deleted)
+		m.DeleteStates(deleted)
+		m.DeleteKernelCoverage(deleted, nil)
+		m.DeleteFeatureGates(deleted)
+
+		for _, query := range []string{completedStatesQuery, ownedObjectsQuery, statesQuery, kernelCoverageQuery, featureGateQuery} {
+			Expect(seriesOf(query, deleted)).To(BeEmpty(), query)
+			Expect(seriesOf(query, sr)).NotTo(BeEmpty(), query)
+		}
+	})
+
+	It("only deletes the kernel coverage of the kernels that are not kept", func() {
+		m := New()
+		m.SetKernelCoverage(deleted, "4.10.3", "4.18.0-305.19.1.el8_4.x86_64", CoverageBuilt)
+		m.SetKernelCoverage(deleted, "4.10.3", "4.18.0-305.25.1.el8_4.x86_64", CoverageBuilt)
+
+		m.DeleteKernelCoverage(deleted, []string{"4.18.0-305.25.1.el8_4.x86_64"})
+
+		series := seriesOf(kernelCoverageQuery, deleted)
+		Expect(series).To(HaveLen(len(coverageStatuses)))
+		for _, metric := range series {
+			labels := make(map[string]string)
+			for _, l := range metric.Label {
+				labels[l.GetName()] = l.GetValue()
+			}
+			Expect(labels).To(HaveKeyWithValue("kernel", "4.18.0-305.25.1.el8_4.x86_64"))
+		}
+
+		m.DeleteKernelCoverage(deleted, nil)
+		Expect(seriesOf(kernelCoverageQuery, deleted)).To(BeEmpty())
+	})
 })
@@ -6,6 +6,7 @@ package metrics
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -33,6 +34,114 @@ func (m *MockMetrics) EXPECT() *MockMetricsMockRecorder {
 	return m.recorder
 }
 
+// DeleteCompletedStates mocks base method.
+func (m *MockMetrics) DeleteCompletedStates(specialResource string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteCompletedStates", specialResource)
+}
+
+// DeleteCompletedStates indicates an expected call of DeleteCompletedStates.
+func (mr *MockMetricsMockRecorder) DeleteCompletedStates(specialResource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompletedStates", reflect.TypeOf((*MockMetrics)(nil).DeleteCompletedStates), specialResource)
+}
+
+// DeleteFeatureGates mocks base method.
+func (m *MockMetrics) DeleteFeatureGates(specialResource string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteFeatureGates", specialResource)
+}
+
+// DeleteFeatureGates indicates an expected call of DeleteFeatureGates.
+func (mr *MockMetricsMockRecorder) DeleteFeatureGates(specialResource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeatureGates", reflect.TypeOf((*MockMetrics)(nil).DeleteFeatureGates), specialResource)
+}
+
+// DeleteKernelCoverage mocks base method.
+func (m *MockMetrics) DeleteKernelCoverage(specialResource string, keep []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteKernelCoverage", specialResource, keep)
+}
+
+// DeleteKernelCoverage indicates an expected call of DeleteKernelCoverage.
+func (mr *MockMetricsMockRecorder) DeleteKernelCoverage(specialResource, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKernelCoverage", reflect.TypeOf((*MockMetrics)(nil).DeleteKernelCoverage), specialResource, keep)
+}
+
+// DeleteOwnedObjects mocks base method.
+func (m *MockMetrics) DeleteOwnedObjects(specialResource string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteOwnedObjects", specialResource)
+}
+
+// DeleteOwnedObjects indicates an expected call of DeleteOwnedObjects.
+func (mr *MockMetricsMockRecorder) DeleteOwnedObjects(specialResource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOwnedObjects", reflect.TypeOf((*MockMetrics)(nil).DeleteOwnedObjects), specialResource)
+}
+
+// DeleteSpecialResourceInfo mocks base method.
+func (m *MockMetrics) DeleteSpecialResourceInfo(specialResource string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteSpecialResourceInfo", specialResource)
+}
+
+// DeleteSpecialResourceInfo indicates an expected call of DeleteSpecialResourceInfo.
+func (mr *MockMetricsMockRecorder) DeleteSpecialResourceInfo(specialResource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSpecialResourceInfo", reflect.TypeOf((*MockMetrics)(nil).DeleteSpecialResourceInfo), specialResource)
+}
+
+// DeleteStates mocks base method.
+func (m *MockMetrics) DeleteStates(specialResource string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteStates", specialResource)
+}
+
+// DeleteStates indicates an expected call of DeleteStates.
+func (mr *MockMetricsMockRecorder) DeleteStates(specialResource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStates", reflect.TypeOf((*MockMetrics)(nil).DeleteStates), specialResource)
+}
+
+// IncReconcileError mocks base method.
+func (m *MockMetrics) IncReconcileError(specialResource, category string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncReconcileError", specialResource, category)
+}
+
+// IncReconcileError indicates an expected call of IncReconcileError.
+func (mr *MockMetricsMockRecorder) IncReconcileError(specialResource, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncReconcileError", reflect.TypeOf((*MockMetrics)(nil).IncReconcileError), specialResource, category)
+}
+
+// IncWatchEvent mocks base method.
+func (m *MockMetrics) IncWatchEvent(kind, event, result string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncWatchEvent", kind, event, result)
+}
+
+// IncWatchEvent indicates an expected call of IncWatchEvent.
+func (mr *MockMetricsMockRecorder) IncWatchEvent(kind, event, result interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncWatchEvent", reflect.TypeOf((*MockMetrics)(nil).IncWatchEvent), kind, event, result)
+}
+
+// ObserveAPIRequest mocks base method.
+func (m *MockMetrics) ObserveAPIRequest(verb, kind string, duration time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveAPIRequest", verb, kind, duration)
+}
+
+// ObserveAPIRequest indicates an expected call of ObserveAPIRequest.
+func (mr *MockMetricsMockRecorder) ObserveAPIRequest(verb, kind, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveAPIRequest", reflect.TypeOf((*MockMetrics)(nil).ObserveAPIRequest), verb, kind, duration)
+}
+
 // SetCompletedKind mocks base method.
 func (m *MockMetrics) SetCompletedKind(specialResource, kind, name, namespace string, value int) {
 	m.ctrl.T.Helper()
@@ -57,6 +166,54 @@ func (mr *MockMetricsMockRecorder) SetCompletedState(specialResource, state, val
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCompletedState", reflect.TypeOf((*MockMetrics)(nil).SetCompletedState), specialResource, state, value)
 }
 
+// SetFeatureGate mocks base method.
+func (m *MockMetrics) SetFeatureGate(specialResource, gate string, enabled bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetFeatureGate", specialResource, gate, enabled)
+}
+
+// SetFeatureGate indicates an expected call of SetFeatureGate.
+func (mr *MockMetricsMockRecorder) SetFeatureGate(specialResource, gate, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFeatureGate", reflect.TypeOf((*MockMetrics)(nil).SetFeatureGate), specialResource, gate, enabled)
+}
+
+// SetKernelCoverage mocks base method.
+func (m *MockMetrics) SetKernelCoverage(specialResource, clusterVersion, kernel, status string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetKernelCoverage", specialResource, clusterVersion, kernel, status)
+}
+
+// SetKernelCoverage indicates an expected call of SetKernelCoverage.
+func (mr *MockMetricsMockRecorder) SetKernelCoverage(specialResource, clusterVersion, kernel, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKernelCoverage", reflect.TypeOf((*MockMetrics)(nil).SetKernelCoverage), specialResource, clusterVersion, kernel, status)
+}
+
+// SetOwnedObjects mocks base method.
+func (m *MockMetrics) SetOwnedObjects(specialResource, kind, clusterVersion string, value int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetOwnedObjects", specialResource, kind, clusterVersion, value)
+}
+
+// SetOwnedObjects indicates an expected call of SetOwnedObjects.
+func (mr *MockMetricsMockRecorder) SetOwnedObjects(specialResource, kind, clusterVersion, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOwnedObjects", reflect.TypeOf((*MockMetrics)(nil).SetOwnedObjects), specialResource, kind, clusterVersion, value)
+}
+
+// SetSpecialResourceInfo mocks base method.
+func (m *MockMetrics) SetSpecialResourceInfo(specialResource, namespace, chart, chartVersion, driverVersion, kernels string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSpecialResourceInfo", specialResource, namespace, chart, chartVersion, driverVersion, kernels)
+}
+
+// SetSpecialResourceInfo indicates an expected call of SetSpecialResourceInfo.
+func (mr *MockMetricsMockRecorder) SetSpecialResourceInfo(specialResource, namespace, chart, chartVersion, driverVersion, kernels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpecialResourceInfo", reflect.TypeOf((*MockMetrics)(nil).SetSpecialResourceInfo), specialResource, namespace, chart, chartVersion, driverVersion, kernels)
+}
+
 // SetSpecialResourcesCreated mocks base method.
 func (m *MockMetrics) SetSpecialResourcesCreated(value int) {
 	m.ctrl.T.Helper()
@@ -69,6 +226,18 @@ func (mr *MockMetricsMockRecorder) SetSpecialResourcesCreated(value interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpecialResourcesCreated", reflect.TypeOf((*MockMetrics)(nil).SetSpecialResourcesCreated), value)
 }
 
+// SetStates mocks base method.
+func (m *MockMetrics) SetStates(specialResource, status string, value int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetStates", specialResource, status, value)
+}
+
+// SetStates indicates an expected call of SetStates.
+func (mr *MockMetricsMockRecorder) SetStates(specialResource, status, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStates", reflect.TypeOf((*MockMetrics)(nil).SetStates), specialResource, status, value)
+}
+
 // SetUsedNodes mocks base method.
 func (m *MockMetrics) SetUsedNodes(crName, kind, name, namespace, nodes string) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,220 @@
+package ownership
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	KindSpecialResource       = "SpecialResource"
+	KindSpecialResourceModule = "SpecialResourceModule"
+
+	SpecialResourceOwnedLabel       = "specialresource.openshift.io/owned"
+	SpecialResourceModuleOwnedLabel = "specialresourcemodule.openshift.io/owned"
+
+	// DeletionPolicyAnnotation set to DeletionPolicyRetain keeps an object
+	// when its SpecialResource is deleted, e.g. a PVC holding build artifacts.
+	DeletionPolicyAnnotation = "specialresource.openshift.io/deletion-policy"
+	DeletionPolicyRetain     = "Retain"
+
+	// OwnerIndex is the field index of the cached objects by owner, see
+	// AddOwnerIndex.
+	OwnerIndex = "specialresource.openshift.io/owner"
+
+	releaseNameAnnotation = "meta.helm.sh/release-name"
+)
+
+// ownedLabels maps the Kind of an owner to the label it sets on its objects
+var ownedLabels = map[string]string{
+	KindSpecialResource:       SpecialResourceOwnedLabel,
+	KindSpecialResourceModule: SpecialResourceModuleOwnedLabel,
+}
+
+// OwnedKinds are the kinds of objects the SpecialResource controller watches
+// and owns.
+var OwnedKinds = []schema.GroupVersionKind{
+	{Group: "", Version: "v1", Kind: "Pod"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "storage.k8s.io", Version: "v1", Kind: "CSIDriver"},
+	{Group: "image.openshift.io", Version: "v1", Kind: "ImageStream"},
+	{Group: "build.openshift.io", Version: "v1", Kind: "BuildConfig"},
+	{Group: "", Version: "v1", Kind: "ConfigMap"},
+	{Group: "", Version: "v1", Kind: "ServiceAccount"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+	{Group: "security.openshift.io", Version: "v1", Kind: "SecurityContextConstraints"},
+	{Group: "", Version: "v1", Kind: "Secret"},
+}
+
+// Owner identifies the custom resource an object was created for
+type Owner struct {
+	Kind string
+	Name string
+}
+
+//go:generate mockgen -source=ownership.go -package=ownership -destination=mock_ownership_api.go
+
+type Ownership interface {
+	GetOwner(obj client.Object) (Owner, bool)
+	IsOwnedBy(obj client.Object, owner client.Object) bool
+	ListOwnedBy(ctx context.Context, owner client.Object, list *unstructured.UnstructuredList) error
+}
+
+type ownership struct {
+	kubeClient clients.ClientsInterface
+	indexed    bool
+}
+
+func New(kubeClient clients.ClientsInterface) Ownership {
+	return &ownership{kubeClient: kubeClient}
+}
+
+// NewIndexed returns an Ownership listing the owned objects through the
+// OwnerIndex, kubeClient has to read the unstructured objects from a cache
+// set up with AddOwnerIndex.
+func NewIndexed(kubeClient clients.ClientsInterface) Ownership {
+	return &ownership{kubeClient: kubeClient, indexed: true}
+}
+
+// AddOwnerIndex indexes the unstructured objects of the OwnedKinds by owner in
+// the cache of indexer, the kinds the cluster does not serve are skipped.
+func AddOwnerIndex(ctx context.Context, indexer client.FieldIndexer) error {
+	for _, gvk := range OwnedKinds {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+
+		if err := indexer.IndexField(ctx, obj, OwnerIndex, indexOwners); err != nil {
+			// OpenShift kinds on vanilla Kubernetes
+			var noMatch *meta.NoKindMatchError
+			if errors.As(err, &noMatch) {
+				continue
+			}
+			return fmt.Errorf("could not index %s by owner: %w", gvk.Kind, err)
+		}
+	}
+
+	return nil
+}
+
+// indexOwners returns the index values of every owner of obj.
+func indexOwners(obj client.Object) []string {
+	values := make([]string, 0)
+
+	for _, ref := range obj.GetOwnerReferences() {
+		if _, known := ownedLabels[ref.Kind]; known {
+			values = append(values, ownerIndexValue(ref.Kind, ref.Name))
+		}
+	}
+
+	// Objects that cannot carry an ownerReference
+	for kind, label := range ownedLabels {
+		if _, found := obj.GetLabels()[label]; found {
+			values = append(values, ownerIndexValue(kind, obj.GetAnnotations()[releaseNameAnnotation]))
+		}
+	}
+
+	return values
+}
+
+func ownerIndexValue(kind, name string) string {
+	return kind + "/" + name
+}
+
+// GetOwner returns the SpecialResource or SpecialResourceModule obj belongs to.
+// The controller ownerReference takes precedence, objects that cannot carry one
+// are matched through the owned label and the helm release name.
+func (o *ownership) GetOwner(obj client.Object) (Owner, bool) {
+	return getOwner(obj)
+}
+
+func getOwner(obj client.Object) (Owner, bool) {
+	for _, ref := range obj.GetOwnerReferences() {
+		if _, known := ownedLabels[ref.Kind]; known {
+			return Owner{Kind: ref.Kind, Name: ref.Name}, true
+		}
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		return Owner{}, false
+	}
+
+	for kind, label := range ownedLabels {
+		if _, found := labels[label]; found {
+			return Owner{Kind: kind, Name: obj.GetAnnotations()[releaseNameAnnotation]}, true
+		}
+	}
+
+	return Owner{}, false
+}
+
+// IsOwnedBy returns true if obj belongs to owner
+func (o *ownership) IsOwnedBy(obj client.Object, owner client.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if owner.GetUID() != "" && ref.UID == owner.GetUID() {
+			return true
+		}
+		if ref.Kind == kindOf(owner) && ref.Name == owner.GetName() {
+			return true
+		}
+	}
+
+	found, ok := getOwner(obj)
+
+	return ok && found.Kind == kindOf(owner) && found.Name == owner.GetName()
+}
+
+// ListOwnedBy lists the objects of the Kind set on list and only keeps the
+// ones that belong to owner. An indexed Ownership only lists the objects of
+// owner from the cache.
+func (o *ownership) ListOwnedBy(ctx context.Context, owner client.Object, list *unstructured.UnstructuredList) error {
+	label, ok := ownedLabels[kindOf(owner)]
+	if !ok {
+		return fmt.Errorf("%s cannot own objects", kindOf(owner))
+	}
+
+	opts := []client.ListOption{client.HasLabels{label}}
+	if o.indexed {
+		opts = append(opts, client.MatchingFields{OwnerIndex: ownerIndexValue(kindOf(owner), owner.GetName())})
+	}
+
+	if err := o.kubeClient.List(ctx, list, opts...); err != nil {
+		return fmt.Errorf("could not list %s: %w", list.GetKind(), err)
+	}
+
+	items := make([]unstructured.Unstructured, 0, len(list.Items))
+	for i := range list.Items {
+		if o.IsOwnedBy(&list.Items[i], owner) {
+			items = append(items, list.Items[i])
+		}
+	}
+	list.Items = items
+
+	return nil
+}
+
+// Retained returns true if obj has to survive the deletion of its owner.
+func Retained(obj client.Object) bool {
+	return obj.GetAnnotations()[DeletionPolicyAnnotation] == DeletionPolicyRetain
+}
+
+// kindOf returns the Kind of obj, typed objects fetched from the API
+// usually do not have their TypeMeta set.
+func kindOf(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
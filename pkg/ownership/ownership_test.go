@@ -0,0 +1,203 @@
+package ownership_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	ctrl       *gomock.Controller
+	mockClient *clients.MockClientsInterface
+)
+
+func TestOwnership(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = clients.NewMockClientsInterface(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "Ownership Suite")
+}
+
+var sr = &v1beta1.SpecialResource{
+	ObjectMeta: metav1.ObjectMeta{Name: "simple-kmod", UID: "1234"},
+}
+
+var _ = Describe("GetOwner", func() {
+	It("should not panic on objects without labels", func() {
+		_, ok := ownership.New(mockClient).GetOwner(&v1.ConfigMap{})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should return the SpecialResourceModule from the ownerReferences", func() {
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: ownership.KindSpecialResourceModule, Name: "srm"},
+				},
+			},
+		}
+
+		owner, ok := ownership.New(mockClient).GetOwner(cm)
+		Expect(ok).To(BeTrue())
+		Expect(owner).To(Equal(ownership.Owner{Kind: ownership.KindSpecialResourceModule, Name: "srm"}))
+	})
+
+	It("should return the SpecialResource from the label and the release name", func() {
+		cr := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{ownership.SpecialResourceOwnedLabel: "true"},
+				Annotations: map[string]string{"meta.helm.sh/release-name": "simple-kmod"},
+			},
+		}
+
+		owner, ok := ownership.New(mockClient).GetOwner(cr)
+		Expect(ok).To(BeTrue())
+		Expect(owner).To(Equal(ownership.Owner{Kind: ownership.KindSpecialResource, Name: "simple-kmod"}))
+	})
+})
+
+var _ = Describe("IsOwnedBy", func() {
+	It("should distinguish between SpecialResources", func() {
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: ownership.KindSpecialResource, Name: "other"},
+				},
+			},
+		}
+
+		o := ownership.New(mockClient)
+		Expect(o.IsOwnedBy(cm, sr)).To(BeFalse())
+
+		cm.OwnerReferences[0].Name = sr.Name
+		Expect(o.IsOwnedBy(cm, sr)).To(BeTrue())
+	})
+})
+
+var _ = Describe("ListOwnedBy", func() {
+	It("should only keep the objects owned by the CR", func() {
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion("rbac.authorization.k8s.io/v1")
+		list.SetKind("ClusterRoleList")
+
+		mockClient.EXPECT().
+			List(context.TODO(), list, client.HasLabels{ownership.SpecialResourceOwnedLabel}).
+			Do(func(_ context.Context, l *unstructured.UnstructuredList, _ client.ListOption) {
+				mine := unstructured.Unstructured{}
+				mine.SetName("mine")
+				mine.SetLabels(map[string]string{ownership.SpecialResourceOwnedLabel: "true"})
+				mine.SetAnnotations(map[string]string{"meta.helm.sh/release-name": sr.Name})
+
+				other := unstructured.Unstructured{}
+				other.SetName("other")
+				other.SetLabels(map[string]string{ownership.SpecialResourceOwnedLabel: "true"})
+				other.SetAnnotations(map[string]string{"meta.helm.sh/release-name": "other"})
+
+				l.Items = []unstructured.Unstructured{mine, other}
+			})
+
+		err := ownership.New(mockClient).ListOwnedBy(context.TODO(), sr, list)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].GetName()).To(Equal("mine"))
+	})
+
+	It("should only list the objects of the CR from the index", func() {
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion("apps/v1")
+		list.SetKind("DaemonSetList")
+
+		mockClient.EXPECT().
+			List(
+				context.TODO(),
+				list,
+				client.HasLabels{ownership.SpecialResourceOwnedLabel},
+				client.MatchingFields{ownership.OwnerIndex: "SpecialResource/" + sr.Name},
+			)
+
+		err := ownership.NewIndexed(mockClient).ListOwnedBy(context.TODO(), sr, list)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error for unknown owners", func() {
+		err := ownership.New(mockClient).ListOwnedBy(context.TODO(), &v1.Pod{}, &unstructured.UnstructuredList{})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// fakeIndexer records the index functions, it fails for the kinds in noMatch.
+type fakeIndexer struct {
+	funcs   map[string]client.IndexerFunc
+	noMatch map[string]bool
+}
+
+func (f *fakeIndexer) IndexField(_ context.Context, obj client.Object, field string, fn client.IndexerFunc) error {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if f.noMatch[kind] {
+		return &meta.NoKindMatchError{GroupKind: obj.GetObjectKind().GroupVersionKind().GroupKind()}
+	}
+
+	Expect(field).To(Equal(ownership.OwnerIndex))
+	f.funcs[kind] = fn
+
+	return nil
+}
+
+var _ = Describe("AddOwnerIndex", func() {
+	It("should index the owned kinds the cluster serves by owner", func() {
+		indexer := &fakeIndexer{
+			funcs:   make(map[string]client.IndexerFunc),
+			noMatch: map[string]bool{"BuildConfig": true},
+		}
+
+		Expect(ownership.AddOwnerIndex(context.TODO(), indexer)).To(Succeed())
+		Expect(indexer.funcs).To(HaveLen(len(ownership.OwnedKinds) - 1))
+		Expect(indexer.funcs).NotTo(HaveKey("BuildConfig"))
+
+		byRef := &unstructured.Unstructured{}
+		byRef.SetOwnerReferences([]metav1.OwnerReference{
+			{Kind: ownership.KindSpecialResourceModule, Name: "srm"},
+			{Kind: "ReplicaSet", Name: "rs"},
+		})
+
+		byLabel := &unstructured.Unstructured{}
+		byLabel.SetLabels(map[string]string{ownership.SpecialResourceOwnedLabel: "true"})
+		byLabel.SetAnnotations(map[string]string{"meta.helm.sh/release-name": sr.Name})
+
+		index := indexer.funcs["DaemonSet"]
+		Expect(index(byRef)).To(Equal([]string{"SpecialResourceModule/srm"}))
+		Expect(index(byLabel)).To(Equal([]string{"SpecialResource/" + sr.Name}))
+		Expect(index(&unstructured.Unstructured{})).To(BeEmpty())
+	})
+
+	It("should return the other errors", func() {
+		Expect(ownership.AddOwnerIndex(context.TODO(), failingIndexer{})).To(HaveOccurred())
+	})
+})
+
+type failingIndexer struct{}
+
+func (failingIndexer) IndexField(context.Context, client.Object, string, client.IndexerFunc) error {
+	return errors.New("random error")
+}
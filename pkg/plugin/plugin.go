@@ -0,0 +1,216 @@
+package plugin
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-psap/special-resource-operator/pkg/runtime"
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	"helm.sh/helm/v3/pkg/postrender"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// FailurePolicy is what happens to a state when the plugin cannot be reached
+// or does not answer properly.
+type FailurePolicy string
+
+const (
+	// Fail fails the state, it is retried on the next reconcile.
+	Fail FailurePolicy = "Fail"
+	// Ignore applies the state as rendered.
+	Ignore FailurePolicy = "Ignore"
+)
+
+// Request is sent to the plugin before a state is applied. The Helm hooks of
+// the state are sent on their own, with the template they are rendered from as
+// Hook.
+type Request struct {
+	SpecialResource    string                      `json:"specialResource"`
+	State              string                      `json:"state"`
+	Kernel             string                      `json:"kernel,omitempty"`
+	Hook               string                      `json:"hook,omitempty"`
+	RuntimeInformation *runtime.RuntimeInformation `json:"runtimeInformation,omitempty"`
+	Manifests          string                      `json:"manifests"`
+}
+
+// Response is the decision of the plugin. Manifests, if set, replace the
+// manifests of the request.
+type Response struct {
+	Allowed   bool   `json:"allowed"`
+	Reason    string `json:"reason,omitempty"`
+	Manifests string `json:"manifests,omitempty"`
+}
+
+// DeniedError is a state the plugin did not allow.
+type DeniedError struct {
+	State  string
+	Reason string
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("state %s denied by the decision plugin: %s", e.State, e.Reason)
+}
+
+// Options configure the plugin endpoint.
+type Options struct {
+	// URL of the endpoint, the plugin is disabled if empty.
+	URL string
+	// CAFile is the CA bundle of an HTTPS endpoint, the system roots are
+	// used if empty.
+	CAFile        string
+	Timeout       time.Duration
+	FailurePolicy FailurePolicy
+}
+
+//go:generate mockgen -source=plugin.go -package=plugin -destination=mock_plugin_api.go
+
+type Plugin interface {
+	PostRenderer(req Request, next postrender.PostRenderer) postrender.PostRenderer
+}
+
+type plugin struct {
+	log    logr.Logger
+	opts   Options
+	client *http.Client
+}
+
+// New returns the Plugin calling the endpoint of opts. A disabled Plugin does
+// not change the post-renderers.
+func New(opts Options) (Plugin, error) {
+	switch opts.FailurePolicy {
+	case Fail, Ignore:
+	default:
+		return nil, fmt.Errorf("unknown failure policy %q, either %s or %s", opts.FailurePolicy, Fail, Ignore)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.CAFile != "" {
+		ca, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the CA of the decision plugin: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no CA certificate found for the decision plugin")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &plugin{
+		log:    zap.New(zap.UseDevMode(true)).WithName(utils.Print("plugin", utils.Brown)),
+		opts:   opts,
+		client: &http.Client{Transport: transport, Timeout: opts.Timeout},
+	}, nil
+}
+
+// PostRenderer returns the post-renderer sending the manifests of the state
+// of req to the plugin, once next transformed them.
+func (p *plugin) PostRenderer(req Request, next postrender.PostRenderer) postrender.PostRenderer {
+	if p.opts.URL == "" {
+		return next
+	}
+
+	return &postRenderer{plugin: p, req: req, next: next}
+}
+
+type postRenderer struct {
+	plugin *plugin
+	req    Request
+	next   postrender.PostRenderer
+}
+
+// String identifies the next post-renderer and the endpoint of the plugin, the
+// decisions of the plugin are not part of it.
+func (r *postRenderer) String() string {
+	id := fmt.Sprintf("plugin %s %s", r.plugin.opts.URL, r.plugin.opts.FailurePolicy)
+	if next, ok := r.next.(fmt.Stringer); ok {
+		id = next.String() + ", " + id
+	}
+
+	return id
+}
+
+func (r *postRenderer) Run(rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	if r.next != nil {
+		var err error
+		if rendered, err = r.next.Run(rendered); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.decide(r.req, rendered)
+}
+
+// RunHook sends the hook rendered from the template path to the plugin. Helm
+// only post-renders the manifests of a release, the hooks are not transformed
+// by the next post-renderer.
+func (r *postRenderer) RunHook(path string, rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	req := r.req
+	req.Hook = path
+
+	return r.decide(req, rendered)
+}
+
+func (r *postRenderer) decide(req Request, rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	// Nothing to decide on
+	if len(bytes.TrimSpace(rendered.Bytes())) == 0 {
+		return rendered, nil
+	}
+
+	req.Manifests = rendered.String()
+
+	resp, err := r.plugin.call(req)
+	if err != nil {
+		if r.plugin.opts.FailurePolicy == Ignore {
+			r.plugin.log.Info("Decision plugin failed, applying the state as rendered", "state", req.State, "error", err)
+			return rendered, nil
+		}
+		return nil, fmt.Errorf("decision plugin failed for state %s: %w", req.State, err)
+	}
+
+	if !resp.Allowed {
+		return nil, sroerrors.Wrap(sroerrors.Blocked, &DeniedError{State: req.State, Reason: resp.Reason})
+	}
+
+	if resp.Manifests != "" {
+		r.plugin.log.Info("Decision plugin changed the manifests", "state", req.State, "reason", resp.Reason)
+		return bytes.NewBufferString(resp.Manifests), nil
+	}
+
+	return rendered, nil
+}
+
+func (p *plugin) call(req Request) (*Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp, err := p.client.Post(p.opts.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", httpResp.Status)
+	}
+
+	resp := &Response{}
+	if err = json.NewDecoder(httpResp.Body).Decode(resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+
+	return resp, nil
+}
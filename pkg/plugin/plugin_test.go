@@ -0,0 +1,163 @@
+package plugin_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	"github.com/openshift-psap/special-resource-operator/pkg/plugin"
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
+)
+
+const manifests = "---\nkind: DaemonSet\n"
+
+func TestPlugin(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Plugin Suite")
+}
+
+type commentPostRenderer struct{}
+
+func (commentPostRenderer) Run(rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	return bytes.NewBufferString(rendered.String() + "# post-rendered\n"), nil
+}
+
+var _ = Describe("Plugin", func() {
+	var (
+		received []plugin.Request
+		respond  func(w http.ResponseWriter)
+		server   *httptest.Server
+	)
+
+	BeforeEach(func() {
+		received = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req := plugin.Request{}
+			Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+			received = append(received, req)
+			respond(w)
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	answer := func(resp plugin.Response) func(w http.ResponseWriter) {
+		return func(w http.ResponseWriter) {
+			Expect(json.NewEncoder(w).Encode(resp)).To(Succeed())
+		}
+	}
+
+	run := func(policy plugin.FailurePolicy) (*bytes.Buffer, error) {
+		p, err := plugin.New(plugin.Options{URL: server.URL, Timeout: time.Second, FailurePolicy: policy})
+		Expect(err).NotTo(HaveOccurred())
+
+		req := plugin.Request{SpecialResource: "simple-kmod", State: "0000-buildconfig.yaml", Kernel: "4.18.0"}
+
+		return p.PostRenderer(req, commentPostRenderer{}).Run(bytes.NewBufferString(manifests))
+	}
+
+	It("should send the post-rendered manifests and keep them when allowed", func() {
+		respond = answer(plugin.Response{Allowed: true})
+
+		out, err := run(plugin.Fail)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal(manifests + "# post-rendered\n"))
+
+		Expect(received).To(HaveLen(1))
+		Expect(received[0].State).To(Equal("0000-buildconfig.yaml"))
+		Expect(received[0].Kernel).To(Equal("4.18.0"))
+		Expect(received[0].Manifests).To(Equal(manifests + "# post-rendered\n"))
+	})
+
+	It("should replace the manifests with the ones of the plugin", func() {
+		respond = answer(plugin.Response{Allowed: true, Manifests: "---\nkind: ConfigMap\n"})
+
+		out, err := run(plugin.Fail)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal("---\nkind: ConfigMap\n"))
+	})
+
+	It("should fail a denied state as blocked", func() {
+		respond = answer(plugin.Response{Allowed: false, Reason: "unsigned image"})
+
+		_, err := run(plugin.Ignore)
+
+		var denied *plugin.DeniedError
+		Expect(errors.As(err, &denied)).To(BeTrue())
+		Expect(denied.Reason).To(Equal("unsigned image"))
+		Expect(sroerrors.CategoryOf(err)).To(Equal(sroerrors.Blocked))
+	})
+
+	DescribeTable("a failing plugin",
+		func(policy plugin.FailurePolicy, fails bool) {
+			respond = func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+
+			out, err := run(policy)
+			if fails {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(Equal(manifests + "# post-rendered\n"))
+		},
+		Entry("fails the state with the Fail policy", plugin.Fail, true),
+		Entry("applies the state as rendered with the Ignore policy", plugin.Ignore, false),
+	)
+
+	It("should send the hooks on their own, not post-rendered", func() {
+		respond = answer(plugin.Response{Allowed: true, Manifests: "---\nkind: Job\n"})
+
+		p, err := plugin.New(plugin.Options{URL: server.URL, Timeout: time.Second, FailurePolicy: plugin.Fail})
+		Expect(err).NotTo(HaveOccurred())
+
+		req := plugin.Request{SpecialResource: "simple-kmod", State: "0000-buildconfig.yaml"}
+		postRenderer := p.PostRenderer(req, commentPostRenderer{}).(helmer.HookPostRenderer)
+
+		out, err := postRenderer.RunHook("simple-kmod/templates/unload.yaml", bytes.NewBufferString(manifests))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal("---\nkind: Job\n"))
+
+		Expect(received).To(HaveLen(1))
+		Expect(received[0].Hook).To(Equal("simple-kmod/templates/unload.yaml"))
+		Expect(received[0].Manifests).To(Equal(manifests))
+	})
+
+	It("should be identified by its endpoint", func() {
+		p, err := plugin.New(plugin.Options{URL: server.URL, FailurePolicy: plugin.Fail})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(p.PostRenderer(plugin.Request{}, nil).(fmt.Stringer).String()).To(Equal("plugin " + server.URL + " Fail"))
+	})
+
+	It("should not call the plugin for an empty state", func() {
+		p, err := plugin.New(plugin.Options{URL: server.URL, FailurePolicy: plugin.Fail})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = p.PostRenderer(plugin.Request{}, nil).Run(bytes.NewBufferString("\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(received).To(BeEmpty())
+	})
+
+	It("should return the next post-renderer when disabled", func() {
+		p, err := plugin.New(plugin.Options{FailurePolicy: plugin.Fail})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(p.PostRenderer(plugin.Request{}, nil)).To(BeNil())
+	})
+
+	It("should refuse an unknown failure policy", func() {
+		_, err := plugin.New(plugin.Options{FailurePolicy: "Maybe"})
+		Expect(err).To(HaveOccurred())
+	})
+})
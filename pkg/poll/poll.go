@@ -13,10 +13,12 @@ import (
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
 	"github.com/openshift-psap/special-resource-operator/pkg/lifecycle"
 	"github.com/openshift-psap/special-resource-operator/pkg/storage"
+	"github.com/openshift-psap/special-resource-operator/pkg/tracing"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
 
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -47,6 +49,39 @@ var (
 	timeout       = time.Second * 30
 )
 
+const (
+	buildPodAnnotation = "openshift.io/build.pod-name"
+	buildLogTailLines  = 20
+	buildLogMaxBytes   = 2048
+)
+
+// transientBuildReasons are the Build status reasons caused by the
+// environment rather than by the driver sources, a new Build may succeed.
+var transientBuildReasons = map[string]bool{
+	"FetchSourceFailed":         true,
+	"FetchImageContentFailed":   true,
+	"PullBuilderImageFailed":    true,
+	"PushImageToRegistryFailed": true,
+}
+
+// BuildFailedError is returned when a Build did not complete
+type BuildFailedError struct {
+	Namespace string
+	Name      string
+	Phase     string
+	Reason    string
+	Log       string
+}
+
+func (e *BuildFailedError) Error() string {
+	return fmt.Sprintf("build %s/%s %s (%s): %s", e.Namespace, e.Name, e.Phase, e.Reason, e.Log)
+}
+
+// Transient returns true if the Build failed for a reason that may not occur again
+func (e *BuildFailedError) Transient() bool {
+	return transientBuildReasons[e.Reason]
+}
+
 func New(kubeClient clients.ClientsInterface, lc lifecycle.Lifecycle, storage storage.Storage) PollActions {
 	actions := pollActions{
 		kubeClient: kubeClient,
@@ -60,6 +95,7 @@ func New(kubeClient clients.ClientsInterface, lc lifecycle.Lifecycle, storage st
 		"BuildConfig":              actions.forBuild,
 		"Secret":                   actions.forSecret,
 		"CustomResourceDefinition": actions.forCRD,
+		"CSIDriver":                actions.forCSIDriver,
 		"Job":                      actions.forJob,
 		"Deployment":               actions.forDeployment,
 		"StatefulSet":              actions.forStatefulSet,
@@ -75,7 +111,7 @@ type statusCallback func(ctx context.Context, obj *unstructured.Unstructured) (b
 func (p *pollActions) forResourceAvailability(ctx context.Context, obj *unstructured.Unstructured) error {
 
 	found := obj.DeepCopy()
-	err := wait.Poll(retryInterval, timeout, func() (done bool, err error) {
+	err := wait.PollWithContext(ctx, retryInterval, timeout, func(ctx context.Context) (done bool, err error) {
 		err = p.kubeClient.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, found)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
@@ -92,7 +128,7 @@ func (p *pollActions) forResourceAvailability(ctx context.Context, obj *unstruct
 func (p *pollActions) ForResourceUnavailability(ctx context.Context, obj *unstructured.Unstructured) error {
 
 	found := obj.DeepCopy()
-	err := wait.Poll(retryInterval, timeout, func() (done bool, err error) {
+	err := wait.PollWithContext(ctx, retryInterval, timeout, func(ctx context.Context) (done bool, err error) {
 		err = p.kubeClient.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, found)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
@@ -150,7 +186,10 @@ func (p *pollActions) ForResource(ctx context.Context, obj *unstructured.Unstruc
 	// DaemonSet NumberUnavailable == 0, etc
 	if wait, ok := p.waitFor[obj.GetKind()]; ok {
 		p.log.Info("ForResource", "Kind", obj.GetKind())
-		if err = wait(ctx, obj); err != nil {
+		_, span := tracing.Start(ctx, "wait", "kind", obj.GetKind(), "name", obj.GetName())
+		err = wait(ctx, obj)
+		span.End(err)
+		if err != nil {
 			return errors.Wrap(err, "Waiting too long for resource")
 		}
 	} else {
@@ -166,18 +205,72 @@ func (p *pollActions) forSecret(ctx context.Context, obj *unstructured.Unstructu
 
 func (p *pollActions) forCRD(ctx context.Context, obj *unstructured.Unstructured) error {
 
-	p.kubeClient.Invalidate()
 	// Lets wait some time for the API server to register the new CRD
 	if err := p.forResourceAvailability(ctx, obj); err != nil {
 		return err
 	}
 
+	// The custom resources of the CRD are rejected until it is established
+	if err := p.forResourceFullAvailability(ctx, obj, crdEstablished); err != nil {
+		return err
+	}
+
+	p.kubeClient.Invalidate()
 	_, err := p.kubeClient.ServerGroups()
 	utils.WarnOnError(err)
 
 	return nil
 }
 
+// crdEstablished returns true once the Established condition of the CRD is True.
+func crdEstablished(_ context.Context, obj *unstructured.Unstructured) (bool, error) {
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false, fmt.Errorf("could not get the conditions of CRD %s: %w", obj.GetName(), err)
+	}
+
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == "Established" {
+			return condition["status"] == "True", nil
+		}
+	}
+
+	return false, nil
+}
+
+func (p *pollActions) forCSIDriver(ctx context.Context, obj *unstructured.Unstructured) error {
+	if err := p.forResourceAvailability(ctx, obj); err != nil {
+		return err
+	}
+
+	// The volumes of the driver cannot be mounted before the node plugin
+	// registered it with the kubelet, which lists it in the CSINode.
+	return wait.PollWithContext(ctx, retryInterval, timeout, func(ctx context.Context) (bool, error) {
+		csiNodes := &storagev1.CSINodeList{}
+		if err := p.kubeClient.List(ctx, csiNodes); err != nil {
+			return false, fmt.Errorf("could not list CSINodes: %w", err)
+		}
+
+		var registered int
+		for _, csiNode := range csiNodes.Items {
+			for _, driver := range csiNode.Spec.Drivers {
+				if driver.Name == obj.GetName() {
+					registered++
+				}
+			}
+		}
+
+		if registered == 0 {
+			p.log.Info("Waiting for registration of ", "CSIDriver", obj.GetName())
+			return false, nil
+		}
+
+		p.log.Info("CSIDriver registered", "CSIDriver", obj.GetName(), "nodes", registered)
+		return true, nil
+	})
+}
+
 func (p *pollActions) forPod(ctx context.Context, obj *unstructured.Unstructured) error {
 	if err := p.forResourceAvailability(ctx, obj); err != nil {
 		return err
@@ -316,6 +409,11 @@ func (p *pollActions) forJob(ctx context.Context, obj *unstructured.Unstructured
 				if stype == "Complete" {
 					return true, nil
 				}
+
+				// A failed Job will not complete anymore, no need to wait
+				if stype == "Failed" {
+					return false, fmt.Errorf("job %s/%s failed", obj.GetNamespace(), obj.GetName())
+				}
 			}
 
 		}
@@ -373,7 +471,7 @@ func (p *pollActions) forLifecycleAvailability(ctx context.Context, obj *unstruc
 		Name:      "special-resource-lifecycle",
 	}
 
-	return wait.Poll(retryInterval, timeout, func() (done bool, err error) {
+	return wait.PollWithContext(ctx, retryInterval, timeout, func(ctx context.Context) (done bool, err error) {
 
 		p.log.Info("Waiting for lifecycle update of ", "Namespace", obj.GetNamespace(), "Name", obj.GetName())
 
@@ -446,15 +544,67 @@ func (p *pollActions) forBuild(ctx context.Context, obj *unstructured.Unstructur
 	if build == nil {
 		return errors.New("Build object not yet available")
 	}
-	callback := makeStatusCallback("Complete", "status", "phase")
-	return p.forResourceFullAvailability(ctx, build, callback)
+	return p.forResourceFullAvailability(ctx, build, p.forBuildCallback)
+}
+
+// forBuildCallback waits for the Build to complete, if the Build did not
+// succeed it stops waiting and returns the tail of the build log so that it
+// ends up in the SpecialResource status even if the build pod is gone later.
+func (p *pollActions) forBuildCallback(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+	phase, _, err := unstructured.NestedString(obj.Object, "status", "phase")
+	if err != nil {
+		return false, err
+	}
+
+	switch phase {
+	case "Complete":
+		return true, nil
+	case "Failed", "Error", "Cancelled":
+		reason, _, _ := unstructured.NestedString(obj.Object, "status", "reason")
+		return false, &BuildFailedError{
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Phase:     phase,
+			Reason:    reason,
+			Log:       p.buildLogTail(ctx, obj),
+		}
+	}
+
+	return false, nil
+}
+
+func (p *pollActions) buildLogTail(ctx context.Context, build *unstructured.Unstructured) string {
+	podName, found := build.GetAnnotations()[buildPodAnnotation]
+	if !found {
+		return "build log not available"
+	}
+
+	tailLines := int64(buildLogTailLines)
+	req := p.kubeClient.GetPodLogs(build.GetNamespace(), podName, &v1.PodLogOptions{TailLines: &tailLines})
+	podLogs, err := req.Stream(ctx)
+	if err != nil {
+		return fmt.Sprintf("could not get build log: %v", err)
+	}
+	defer podLogs.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err = io.Copy(buf, podLogs); err != nil {
+		return fmt.Sprintf("could not read build log: %v", err)
+	}
+
+	logs := buf.String()
+	if len(logs) > buildLogMaxBytes {
+		logs = "..." + logs[len(logs)-buildLogMaxBytes:]
+	}
+
+	return logs
 }
 
 func (p *pollActions) forResourceFullAvailability(ctx context.Context, obj *unstructured.Unstructured, callback statusCallback) error {
 
 	found := obj.DeepCopy()
 
-	return wait.Poll(retryInterval, timeout, func() (bool, error) {
+	return wait.PollWithContext(ctx, retryInterval, timeout, func(ctx context.Context) (bool, error) {
 		err := p.kubeClient.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, found)
 		if err != nil {
 			p.log.Error(err, "failed to get an object", "name", obj.GetName(), "namespace", obj.GetNamespace())
@@ -2,6 +2,7 @@ package poll
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
@@ -17,6 +18,7 @@ import (
 	"github.com/openshift-psap/special-resource-operator/pkg/lifecycle"
 	"github.com/openshift-psap/special-resource-operator/pkg/storage"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -67,6 +69,25 @@ func prepareUnstructured(kind, name, namespace string) *unstructured.Unstructure
 	return obj
 }
 
+func prepareReq(log string) *restclient.Request {
+	roundTripper := func(*http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Set("Content-Type", "text/plain")
+
+		body := io.NopCloser(strings.NewReader(log))
+
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       body,
+		}
+		return resp, nil
+	}
+	fakeHTTPClient := fakerestclient.CreateHTTPClient(roundTripper)
+	return restclient.NewRequestWithClient(nil, "",
+		restclient.ClientContentConfig{}, fakeHTTPClient)
+}
+
 var _ = Context("Waiting for resource", func() {
 	// Following test focuses on forResourceAvailability so other tests can focus on more specific use cases
 	DescribeTable("Namespace/Certificates/Secrets",
@@ -161,18 +182,49 @@ var _ = Context("Waiting for resource", func() {
 	)
 
 	Specify("should work for CRDs", func() {
-		// forCRD
-		mockClientsInterface.EXPECT().Invalidate()
-
 		// forResourceAvailability
 		mockClientsInterface.EXPECT().Get(Any(), Any(), Any()).Return(nil)
 
+		// forResourceFullAvailability
+		mockClientsInterface.EXPECT().Get(Any(), Any(), Any()).Do(
+			func(_ context.Context, _ types.NamespacedName, obj client.Object) {
+				conditions := []interface{}{
+					map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+					map[string]interface{}{"type": "Established", "status": "True"},
+				}
+				Expect(unstructured.SetNestedSlice(obj.(*unstructured.Unstructured).Object, conditions, "status", "conditions")).To(Succeed())
+			},
+		)
+
 		// forCRD
+		mockClientsInterface.EXPECT().Invalidate()
 		mockClientsInterface.EXPECT().ServerGroups().Return(nil, nil)
 
 		Expect(pa.ForResource(context.Background(), prepareUnstructured("CustomResourceDefinition", "crd-name", ""))).To(Succeed())
 	})
 
+	DescribeTable("should work for CSIDrivers",
+		func(drivers []string, matcher gtypes.GomegaMatcher) {
+			// forResourceAvailability
+			mockClientsInterface.EXPECT().Get(Any(), Any(), Any()).Return(nil)
+
+			// forCSIDriver
+			mockClientsInterface.EXPECT().List(Any(), Any()).
+				DoAndReturn(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+					csiNode := storagev1.CSINode{}
+					for _, driver := range drivers {
+						csiNode.Spec.Drivers = append(csiNode.Spec.Drivers, storagev1.CSINodeDriver{Name: driver})
+					}
+					list.(*storagev1.CSINodeList).Items = []storagev1.CSINode{csiNode}
+					return nil
+				}).AnyTimes()
+
+			Expect(pa.ForResource(context.Background(), prepareUnstructured("CSIDriver", "my-driver.csi.example.com", ""))).To(matcher)
+		},
+		Entry("when a node registered the driver", []string{"other.csi.example.com", "my-driver.csi.example.com"}, Succeed()),
+		Entry("when no node registered the driver", []string{"other.csi.example.com"}, Not(Succeed())),
+	)
+
 	DescribeTable("should work for StatefulSets",
 		func(desiredReplicas, currentReplicas int64, matcher gtypes.GomegaMatcher) {
 			// forResourceAvailability
@@ -217,6 +269,7 @@ var _ = Context("Waiting for resource", func() {
 		},
 		Entry("which have finished", "Complete", Succeed()),
 		Entry("which are still running", "Running", Not(Succeed())),
+		Entry("which have failed", "Failed", Not(Succeed())),
 	)
 
 	DescribeTable("should work for Deployments",
@@ -258,6 +311,22 @@ var _ = Context("Waiting for resource", func() {
 	)
 })
 
+var _ = Context("Cancelling the context", func() {
+	It("should stop waiting", func() {
+		timeout = time.Hour
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		mockClientsInterface.EXPECT().Get(Any(), Any(), Any()).
+			DoAndReturn(func(_ context.Context, _ types.NamespacedName, _ client.Object) error {
+				cancel()
+				return apierrors.NewNotFound(v1.Resource("pods"), "pod-name")
+			})
+
+		Expect(pa.ForResource(ctx, prepareUnstructured("Pod", "pod-name", namespace))).To(MatchError(wait.ErrWaitTimeout))
+	})
+})
+
 var _ = Context("Waiting for Build", func() {
 	It("should fail when resource is not created yet", func() {
 		// forResourceAvailability
@@ -291,6 +360,43 @@ var _ = Context("Waiting for Build", func() {
 
 		Expect(pa.ForResource(context.Background(), prepareUnstructured("BuildConfig", "build-name", namespace))).To(Succeed())
 	})
+	It("resource is created and belongs to my BuildConfig and failed", func() {
+		// forResourceAvailability
+		mockClientsInterface.EXPECT().Get(Any(), Any(), Any()).Return(nil)
+		// forBuild
+		mockClientsInterface.EXPECT().
+			List(Any(), Any(), Any()).
+			DoAndReturn(func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+				build := prepareUnstructured("Build", "build-name-1", namespace)
+				build.SetAnnotations(map[string]string{"openshift.io/build.pod-name": "build-name-1-build"})
+				Expect(unstructured.SetNestedSlice(build.Object, []interface{}{map[string]interface{}{
+					"name": "build-name",
+				}}, "metadata", "ownerReferences")).To(Succeed())
+				u := obj.(*unstructured.UnstructuredList)
+				u.Items = append(u.Items, *build)
+				return nil
+			})
+		// forResourceFullAvailability
+		mockClientsInterface.EXPECT().Get(Any(), Any(), Any()).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+				u := o.(*unstructured.Unstructured)
+				Expect(unstructured.SetNestedField(u.Object, "Failed", "status", "phase")).To(Succeed())
+				Expect(unstructured.SetNestedField(u.Object, "FetchSourceFailed", "status", "reason")).To(Succeed())
+				return nil
+			})
+		// buildLogTail
+		mockClientsInterface.EXPECT().
+			GetPodLogs(namespace, "build-name-1-build", Any()).
+			Return(prepareReq("make: *** [Makefile:2: all] Error 2"))
+
+		err := pa.ForResource(context.Background(), prepareUnstructured("BuildConfig", "build-name", namespace))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Failed (FetchSourceFailed): make: *** [Makefile:2: all] Error 2"))
+
+		var buildErr *BuildFailedError
+		Expect(errors.As(err, &buildErr)).To(BeTrue())
+		Expect(buildErr.Transient()).To(BeTrue())
+	})
 	It("resource is created and does not belong to my BuildConfig", func() {
 		// forResourceAvailability
 		mockClientsInterface.EXPECT().Get(Any(), Any(), Any()).Return(nil)
@@ -434,25 +540,6 @@ LAIJPJUuQI3vpzi1gh4NoZuP9eFZcIGZbPwouqd23`
 	longerLogWithPattern := longerLog + `driver loaded
 NoZuP9eFZcIGZbPwou2d23zZJPaSyFS22PBWlSxHO`
 
-	prepareReq := func(log string) *restclient.Request {
-		roundTripper := func(*http.Request) (*http.Response, error) {
-			header := http.Header{}
-			header.Set("Content-Type", "text/plain")
-
-			body := io.NopCloser(strings.NewReader(log))
-
-			resp := &http.Response{
-				StatusCode: http.StatusOK,
-				Header:     header,
-				Body:       body,
-			}
-			return resp, nil
-		}
-		fakeHTTPClient := fakerestclient.CreateHTTPClient(roundTripper)
-		return restclient.NewRequestWithClient(nil, "",
-			restclient.ClientContentConfig{}, fakeHTTPClient)
-	}
-
 	DescribeTable("searches Pods' log for a pattern",
 		func(log string, shouldBeFound bool) {
 			podName := daemonSetName + "-123456"
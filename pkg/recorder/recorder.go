@@ -0,0 +1,325 @@
+package recorder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/redact"
+	"github.com/openshift-psap/special-resource-operator/pkg/storage"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DumpAnnotation requests the recorded manifests of a SpecialResource to be
+	// written to the <name>-manifests ConfigMap of its namespace. The annotation
+	// is removed once the dump is done.
+	DumpAnnotation = "specialresource.openshift.io/dump-manifests"
+
+	// DumpKey is the key of the manifests in the dump ConfigMap
+	DumpKey = "manifests.yaml"
+
+	// historyLimit is the number of manifests kept per state and version
+	historyLimit = 5
+
+	// sizeLimit is the size of the encoded manifests kept per SpecialResource,
+	// the oldest ones are dropped first. The manifests of all SpecialResources
+	// share one object, limited to 1MiB.
+	sizeLimit = 128 * 1024
+
+	storeName = "special-resource-manifests"
+)
+
+// Record is a manifest applied by the operator.
+type Record struct {
+	AppliedAt time.Time `json:"appliedAt"`
+	Manifest  string    `json:"manifest"`
+}
+
+//go:generate mockgen -source=recorder.go -package=recorder -destination=mock_recorder_api.go
+
+type Recorder interface {
+	Add(specialResource string, obj *unstructured.Unstructured)
+	Dump(ctx context.Context, sr *v1beta1.SpecialResource) error
+	Flush(ctx context.Context, specialResource, state, version string, secrets []string) error
+}
+
+type recorder struct {
+	kubeClient clients.ClientsInterface
+	storage    storage.Storage
+	log        logr.Logger
+	enabled    bool
+
+	mutex   sync.Mutex
+	pending map[string][]*unstructured.Unstructured
+}
+
+// New returns a Recorder keeping the last manifests applied for every state
+// in the storage. A disabled Recorder records nothing, dumps are still served.
+func New(kubeClient clients.ClientsInterface, storage storage.Storage, enabled bool) Recorder {
+	return &recorder{
+		kubeClient: kubeClient,
+		storage:    storage,
+		log:        zap.New(zap.UseDevMode(true)).WithName(utils.Print("recorder", utils.Brown)),
+		enabled:    enabled,
+		pending:    make(map[string][]*unstructured.Unstructured),
+	}
+}
+
+// Add buffers an object applied for the SpecialResource until the next Flush.
+// The data of Secrets is redacted.
+func (r *recorder) Add(specialResource string, obj *unstructured.Unstructured) {
+	if !r.enabled {
+		return
+	}
+
+	obj = obj.DeepCopy()
+	if obj.GetKind() == "Secret" && obj.GetAPIVersion() == "v1" {
+		for _, field := range []string{"data", "stringData"} {
+			data, _, _ := unstructured.NestedMap(obj.Object, field)
+			for key := range data {
+				data[key] = redact.Redacted
+			}
+			if data != nil {
+				_ = unstructured.SetNestedMap(obj.Object, data, field)
+			}
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.pending[specialResource] = append(r.pending[specialResource], obj)
+}
+
+// Flush stores the objects buffered for the SpecialResource as the manifest of
+// the state and version, unless it did not change since the last one. The
+// secrets, the values fetched from secret providers, are redacted from it.
+func (r *recorder) Flush(ctx context.Context, specialResource, state, version string, secrets []string) error {
+	r.mutex.Lock()
+	objs := r.pending[specialResource]
+	delete(r.pending, specialResource)
+	r.mutex.Unlock()
+
+	if len(objs) == 0 {
+		return nil
+	}
+
+	var manifest strings.Builder
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return fmt.Errorf("could not marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		manifest.WriteString("---\n")
+		manifest.WriteString(redact.Literals(string(data), secrets))
+	}
+
+	records, err := r.load(ctx, specialResource)
+	if err != nil {
+		return err
+	}
+
+	key := recordKey(state, version)
+	history := records[key]
+
+	if len(history) > 0 && history[len(history)-1].Manifest == manifest.String() {
+		return nil
+	}
+
+	history = append(history, Record{AppliedAt: time.Now().UTC(), Manifest: manifest.String()})
+	if len(history) > historyLimit {
+		history = history[len(history)-historyLimit:]
+	}
+	records[key] = history
+
+	value, err := encode(records)
+	for err == nil && len(value) > sizeLimit {
+		if !dropOldest(records, key) {
+			return fmt.Errorf("the manifests of %s exceed %d bytes", specialResource, sizeLimit)
+		}
+		value, err = encode(records)
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.storage.UpdateConfigMapEntry(ctx, specialResource, value, storeNamespacedName())
+}
+
+// dropOldest removes the oldest record, but the last one of current. It returns
+// false if there is none.
+func dropOldest(records map[string][]Record, current string) bool {
+	oldest := ""
+	for key, history := range records {
+		if len(history) == 0 || key == current && len(history) == 1 {
+			continue
+		}
+		if oldest == "" || history[0].AppliedAt.Before(records[oldest][0].AppliedAt) {
+			oldest = key
+		}
+	}
+
+	if oldest == "" {
+		return false
+	}
+
+	if records[oldest] = records[oldest][1:]; len(records[oldest]) == 0 {
+		delete(records, oldest)
+	}
+
+	return true
+}
+
+// Dump writes the recorded manifests of the SpecialResource if requested by
+// its DumpAnnotation.
+func (r *recorder) Dump(ctx context.Context, sr *v1beta1.SpecialResource) error {
+	if _, ok := sr.GetAnnotations()[DumpAnnotation]; !ok {
+		return nil
+	}
+
+	records, err := r.load(ctx, sr.Name)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var dump strings.Builder
+	for _, key := range keys {
+		for _, record := range records[key] {
+			fmt.Fprintf(&dump, "# %s applied at %s\n%s", key, record.AppliedAt.Format(time.RFC3339), record.Manifest)
+		}
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sr.Name + "-manifests",
+			Namespace: sr.Spec.Namespace,
+		},
+	}
+
+	_, err = r.kubeClient.CreateOrUpdate(ctx, cm, func() error {
+		cm.SetOwnerReferences([]metav1.OwnerReference{
+			*metav1.NewControllerRef(sr, v1beta1.GroupVersion.WithKind("SpecialResource")),
+		})
+		cm.Data = map[string]string{DumpKey: dump.String()}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not write the manifests of %s: %w", sr.Name, err)
+	}
+
+	r.log.Info("Dumped the recorded manifests", "specialresource", sr.Name, "configmap", cm.Namespace+"/"+cm.Name)
+
+	annotations := sr.GetAnnotations()
+	delete(annotations, DumpAnnotation)
+	sr.SetAnnotations(annotations)
+
+	if err = r.kubeClient.Update(ctx, sr); err != nil {
+		return fmt.Errorf("could not remove the %s annotation: %w", DumpAnnotation, err)
+	}
+
+	return nil
+}
+
+func (r *recorder) load(ctx context.Context, specialResource string) (map[string][]Record, error) {
+	value, err := r.storage.CheckConfigMapEntry(ctx, specialResource, storeNamespacedName())
+	if apierrors.IsNotFound(err) {
+		// The store is not shipped with the operator
+		value, err = "", storage.CreateConfigMap(ctx, r.kubeClient, storeNamespacedName())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the manifests of %s: %w", specialResource, err)
+	}
+
+	return decode(value)
+}
+
+func recordKey(state, version string) string {
+	if version == "" {
+		return state
+	}
+	return state + "@" + version
+}
+
+func storeNamespacedName() types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: os.Getenv("OPERATOR_NAMESPACE"),
+		Name:      storeName,
+	}
+}
+
+// encode compresses the records, entries of a ConfigMap are limited to 1MiB in
+// total.
+func encode(records map[string][]Record) (string, error) {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	if _, err = zw.Write(data); err != nil {
+		return "", err
+	}
+	if err = zw.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func decode(value string) (map[string][]Record, error) {
+	records := make(map[string][]Record)
+
+	if value == "" {
+		return records, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the manifests: %w", err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress the manifests: %w", err)
+	}
+	defer zr.Close()
+
+	data, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress the manifests: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("could not unmarshal the manifests: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,270 @@
+package recorder_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/recorder"
+	"github.com/openshift-psap/special-resource-operator/pkg/storage"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	srName  = "simple-kmod"
+	state   = "templates/0000-buildconfig.yaml"
+	version = "4.18.0-305.19.1.el8_4.x86_64"
+)
+
+var (
+	ctrl        *gomock.Controller
+	mockClient  *clients.MockClientsInterface
+	mockStorage *storage.MockStorage
+)
+
+func TestRecorder(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = clients.NewMockClientsInterface(ctrl)
+		mockStorage = storage.NewMockStorage(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "Recorder Suite")
+}
+
+func newObj() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind("DaemonSet")
+	obj.SetNamespace(srName)
+	obj.SetName(srName + "-driver-container")
+	return obj
+}
+
+// newLargeObj returns an object with an incompressible annotation of size
+// bytes.
+func newLargeObj(size int) *unstructured.Unstructured {
+	data := make([]byte, size*3/4)
+	_, err := rand.Read(data)
+	Expect(err).NotTo(HaveOccurred())
+
+	obj := newObj()
+	obj.SetAnnotations(map[string]string{"data": base64.StdEncoding.EncodeToString(data)})
+	return obj
+}
+
+// dump returns the manifests dumped from the stored value.
+func dump(r recorder.Recorder, stored string) string {
+	sr := &v1beta1.SpecialResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        srName,
+			Annotations: map[string]string{recorder.DumpAnnotation: ""},
+		},
+		Spec: v1beta1.SpecialResourceSpec{Namespace: srName},
+	}
+
+	var manifests string
+
+	mockStorage.EXPECT().CheckConfigMapEntry(gomock.Any(), srName, gomock.Any()).Return(stored, nil)
+	mockClient.EXPECT().
+		CreateOrUpdate(gomock.Any(), gomock.AssignableToTypeOf(&v1.ConfigMap{}), gomock.Any()).
+		DoAndReturn(func(_ context.Context, obj client.Object, fn controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+			Expect(fn()).To(Succeed())
+			manifests = obj.(*v1.ConfigMap).Data[recorder.DumpKey]
+			return controllerutil.OperationResultCreated, nil
+		})
+	mockClient.EXPECT().Update(gomock.Any(), sr)
+
+	Expect(r.Dump(context.Background(), sr)).To(Succeed())
+
+	return manifests
+}
+
+// record flushes a single object and returns the value stored for it.
+func record(r recorder.Recorder, previous string, secrets ...string) string {
+	var stored string
+
+	mockStorage.EXPECT().CheckConfigMapEntry(gomock.Any(), srName, gomock.Any()).Return(previous, nil)
+	mockStorage.EXPECT().
+		UpdateConfigMapEntry(gomock.Any(), srName, gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, _ string, value string, _ types.NamespacedName) {
+			stored = value
+		})
+
+	obj := newObj()
+	obj.SetAnnotations(map[string]string{"license": "s3cr3t"})
+
+	r.Add(srName, obj)
+	Expect(r.Flush(context.Background(), srName, state, version, secrets)).To(Succeed())
+
+	return stored
+}
+
+var _ = Describe("Flush", func() {
+	It("should not store anything without objects", func() {
+		r := recorder.New(mockClient, mockStorage, true)
+
+		Expect(r.Flush(context.Background(), srName, state, version, nil)).To(Succeed())
+	})
+
+	It("should not record anything when disabled", func() {
+		r := recorder.New(mockClient, mockStorage, false)
+
+		r.Add(srName, newObj())
+		Expect(r.Flush(context.Background(), srName, state, version, nil)).To(Succeed())
+	})
+
+	It("should only store manifests that changed", func() {
+		r := recorder.New(mockClient, mockStorage, true)
+
+		stored := record(r, "")
+		Expect(stored).NotTo(BeEmpty())
+
+		mockStorage.EXPECT().CheckConfigMapEntry(gomock.Any(), srName, gomock.Any()).Return(stored, nil)
+
+		obj := newObj()
+		obj.SetAnnotations(map[string]string{"license": "s3cr3t"})
+
+		r.Add(srName, obj)
+		Expect(r.Flush(context.Background(), srName, state, version, nil)).To(Succeed())
+	})
+
+	It("should create the store if it does not exist", func() {
+		r := recorder.New(mockClient, mockStorage, true)
+
+		gomock.InOrder(
+			mockStorage.EXPECT().
+				CheckConfigMapEntry(gomock.Any(), srName, gomock.Any()).
+				Return("", k8serrors.NewNotFound(v1.Resource("configmap"), "special-resource-manifests")),
+			mockClient.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&v1.ConfigMap{})),
+			mockStorage.EXPECT().UpdateConfigMapEntry(gomock.Any(), srName, gomock.Any(), gomock.Any()),
+		)
+
+		r.Add(srName, newObj())
+		Expect(r.Flush(context.Background(), srName, state, version, nil)).To(Succeed())
+	})
+
+	It("should redact the data of Secrets", func() {
+		r := recorder.New(mockClient, mockStorage, true)
+
+		secret := &unstructured.Unstructured{}
+		secret.SetAPIVersion("v1")
+		secret.SetKind("Secret")
+		secret.SetName("pull-secret")
+		Expect(unstructured.SetNestedStringMap(secret.Object, map[string]string{"token": "dG9rZW4="}, "data")).To(Succeed())
+		Expect(unstructured.SetNestedStringMap(secret.Object, map[string]string{"password": "p4ssw0rd"}, "stringData")).To(Succeed())
+
+		r.Add(srName, secret)
+		stored := record(r, "")
+
+		Expect(dump(r, stored)).To(And(
+			ContainSubstring("token: REDACTED"),
+			ContainSubstring("password: REDACTED"),
+			Not(ContainSubstring("p4ssw0rd")),
+			Not(ContainSubstring("dG9rZW4=")),
+		))
+	})
+
+	It("should drop the oldest manifests above the size limit", func() {
+		r := recorder.New(mockClient, mockStorage, true)
+
+		var stored string
+
+		mockStorage.EXPECT().
+			UpdateConfigMapEntry(gomock.Any(), srName, gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, _ string, value string, _ types.NamespacedName) {
+				stored = value
+			}).
+			Times(2)
+
+		flush := func(previous string, state string) (string, error) {
+			mockStorage.EXPECT().CheckConfigMapEntry(gomock.Any(), srName, gomock.Any()).Return(previous, nil)
+
+			r.Add(srName, newLargeObj(80*1024))
+			err := r.Flush(context.Background(), srName, state, version, nil)
+
+			return stored, err
+		}
+
+		previous, err := flush("", "templates/0000-first.yaml")
+		Expect(err).NotTo(HaveOccurred())
+
+		stored, err = flush(previous, "templates/0001-second.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(stored)).To(BeNumerically("<=", 128*1024))
+
+		manifests := dump(r, stored)
+		Expect(manifests).NotTo(ContainSubstring("templates/0000-first.yaml"))
+		Expect(manifests).To(ContainSubstring("templates/0001-second.yaml"))
+	})
+
+	It("should fail if a single manifest is above the size limit", func() {
+		r := recorder.New(mockClient, mockStorage, true)
+
+		mockStorage.EXPECT().CheckConfigMapEntry(gomock.Any(), srName, gomock.Any()).Return("", nil)
+
+		r.Add(srName, newLargeObj(256*1024))
+		Expect(r.Flush(context.Background(), srName, state, version, nil)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Dump", func() {
+	It("should do nothing without the annotation", func() {
+		r := recorder.New(mockClient, mockStorage, true)
+
+		Expect(r.Dump(context.Background(), &v1beta1.SpecialResource{})).To(Succeed())
+	})
+
+	It("should write the manifests and remove the annotation", func() {
+		r := recorder.New(mockClient, mockStorage, true)
+		stored := record(r, "", "s3cr3t")
+
+		sr := &v1beta1.SpecialResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        srName,
+				Annotations: map[string]string{recorder.DumpAnnotation: ""},
+			},
+			Spec: v1beta1.SpecialResourceSpec{Namespace: srName},
+		}
+
+		var dump string
+
+		gomock.InOrder(
+			mockStorage.EXPECT().CheckConfigMapEntry(gomock.Any(), srName, gomock.Any()).Return(stored, nil),
+			mockClient.EXPECT().
+				CreateOrUpdate(gomock.Any(), gomock.AssignableToTypeOf(&v1.ConfigMap{}), gomock.Any()).
+				DoAndReturn(func(_ context.Context, obj client.Object, fn controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+					Expect(fn()).To(Succeed())
+					Expect(obj.GetName()).To(Equal(srName + "-manifests"))
+					dump = obj.(*v1.ConfigMap).Data[recorder.DumpKey]
+					return controllerutil.OperationResultCreated, nil
+				}),
+			mockClient.EXPECT().Update(gomock.Any(), sr),
+		)
+
+		Expect(r.Dump(context.Background(), sr)).To(Succeed())
+
+		Expect(dump).To(HavePrefix("# " + state + "@" + version + " applied at "))
+		Expect(dump).To(ContainSubstring("name: simple-kmod-driver-container"))
+		Expect(dump).To(ContainSubstring("license: REDACTED"))
+		Expect(sr.GetAnnotations()).NotTo(HaveKey(recorder.DumpAnnotation))
+	})
+})
@@ -0,0 +1,182 @@
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Redacted replaces the secrets in the logs.
+const Redacted = "REDACTED"
+
+type rule struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// builtins are the secrets the registry, the API clients and the chart
+// repositories may put in their errors: the credentials of the pull secrets,
+// as JSON or YAML,
+// authorization headers, the user info of URLs and the query parameters of
+// signed URLs.
+var builtins = []rule{
+	{
+		re:   regexp.MustCompile(`(?i)("(?:auth|password|identitytoken|registrytoken)"\s*:\s*")[^"]*`),
+		repl: "${1}" + Redacted,
+	},
+	{
+		re:   regexp.MustCompile(`(?im)^(\s*(?:-\s+)?["']?(?:auth|password|identitytoken|registrytoken)["']?\s*:[ \t]+)[^\s{\[|>].*$`),
+		repl: "${1}" + Redacted,
+	},
+	{
+		re:   regexp.MustCompile(`(?i)(authorization["']?\s*[:=]\s*["']?(?:bearer|basic)\s+)[^\s"',]+`),
+		repl: "${1}" + Redacted,
+	},
+	{
+		re:   regexp.MustCompile(`(?i)\b(bearer\s+)[a-z0-9._~+/-]+=*`),
+		repl: "${1}" + Redacted,
+	},
+	{
+		re:   regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`),
+		repl: "${1}" + Redacted + "@",
+	},
+	{
+		re:   regexp.MustCompile(`(?i)([?&](?:x-amz-signature|x-amz-credential|x-amz-security-token|x-goog-signature|x-goog-credential|sig|signature|token|access_token)=)[^&\s"']+`),
+		repl: "${1}" + Redacted,
+	},
+}
+
+var (
+	mu      sync.RWMutex
+	enabled = true
+	rules   = builtins
+)
+
+// Configure enables or disables the redaction, and adds patterns, a comma
+// separated list of regular expressions, to the secrets redacted. The whole
+// match of such a pattern is replaced.
+func Configure(enable bool, patterns string) error {
+	configured := append([]rule{}, builtins...)
+
+	for _, p := range strings.Split(patterns, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+
+		configured = append(configured, rule{re: re, repl: Redacted})
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	enabled = enable
+	rules = configured
+
+	return nil
+}
+
+// String returns s with its secrets redacted.
+func String(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if !enabled {
+		return s
+	}
+
+	for _, r := range rules {
+		s = r.re.ReplaceAllString(s, r.repl)
+	}
+
+	return s
+}
+
+// Literals returns s with every occurrence of the non-empty literals redacted,
+// e.g. the values fetched from secret providers, which have no known shape.
+func Literals(s string, literals []string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if !enabled {
+		return s
+	}
+
+	for _, l := range literals {
+		if l != "" {
+			s = strings.ReplaceAll(s, l, Redacted)
+		}
+	}
+
+	return s
+}
+
+type logger struct {
+	logr.Logger
+}
+
+// Logger returns a Logger redacting the secrets of the messages, errors and
+// values before they are passed to l.
+func Logger(l logr.Logger) logr.Logger {
+	return &logger{Logger: logr.WithCallDepth(l, 1)}
+}
+
+func (l *logger) Info(msg string, keysAndValues ...interface{}) {
+	l.Logger.Info(String(msg), values(keysAndValues)...)
+}
+
+func (l *logger) Error(err error, msg string, keysAndValues ...interface{}) {
+	if err != nil {
+		err = errors.New(String(err.Error()))
+	}
+
+	l.Logger.Error(err, String(msg), values(keysAndValues)...)
+}
+
+func (l *logger) V(level int) logr.Logger {
+	return &logger{Logger: l.Logger.V(level)}
+}
+
+func (l *logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return &logger{Logger: l.Logger.WithValues(values(keysAndValues)...)}
+}
+
+func (l *logger) WithName(name string) logr.Logger {
+	return &logger{Logger: l.Logger.WithName(name)}
+}
+
+// values redacts the values that are or print as strings, the keys are left
+// as is.
+func values(keysAndValues []interface{}) []interface{} {
+	redacted := make([]interface{}, len(keysAndValues))
+
+	for i, v := range keysAndValues {
+		if i%2 == 0 {
+			redacted[i] = v
+			continue
+		}
+
+		switch v := v.(type) {
+		case string:
+			redacted[i] = String(v)
+		case []byte:
+			redacted[i] = String(string(v))
+		case error:
+			redacted[i] = String(v.Error())
+		case fmt.Stringer:
+			redacted[i] = String(v.String())
+		default:
+			redacted[i] = v
+		}
+	}
+
+	return redacted
+}
@@ -35,6 +35,21 @@ func (m *MockRegistry) EXPECT() *MockRegistryMockRecorder {
 	return m.recorder
 }
 
+// Digest mocks base method.
+func (m *MockRegistry) Digest(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Digest", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Digest indicates an expected call of Digest.
+func (mr *MockRegistryMockRecorder) Digest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Digest", reflect.TypeOf((*MockRegistry)(nil).Digest), arg0, arg1)
+}
+
 // ExtractToolkitRelease mocks base method.
 func (m *MockRegistry) ExtractToolkitRelease(arg0 v1.Layer) (DriverToolkitEntry, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +80,20 @@ func (mr *MockRegistryMockRecorder) LastLayer(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastLayer", reflect.TypeOf((*MockRegistry)(nil).LastLayer), arg0, arg1)
 }
 
+// Reachable mocks base method.
+func (m *MockRegistry) Reachable() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reachable")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reachable indicates an expected call of Reachable.
+func (mr *MockRegistryMockRecorder) Reachable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reachable", reflect.TypeOf((*MockRegistry)(nil).Reachable))
+}
+
 // ReleaseManifests mocks base method.
 func (m *MockRegistry) ReleaseManifests(arg0 v1.Layer) (string, string, error) {
 	m.ctrl.T.Helper()
@@ -2,28 +2,50 @@ package registry
 
 import (
 	"archive/tar"
+	"bufio"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/klauspost/compress/zstd"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/dtk"
+	"github.com/openshift-psap/special-resource-operator/pkg/redact"
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
+	"github.com/openshift-psap/special-resource-operator/pkg/tracing"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
 const (
+	// maxIdleConnsPerHost is the number of connections to a registry kept
+	// open between lookups, the lookups of several kernels run concurrently.
+	maxIdleConnsPerHost = 10
+
 	pullSecretNamespace = "openshift-config"
 	pullSecretName      = "pull-secret"
 	pullSecretFileName  = ".dockerconfigjson"
@@ -39,21 +61,99 @@ type DriverToolkitEntry struct {
 //go:generate mockgen -source=registry.go -package=registry -destination=mock_registry_api.go
 
 type Registry interface {
+	Digest(context.Context, string) (string, error)
 	LastLayer(context.Context, string) (v1.Layer, error)
 	ExtractToolkitRelease(v1.Layer) (DriverToolkitEntry, error)
 	ReleaseManifests(v1.Layer) (string, string, error)
+	Reachable() error
 }
 
-func NewRegistry(kubeClient clients.ClientsInterface) Registry {
+// NewRegistry returns a Registry issuing at most qps requests per second, with
+// bursts of burst requests. A qps of 0 disables the limit.
+func NewRegistry(kubeClient clients.ClientsInterface, qps float64, burst int) Registry {
 	return &registry{
 		kubeClient: kubeClient,
-		log:        zap.New(zap.UseDevMode(true)).WithName(utils.Print("registry", utils.Brown)),
+		log:        redact.Logger(zap.New(zap.UseDevMode(true)).WithName(utils.Print("registry", utils.Brown))),
+		limiter:    newLimiter(qps, burst),
+		transport:  newTransport(),
+	}
+}
+
+// newTransport returns the transport shared by the lookups, pooling the
+// connections to the registries instead of opening new ones for the manifest
+// and the layers of every image.
+func newTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = maxIdleConnsPerHost
+
+	return t
+}
+
+func newLimiter(qps float64, burst int) *rate.Limiter {
+	if qps <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
 	}
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(qps), burst)
 }
 
 type registry struct {
 	kubeClient clients.ClientsInterface
 	log        logr.Logger
+	limiter    *rate.Limiter
+	transport  http.RoundTripper
+
+	// lookups deduplicates the concurrent lookups of the same image
+	lookups singleflight.Group
+
+	// reachability is the outcome of the last request to a registry
+	reachability atomic.Value
+}
+
+type reachability struct {
+	err error
+}
+
+// Reachable returns the error of the last image lookup, nil if it reached the
+// registry or no lookup was made yet.
+func (r *registry) Reachable() error {
+	last, ok := r.reachability.Load().(reachability)
+	if !ok {
+		return nil
+	}
+	return last.err
+}
+
+// observe records the outcome of a request to a registry. A missing manifest
+// or tag is the answer of a reachable registry, e.g. to a CR with a wrong
+// image, it does not make the operator unready.
+func (r *registry) observe(err error) {
+	if missingImage(err) {
+		err = nil
+	}
+	r.reachability.Store(reachability{err: err})
+}
+
+// missingImage returns true if err is a 404 of a registry for a repository,
+// manifest or tag it does not have. The responses to HEAD requests have no
+// body, and thus no error codes.
+func missingImage(err error) bool {
+	var terr *transport.Error
+	if !errors.As(err, &terr) || terr.StatusCode != http.StatusNotFound {
+		return false
+	}
+
+	for _, diagnostic := range terr.Errors {
+		switch diagnostic.Code {
+		case transport.ManifestUnknownErrorCode, transport.NameUnknownErrorCode:
+		default:
+			return false
+		}
+	}
+
+	return true
 }
 
 type dockerAuth struct {
@@ -103,7 +203,61 @@ func (r *registry) getImageRegistryCredentials(ctx context.Context, registry str
 	}
 }
 
+// craneOptions returns the options of the requests to a registry: the pooled
+// transport, and auth if it has credentials.
+func (r *registry) craneOptions(auth dockerAuth) []crane.Option {
+	opts := []crane.Option{crane.WithTransport(r.transport)}
+	if auth.Auth != "" {
+		opts = append(opts, crane.WithAuth(authn.FromConfig(authn.AuthConfig{Username: auth.Email, Auth: auth.Auth})))
+	}
+
+	return opts
+}
+
+// Digest returns image pinned to the digest its tag currently points to, e.g.
+// quay.io/org/repo@sha256:... for quay.io/org/repo:tag. The pull secret of the
+// cluster is used if it has credentials for the registry.
+func (r *registry) Digest(ctx context.Context, image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("invalid image %s: %w", image, err)
+	}
+
+	auth, _ := r.getImageRegistryCredentials(ctx, ref.Context().RegistryStr())
+	opts := r.craneOptions(auth)
+
+	if err = r.limiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("registry lookup of %s: %w", image, err)
+	}
+
+	_, span := tracing.Start(ctx, "registry.lookup", "image", image)
+	digest, err := crane.Digest(image, opts...)
+	span.End(err)
+	r.observe(err)
+	if err != nil {
+		return "", sroerrors.Wrap(sroerrors.Registry, err)
+	}
+
+	return ref.Context().Name() + "@" + digest, nil
+}
+
+// LastLayer returns the last layer of the image entry. Concurrent calls for the
+// same image share a single lookup.
 func (r *registry) LastLayer(ctx context.Context, entry string) (v1.Layer, error) {
+	layer, err, shared := r.lookups.Do(entry, func() (interface{}, error) {
+		return r.lastLayer(ctx, entry)
+	})
+	if shared {
+		r.log.Info("Shared registry lookup", "image", entry)
+	}
+	if err != nil {
+		return nil, sroerrors.Wrap(sroerrors.Registry, err)
+	}
+
+	return layer.(v1.Layer), nil
+}
+
+func (r *registry) lastLayer(ctx context.Context, entry string) (v1.Layer, error) {
 	registry, err := r.registryFromImageURL(entry)
 	if err != nil {
 		return nil, err
@@ -123,12 +277,15 @@ func (r *registry) LastLayer(ctx context.Context, entry string) (v1.Layer, error
 		repo = tag[0]
 	}
 
-	var registryAuths []crane.Option
-	if auth.Auth != "" {
-		registryAuths = append(registryAuths, crane.WithAuth(authn.FromConfig(authn.AuthConfig{Username: auth.Email, Auth: auth.Auth})))
+	opts := r.craneOptions(auth)
+
+	if err = r.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("registry lookup of %s: %w", entry, err)
 	}
 
-	manifest, err := crane.Manifest(entry, registryAuths...)
+	_, span := tracing.Start(ctx, "registry.lookup", "image", entry)
+	manifest, err := crane.Manifest(entry, opts...)
+	span.End(err)
 	if err != nil {
 		return nil, err
 	}
@@ -143,92 +300,61 @@ func (r *registry) LastLayer(ctx context.Context, entry string) (v1.Layer, error
 		return nil, err
 	}
 
+	// An image index lists manifests instead of layers
+	if len(layers) == 0 {
+		mediaType, _, _ := unstructured.NestedString(release.Object, "mediaType")
+		return nil, fmt.Errorf("image %s has no layers, unsupported manifest %q", entry, mediaType)
+	}
+
 	last := layers[len(layers)-1]
 
 	digest := last.(map[string]interface{})["digest"].(string)
 
-	return crane.PullLayer(repo+"@"+digest, registryAuths...)
+	if err = r.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("registry lookup of %s: %w", entry, err)
+	}
+
+	return crane.PullLayer(repo+"@"+digest, opts...)
 }
 
+// ExtractToolkitRelease reads the metadata of the Driver Toolkit from its last
+// layer. The errors are the ones of the dtk package, dtk.ErrNotDriverToolkit
+// for a layer that has no metadata and a *dtk.CorruptError for metadata that
+// cannot be read.
 func (r *registry) ExtractToolkitRelease(layer v1.Layer) (DriverToolkitEntry, error) {
-	var dtk DriverToolkitEntry
-
-	targz, err := layer.Compressed()
+	content, err := r.layerContent(layer)
 	if err != nil {
-		return dtk, err
+		return DriverToolkitEntry{}, err
 	}
-	defer r.dclose(targz)
+	defer r.dclose(content)
 
-	gr, err := gzip.NewReader(targz)
+	rel, err := dtk.Read(content)
 	if err != nil {
-		return dtk, err
+		return DriverToolkitEntry{}, err
 	}
-	defer r.dclose(gr)
-
-	tr := tar.NewReader(gr)
-
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
-		}
-
-		if header.Name == "etc/driver-toolkit-release.json" {
-			buff, err := io.ReadAll(tr)
-			if err != nil {
-				return dtk, err
-			}
-
-			obj := unstructured.Unstructured{}
-
-			if err = json.Unmarshal(buff, &obj.Object); err != nil {
-				return dtk, err
-			}
-
-			entry, _, err := unstructured.NestedString(obj.Object, "KERNEL_VERSION")
-			if err != nil {
-				return dtk, err
-			}
-			r.log.Info("DTK", "kernel-version", entry)
-			dtk.KernelFullVersion = entry
-
-			entry, _, err = unstructured.NestedString(obj.Object, "RT_KERNEL_VERSION")
-			if err != nil {
-				return dtk, err
-			}
-			r.log.Info("DTK", "rt-kernel-version", entry)
-			dtk.RTKernelFullVersion = entry
-
-			entry, _, err = unstructured.NestedString(obj.Object, "RHEL_VERSION")
-			if err != nil {
-				return dtk, err
-			}
-			r.log.Info("DTK", "rhel-version", entry)
-			dtk.OSVersion = entry
-
-			return dtk, err
-		}
 
-	}
-
-	return dtk, errors.New("Missing driver toolkit entry: /etc/driver-toolkit-release.json")
+	r.log.Info("DTK",
+		"schema-version", rel.SchemaVersion,
+		"kernel-version", rel.KernelFullVersion,
+		"rt-kernel-version", rel.RTKernelFullVersion,
+		"rhel-version", rel.OSVersion)
+
+	return DriverToolkitEntry{
+		KernelFullVersion:   rel.KernelFullVersion,
+		RTKernelFullVersion: rel.RTKernelFullVersion,
+		OSVersion:           rel.OSVersion,
+	}, nil
 }
 
 func (r *registry) ReleaseManifests(layer v1.Layer) (string, string, error) {
 
-	targz, err := layer.Compressed()
+	content, err := r.layerContent(layer)
 	if err != nil {
 		return "", "", err
 	}
-	defer r.dclose(targz)
+	defer r.dclose(content)
 
-	gr, err := gzip.NewReader(targz)
-	if err != nil {
-		return "", "", err
-	}
-	defer r.dclose(gr)
-
-	tr := tar.NewReader(gr)
+	tr := tar.NewReader(content)
 
 	version := ""
 	imageURL := ""
@@ -298,6 +424,62 @@ func (r *registry) ReleaseManifests(layer v1.Layer) (string, string, error) {
 	return version, imageURL, nil
 }
 
+// layerContent returns the tar archive of layer, compressed with gzip, with
+// zstd or not at all. The compression is detected from the content rather than
+// from the Docker or OCI media type of the layer, which the registry may not
+// report.
+func (r *registry) layerContent(layer v1.Layer) (io.ReadCloser, error) {
+	blob, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(blob)
+
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.dclose(blob)
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			r.dclose(blob)
+			return nil, err
+		}
+		return &layerReader{Reader: gr, closers: []io.Closer{gr, blob}}, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			r.dclose(blob)
+			return nil, err
+		}
+		rc := zr.IOReadCloser()
+		return &layerReader{Reader: rc, closers: []io.Closer{rc, blob}}, nil
+	default:
+		return &layerReader{Reader: br, closers: []io.Closer{blob}}, nil
+	}
+}
+
+// layerReader reads a decompressed layer, closing it closes the decompressor
+// and the blob.
+type layerReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (l *layerReader) Close() error {
+	var first error
+	for _, c := range l.closers {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
 func (r *registry) dclose(c io.Closer) {
 	if err := c.Close(); err != nil {
 		utils.WarnOnError(err)
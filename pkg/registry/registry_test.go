@@ -1,16 +1,31 @@
 package registry
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/crane"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/klauspost/compress/zstd"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
-	v1 "k8s.io/api/core/v1"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
 )
@@ -20,6 +35,18 @@ func TestRegistry(t *testing.T) {
 	RunSpecs(t, "Registry Suite")
 }
 
+var _ = Describe("newLimiter", func() {
+	It("should not limit without QPS", func() {
+		Expect(newLimiter(0, 5).Limit()).To(Equal(rate.Inf))
+	})
+
+	It("should allow at least one request per burst", func() {
+		l := newLimiter(0.5, 0)
+		Expect(l.Limit()).To(Equal(rate.Limit(0.5)))
+		Expect(l.Burst()).To(Equal(1))
+	})
+})
+
 var _ = Describe("registryFromImageURL", func() {
 	DescribeTable("should parse URLs as expected",
 		func(image, expectedHost string) {
@@ -53,11 +80,11 @@ var _ = Describe("getImageRegistryCredentials", func() {
 	BeforeEach(func() {
 		ctrl := gomock.NewController(GinkgoT())
 		kubeClient = clients.NewMockClientsInterface(ctrl)
-		r = NewRegistry(kubeClient)
+		r = NewRegistry(kubeClient, 0, 0)
 	})
 
 	DescribeTable("should fail in following scenarios",
-		func(secret *v1.Secret, getError error, url string, expectedErrorStr string) {
+		func(secret *corev1.Secret, getError error, url string, expectedErrorStr string) {
 			kubeClient.EXPECT().
 				GetSecret(context.Background(), expectedNamespace, expectedName, gomock.Any()).
 				Return(secret, getError)
@@ -70,15 +97,15 @@ var _ = Describe("getImageRegistryCredentials", func() {
 			nil, errors.New(""), url,
 			"could not retrieve pull secrets"),
 		Entry("pull-secret has no data",
-			&v1.Secret{}, nil, url,
+			&corev1.Secret{}, nil, url,
 			"could not find data"),
 		Entry("pull-secret doesn't have an entry for requested host",
-			&v1.Secret{Data: map[string][]byte{expectedFile: []byte(config)}}, nil, "other-registry.io",
+			&corev1.Secret{Data: map[string][]byte{expectedFile: []byte(config)}}, nil, "other-registry.io",
 			"does not contain auth for registry"),
 	)
 
 	It("will work for expected scenario", func() {
-		pullSecret := &v1.Secret{
+		pullSecret := &corev1.Secret{
 			Data: map[string][]byte{
 				expectedFile: []byte(config),
 			},
@@ -92,3 +119,121 @@ var _ = Describe("getImageRegistryCredentials", func() {
 		Expect(da).To(Equal(dockerAuth{Auth: auth, Email: email}))
 	})
 })
+
+var _ = Describe("Digest", func() {
+	It("should reuse the connections to the registry", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := clients.NewMockClientsInterface(ctrl)
+		kubeClient.EXPECT().GetSecret(context.Background(), "openshift-config", "pull-secret", gomock.Any()).
+			Return(nil, errors.New("no pull secret")).
+			Times(3)
+
+		var conns int32
+
+		server := httptest.NewUnstartedServer(ggcrregistry.New())
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddInt32(&conns, 1)
+			}
+		}
+		server.StartTLS()
+		defer server.Close()
+
+		serverTransport := server.Client().Transport.(*http.Transport)
+
+		image := strings.TrimPrefix(server.URL, "https://") + "/org/repo:tag"
+		Expect(crane.Push(empty.Image, image, crane.WithTransport(serverTransport))).To(Succeed())
+		atomic.StoreInt32(&conns, 0)
+
+		r := NewRegistry(kubeClient, 0, 0).(*registry)
+		r.transport.(*http.Transport).TLSClientConfig = serverTransport.TLSClientConfig
+
+		for i := 0; i < 3; i++ {
+			_, err := r.Digest(context.Background(), image)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(atomic.LoadInt32(&conns)).To(BeEquivalentTo(1))
+	})
+
+	It("should only be unreachable when the registry does not answer", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := clients.NewMockClientsInterface(ctrl)
+		kubeClient.EXPECT().GetSecret(context.Background(), "openshift-config", "pull-secret", gomock.Any()).
+			Return(nil, errors.New("no pull secret")).
+			AnyTimes()
+
+		server := httptest.NewServer(ggcrregistry.New())
+		host := strings.TrimPrefix(server.URL, "http://")
+
+		r := NewRegistry(kubeClient, 0, 0).(*registry)
+
+		_, err := r.Digest(context.Background(), host+"/org/missing:tag")
+		Expect(err).To(HaveOccurred())
+		Expect(r.Reachable()).NotTo(HaveOccurred())
+
+		server.Close()
+
+		_, err = r.Digest(context.Background(), host+"/org/missing:tag")
+		Expect(err).To(HaveOccurred())
+		Expect(r.Reachable()).To(HaveOccurred())
+	})
+})
+
+// blobLayer is a layer whose compressed content is blob.
+type blobLayer struct {
+	v1.Layer
+	blob []byte
+}
+
+func (l *blobLayer) Compressed() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(l.blob)), nil
+}
+
+var _ = Describe("ExtractToolkitRelease", func() {
+	release := func() []byte {
+		content := `{"KERNEL_VERSION": "4.18.0-305.el8.x86_64", "RT_KERNEL_VERSION": "4.18.0-305.rt7.72.el8.x86_64", "RHEL_VERSION": "8.4"}`
+
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		Expect(tw.WriteHeader(&tar.Header{Name: "etc/driver-toolkit-release.json", Mode: 0644, Size: int64(len(content))})).To(Succeed())
+		_, err := tw.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tw.Close()).To(Succeed())
+
+		return buf.Bytes()
+	}
+
+	DescribeTable("should read the release of the toolkit",
+		func(compress func([]byte) []byte) {
+			dtk, err := NewRegistry(nil, 0, 0).ExtractToolkitRelease(&blobLayer{blob: compress(release())})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dtk).To(Equal(DriverToolkitEntry{
+				KernelFullVersion:   "4.18.0-305.el8.x86_64",
+				RTKernelFullVersion: "4.18.0-305.rt7.72.el8.x86_64",
+				OSVersion:           "8.4",
+			}))
+		},
+		Entry("gzip", func(b []byte) []byte {
+			var buf bytes.Buffer
+			gw := gzip.NewWriter(&buf)
+			_, err := gw.Write(b)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gw.Close()).To(Succeed())
+			return buf.Bytes()
+		}),
+		Entry("zstd", func(b []byte) []byte {
+			zw, err := zstd.NewWriter(nil)
+			Expect(err).NotTo(HaveOccurred())
+			return zw.EncodeAll(b, nil)
+		}),
+		Entry("uncompressed", func(b []byte) []byte {
+			return b
+		}),
+	)
+
+	It("should fail on a layer that is not an archive", func() {
+		_, err := NewRegistry(nil, 0, 0).ExtractToolkitRelease(&blobLayer{blob: []byte("not a tar archive at all")})
+		Expect(err).To(HaveOccurred())
+	})
+})
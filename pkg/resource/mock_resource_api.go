@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	v1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,6 +36,20 @@ func (m *MockCreator) EXPECT() *MockCreatorMockRecorder {
 	return m.recorder
 }
 
+// AppliedObjects mocks base method.
+func (m *MockCreator) AppliedObjects(specialResource string) []v1beta1.ObjectReference {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppliedObjects", specialResource)
+	ret0, _ := ret[0].([]v1beta1.ObjectReference)
+	return ret0
+}
+
+// AppliedObjects indicates an expected call of AppliedObjects.
+func (mr *MockCreatorMockRecorder) AppliedObjects(specialResource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppliedObjects", reflect.TypeOf((*MockCreator)(nil).AppliedObjects), specialResource)
+}
+
 // CreateFromYAML mocks base method.
 func (m *MockCreator) CreateFromYAML(arg0 context.Context, arg1 []byte, arg2 bool, arg3 v1.Object, arg4, arg5 string, arg6 map[string]string, arg7, arg8 string) error {
 	m.ctrl.T.Helper()
@@ -48,3 +63,17 @@ func (mr *MockCreatorMockRecorder) CreateFromYAML(arg0, arg1, arg2, arg3, arg4,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFromYAML", reflect.TypeOf((*MockCreator)(nil).CreateFromYAML), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 }
+
+// SkippedObjects mocks base method.
+func (m *MockCreator) SkippedObjects(specialResource string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SkippedObjects", specialResource)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SkippedObjects indicates an expected call of SkippedObjects.
+func (mr *MockCreatorMockRecorder) SkippedObjects(specialResource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SkippedObjects", reflect.TypeOf((*MockCreator)(nil).SkippedObjects), specialResource)
+}
@@ -0,0 +1,127 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// monitoringGroup is the API group of the Prometheus Operator objects,
+	// e.g. ServiceMonitors and PrometheusRules.
+	monitoringGroup = "monitoring.coreos.com"
+
+	// clusterMonitoringLabel set to true on a namespace makes the platform
+	// monitoring of OpenShift scrape it.
+	clusterMonitoringLabel = "openshift.io/cluster-monitoring"
+	openshiftMonitoring    = "openshift-monitoring"
+)
+
+// clusterMonitoringConfig is the config of the OpenShift monitoring stack, it
+// enables the monitoring of user-defined projects.
+var clusterMonitoringConfig = types.NamespacedName{Namespace: openshiftMonitoring, Name: "cluster-monitoring-config"}
+
+// monitoringUnavailable returns why the cluster would not act on obj, a
+// Prometheus Operator object, or an empty string if it would or obj is not
+// one. On OpenShift the objects are only picked up in the namespaces of the
+// platform monitoring, or with the monitoring of user-defined projects.
+func (c *creator) monitoringUnavailable(ctx context.Context, obj *unstructured.Unstructured) (string, error) {
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != monitoringGroup {
+		return "", nil
+	}
+
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+
+	served, err := c.kubeClient.HasResource(resource)
+	if err != nil {
+		return "", fmt.Errorf("could not look up %s: %w", resource.Resource, err)
+	}
+	if !served {
+		return "the Prometheus Operator CRDs are not installed", nil
+	}
+
+	// Any other Prometheus Operator watches all the namespaces
+	if _, err = c.kubeClient.GetNamespace(ctx, openshiftMonitoring, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("could not get namespace %s: %w", openshiftMonitoring, err)
+	}
+
+	ns, err := c.kubeClient.GetNamespace(ctx, obj.GetNamespace(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("could not get namespace %s: %w", obj.GetNamespace(), err)
+	}
+	if err == nil && ns.GetLabels()[clusterMonitoringLabel] == "true" {
+		return "", nil
+	}
+
+	enabled, err := c.userWorkloadMonitoring(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !enabled {
+		return "the monitoring of user-defined projects is not enabled", nil
+	}
+
+	return "", nil
+}
+
+// userWorkloadMonitoring returns whether the monitoring of user-defined
+// projects is enabled in the OpenShift monitoring config, it is not without one.
+func (c *creator) userWorkloadMonitoring(ctx context.Context) (bool, error) {
+	cm := &corev1.ConfigMap{}
+
+	err := c.kubeClient.Get(ctx, clusterMonitoringConfig, cm)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("could not get the monitoring config: %w", err)
+	}
+
+	config := struct {
+		EnableUserWorkload bool `json:"enableUserWorkload"`
+	}{}
+
+	if err = yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &config); err != nil {
+		return false, fmt.Errorf("invalid monitoring config: %w", err)
+	}
+
+	return config.EnableUserWorkload, nil
+}
+
+// SkippedObjects returns the objects skipped for the SpecialResource since the
+// last call, with the reason they were skipped.
+func (c *creator) SkippedObjects(specialResource string) []string {
+	c.skippedMutex.Lock()
+	defer c.skippedMutex.Unlock()
+
+	skipped := make([]string, 0, len(c.skipped[specialResource]))
+	for note := range c.skipped[specialResource] {
+		skipped = append(skipped, note)
+	}
+	delete(c.skipped, specialResource)
+
+	sort.Strings(skipped)
+
+	return skipped
+}
+
+// addSkipped buffers obj until the next SkippedObjects.
+func (c *creator) addSkipped(specialResource string, obj *unstructured.Unstructured, reason string) {
+	c.skippedMutex.Lock()
+	defer c.skippedMutex.Unlock()
+
+	if c.skipped[specialResource] == nil {
+		c.skipped[specialResource] = make(map[string]bool)
+	}
+	c.skipped[specialResource][objectRef(obj)+": "+reason] = true
+}
@@ -0,0 +1,119 @@
+package resource
+
+import (
+	"context"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
+)
+
+// PolicyDeniedError lists the objects of a state that the admission policies
+// of the cluster, e.g. Gatekeeper constraints, deny in a server-side dry-run.
+type PolicyDeniedError struct {
+	Denials []string
+}
+
+func (e *PolicyDeniedError) Error() string {
+	return "denied by the admission policies of the cluster: " + strings.Join(e.Denials, "; ")
+}
+
+// admissionDenials are the messages of the API server for the requests that an
+// admission plugin or webhook denied, as opposed to the ones the client is not
+// authorized to make, which are forbidden as well.
+var admissionDenials = []string{
+	"admission webhook",    // validating webhooks, e.g. Gatekeeper or Kyverno
+	"denied request",       // ValidatingAdmissionPolicy
+	"violates PodSecurity", // Pod Security admission
+	"unable to validate against any security context constraint",
+}
+
+// admissionDenied returns true if err is the denial of an admission plugin or
+// webhook.
+func admissionDenied(err error) bool {
+	if !apierrors.IsForbidden(err) {
+		return false
+	}
+
+	for _, denial := range admissionDenials {
+		if strings.Contains(err.Error(), denial) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkPolicies submits the objects of yamlSpecs to the admission chain of the
+// API server in a server-side dry-run, so that a state that the policies of the
+// cluster would deny is not applied partially. Only the denials are reported,
+// the other errors, e.g. the namespace of the state does not exist yet, are
+// left to the apply.
+func (c *creator) checkPolicies(
+	ctx context.Context,
+	yamlSpecs [][]byte,
+	owner v1.Object,
+	name string,
+	namespace string,
+	nodeSelector map[string]string,
+	kernelFullVersion string,
+	operatingSystemMajorMinor string) error {
+
+	kubeClient, err := c.clientFor(owner)
+	if err != nil {
+		return err
+	}
+
+	var denials []string
+
+	for _, yamlSpec := range yamlSpecs {
+		obj, err := c.parseObj(yamlSpec, namespace)
+		if err != nil {
+			return err
+		}
+
+		if err = c.prepareObj(obj, owner, nodeSelector, kernelFullVersion, operatingSystemMajorMinor); err != nil {
+			return err
+		}
+
+		if err = c.setOwner(obj, owner, name, namespace); err != nil {
+			return err
+		}
+
+		found := obj.DeepCopy()
+
+		err = kubeClient.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, found)
+		if apierrors.IsNotFound(err) {
+			err = kubeClient.Create(ctx, obj, client.DryRunAll)
+			// The schema validation of a new object is as much a policy as
+			// the admission webhooks are
+			if admissionDenied(err) || apierrors.IsInvalid(err) {
+				denials = append(denials, objectRef(obj)+": "+err.Error())
+			}
+			continue
+		}
+
+		if err != nil {
+			c.log.Info("Skipping the policy check", "object", objectRef(obj), "error", err)
+			continue
+		}
+
+		obj.SetResourceVersion(found.GetResourceVersion())
+
+		// An existing object may be invalid to update, e.g. an immutable
+		// field, CRUD recreates it, only the admission denials count
+		if err = kubeClient.Update(ctx, obj, client.DryRunAll); admissionDenied(err) {
+			denials = append(denials, objectRef(obj)+": "+err.Error())
+		}
+	}
+
+	if len(denials) > 0 {
+		return sroerrors.Wrap(sroerrors.Blocked, &PolicyDeniedError{Denials: denials})
+	}
+
+	return nil
+}
@@ -3,41 +3,79 @@ package resource
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/releaseutil"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/yaml"
 
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/internal/resourcehelper"
+	"github.com/openshift-psap/special-resource-operator/pkg/audit"
+	"github.com/openshift-psap/special-resource-operator/pkg/budget"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/featuregates"
 	"github.com/openshift-psap/special-resource-operator/pkg/filter"
 	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
 	"github.com/openshift-psap/special-resource-operator/pkg/lifecycle"
 	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
 	"github.com/openshift-psap/special-resource-operator/pkg/poll"
 	"github.com/openshift-psap/special-resource-operator/pkg/proxy"
+	"github.com/openshift-psap/special-resource-operator/pkg/recorder"
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	"github.com/openshift-psap/special-resource-operator/pkg/watcher"
 	"github.com/openshift-psap/special-resource-operator/pkg/yamlutil"
 )
 
+const (
+	// FieldManager owns the fields SRO creates, updates or applies with a
+	// server-side apply.
+	FieldManager = "special-resource-operator"
+
+	// appliedObjectsLimit is the number of objects listed per state in the
+	// status of the owner.
+	appliedObjectsLimit = 100
+
+	// AdoptAnnotation set to true on the owner brings the objects that
+	// already exist, e.g. deployed from plain manifests before SRO, under
+	// its management instead of failing on them.
+	AdoptAnnotation = "specialresource.openshift.io/adopt"
+)
+
 var (
 	UpdateVendor string
+
+	// ApplyBackoff is how an object is retried when its apply fails for a
+	// transient reason, e.g. an admission webhook timeout.
+	ApplyBackoff = wait.Backoff{Steps: 3, Duration: 2 * time.Second, Factor: 2, Jitter: 0.1}
+
+	lastKinds = []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}
 )
 
 //go:generate mockgen -source=resource.go -package=resource -destination=mock_resource_api.go
 
 type Creator interface {
+	AppliedObjects(specialResource string) []srov1beta1.ObjectReference
 	CreateFromYAML(context.Context, []byte, bool, v1.Object, string, string, map[string]string, string, string) error
+	SkippedObjects(specialResource string) []string
 }
 
 type creator struct {
@@ -50,6 +88,17 @@ type creator struct {
 	proxyAPI      proxy.ProxyAPI
 	scheme        *runtime.Scheme
 	helper        resourcehelper.Helper
+	recorder      recorder.Recorder
+	featureGates  featuregates.FeatureGates
+	watcher       watcher.Watcher
+	budget        budget.Budget
+	auditLog      audit.Log
+
+	appliedMutex sync.Mutex
+	applied      map[string][]srov1beta1.ObjectReference
+
+	skippedMutex sync.Mutex
+	skipped      map[string]map[string]bool
 }
 
 func NewCreator(
@@ -61,6 +110,11 @@ func NewCreator(
 	lc lifecycle.Lifecycle,
 	proxyAPI proxy.ProxyAPI,
 	resHelper resourcehelper.Helper,
+	rec recorder.Recorder,
+	featureGates featuregates.FeatureGates,
+	w watcher.Watcher,
+	b budget.Budget,
+	auditLog audit.Log,
 ) Creator {
 	return &creator{
 		kubeClient:    kubeClient,
@@ -72,7 +126,44 @@ func NewCreator(
 		scheme:        scheme,
 		proxyAPI:      proxyAPI,
 		helper:        resHelper,
+		recorder:      rec,
+		featureGates:  featureGates,
+		watcher:       w,
+		budget:        b,
+		auditLog:      auditLog,
+		applied:       make(map[string][]srov1beta1.ObjectReference),
+		skipped:       make(map[string]map[string]bool),
+	}
+}
+
+// AppliedObjects returns the objects applied for the SpecialResource since the
+// last call, at most appliedObjectsLimit of them.
+func (c *creator) AppliedObjects(specialResource string) []srov1beta1.ObjectReference {
+	c.appliedMutex.Lock()
+	defer c.appliedMutex.Unlock()
+
+	refs := c.applied[specialResource]
+	delete(c.applied, specialResource)
+
+	return refs
+}
+
+// addApplied buffers obj until the next AppliedObjects.
+func (c *creator) addApplied(specialResource string, obj *unstructured.Unstructured) {
+	c.appliedMutex.Lock()
+	defer c.appliedMutex.Unlock()
+
+	if len(c.applied[specialResource]) >= appliedObjectsLimit {
+		return
 	}
+
+	c.applied[specialResource] = append(c.applied[specialResource], srov1beta1.ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+	})
 }
 
 func (c *creator) AfterCRUD(ctx context.Context, obj *unstructured.Unstructured, namespace string) error {
@@ -131,9 +222,44 @@ func (c *creator) CreateFromYAML(
 
 	scanner := yamlutil.NewYAMLScanner(yamlFile)
 
+	var yamlSpecs [][]byte
+
 	for scanner.Scan() {
+		yamlSpecs = append(yamlSpecs, scanner.Bytes())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to scan manifest: %w", err)
+	}
+
+	// Namespaces, CRDs, ServiceAccounts and RBAC have to exist before the
+	// workloads using them, objects of the same kind keep the template order
+	sort.SliceStable(yamlSpecs, func(i, j int) bool {
+		return kindPriority(yamlSpecs[i]) < kindPriority(yamlSpecs[j])
+	})
+
+	// A state denied by the admission policies is not applied at all, instead
+	// of failing halfway through its objects
+	if c.featureGates.Enabled(featuregates.PreApplyPolicyCheck, owner) {
+		err := c.checkPolicies(
+			ctx,
+			yamlSpecs,
+			owner,
+			name,
+			namespace,
+			nodeSelector,
+			kernelFullVersion,
+			operatingSystemMajorMinor)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The objects are independent of each other, one that cannot be applied
+	// does not prevent the next ones from being applied
+	var errs []error
 
-		yamlSpec := scanner.Bytes()
+	for _, yamlSpec := range yamlSpecs {
 
 		err := c.createObjFromYAML(
 			ctx,
@@ -146,14 +272,53 @@ func (c *creator) CreateFromYAML(
 			kernelFullVersion,
 			operatingSystemMajorMinor)
 		if err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to scan manifest: %w", err)
+	return utilerrors.NewAggregate(errs)
+}
+
+// kindPriority returns the rank of the kind of yamlSpec in the apply order:
+// the kinds of the Helm install order, as extended by the helmer, then the
+// unknown kinds, e.g. custom resources, and the admission webhooks last, they
+// would intercept the objects of the state before their Service is running.
+func kindPriority(yamlSpec []byte) int {
+	typeMeta := v1.TypeMeta{}
+	// A manifest that cannot be parsed is reported when it is applied
+	_ = yaml.Unmarshal(yamlSpec, &typeMeta)
+
+	for i, kind := range releaseutil.InstallOrder {
+		if kind == typeMeta.Kind {
+			return i
+		}
 	}
 
+	for i, kind := range lastKinds {
+		if kind == typeMeta.Kind {
+			return len(releaseutil.InstallOrder) + 1 + i
+		}
+	}
+
+	return len(releaseutil.InstallOrder)
+}
+
+// setOwner sets the owner reference and the release metadata of name on obj.
+// SpecialResource is the parent, all other objects are childs and need a
+// reference, except the retained ones.
+func (c *creator) setOwner(obj *unstructured.Unstructured, owner v1.Object, name string, namespace string) error {
+	if obj.GetKind() == "SpecialResource" || obj.GetKind() == "Namespace" {
+		return nil
+	}
+
+	if !ownership.Retained(obj) {
+		if err := controllerutil.SetControllerReference(owner, obj, c.scheme); err != nil {
+			return fmt.Errorf("could not set the owner reference: %w", err)
+		}
+	}
+
+	c.helper.SetMetaData(obj, name, namespace)
+
 	return nil
 }
 
@@ -167,21 +332,24 @@ func (c *creator) CRUD(ctx context.Context, obj *unstructured.Unstructured, rele
 		logg = c.log.WithValues("Kind", obj.GetKind()+": "+obj.GetName())
 	}
 
-	// SpecialResource is the parent, all other objects are childs and need a reference
-	// but only set the ownerreference if created by SRO do not set ownerreference per default
-	if obj.GetKind() != "SpecialResource" && obj.GetKind() != "Namespace" {
-		if err := controllerutil.SetControllerReference(owner, obj, c.scheme); err != nil {
-			return err
-		}
+	// Retained objects do not get an owner reference, the garbage collector
+	// would delete them with their owner
+	retained := ownership.Retained(obj)
 
-		c.helper.SetMetaData(obj, name, namespace)
+	if err := c.setOwner(obj, owner, name, namespace); err != nil {
+		return err
+	}
+
+	kubeClient, err := c.clientFor(owner)
+	if err != nil {
+		return err
 	}
 
 	found := obj.DeepCopy()
 
 	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
 
-	err := c.kubeClient.Get(ctx, key, found)
+	err = kubeClient.Get(ctx, key, found)
 
 	if apierrors.IsNotFound(err) {
 		oneTimer, err := c.helper.IsOneTimer(obj)
@@ -205,13 +373,15 @@ func (c *creator) CRUD(ctx context.Context, obj *unstructured.Unstructured, rele
 		}
 
 		// If we create the resource set the owner reference
-		if err = controllerutil.SetControllerReference(owner, obj, c.scheme); err != nil {
-			return fmt.Errorf("could not set the owner reference: %w", err)
+		if !retained {
+			if err = controllerutil.SetControllerReference(owner, obj, c.scheme); err != nil {
+				return fmt.Errorf("could not set the owner reference: %w", err)
+			}
 		}
 
 		c.helper.SetMetaData(obj, name, namespace)
 
-		if err = c.kubeClient.Create(ctx, obj); err != nil {
+		if err = kubeClient.Create(ctx, obj, client.FieldOwner(FieldManager)); err != nil {
 			if apierrors.IsForbidden(err) {
 				return fmt.Errorf("API error: forbidden: %w", err)
 			}
@@ -219,6 +389,8 @@ func (c *creator) CRUD(ctx context.Context, obj *unstructured.Unstructured, rele
 			return fmt.Errorf("unknown error: %w", err)
 		}
 
+		c.auditLog.Record(name, audit.ObjectCreated, objectRef(obj), "not found")
+
 		return nil
 	}
 
@@ -230,9 +402,38 @@ func (c *creator) CRUD(ctx context.Context, obj *unstructured.Unstructured, rele
 		return fmt.Errorf("unexpected error: %w", err)
 	}
 
+	// Namespaces may be shared and SpecialResources are created by the
+	// user, other objects are only taken over when the owner asks for it
+	if _, managed := found.GetLabels()[filter.OwnedLabel]; !managed && obj.GetKind() != "SpecialResource" && obj.GetKind() != "Namespace" {
+		if owner.GetAnnotations()[AdoptAnnotation] != "true" {
+			return fmt.Errorf("already exists and is not managed by SRO, annotate %s with %s=true to adopt it", owner.GetName(), AdoptAnnotation)
+		}
+
+		logg.Info("Found, adopting")
+		if err = c.adopt(ctx, kubeClient, found, obj, owner); err != nil {
+			return err
+		}
+
+		c.auditLog.Record(name, audit.ObjectAdopted, objectRef(obj), AdoptAnnotation)
+	}
+
+	// Objects created before they were retained still have an owner
+	// reference, and are neither updated if not updateable nor if unchanged
+	if retained {
+		if err = c.release(ctx, kubeClient, found, owner); err != nil {
+			return err
+		}
+	}
+
+	// The object is listed with its UID in the status of the owner
+	obj.SetUID(found.GetUID())
+
 	// Not updating Pod because we can only update image and some other
-	// specific minor fields.
-	if c.helper.IsNotUpdateable(obj.GetKind()) {
+	// specific minor fields, unless the template asks for a recreation.
+	notUpdateable := c.helper.IsNotUpdateable(obj.GetKind())
+	recreate := obj.GetAnnotations()["specialresource.openshift.io/update-strategy"] == "Recreate"
+
+	if notUpdateable && !recreate {
 		logg.Info("Not Updateable", "Resource", obj.GetKind())
 		return nil
 	}
@@ -246,6 +447,28 @@ func (c *creator) CRUD(ctx context.Context, obj *unstructured.Unstructured, rele
 		return nil
 	}
 
+	if notUpdateable {
+		logg.Info("Found, recreating")
+		if err = c.recreate(ctx, kubeClient, found, obj); err != nil {
+			return err
+		}
+
+		c.auditLog.Record(name, audit.ObjectRecreated, objectRef(obj), "hash changed, Recreate update strategy")
+
+		return nil
+	}
+
+	if c.featureGates.Enabled(featuregates.ServerSideApply, owner) {
+		logg.Info("Found, applying")
+		if err = c.apply(ctx, kubeClient, obj); err != nil {
+			return err
+		}
+
+		c.auditLog.Record(name, audit.ObjectUpdated, objectRef(obj), "hash changed, server-side apply")
+
+		return nil
+	}
+
 	logg.Info("Found, updating")
 	required := obj.DeepCopy()
 
@@ -260,13 +483,169 @@ func (c *creator) CRUD(ctx context.Context, obj *unstructured.Unstructured, rele
 		return fmt.Errorf("couldn't Update ResourceVersion: %w", err)
 	}
 
-	if err = c.kubeClient.Update(ctx, required); err != nil {
+	if err = kubeClient.Update(ctx, required, client.FieldOwner(FieldManager)); err != nil {
 		return fmt.Errorf("couldn't Update Resource: %w", err)
 	}
 
+	c.auditLog.Record(name, audit.ObjectUpdated, objectRef(obj), "hash changed")
+
 	return nil
 }
 
+// release removes the owner references to owner from found, so that the
+// garbage collector does not delete it with owner.
+func (c *creator) release(ctx context.Context, kubeClient clients.ClientsInterface, found *unstructured.Unstructured, owner v1.Object) error {
+	refs := make([]v1.OwnerReference, 0)
+
+	for _, ref := range found.GetOwnerReferences() {
+		if ref.UID != owner.GetUID() {
+			refs = append(refs, ref)
+		}
+	}
+
+	if len(refs) == len(found.GetOwnerReferences()) {
+		return nil
+	}
+
+	released := found.DeepCopy()
+	released.SetOwnerReferences(refs)
+
+	if err := kubeClient.Patch(ctx, released, client.MergeFrom(found)); err != nil {
+		return fmt.Errorf("couldn't remove the owner reference of a retained Resource: %w", err)
+	}
+
+	released.DeepCopyInto(found)
+
+	return nil
+}
+
+// adopt labels found, an object SRO did not create, like obj and makes the
+// owner of obj its controller, so that it is updated and deleted as if SRO
+// had created it. found is updated with the patched object.
+func (c *creator) adopt(ctx context.Context, kubeClient clients.ClientsInterface, found, obj *unstructured.Unstructured, owner v1.Object) error {
+	adopted := found.DeepCopy()
+
+	labels := adopted.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for k, v := range obj.GetLabels() {
+		labels[k] = v
+	}
+	adopted.SetLabels(labels)
+
+	annotations := adopted.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	for k, v := range obj.GetAnnotations() {
+		annotations[k] = v
+	}
+	adopted.SetAnnotations(annotations)
+
+	if ref := v1.GetControllerOf(obj); ref != nil {
+		switch controller := v1.GetControllerOf(found); {
+		case controller == nil:
+			adopted.SetOwnerReferences(append(found.GetOwnerReferences(), *ref))
+		case controller.UID != ref.UID:
+			return fmt.Errorf("cannot adopt, already controlled by %s %s", controller.Kind, controller.Name)
+		}
+	}
+
+	if err := kubeClient.Patch(ctx, adopted, client.MergeFrom(found)); err != nil {
+		return fmt.Errorf("couldn't Adopt Resource: %w", err)
+	}
+
+	if o, ok := owner.(runtime.Object); ok {
+		kubeClient.Event(o, corev1.EventTypeNormal, "Adopted", objectRef(obj)+" was adopted")
+	}
+
+	adopted.DeepCopyInto(found)
+
+	return nil
+}
+
+// apply updates obj with a server-side apply. SRO only owns the fields rendered
+// by the chart, the fields set by other controllers, e.g. the replicas of an
+// HPA or an injected sidecar, are kept.
+func (c *creator) apply(ctx context.Context, kubeClient clients.ClientsInterface, obj *unstructured.Unstructured) error {
+	required := obj.DeepCopy()
+
+	if err := utils.Annotate(required); err != nil {
+		return fmt.Errorf("can not annotate with hash: %w", err)
+	}
+
+	// An apply configuration must not carry them
+	required.SetResourceVersion("")
+	required.SetManagedFields(nil)
+	required.SetUID("")
+
+	if err := kubeClient.Patch(ctx, required, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("couldn't Apply Resource: %w", err)
+	}
+
+	return nil
+}
+
+// recreate deletes found and creates obj once found is gone, for the kinds
+// that cannot be updated in place.
+func (c *creator) recreate(ctx context.Context, kubeClient clients.ClientsInterface, found, obj *unstructured.Unstructured) error {
+	if err := kubeClient.Delete(ctx, found); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("couldn't Delete Resource: %w", err)
+	}
+
+	if err := c.pollActions.ForResourceUnavailability(ctx, found); err != nil {
+		return fmt.Errorf("could not wait for the deletion: %w", err)
+	}
+
+	required := obj.DeepCopy()
+	required.SetUID("")
+
+	if err := utils.Annotate(required); err != nil {
+		return fmt.Errorf("can not annotate with hash: %w", err)
+	}
+
+	if err := kubeClient.Create(ctx, required, client.FieldOwner(FieldManager)); err != nil {
+		return fmt.Errorf("couldn't Create Resource: %w", err)
+	}
+
+	obj.SetUID(required.GetUID())
+
+	return nil
+}
+
+// isTransient returns true for the apply errors worth retrying.
+func isTransient(err error) bool {
+	return strings.Contains(err.Error(), "failed calling webhook") ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTooManyRequests(err)
+}
+
+// objectRef identifies obj in the errors, e.g. DaemonSet simple-kmod/driver.
+func objectRef(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetKind() + " " + obj.GetName()
+	}
+	return obj.GetKind() + " " + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// clientFor returns the clients applying the objects of owner, the ones of the
+// operator unless owner is a SpecialResource with a serviceAccountName.
+func (c *creator) clientFor(owner v1.Object) (clients.ClientsInterface, error) {
+	sr, ok := owner.(*srov1beta1.SpecialResource)
+	if !ok || sr.Spec.ServiceAccountName == "" {
+		return c.kubeClient, nil
+	}
+
+	kubeClient, err := c.kubeClient.Impersonate(sr.Spec.Namespace, sr.Spec.ServiceAccountName)
+	if err != nil {
+		return nil, fmt.Errorf("could not impersonate ServiceAccount %s/%s: %w", sr.Spec.Namespace, sr.Spec.ServiceAccountName, err)
+	}
+
+	return kubeClient, nil
+}
+
 func (c *creator) checkForImagePullBackOff(ctx context.Context, obj *unstructured.Unstructured, namespace string) error {
 
 	if err := c.pollActions.ForDaemonSet(ctx, obj); err == nil {
@@ -340,27 +719,19 @@ func (c *creator) checkForImagePullBackOff(ctx context.Context, obj *unstructure
 	return fmt.Errorf("unexpected Phase of Pods in DameonSet: %s", obj.GetName())
 }
 
-func (c *creator) createObjFromYAML(
-	ctx context.Context,
-	yamlSpec []byte,
-	releaseInstalled bool,
-	owner v1.Object,
-	name string,
-	namespace string,
-	nodeSelector map[string]string,
-	kernelFullVersion string,
-	operatingSystemMajorMinor string) error {
+// parseObj returns the object of yamlSpec, in namespace unless it sets its own.
+func (c *creator) parseObj(yamlSpec []byte, namespace string) (*unstructured.Unstructured, error) {
 	obj := &unstructured.Unstructured{
 		Object: map[string]interface{}{},
 	}
 
 	jsonSpec, err := yaml.YAMLToJSON(yamlSpec)
 	if err != nil {
-		return fmt.Errorf("Could not convert yaml file to json: %s: error %w", string(yamlSpec), err)
+		return nil, sroerrors.Wrap(sroerrors.Render, fmt.Errorf("Could not convert yaml file to json: %s: error %w", string(yamlSpec), err))
 	}
 
 	if err = obj.UnmarshalJSON(jsonSpec); err != nil {
-		return fmt.Errorf("cannot unmarshall json spec, check your manifest: %s: %w", jsonSpec, err)
+		return nil, sroerrors.Wrap(sroerrors.Render, fmt.Errorf("cannot unmarshall json spec, check your manifest: %s: %w", jsonSpec, err))
 	}
 
 	//  Do not override the namespace if already set
@@ -369,34 +740,89 @@ func (c *creator) createObjFromYAML(
 		obj.SetNamespace(namespace)
 	}
 
-	yamlKind := obj.GetKind()
-	yamlName := obj.GetName()
-	yamlNamespace := obj.GetNamespace()
-	metricValue := 0
-	defer func() {
-		c.metricsClient.SetCompletedKind(name, yamlKind, yamlName, yamlNamespace, metricValue)
-	}()
+	return obj, nil
+}
+
+// prepareObj sets the labels, the scheduling and the overrides of owner on obj.
+func (c *creator) prepareObj(
+	obj *unstructured.Unstructured,
+	owner v1.Object,
+	nodeSelector map[string]string,
+	kernelFullVersion string,
+	operatingSystemMajorMinor string) error {
 
 	// We used this for predicate filtering, we're watching a lot of
 	// API Objects we want to ignore all objects that do not have this
 	// label.
-	if err = c.helper.SetLabel(obj, filter.OwnedLabel); err != nil {
+	if err := c.helper.SetLabel(obj, filter.OwnedLabel); err != nil {
 		return fmt.Errorf("could not set label: %w", err)
 	}
 	// kernel affinity related attributes only set if there is an
 	// annotation specialresource.openshift.io/kernel-affine: true
 	if c.kernelData.IsObjectAffine(obj) {
-		if err = c.kernelData.SetAffineAttributes(obj, kernelFullVersion, operatingSystemMajorMinor); err != nil {
+		if err := c.kernelData.SetAffineAttributes(obj, kernelFullVersion, operatingSystemMajorMinor); err != nil {
 			return fmt.Errorf("cannot set kernel affine attributes: %w", err)
 		}
 	}
 
 	// Add nodeSelector terms defined for the specialresource CR to the object
 	// we do not want to spread HW enablement stacks on all nodes
-	if err = c.helper.SetNodeSelectorTerms(obj, nodeSelector); err != nil {
+	if err := c.helper.SetNodeSelectorTerms(obj, nodeSelector); err != nil {
 		return fmt.Errorf("setting NodeSelectorTerms failed: %w", err)
 	}
 
+	// Scheduling and resource overrides set by the admin in the CR take
+	// precedence over whatever the vendor chart rendered
+	if sr, ok := owner.(*srov1beta1.SpecialResource); ok {
+		if err := c.helper.SetPodOverrides(obj, sr.Spec.PodOverrides); err != nil {
+			return fmt.Errorf("setting PodOverrides failed: %w", err)
+		}
+		if err := c.helper.SetBuildOverrides(obj, sr.Spec.DriverContainer.BuildOverrides); err != nil {
+			return fmt.Errorf("setting BuildOverrides failed: %w", err)
+		}
+		if ccache := sr.Spec.DriverContainer.Ccache; ccache.Enabled {
+			if err := c.helper.SetCcacheVolume(obj, resourcehelper.CcacheClaimName(sr.Name), resourcehelper.CcacheSize(ccache)); err != nil {
+				return fmt.Errorf("setting ccache volume failed: %w", err)
+			}
+		}
+		if sr.Spec.DriverContainer.Entitled {
+			if err := c.helper.SetEntitlementVolume(obj); err != nil {
+				return fmt.Errorf("setting entitlement volume failed: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *creator) createObjFromYAML(
+	ctx context.Context,
+	yamlSpec []byte,
+	releaseInstalled bool,
+	owner v1.Object,
+	name string,
+	namespace string,
+	nodeSelector map[string]string,
+	kernelFullVersion string,
+	operatingSystemMajorMinor string) error {
+
+	obj, err := c.parseObj(yamlSpec, namespace)
+	if err != nil {
+		return err
+	}
+
+	yamlKind := obj.GetKind()
+	yamlName := obj.GetName()
+	yamlNamespace := obj.GetNamespace()
+	metricValue := 0
+	defer func() {
+		c.metricsClient.SetCompletedKind(name, yamlKind, yamlName, yamlNamespace, metricValue)
+	}()
+
+	if err = c.prepareObj(obj, owner, nodeSelector, kernelFullVersion, operatingSystemMajorMinor); err != nil {
+		return err
+	}
+
 	// We are only building a driver-container if we cannot pull the image
 	// We are asuming that vendors provide pre compiled DriverContainers
 	// If err == nil, build a new container, if err != nil skip it
@@ -405,31 +831,141 @@ func (c *creator) createObjFromYAML(
 		return nil
 	}
 
+	// Monitoring objects the cluster would not act on would fail the state,
+	// or be left unused
+	reason, err := c.monitoringUnavailable(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		c.log.Info("Skipping monitoring object", "object", objectRef(obj), "reason", reason)
+		c.addSkipped(name, obj, reason)
+		return nil
+	}
+
+	if err = c.budget.Allow(name, obj); err != nil {
+		return err
+	}
+
 	// Callbacks before CRUD will update the manifests
 	if err = c.BeforeCRUD(obj, owner); err != nil {
 		return fmt.Errorf("before CRUD hooks failed: %w", err)
 	}
-	// Create Update Delete Patch resources
-	err = c.CRUD(ctx, obj, releaseInstalled, owner, name, namespace)
+
+	if sr, ok := owner.(*srov1beta1.SpecialResource); ok && resourcehelper.IsWorkload(obj.GetKind()) {
+		c.previewNodeSelection(ctx, obj, sr)
+	}
+
+	// Create Update Delete Patch resources, the admission webhooks of the
+	// cluster may time out while their operator is rolling out
+	err = retry.OnError(ApplyBackoff, isTransient, func() error {
+		return c.CRUD(ctx, obj, releaseInstalled, owner, name, namespace)
+	})
 	if err != nil {
-		if strings.Contains(err.Error(), "failed calling webhook") {
-			return fmt.Errorf("webhook not ready, requeue: %w", err)
+		if isTransient(err) {
+			return sroerrors.Wrap(sroerrors.Apply, fmt.Errorf("%s: webhook not ready, requeue: %w", objectRef(obj), err))
 		}
 
-		return fmt.Errorf("CRUD exited non-zero on Object: %+v: %w", obj, err)
+		return sroerrors.Wrap(sroerrors.Apply, fmt.Errorf("%s: %w", objectRef(obj), err))
+	}
+
+	// Deleting an object of a kind the controller does not own statically,
+	// e.g. a Route, only triggers a reconcile once the kind is watched
+	if v1.GetControllerOf(obj) != nil {
+		if err = c.watcher.Watch(obj.GroupVersionKind()); err != nil {
+			c.log.Info("Could not watch", "kind", obj.GetKind(), "error", err)
+		}
 	}
 
 	// Callbacks after CRUD will wait for ressource and check status
 	if err = c.AfterCRUD(ctx, obj, namespace); err != nil {
-		return fmt.Errorf("after CRUD hooks failed: %w", err)
+		c.retryFailedBuild(ctx, obj, owner, err)
+
+		category := sroerrors.Apply
+		if errors.Is(err, wait.ErrWaitTimeout) {
+			category = sroerrors.WaitTimeout
+		}
+		return sroerrors.Wrap(category, fmt.Errorf("%s: after CRUD hooks failed: %w", objectRef(obj), err))
+	}
+
+	if sr, ok := owner.(*srov1beta1.SpecialResource); ok && obj.GetKind() == "BuildConfig" {
+		delete(sr.Status.BuildAttempts, obj.GetName())
 	}
 
 	c.sendNodesMetrics(ctx, obj, name)
 
+	c.recorder.Add(name, obj)
+	c.addApplied(name, obj)
+
 	metricValue = 1
 	return nil
 }
 
+// retryFailedBuild deletes the BuildConfig of a Build that failed for a
+// transient reason. The BuildConfig is recreated on the next reconcile, which
+// is rate limited by the controller, and its ConfigChange trigger starts a new
+// Build. The attempts are tracked in the SpecialResource status.
+func (c *creator) retryFailedBuild(ctx context.Context, obj *unstructured.Unstructured, owner v1.Object, err error) {
+
+	var buildErr *poll.BuildFailedError
+	if !errors.As(err, &buildErr) || !buildErr.Transient() {
+		return
+	}
+
+	sr, ok := owner.(*srov1beta1.SpecialResource)
+	if !ok {
+		return
+	}
+
+	attempts := sr.Status.BuildAttempts[obj.GetName()]
+	if attempts >= sr.Spec.DriverContainer.BuildRetries {
+		c.log.Info("No build retries left", "BuildConfig", obj.GetName(), "BuildRetries", sr.Spec.DriverContainer.BuildRetries)
+		return
+	}
+
+	kubeClient, err := c.clientFor(owner)
+	if err != nil {
+		c.log.Info("Could not retry build", "BuildConfig", obj.GetName(), "error", err)
+		return
+	}
+
+	if sr.Status.BuildAttempts == nil {
+		sr.Status.BuildAttempts = make(map[string]int32)
+	}
+	sr.Status.BuildAttempts[obj.GetName()] = attempts + 1
+
+	c.log.Info("Retrying build", "BuildConfig", obj.GetName(), "Reason", buildErr.Reason, "Attempt", attempts+1)
+	utils.WarnOnError(kubeClient.Delete(ctx, obj))
+}
+
+// previewNodeSelection records in the status of sr how many nodes the Pods of
+// the workload obj can run on, and warns if there are none: a DaemonSet no
+// node matches is rolled out successfully, on no node.
+func (c *creator) previewNodeSelection(ctx context.Context, obj *unstructured.Unstructured, sr *srov1beta1.SpecialResource) {
+	nodes := &corev1.NodeList{}
+	if err := c.kubeClient.List(ctx, nodes); err != nil {
+		c.log.Info("Could not list the nodes", "error", err)
+		return
+	}
+
+	count, err := resourcehelper.MatchingNodes(obj, nodes.Items)
+	if err != nil {
+		c.log.Info("Could not match the nodes", "object", objectRef(obj), "error", err)
+		return
+	}
+
+	if sr.Status.MatchingNodes == nil {
+		sr.Status.MatchingNodes = make(map[string]int32)
+	}
+	sr.Status.MatchingNodes[objectRef(obj)] = int32(count)
+
+	if count == 0 {
+		c.log.Info("No node matches the nodeSelector and node affinity", "object", objectRef(obj))
+		c.kubeClient.Event(sr, corev1.EventTypeWarning, "NoMatchingNodes",
+			fmt.Sprintf("%s: no node matches its nodeSelector and node affinity", objectRef(obj)))
+	}
+}
+
 func (c *creator) rebuildDriverContainer(obj *unstructured.Unstructured) error {
 
 	logger := c.log.WithValues("Kind", obj.GetKind(), "Namespace", obj.GetNamespace(), "Name", obj.GetName())
@@ -3,7 +3,9 @@ package resource
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -15,17 +17,26 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	kubetypes "k8s.io/apimachinery/pkg/types"
 
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/internal/resourcehelper"
+	"github.com/openshift-psap/special-resource-operator/pkg/audit"
+	"github.com/openshift-psap/special-resource-operator/pkg/budget"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/featuregates"
 	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
 	"github.com/openshift-psap/special-resource-operator/pkg/lifecycle"
 	"github.com/openshift-psap/special-resource-operator/pkg/metrics"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
 	"github.com/openshift-psap/special-resource-operator/pkg/poll"
 	"github.com/openshift-psap/special-resource-operator/pkg/proxy"
+	"github.com/openshift-psap/special-resource-operator/pkg/recorder"
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	"github.com/openshift-psap/special-resource-operator/pkg/watcher"
 )
 
 var (
@@ -51,6 +62,11 @@ var _ = Describe("creator_CreateFromYAML", func() {
 		kernelData    *kernel.MockKernelData
 		proxyAPI      *proxy.MockProxyAPI
 		helper        *resourcehelper.MockHelper
+		mockRecorder  *recorder.MockRecorder
+		featureGates  *featuregates.MockFeatureGates
+		mockWatcher   *watcher.MockWatcher
+		mockBudget    *budget.MockBudget
+		mockAudit     *audit.MockLog
 	)
 
 	BeforeEach(func() {
@@ -62,6 +78,16 @@ var _ = Describe("creator_CreateFromYAML", func() {
 		kernelData = kernel.NewMockKernelData(ctrl)
 		proxyAPI = proxy.NewMockProxyAPI(ctrl)
 		helper = resourcehelper.NewMockHelper(ctrl)
+		mockRecorder = recorder.NewMockRecorder(ctrl)
+		featureGates = featuregates.NewMockFeatureGates(ctrl)
+		featureGates.EXPECT().Enabled(featuregates.ServerSideApply, gomock.Any()).Return(false).AnyTimes()
+		featureGates.EXPECT().Enabled(featuregates.PreApplyPolicyCheck, gomock.Any()).Return(false).AnyTimes()
+		mockWatcher = watcher.NewMockWatcher(ctrl)
+		mockWatcher.EXPECT().Watch(gomock.Any()).AnyTimes()
+		mockBudget = budget.NewMockBudget(ctrl)
+		mockBudget.EXPECT().Allow(gomock.Any(), gomock.Any()).AnyTimes()
+		mockAudit = audit.NewMockLog(ctrl)
+		mockAudit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	})
 
 	AfterEach(func() {
@@ -122,6 +148,7 @@ spec:
 				}),
 			kubeClient.EXPECT().Get(context.TODO(), nsn, unstructuredMatcher).Times(1),
 			helper.EXPECT().IsNotUpdateable("Pod").Times(1).Return(true),
+			mockRecorder.EXPECT().Add(specialResourceName, gomock.Any()),
 			metricsClient.EXPECT().SetCompletedKind(specialResourceName, "Pod", "nginx", namespace, 1).Times(1),
 		)
 
@@ -131,7 +158,7 @@ spec:
 		Expect(err).NotTo(HaveOccurred())
 
 		err =
-			NewCreator(kubeClient, metricsClient, pollActions, kernelData, scheme, mockLifecycle, proxyAPI, helper).
+			NewCreator(kubeClient, metricsClient, pollActions, kernelData, scheme, mockLifecycle, proxyAPI, helper, mockRecorder, featureGates, mockWatcher, mockBudget, mockAudit).
 				CreateFromYAML(
 					context.TODO(),
 					yamlSpec,
@@ -244,7 +271,8 @@ spec:
 				}),
 			kubeClient.
 				EXPECT().
-				Create(context.TODO(), &newPod),
+				Create(context.TODO(), &newPod, client.FieldOwner(FieldManager)),
+			mockRecorder.EXPECT().Add(specialResourceName, gomock.Any()),
 			metricsClient.
 				EXPECT().
 				SetCompletedKind(specialResourceName, "Pod", name, namespace, 1),
@@ -256,7 +284,7 @@ spec:
 		Expect(err).NotTo(HaveOccurred())
 
 		err =
-			NewCreator(kubeClient, metricsClient, pollActions, kernelData, scheme, mockLifecycle, proxyAPI, helper).
+			NewCreator(kubeClient, metricsClient, pollActions, kernelData, scheme, mockLifecycle, proxyAPI, helper, mockRecorder, featureGates, mockWatcher, mockBudget, mockAudit).
 				CreateFromYAML(
 					context.TODO(),
 					yamlSpec,
@@ -271,6 +299,278 @@ spec:
 
 		Expect(err).NotTo(HaveOccurred())
 	})
+
+	It("should retry webhook timeouts and apply the other objects of a failed one", func() {
+		const (
+			namespace           = "ns"
+			specialResourceName = "special-resource"
+		)
+
+		backoff := ApplyBackoff
+		ApplyBackoff.Duration = time.Millisecond
+		defer func() { ApplyBackoff = backoff }()
+
+		twoPods := []byte(`---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: redis
+`)
+
+		owner := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: namespace}}
+
+		helper.EXPECT().IsNamespaced("Pod").Return(true).AnyTimes()
+		helper.EXPECT().SetLabel(gomock.Any(), ownedLabel).AnyTimes()
+		helper.EXPECT().SetNodeSelectorTerms(gomock.Any(), gomock.Any()).AnyTimes()
+		helper.EXPECT().SetMetaData(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		helper.EXPECT().IsOneTimer(gomock.Any()).AnyTimes()
+		kernelData.EXPECT().IsObjectAffine(gomock.Any()).Return(false).AnyTimes()
+
+		webhookErr := errors.New(`Internal error occurred: failed calling webhook "webhook.example.com": context deadline exceeded`)
+
+		kubeClient.EXPECT().
+			Get(context.TODO(), kubetypes.NamespacedName{Namespace: namespace, Name: "nginx"}, unstructuredMatcher).
+			Return(k8serrors.NewNotFound(v1.Resource("pod"), "nginx")).
+			Times(2)
+		gomock.InOrder(
+			kubeClient.EXPECT().Create(context.TODO(), unstructuredMatcher, client.FieldOwner(FieldManager)).Return(webhookErr),
+			kubeClient.EXPECT().Create(context.TODO(), unstructuredMatcher, client.FieldOwner(FieldManager)),
+		)
+		kubeClient.EXPECT().
+			Get(context.TODO(), kubetypes.NamespacedName{Namespace: namespace, Name: "redis"}, unstructuredMatcher).
+			Return(k8serrors.NewForbidden(v1.Resource("pod"), "redis", errors.New(`admission webhook "validation.gatekeeper.sh" denied the request`)))
+
+		mockRecorder.EXPECT().Add(specialResourceName, gomock.Any())
+		metricsClient.EXPECT().SetCompletedKind(specialResourceName, "Pod", "nginx", namespace, 1)
+		metricsClient.EXPECT().SetCompletedKind(specialResourceName, "Pod", "redis", namespace, 0)
+
+		scheme := runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+
+		err := NewCreator(kubeClient, metricsClient, pollActions, kernelData, scheme, mockLifecycle, proxyAPI, helper, mockRecorder, featureGates, mockWatcher, mockBudget, mockAudit).
+			CreateFromYAML(context.TODO(), twoPods, false, &owner, specialResourceName, namespace, nil, "", "")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("Pod ns/redis: "))
+		Expect(err.Error()).NotTo(ContainSubstring("nginx"))
+		Expect(sroerrors.CategoryOf(err)).To(Equal(sroerrors.Apply))
+	})
+
+	It("should apply the objects by kind priority", func() {
+		const namespace = "ns"
+
+		manifests := []byte(`---
+apiVersion: admissionregistration.k8s.io/v1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: webhook
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: nginx
+`)
+
+		owner := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: namespace}}
+
+		helper.EXPECT().IsNamespaced(gomock.Any()).Return(true).AnyTimes()
+		helper.EXPECT().SetLabel(gomock.Any(), ownedLabel).AnyTimes()
+		helper.EXPECT().SetNodeSelectorTerms(gomock.Any(), gomock.Any()).AnyTimes()
+		helper.EXPECT().SetMetaData(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		kernelData.EXPECT().IsObjectAffine(gomock.Any()).Return(false).AnyTimes()
+		metricsClient.EXPECT().SetCompletedKind(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+		kinds := make([]string, 0)
+		kubeClient.EXPECT().
+			Get(context.TODO(), gomock.Any(), unstructuredMatcher).
+			DoAndReturn(func(_ context.Context, _ kubetypes.NamespacedName, obj client.Object) error {
+				kinds = append(kinds, obj.(*unstructured.Unstructured).GetKind())
+				return errors.New("random error")
+			}).
+			Times(4)
+
+		scheme := runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+
+		err := NewCreator(kubeClient, metricsClient, pollActions, kernelData, scheme, mockLifecycle, proxyAPI, helper, mockRecorder, featureGates, mockWatcher, mockBudget, mockAudit).
+			CreateFromYAML(context.TODO(), manifests, false, &owner, "special-resource", namespace, nil, "", "")
+
+		Expect(err).To(HaveOccurred())
+		Expect(kinds).To(Equal([]string{"ServiceAccount", "Pod", "Widget", "MutatingWebhookConfiguration"}))
+	})
+
+	It("should not apply the objects exceeding the resource budget", func() {
+		const namespace = "ns"
+
+		pod := []byte(`---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+`)
+
+		owner := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: namespace}}
+
+		helper.EXPECT().IsNamespaced("Pod").Return(true).AnyTimes()
+		helper.EXPECT().SetLabel(gomock.Any(), ownedLabel)
+		helper.EXPECT().SetNodeSelectorTerms(gomock.Any(), gomock.Any())
+		kernelData.EXPECT().IsObjectAffine(gomock.Any()).Return(false)
+		metricsClient.EXPECT().SetCompletedKind("special-resource", "Pod", "nginx", namespace, 0)
+
+		b, err := budget.New("Pod=0")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = NewCreator(kubeClient, metricsClient, pollActions, kernelData, nil, mockLifecycle, proxyAPI, helper, mockRecorder, featureGates, mockWatcher, b, mockAudit).
+			CreateFromYAML(context.TODO(), pod, false, &owner, "special-resource", namespace, nil, "", "")
+
+		Expect(err).To(HaveOccurred())
+		Expect(sroerrors.CategoryOf(err)).To(Equal(sroerrors.Blocked))
+	})
+})
+
+var _ = Describe("creator_checkPolicies", func() {
+	const namespace = "ns"
+
+	var (
+		ctrl         *gomock.Controller
+		kubeClient   *clients.MockClientsInterface
+		kernelData   *kernel.MockKernelData
+		helper       *resourcehelper.MockHelper
+		featureGates *featuregates.MockFeatureGates
+		c            *creator
+	)
+
+	nginx := []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+`)
+	redis := []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: redis
+`)
+
+	owner := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: namespace}}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubeClient = clients.NewMockClientsInterface(ctrl)
+		kernelData = kernel.NewMockKernelData(ctrl)
+		helper = resourcehelper.NewMockHelper(ctrl)
+		featureGates = featuregates.NewMockFeatureGates(ctrl)
+
+		helper.EXPECT().IsNamespaced("Pod").Return(true).AnyTimes()
+		helper.EXPECT().SetLabel(gomock.Any(), ownedLabel).AnyTimes()
+		helper.EXPECT().SetNodeSelectorTerms(gomock.Any(), gomock.Any()).AnyTimes()
+		helper.EXPECT().SetMetaData(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		kernelData.EXPECT().IsObjectAffine(gomock.Any()).Return(false).AnyTimes()
+
+		scheme := runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+
+		c = NewCreator(kubeClient, nil, nil, kernelData, scheme, nil, nil, helper, nil, featureGates, nil, nil, nil).(*creator)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should aggregate the denials of the dry-run", func() {
+		kubeClient.EXPECT().
+			Get(context.TODO(), kubetypes.NamespacedName{Namespace: namespace, Name: "nginx"}, unstructuredMatcher).
+			Return(k8serrors.NewNotFound(v1.Resource("pod"), "nginx"))
+		kubeClient.EXPECT().
+			Create(context.TODO(), unstructuredMatcher, client.DryRunAll).
+			Return(k8serrors.NewForbidden(v1.Resource("pod"), "nginx", errors.New(`admission webhook "validation.gatekeeper.sh" denied the request: privileged containers are not allowed`)))
+		kubeClient.EXPECT().
+			Get(context.TODO(), kubetypes.NamespacedName{Namespace: namespace, Name: "redis"}, unstructuredMatcher).
+			DoAndReturn(func(_ context.Context, _ kubetypes.NamespacedName, obj client.Object) error {
+				obj.SetResourceVersion("42")
+				return nil
+			})
+		kubeClient.EXPECT().
+			Update(context.TODO(), unstructuredMatcher, client.DryRunAll).
+			DoAndReturn(func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+				Expect(obj.GetResourceVersion()).To(Equal("42"))
+				return k8serrors.NewForbidden(v1.Resource("pod"), "redis", errors.New("violates PodSecurity \"restricted:latest\": missing required label"))
+			})
+
+		err := c.checkPolicies(context.TODO(), [][]byte{nginx, redis}, owner, "special-resource", namespace, nil, "", "")
+
+		var policyErr *PolicyDeniedError
+		Expect(errors.As(err, &policyErr)).To(BeTrue())
+		Expect(policyErr.Denials).To(HaveLen(2))
+		Expect(policyErr.Denials[0]).To(HavePrefix("Pod ns/nginx: "))
+		Expect(policyErr.Denials[1]).To(HavePrefix("Pod ns/redis: "))
+		Expect(sroerrors.CategoryOf(err)).To(Equal(sroerrors.Blocked))
+	})
+
+	It("should leave the errors that are not denials to the apply", func() {
+		kubeClient.EXPECT().
+			Get(context.TODO(), kubetypes.NamespacedName{Namespace: namespace, Name: "nginx"}, unstructuredMatcher).
+			Return(k8serrors.NewNotFound(v1.Resource("pod"), "nginx"))
+		kubeClient.EXPECT().
+			Create(context.TODO(), unstructuredMatcher, client.DryRunAll).
+			Return(k8serrors.NewNotFound(v1.Resource("namespace"), namespace))
+		kubeClient.EXPECT().
+			Get(context.TODO(), kubetypes.NamespacedName{Namespace: namespace, Name: "redis"}, unstructuredMatcher)
+		kubeClient.EXPECT().
+			Update(context.TODO(), unstructuredMatcher, client.DryRunAll).
+			Return(k8serrors.NewInvalid(v1.SchemeGroupVersion.WithKind("Pod").GroupKind(), "redis", nil))
+
+		err := c.checkPolicies(context.TODO(), [][]byte{nginx, redis}, owner, "special-resource", namespace, nil, "", "")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not take an authorization error for a denial", func() {
+		kubeClient.EXPECT().
+			Get(context.TODO(), kubetypes.NamespacedName{Namespace: namespace, Name: "nginx"}, unstructuredMatcher).
+			Return(k8serrors.NewNotFound(v1.Resource("pod"), "nginx"))
+		kubeClient.EXPECT().
+			Create(context.TODO(), unstructuredMatcher, client.DryRunAll).
+			Return(k8serrors.NewForbidden(v1.Resource("pod"), "nginx",
+				errors.New(`User "system:serviceaccount:ns:driver" cannot create resource "pods" in API group "" in the namespace "ns"`)))
+
+		err := c.checkPolicies(context.TODO(), [][]byte{nginx}, owner, "special-resource", namespace, nil, "", "")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not apply a state that is denied", func() {
+		featureGates.EXPECT().Enabled(featuregates.PreApplyPolicyCheck, owner).Return(true)
+
+		kubeClient.EXPECT().
+			Get(context.TODO(), gomock.Any(), unstructuredMatcher).
+			Return(k8serrors.NewNotFound(v1.Resource("pod"), "")).
+			Times(2)
+		kubeClient.EXPECT().
+			Create(context.TODO(), unstructuredMatcher, client.DryRunAll).
+			Return(k8serrors.NewForbidden(v1.Resource("pod"), "redis", errors.New(`admission webhook "validation.gatekeeper.sh" denied the request`)))
+		kubeClient.EXPECT().
+			Create(context.TODO(), unstructuredMatcher, client.DryRunAll)
+
+		manifests := []byte("---\n" + string(nginx) + "---\n" + string(redis))
+
+		err := c.CreateFromYAML(context.TODO(), manifests, false, owner, "special-resource", namespace, nil, "", "")
+
+		var policyErr *PolicyDeniedError
+		Expect(errors.As(err, &policyErr)).To(BeTrue())
+	})
 })
 
 var _ = Describe("creator_CheckForImagePullBackOff", func() {
@@ -305,7 +605,7 @@ var _ = Describe("creator_CheckForImagePullBackOff", func() {
 
 		pollActions.EXPECT().ForDaemonSet(context.TODO(), ds)
 
-		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil).(*creator).
+		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
 			checkForImagePullBackOff(context.TODO(), ds, namespace)
 
 		Expect(err).NotTo(HaveOccurred())
@@ -329,7 +629,7 @@ var _ = Describe("creator_CheckForImagePullBackOff", func() {
 			kubeClient.EXPECT().List(context.TODO(), &v1.PodList{}, opts...).Return(randomError),
 		)
 
-		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil).(*creator).
+		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
 			checkForImagePullBackOff(context.TODO(), ds, namespace)
 
 		Expect(err).To(Equal(randomError))
@@ -343,7 +643,7 @@ var _ = Describe("creator_CheckForImagePullBackOff", func() {
 			kubeClient.EXPECT().List(context.TODO(), &v1.PodList{}, opts...),
 		)
 
-		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil).(*creator).
+		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
 			checkForImagePullBackOff(context.TODO(), ds, namespace)
 
 		Expect(err).To(HaveOccurred())
@@ -382,7 +682,7 @@ var _ = Describe("creator_CheckForImagePullBackOff", func() {
 				}),
 		)
 
-		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil).(*creator).
+		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
 			checkForImagePullBackOff(context.TODO(), ds, namespace)
 
 		Expect(err).To(MatchError("ImagePullBackOff need to rebuild " + vendor + " driver-container"))
@@ -419,7 +719,7 @@ var _ = Describe("creator_CheckForImagePullBackOff", func() {
 				}),
 		)
 
-		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil).(*creator).
+		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
 			checkForImagePullBackOff(context.TODO(), ds, namespace)
 
 		Expect(err).NotTo(HaveOccurred())
@@ -445,7 +745,7 @@ var _ = Describe("creator_CheckForImagePullBackOff", func() {
 				}),
 		)
 
-		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil).(*creator).
+		err := NewCreator(kubeClient, nil, pollActions, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
 			checkForImagePullBackOff(context.TODO(), ds, namespace)
 
 		Expect(err).NotTo(HaveOccurred())
@@ -472,7 +772,7 @@ var _ = Describe("creator_BeforeCRUD", func() {
 
 		proxyAPI.EXPECT().Setup(obj).Return(nil).Times(1)
 
-		err := NewCreator(nil, nil, nil, nil, nil, nil, proxyAPI, nil).(*creator).
+		err := NewCreator(nil, nil, nil, nil, nil, nil, proxyAPI, nil, nil, nil, nil, nil, nil).(*creator).
 			BeforeCRUD(obj, nil)
 
 		Expect(err).ToNot(HaveOccurred())
@@ -500,7 +800,7 @@ var _ = Describe("creator_AfterCRUD", func() {
 
 			expectations()
 
-			err := NewCreator(nil, nil, pollActions, nil, nil, nil, nil, nil).(*creator).
+			err := NewCreator(nil, nil, pollActions, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
 				AfterCRUD(context.Background(), obj, "ns")
 
 			Expect(err).ToNot(HaveOccurred())
@@ -539,18 +839,88 @@ var _ = Describe("creator_AfterCRUD", func() {
 
 		pollActions.EXPECT().ForResource(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
-		err := NewCreator(nil, nil, pollActions, nil, nil, nil, nil, nil).(*creator).
+		err := NewCreator(nil, nil, pollActions, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
 			AfterCRUD(context.Background(), obj, "ns")
 
 		Expect(err).ToNot(HaveOccurred())
 	})
 })
 
-var _ = Describe("creator_CRUD", func() {
+var _ = Describe("creator_retryFailedBuild", func() {
 	var (
 		ctrl       *gomock.Controller
 		kubeClient *clients.MockClientsInterface
-		helper     *resourcehelper.MockHelper
+		obj        *unstructured.Unstructured
+		sr         *srov1beta1.SpecialResource
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubeClient = clients.NewMockClientsInterface(ctrl)
+
+		obj = &unstructured.Unstructured{}
+		obj.SetKind("BuildConfig")
+		obj.SetName("simple-kmod-driver-build")
+
+		sr = &srov1beta1.SpecialResource{}
+		sr.Spec.DriverContainer.BuildRetries = 1
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should delete the BuildConfig and count the attempt on transient failures", func() {
+		kubeClient.EXPECT().Delete(context.TODO(), obj).Times(1)
+
+		err := fmt.Errorf("wrapped: %w", &poll.BuildFailedError{Reason: "FetchSourceFailed"})
+		NewCreator(kubeClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
+			retryFailedBuild(context.TODO(), obj, sr, err)
+
+		Expect(sr.Status.BuildAttempts).To(HaveKeyWithValue("simple-kmod-driver-build", int32(1)))
+	})
+
+	It("should delete the BuildConfig as the ServiceAccount of the SpecialResource", func() {
+		sr.Spec.Namespace = "ns"
+		sr.Spec.ServiceAccountName = "installer"
+		impersonated := clients.NewMockClientsInterface(ctrl)
+
+		kubeClient.EXPECT().Impersonate("ns", "installer").Return(impersonated, nil)
+		impersonated.EXPECT().Delete(context.TODO(), obj)
+
+		err := &poll.BuildFailedError{Reason: "FetchSourceFailed"}
+		NewCreator(kubeClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
+			retryFailedBuild(context.TODO(), obj, sr, err)
+
+		Expect(sr.Status.BuildAttempts).To(HaveKeyWithValue("simple-kmod-driver-build", int32(1)))
+	})
+
+	It("should not retry once the retries are exhausted", func() {
+		sr.Status.BuildAttempts = map[string]int32{"simple-kmod-driver-build": 1}
+
+		err := &poll.BuildFailedError{Reason: "PushImageToRegistryFailed"}
+		NewCreator(kubeClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
+			retryFailedBuild(context.TODO(), obj, sr, err)
+
+		Expect(sr.Status.BuildAttempts).To(HaveKeyWithValue("simple-kmod-driver-build", int32(1)))
+	})
+
+	It("should not retry failures of the driver sources", func() {
+		err := &poll.BuildFailedError{Reason: "GenericBuildFailed"}
+		NewCreator(kubeClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
+			retryFailedBuild(context.TODO(), obj, sr, err)
+
+		Expect(sr.Status.BuildAttempts).To(BeEmpty())
+	})
+})
+
+var _ = Describe("creator_CRUD", func() {
+	var (
+		ctrl         *gomock.Controller
+		kubeClient   *clients.MockClientsInterface
+		helper       *resourcehelper.MockHelper
+		featureGates *featuregates.MockFeatureGates
+		auditLog     *audit.MockLog
 
 		c *creator
 	)
@@ -559,11 +929,14 @@ var _ = Describe("creator_CRUD", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		kubeClient = clients.NewMockClientsInterface(ctrl)
 		helper = resourcehelper.NewMockHelper(ctrl)
+		featureGates = featuregates.NewMockFeatureGates(ctrl)
 
 		scheme := runtime.NewScheme()
 		Expect(v1.AddToScheme(scheme)).To(Succeed())
 
-		c = NewCreator(kubeClient, nil, nil, nil, scheme, nil, nil, helper).(*creator)
+		auditLog = audit.NewMockLog(ctrl)
+
+		c = NewCreator(kubeClient, nil, nil, nil, scheme, nil, nil, helper, nil, featureGates, nil, nil, auditLog).(*creator)
 	})
 
 	specialResourceName := "special-resource"
@@ -575,11 +948,13 @@ var _ = Describe("creator_CRUD", func() {
 		},
 	}
 
+	// Rendered objects are labeled as owned before CRUD
 	prepareUnstructured := func(kind, name, namespace string) *unstructured.Unstructured {
 		u := &unstructured.Unstructured{}
 		u.SetKind(kind)
 		u.SetName(name)
 		u.SetNamespace(namespace)
+		u.SetLabels(map[string]string{ownedLabel: "true"})
 		return u
 	}
 
@@ -624,7 +999,8 @@ var _ = Describe("creator_CRUD", func() {
 			if isOneTimer && releaseInstalled {
 				times = 0
 			}
-			kubeClient.EXPECT().Create(gomock.Any(), gomock.Any()).Times(times)
+			kubeClient.EXPECT().Create(gomock.Any(), gomock.Any(), client.FieldOwner(FieldManager)).Times(times)
+			auditLog.EXPECT().Record(specialResourceName, audit.ObjectCreated, "Pod ns/nginx", "not found").Times(times)
 
 			Expect(c.CRUD(context.Background(), obj, releaseInstalled, &owner, specialResourceName, namespace)).To(Succeed())
 		},
@@ -633,6 +1009,82 @@ var _ = Describe("creator_CRUD", func() {
 		Entry("object is not OneTimer & release is installed = object recreation", false, true),
 		Entry("object is not OneTimer & release is not installed = object recreation", false, false))
 
+	It("should not set the owner reference of a retained object", func() {
+		obj := prepareUnstructured("PersistentVolumeClaim", "ccache", namespace)
+		obj.SetAnnotations(map[string]string{ownership.DeletionPolicyAnnotation: ownership.DeletionPolicyRetain})
+
+		helper.EXPECT().IsNamespaced(obj.GetKind()).Return(true)
+		helper.EXPECT().SetMetaData(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		kubeClient.EXPECT().
+			Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: "ccache"}, gomock.Any()).
+			Return(&k8serrors.StatusError{ErrStatus: metav1.Status{Reason: metav1.StatusReasonNotFound}})
+		helper.EXPECT().IsOneTimer(obj).Return(false, nil)
+		kubeClient.EXPECT().Create(gomock.Any(), obj, client.FieldOwner(FieldManager)).Do(func(_ context.Context, o client.Object, _ ...client.CreateOption) {
+			Expect(o.GetOwnerReferences()).To(BeEmpty())
+		})
+		auditLog.EXPECT().Record(specialResourceName, audit.ObjectCreated, gomock.Any(), gomock.Any())
+
+		Expect(c.CRUD(context.Background(), obj, false, &owner, specialResourceName, namespace)).To(Succeed())
+	})
+
+	It("should recreate a changed Pod with the Recreate update strategy", func() {
+		pollActions := poll.NewMockPollActions(ctrl)
+		c.pollActions = pollActions
+
+		obj := prepareUnstructured("Pod", "nginx", namespace)
+		obj.SetAnnotations(map[string]string{"specialresource.openshift.io/update-strategy": "Recreate"})
+
+		helper.EXPECT().IsNamespaced(obj.GetKind()).Return(true)
+		helper.EXPECT().SetMetaData(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		kubeClient.EXPECT().
+			Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: "nginx"}, gomock.Any()).
+			Return(nil)
+		helper.EXPECT().IsNotUpdateable(obj.GetKind()).Return(true)
+
+		gomock.InOrder(
+			kubeClient.EXPECT().Delete(gomock.Any(), gomock.Any()),
+			pollActions.EXPECT().ForResourceUnavailability(gomock.Any(), gomock.Any()),
+			kubeClient.EXPECT().Create(gomock.Any(), gomock.Any(), client.FieldOwner(FieldManager)).Do(func(_ context.Context, o client.Object, _ ...client.CreateOption) {
+				Expect(o.GetAnnotations()).To(HaveKey("specialresource.openshift.io/hash"))
+			}),
+			auditLog.EXPECT().Record(specialResourceName, audit.ObjectRecreated, "Pod ns/nginx", gomock.Any()),
+		)
+
+		Expect(c.CRUD(context.Background(), obj, false, &owner, specialResourceName, namespace)).To(Succeed())
+	})
+
+	It("should remove the owner reference of an existing object that is now retained", func() {
+		retainer := owner.DeepCopy()
+		retainer.SetUID("owner-uid")
+
+		controller := true
+		existing := prepareUnstructured("PersistentVolumeClaim", "ccache", namespace)
+		existing.SetLabels(map[string]string{ownedLabel: "true"})
+		existing.SetOwnerReferences([]metav1.OwnerReference{
+			{Kind: "SpecialResource", Name: retainer.GetName(), UID: retainer.GetUID(), Controller: &controller},
+		})
+
+		obj := prepareUnstructured("PersistentVolumeClaim", "ccache", namespace)
+		obj.SetAnnotations(map[string]string{ownership.DeletionPolicyAnnotation: ownership.DeletionPolicyRetain})
+
+		helper.EXPECT().IsNamespaced(obj.GetKind()).Return(true)
+		helper.EXPECT().SetMetaData(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		kubeClient.EXPECT().
+			Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: "ccache"}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+				existing.DeepCopyInto(o.(*unstructured.Unstructured))
+				return nil
+			})
+		kubeClient.EXPECT().
+			Patch(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, o client.Object, _ client.Patch, _ ...client.PatchOption) {
+				Expect(o.GetOwnerReferences()).To(BeEmpty())
+			})
+		helper.EXPECT().IsNotUpdateable(obj.GetKind()).Return(true)
+
+		Expect(c.CRUD(context.Background(), obj, false, retainer, specialResourceName, namespace)).To(Succeed())
+	})
+
 	DescribeTable("GET fails",
 		func(errReason metav1.StatusReason, expectedSubstring string) {
 			name := "nginx"
@@ -652,6 +1104,114 @@ var _ = Describe("creator_CRUD", func() {
 		Entry("other errors", metav1.StatusReasonUnauthorized, "unexpected error"),
 	)
 
+	It("should apply as the ServiceAccount of the SpecialResource", func() {
+		Expect(srov1beta1.AddToScheme(c.scheme)).To(Succeed())
+
+		sr := srov1beta1.SpecialResource{
+			ObjectMeta: metav1.ObjectMeta{Name: specialResourceName},
+			Spec:       srov1beta1.SpecialResourceSpec{Namespace: namespace, ServiceAccountName: "installer"},
+		}
+		obj := prepareUnstructured("Pod", "nginx", namespace)
+		impersonated := clients.NewMockClientsInterface(ctrl)
+
+		helper.EXPECT().IsNamespaced(obj.GetKind()).Return(true)
+		helper.EXPECT().SetMetaData(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		kubeClient.EXPECT().Impersonate(namespace, "installer").Return(impersonated, nil)
+		impersonated.EXPECT().
+			Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: "nginx"}, gomock.Any()).
+			Return(&k8serrors.StatusError{ErrStatus: metav1.Status{Reason: metav1.StatusReasonNotFound}})
+		helper.EXPECT().IsOneTimer(obj).Return(false, nil)
+		impersonated.EXPECT().Create(gomock.Any(), obj, client.FieldOwner(FieldManager)).
+			Return(&k8serrors.StatusError{ErrStatus: metav1.Status{Reason: metav1.StatusReasonForbidden}})
+
+		err := c.CRUD(context.Background(), obj, false, &sr, specialResourceName, namespace)
+		Expect(k8serrors.IsForbidden(err)).To(BeTrue())
+	})
+
+	Context("when the object was not created by SRO", func() {
+		var existing *unstructured.Unstructured
+
+		getExisting := func(obj *unstructured.Unstructured) {
+			kubeClient.EXPECT().
+				Get(gomock.Any(), types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+					existing.DeepCopyInto(o.(*unstructured.Unstructured))
+					return nil
+				})
+		}
+
+		BeforeEach(func() {
+			existing = prepareUnstructured("ConfigMap", "manual", namespace)
+			existing.SetLabels(map[string]string{"app": "manual"})
+			existing.SetResourceVersion("1")
+
+			helper.EXPECT().IsNamespaced(gomock.Any()).Return(true)
+			helper.EXPECT().SetMetaData(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		})
+
+		It("should fail without the adopt annotation", func() {
+			obj := prepareUnstructured("ConfigMap", "manual", namespace)
+			getExisting(obj)
+
+			err := c.CRUD(context.Background(), obj, false, &owner, specialResourceName, namespace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(AdoptAnnotation))
+		})
+
+		It("should label and own it, then update it with the adopt annotation", func() {
+			adopter := owner.DeepCopy()
+			adopter.SetUID("owner-uid")
+			adopter.SetAnnotations(map[string]string{AdoptAnnotation: "true"})
+
+			obj := prepareUnstructured("ConfigMap", "manual", namespace)
+			getExisting(obj)
+
+			helper.EXPECT().IsNotUpdateable(obj.GetKind()).Return(false)
+			featureGates.EXPECT().Enabled(featuregates.ServerSideApply, adopter).Return(false)
+
+			gomock.InOrder(
+				kubeClient.EXPECT().
+					Patch(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, o client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						Expect(o.GetLabels()).To(Equal(map[string]string{"app": "manual", ownedLabel: "true"}))
+						Expect(o.GetOwnerReferences()).To(HaveLen(1))
+						Expect(o.GetOwnerReferences()[0].UID).To(Equal(adopter.GetUID()))
+						o.SetResourceVersion("2")
+						return nil
+					}),
+				kubeClient.EXPECT().Event(adopter, v1.EventTypeNormal, "Adopted", gomock.Any()),
+				auditLog.EXPECT().Record(specialResourceName, audit.ObjectAdopted, "ConfigMap ns/manual", AdoptAnnotation),
+				helper.EXPECT().
+					UpdateResourceVersion(gomock.Any(), gomock.Any()).
+					Do(func(_, found *unstructured.Unstructured) {
+						Expect(found.GetResourceVersion()).To(Equal("2"))
+					}),
+				kubeClient.EXPECT().Update(gomock.Any(), gomock.Any(), client.FieldOwner(FieldManager)),
+				auditLog.EXPECT().Record(specialResourceName, audit.ObjectUpdated, "ConfigMap ns/manual", gomock.Any()),
+			)
+
+			Expect(c.CRUD(context.Background(), obj, false, adopter, specialResourceName, namespace)).To(Succeed())
+		})
+
+		It("should not adopt an object controlled by another owner", func() {
+			adopter := owner.DeepCopy()
+			adopter.SetUID("owner-uid")
+			adopter.SetAnnotations(map[string]string{AdoptAnnotation: "true"})
+
+			controller := true
+			existing.SetOwnerReferences([]metav1.OwnerReference{
+				{Kind: "Deployment", Name: "other", UID: "other-uid", Controller: &controller},
+			})
+
+			obj := prepareUnstructured("ConfigMap", "manual", namespace)
+			getExisting(obj)
+
+			kubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			Expect(c.CRUD(context.Background(), obj, false, adopter, specialResourceName, namespace)).NotTo(Succeed())
+		})
+	})
+
 	DescribeTable("updating the object",
 		func(mockSetups func(*unstructured.Unstructured), assert func()) {
 			name := "nginx"
@@ -709,14 +1269,256 @@ var _ = Describe("creator_CRUD", func() {
 					})
 
 				helper.EXPECT().IsNotUpdateable(obj.GetKind()).Return(false)
+				featureGates.EXPECT().Enabled(featuregates.ServerSideApply, &owner).Return(false)
 				helper.EXPECT().UpdateResourceVersion(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			func() {
-				kubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.Object) error {
+				kubeClient.EXPECT().Update(gomock.Any(), gomock.Any(), client.FieldOwner(FieldManager)).DoAndReturn(func(_ context.Context, o client.Object, _ ...client.UpdateOption) error {
 					Expect(o.GetAnnotations()).To(HaveKey("specialresource.openshift.io/hash"))
 					return nil
 				}).Times(1)
+				auditLog.EXPECT().Record(specialResourceName, audit.ObjectUpdated, "Pod ns/nginx", "hash changed")
+			},
+		),
+		Entry("will apply the rendered fields with ServerSideApply",
+			func(obj *unstructured.Unstructured) {
+				kubeClient.EXPECT().
+					Get(gomock.Any(), types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ client.ObjectKey, o client.Object) error {
+						u := o.(*unstructured.Unstructured)
+						obj.DeepCopyInto(u)
+						u.SetResourceVersion("42")
+						return nil
+					})
+
+				helper.EXPECT().IsNotUpdateable(obj.GetKind()).Return(false)
+				featureGates.EXPECT().Enabled(featuregates.ServerSideApply, &owner).Return(true)
+			},
+			func() {
+				kubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+				kubeClient.EXPECT().
+					Patch(gomock.Any(), gomock.Any(), client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership).
+					DoAndReturn(func(_ context.Context, o client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						Expect(o.GetAnnotations()).To(HaveKey("specialresource.openshift.io/hash"))
+						Expect(o.GetResourceVersion()).To(BeEmpty())
+						return nil
+					})
+				auditLog.EXPECT().Record(specialResourceName, audit.ObjectUpdated, "Pod ns/nginx", "hash changed, server-side apply")
 			},
 		),
 	)
 })
+
+var _ = Describe("creator_previewNodeSelection", func() {
+	var (
+		ctrl       *gomock.Controller
+		kubeClient *clients.MockClientsInterface
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubeClient = clients.NewMockClientsInterface(ctrl)
+	})
+
+	getDaemonSet := func(nodeSelector map[string]string) *unstructured.Unstructured {
+		ds := &unstructured.Unstructured{}
+		ds.SetAPIVersion("apps/v1")
+		ds.SetKind("DaemonSet")
+		ds.SetNamespace("ns")
+		ds.SetName("driver")
+		Expect(unstructured.SetNestedStringMap(ds.Object, nodeSelector, "spec", "template", "spec", "nodeSelector")).To(Succeed())
+
+		return ds
+	}
+
+	listNodes := func() *gomock.Call {
+		return kubeClient.EXPECT().List(context.TODO(), &v1.NodeList{}).
+			DoAndReturn(func(_ context.Context, nodes *v1.NodeList, _ ...client.ListOption) error {
+				nodes.Items = []v1.Node{
+					{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"gpu": "true"}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"gpu": "true"}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "node3"}},
+				}
+				return nil
+			})
+	}
+
+	It("should record the number of nodes matching the workload", func() {
+		sr := &srov1beta1.SpecialResource{}
+
+		listNodes()
+
+		NewCreator(kubeClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
+			previewNodeSelection(context.TODO(), getDaemonSet(map[string]string{"gpu": "true"}), sr)
+
+		Expect(sr.Status.MatchingNodes).To(Equal(map[string]int32{"DaemonSet ns/driver": 2}))
+	})
+
+	It("should warn if no node matches the workload", func() {
+		sr := &srov1beta1.SpecialResource{}
+
+		gomock.InOrder(
+			listNodes(),
+			kubeClient.EXPECT().Event(sr, v1.EventTypeWarning, "NoMatchingNodes", gomock.Any()),
+		)
+
+		NewCreator(kubeClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator).
+			previewNodeSelection(context.TODO(), getDaemonSet(map[string]string{"gpu": "false"}), sr)
+
+		Expect(sr.Status.MatchingNodes).To(Equal(map[string]int32{"DaemonSet ns/driver": 0}))
+	})
+})
+
+var _ = Describe("creator_AppliedObjects", func() {
+	getPod := func(name string) *unstructured.Unstructured {
+		pod := &unstructured.Unstructured{}
+		pod.SetAPIVersion("v1")
+		pod.SetKind("Pod")
+		pod.SetNamespace("ns")
+		pod.SetName(name)
+		pod.SetUID(kubetypes.UID("uid-" + name))
+
+		return pod
+	}
+
+	It("should return the objects applied since the last call", func() {
+		c := NewCreator(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator)
+
+		c.addApplied("special-resource", getPod("driver"))
+
+		Expect(c.AppliedObjects("special-resource")).To(Equal([]srov1beta1.ObjectReference{
+			{APIVersion: "v1", Kind: "Pod", Namespace: "ns", Name: "driver", UID: "uid-driver"},
+		}))
+		Expect(c.AppliedObjects("special-resource")).To(BeEmpty())
+	})
+
+	It("should list at most appliedObjectsLimit objects", func() {
+		c := NewCreator(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator)
+
+		for i := 0; i <= appliedObjectsLimit; i++ {
+			c.addApplied("special-resource", getPod(fmt.Sprintf("driver-%d", i)))
+		}
+
+		Expect(c.AppliedObjects("special-resource")).To(HaveLen(appliedObjectsLimit))
+	})
+})
+
+var _ = Describe("creator_monitoringUnavailable", func() {
+	var (
+		ctrl       *gomock.Controller
+		kubeClient *clients.MockClientsInterface
+		c          *creator
+		sm         *unstructured.Unstructured
+	)
+
+	const userWorkload = "enableUserWorkload: true\n"
+
+	serviceMonitors := schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"}
+	monitoringConfig := types.NamespacedName{Namespace: "openshift-monitoring", Name: "cluster-monitoring-config"}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubeClient = clients.NewMockClientsInterface(ctrl)
+
+		c = NewCreator(kubeClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator)
+
+		sm = &unstructured.Unstructured{}
+		sm.SetAPIVersion("monitoring.coreos.com/v1")
+		sm.SetKind("ServiceMonitor")
+		sm.SetNamespace("driver")
+		sm.SetName("metrics")
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should not check the other objects", func() {
+		pod := &unstructured.Unstructured{}
+		pod.SetAPIVersion("v1")
+		pod.SetKind("Pod")
+
+		reason, err := c.monitoringUnavailable(context.TODO(), pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("should skip the objects without the Prometheus Operator CRDs", func() {
+		kubeClient.EXPECT().HasResource(serviceMonitors).Return(false, nil)
+
+		reason, err := c.monitoringUnavailable(context.TODO(), sm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal("the Prometheus Operator CRDs are not installed"))
+	})
+
+	It("should apply the objects without the OpenShift monitoring stack", func() {
+		gomock.InOrder(
+			kubeClient.EXPECT().HasResource(serviceMonitors).Return(true, nil),
+			kubeClient.EXPECT().
+				GetNamespace(context.TODO(), "openshift-monitoring", metav1.GetOptions{}).
+				Return(nil, k8serrors.NewNotFound(v1.Resource("namespace"), "openshift-monitoring")),
+		)
+
+		reason, err := c.monitoringUnavailable(context.TODO(), sm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("should apply the objects of the namespaces of the platform monitoring", func() {
+		ns := &v1.Namespace{}
+		ns.SetLabels(map[string]string{"openshift.io/cluster-monitoring": "true"})
+
+		gomock.InOrder(
+			kubeClient.EXPECT().HasResource(serviceMonitors).Return(true, nil),
+			kubeClient.EXPECT().GetNamespace(context.TODO(), "openshift-monitoring", metav1.GetOptions{}).Return(&v1.Namespace{}, nil),
+			kubeClient.EXPECT().GetNamespace(context.TODO(), "driver", metav1.GetOptions{}).Return(ns, nil),
+		)
+
+		reason, err := c.monitoringUnavailable(context.TODO(), sm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+	})
+
+	DescribeTable("should follow the monitoring of user-defined projects",
+		func(config *v1.ConfigMap, expected string) {
+			gomock.InOrder(
+				kubeClient.EXPECT().HasResource(serviceMonitors).Return(true, nil),
+				kubeClient.EXPECT().GetNamespace(context.TODO(), "openshift-monitoring", metav1.GetOptions{}).Return(&v1.Namespace{}, nil),
+				kubeClient.EXPECT().GetNamespace(context.TODO(), "driver", metav1.GetOptions{}).Return(&v1.Namespace{}, nil),
+				kubeClient.EXPECT().
+					Get(context.TODO(), monitoringConfig, gomock.AssignableToTypeOf(&v1.ConfigMap{})).
+					DoAndReturn(func(_ context.Context, _ types.NamespacedName, cm *v1.ConfigMap) error {
+						if config == nil {
+							return k8serrors.NewNotFound(v1.Resource("configmap"), monitoringConfig.Name)
+						}
+						config.DeepCopyInto(cm)
+						return nil
+					}),
+			)
+
+			reason, err := c.monitoringUnavailable(context.TODO(), sm)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reason).To(Equal(expected))
+		},
+		Entry("enabled", &v1.ConfigMap{Data: map[string]string{"config.yaml": userWorkload}}, ""),
+		Entry("disabled", &v1.ConfigMap{Data: map[string]string{"config.yaml": "prometheusK8s: {}\n"}}, "the monitoring of user-defined projects is not enabled"),
+		Entry("without a config", nil, "the monitoring of user-defined projects is not enabled"),
+	)
+})
+
+var _ = Describe("creator_SkippedObjects", func() {
+	It("should return the skipped objects once", func() {
+		c := NewCreator(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*creator)
+
+		sm := &unstructured.Unstructured{}
+		sm.SetKind("ServiceMonitor")
+		sm.SetNamespace("driver")
+		sm.SetName("metrics")
+
+		c.addSkipped("sr", sm, "reason")
+		c.addSkipped("sr", sm, "reason")
+
+		Expect(c.SkippedObjects("sr")).To(Equal([]string{"ServiceMonitor driver/metrics: reason"}))
+		Expect(c.SkippedObjects("sr")).To(BeEmpty())
+	})
+})
@@ -3,12 +3,15 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/go-logr/logr"
 	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/assets"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
 	"github.com/openshift-psap/special-resource-operator/pkg/cluster"
+	"github.com/openshift-psap/special-resource-operator/pkg/csi"
 	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
 
 	"github.com/openshift-psap/special-resource-operator/pkg/proxy"
@@ -17,6 +20,7 @@ import (
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
@@ -32,23 +36,51 @@ type ResourceGroupName struct {
 	CSIDriver              string `json:"csiDriver"`
 }
 
+const (
+	nfdPCILabelPrefix       = "feature.node.kubernetes.io/pci-"
+	inventoryPCILabelPrefix = assets.InventoryLabelPrefix + "pci-"
+	pciLabelSuffix          = ".present"
+	nfdNUMALabel            = "feature.node.kubernetes.io/memory-numa"
+)
+
+// NodeHardware holds the hardware facts NFD advertises for a single node.
+type NodeHardware struct {
+	// PCIDevices are the PCI device identifiers from the pci-<id>.present labels set by NFD
+	// or by the built-in device-inventory state, e.g. 0300_10de.
+	PCIDevices []string `json:"pciDevices,omitempty"`
+	// NUMA is true if NFD detected more than one NUMA node.
+	NUMA bool `json:"numa,omitempty"`
+}
+
 type RuntimeInformation struct {
-	Kind                      string                         `json:"kind"`
-	OperatingSystemMajor      string                         `json:"operatingSystemMajor"`
-	OperatingSystemMajorMinor string                         `json:"operatingSystemMajorMinor"`
-	OperatingSystemDecimal    string                         `json:"operatingSystemDecimal"`
-	KernelFullVersion         string                         `json:"kernelFullVersion"`
-	KernelPatchVersion        string                         `json:"kernelPatchVersion"`
-	DriverToolkitImage        string                         `json:"driverToolkitImage"`
-	Platform                  string                         `json:"platform"`
-	ClusterVersion            string                         `json:"clusterVersion"`
-	ClusterVersionMajorMinor  string                         `json:"clusterVersionMajorMinor"`
-	ClusterUpgradeInfo        map[string]upgrade.NodeVersion `json:"clusterUpgradeInfo"`
-	PushSecretName            string                         `json:"pushSecretName"`
-	OSImageURL                string                         `json:"osImageURL"`
-	Proxy                     proxy.Configuration            `json:"proxy"`
-	GroupName                 ResourceGroupName              `json:"groupName"`
-	SpecialResource           srov1beta1.SpecialResource     `json:"specialresource"`
+	Kind                      string                               `json:"kind"`
+	OperatingSystemMajor      string                               `json:"operatingSystemMajor"`
+	OperatingSystemMajorMinor string                               `json:"operatingSystemMajorMinor"`
+	OperatingSystemDecimal    string                               `json:"operatingSystemDecimal"`
+	KernelFullVersion         string                               `json:"kernelFullVersion"`
+	KernelPatchVersion        string                               `json:"kernelPatchVersion"`
+	KernelVersion             kernel.Version                       `json:"kernelVersion"`
+	DriverToolkitImage        string                               `json:"driverToolkitImage"`
+	Platform                  string                               `json:"platform"`
+	ClusterVersion            string                               `json:"clusterVersion"`
+	ClusterVersionMajorMinor  string                               `json:"clusterVersionMajorMinor"`
+	ClusterUpgradeInfo        map[string]upgrade.NodeVersion       `json:"clusterUpgradeInfo"`
+	NodeHardware              map[string]NodeHardware              `json:"nodeHardware"`
+	NodesByKernel             map[string]int                       `json:"nodesByKernel"`
+	NodesByLabel              map[string]int                       `json:"nodesByLabel"`
+	PushSecretName            string                               `json:"pushSecretName"`
+	OSImageURL                string                               `json:"osImageURL"`
+	MachineConfigPools        map[string]cluster.MachineConfigPool `json:"machineConfigPools"`
+	Proxy                     proxy.Configuration                  `json:"proxy"`
+	CSISidecars               csi.Sidecars                         `json:"csiSidecars"`
+	GroupName                 ResourceGroupName                    `json:"groupName"`
+	SpecialResource           srov1beta1.SpecialResource           `json:"specialresource"`
+}
+
+// Values returns the runtime information as chart values. Charts rely on its
+// keys, they are listed as runtime variables in docs/recipes.md.
+func (info *RuntimeInformation) Values() (map[string]interface{}, error) {
+	return k8sruntime.DefaultUnstructuredConverter.ToUnstructured(info)
 }
 
 //go:generate mockgen -source=runtime.go -package=runtime -destination=mock_runtime_api.go
@@ -89,14 +121,19 @@ func (rt *runtime) LogRuntimeInformation(info *RuntimeInformation) {
 		"OperatingSystemDecimal", info.OperatingSystemDecimal,
 		"KernelFullVersion", info.KernelFullVersion,
 		"KernelPatchVersion", info.KernelPatchVersion,
+		"KernelVersion", info.KernelVersion,
 		"DriverToolkitImage", info.DriverToolkitImage,
 		"Platform", info.Platform,
 		"ClusterVersion", info.ClusterVersion,
 		"ClusterVersionMajorMinor", info.ClusterVersionMajorMinor,
 		"ClusterUpgradeInfo", info.ClusterUpgradeInfo,
+		"NodeHardware", info.NodeHardware,
+		"NodesByKernel", info.NodesByKernel,
 		"PushSecretName", info.PushSecretName,
 		"OSImageURL", info.OSImageURL,
-		"Proxy", info.Proxy)
+		"MachineConfigPools", info.MachineConfigPools,
+		"Proxy", info.Proxy,
+		"CSISidecars", info.CSISidecars)
 }
 
 func (rt *runtime) GetRuntimeInformation(ctx context.Context, sr *srov1beta1.SpecialResource) (*RuntimeInformation, error) {
@@ -108,14 +145,20 @@ func (rt *runtime) GetRuntimeInformation(ctx context.Context, sr *srov1beta1.Spe
 		OperatingSystemDecimal:    "",
 		KernelFullVersion:         "",
 		KernelPatchVersion:        "",
+		KernelVersion:             kernel.Version{},
 		DriverToolkitImage:        "",
 		Platform:                  "",
 		ClusterVersion:            "",
 		ClusterVersionMajorMinor:  "",
 		ClusterUpgradeInfo:        make(map[string]upgrade.NodeVersion),
+		NodeHardware:              make(map[string]NodeHardware),
+		NodesByKernel:             make(map[string]int),
+		NodesByLabel:              make(map[string]int),
 		PushSecretName:            "",
 		OSImageURL:                "",
+		MachineConfigPools:        make(map[string]cluster.MachineConfigPool),
 		Proxy:                     proxy.Configuration{},
+		CSISidecars:               csi.Sidecars{},
 		GroupName:                 ResourceGroupName{DriverBuild: "driver-build", DriverContainer: "driver-container", RuntimeEnablement: "runtime-enablement", DevicePlugin: "device-plugin", DeviceMonitoring: "device-monitoring", DeviceDashboard: "device-dashboard", DeviceFeatureDiscovery: "device-feature-discovery", CSIDriver: "csi-driver"},
 	}
 
@@ -124,6 +167,13 @@ func (rt *runtime) GetRuntimeInformation(ctx context.Context, sr *srov1beta1.Spe
 		return nil, fmt.Errorf("failed to get nodes list during getRuntimeInformation: %w", err)
 	}
 
+	if sr.Spec.ReadyNodesOnly {
+		nodeList = rt.readyNodes(nodeList)
+		if len(nodeList.Items) == 0 {
+			return nil, errors.New("no ready node matches the nodeSelector")
+		}
+	}
+
 	info.OperatingSystemMajor, info.OperatingSystemMajorMinor, info.OperatingSystemDecimal, err = rt.clusterAPI.OperatingSystem(nodeList)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get operating system: %w", err)
@@ -139,6 +189,11 @@ func (rt *runtime) GetRuntimeInformation(ctx context.Context, sr *srov1beta1.Spe
 		return nil, fmt.Errorf("failed to get kernel patch version: %w", err)
 	}
 
+	info.KernelVersion, err = rt.kernelAPI.ParseVersion(info.KernelFullVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kernel version: %w", err)
+	}
+
 	// Only want to initialize the platform once.
 	if info.Platform == "" {
 		info.Platform, err = rt.kubeClient.GetPlatform()
@@ -157,6 +212,21 @@ func (rt *runtime) GetRuntimeInformation(ctx context.Context, sr *srov1beta1.Spe
 		return nil, fmt.Errorf("failed to get upgrade info: %w", err)
 	}
 
+	// Facts are read from the labels of the nodes we already have, no extra API calls.
+	info.NodeHardware = getNodeHardware(nodeList, sr.Spec.HardwareFacts)
+	info.NodesByKernel, info.NodesByLabel = getNodeCounts(nodeList)
+
+	// The CSI sidecars have to work with the kubelet of every node
+	kubernetesVersion, err := csi.KubernetesVersion(nodeList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes version: %w", err)
+	}
+
+	info.CSISidecars, err = csi.SidecarsFor(kubernetesVersion, sr.Spec.Set.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CSI sidecars: %w", err)
+	}
+
 	info.PushSecretName, err = rt.getPushSecretName(ctx, sr, info.Platform)
 	utils.WarnOnError(err)
 
@@ -165,6 +235,14 @@ func (rt *runtime) GetRuntimeInformation(ctx context.Context, sr *srov1beta1.Spe
 		return nil, fmt.Errorf("failed to get OSImageURL: %w", err)
 	}
 
+	pools, err := rt.clusterAPI.MachineConfigPools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the MachineConfigPools: %w", err)
+	}
+	if pools != nil {
+		info.MachineConfigPools = pools
+	}
+
 	info.Proxy, err = rt.proxyAPI.ClusterConfiguration(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Proxy Configuration: %w", err)
@@ -175,6 +253,21 @@ func (rt *runtime) GetRuntimeInformation(ctx context.Context, sr *srov1beta1.Spe
 	return info, nil
 }
 
+// readyNodes returns the nodes of nodeList that are ready and schedulable.
+func (rt *runtime) readyNodes(nodeList *corev1.NodeList) *corev1.NodeList {
+	ready := &corev1.NodeList{}
+
+	for _, node := range nodeList.Items {
+		if !utils.NodeReady(&node) {
+			rt.log.Info("Ignoring a node that is not ready", "node", node.GetName())
+			continue
+		}
+		ready.Items = append(ready.Items, node)
+	}
+
+	return ready
+}
+
 func (rt *runtime) getPushSecretName(ctx context.Context, sr *srov1beta1.SpecialResource, platform string) (string, error) {
 	secrets := &corev1.SecretList{}
 	err := rt.kubeClient.List(ctx, secrets, client.InNamespace(sr.Spec.Namespace))
@@ -189,3 +282,67 @@ func (rt *runtime) getPushSecretName(ctx context.Context, sr *srov1beta1.Special
 	}
 	return "", errors.New("cannot find Secret builder-dockercfg")
 }
+
+func getNodeHardware(nodeList *corev1.NodeList, facts []srov1beta1.HardwareFact) map[string]NodeHardware {
+	hardware := make(map[string]NodeHardware)
+	if len(facts) == 0 {
+		return hardware
+	}
+
+	var collectPCI, collectNUMA bool
+	for _, fact := range facts {
+		switch fact {
+		case srov1beta1.HardwareFactPCI:
+			collectPCI = true
+		case srov1beta1.HardwareFactNUMA:
+			collectNUMA = true
+		}
+	}
+
+	for _, node := range nodeList.Items {
+		nh := NodeHardware{}
+		labels := node.GetLabels()
+		if collectPCI {
+			devices := make(map[string]bool)
+			for label, value := range labels {
+				if value != "true" || !strings.HasSuffix(label, pciLabelSuffix) {
+					continue
+				}
+				for _, prefix := range []string{nfdPCILabelPrefix, inventoryPCILabelPrefix} {
+					if strings.HasPrefix(label, prefix) {
+						devices[strings.TrimSuffix(strings.TrimPrefix(label, prefix), pciLabelSuffix)] = true
+					}
+				}
+			}
+			for device := range devices {
+				nh.PCIDevices = append(nh.PCIDevices, device)
+			}
+			sort.Strings(nh.PCIDevices)
+		}
+		if collectNUMA {
+			nh.NUMA = labels[nfdNUMALabel] == "true"
+		}
+		hardware[node.GetName()] = nh
+	}
+
+	return hardware
+}
+
+// getNodeCounts counts the selected nodes per kernel version, and per label as
+// <key>=<value>, so that charts can size their workloads or skip the states no
+// node needs.
+func getNodeCounts(nodeList *corev1.NodeList) (map[string]int, map[string]int) {
+	byKernel := make(map[string]int)
+	byLabel := make(map[string]int)
+
+	for _, node := range nodeList.Items {
+		if kernelVersion := node.Status.NodeInfo.KernelVersion; kernelVersion != "" {
+			byKernel[kernelVersion]++
+		}
+		for key, value := range node.GetLabels() {
+			byLabel[key+"="+value]++
+		}
+	}
+
+	return byKernel, byLabel
+}
@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"testing"
@@ -12,6 +13,7 @@ import (
 
 	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
 	"github.com/openshift-psap/special-resource-operator/pkg/cluster"
@@ -22,6 +24,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
+var randomError = errors.New("random error")
+
 func TestPkgRutime(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Runtime Suite")
@@ -136,17 +140,22 @@ var _ = Describe("GetRuntimeInformation", func() {
 		osDecimal := "osDecimal"
 		kernelFullVersion := "kernelFullVersion"
 		kernelPatchVersion := "kernelPatchVersion"
+		kernelVersion := kernel.Version{Major: 4, Minor: 18, Build: "305"}
 		platform := "platform"
 		clusterVersion := "clusterVersion"
 		clusterVersionMajorMinor := "clusterMajorMinor"
 		clusterUpgradeInfo := map[string]upgrade.NodeVersion{"key": {}}
 		osImageURL := "osImageURL"
+		machineConfigPools := map[string]cluster.MachineConfigPool{
+			"worker": {OSImageURL: "workerOSImageURL", RenderedConfig: "rendered-worker", Paused: true},
+		}
 		proxyConfiguration := proxy.Configuration{}
 
 		mockKubeClient.EXPECT().GetNodesByLabels(gomock.Any(), sr.Spec.NodeSelector).Return(&nodeList, nil)
 		mockCluster.EXPECT().OperatingSystem(&nodeList).Return(osMajor, osMajorMinor, osDecimal, nil)
 		mockKernel.EXPECT().FullVersion(&nodeList).Return(kernelFullVersion, nil)
 		mockKernel.EXPECT().PatchVersion(kernelFullVersion).Return(kernelPatchVersion, nil)
+		mockKernel.EXPECT().ParseVersion(kernelFullVersion).Return(kernelVersion, nil)
 		mockKubeClient.EXPECT().GetPlatform().Return(platform, nil)
 		mockCluster.EXPECT().Version(gomock.Any()).Return(clusterVersion, clusterVersionMajorMinor, nil)
 		mockClusterInfo.EXPECT().GetClusterInfo(gomock.Any(), &nodeList).Return(clusterUpgradeInfo, nil)
@@ -159,6 +168,7 @@ var _ = Describe("GetRuntimeInformation", func() {
 				return nil
 			})
 		mockCluster.EXPECT().OSImageURL(gomock.Any()).Return(osImageURL, nil)
+		mockCluster.EXPECT().MachineConfigPools(gomock.Any()).Return(machineConfigPools, nil)
 		mockProxy.EXPECT().ClusterConfiguration(gomock.Any()).Return(proxyConfiguration, nil)
 
 		runInfo, err := runtimeStruct.GetRuntimeInformation(context.TODO(), sr)
@@ -168,12 +178,172 @@ var _ = Describe("GetRuntimeInformation", func() {
 		Expect(runInfo.OperatingSystemDecimal).To(Equal(osDecimal))
 		Expect(runInfo.KernelFullVersion).To(Equal(kernelFullVersion))
 		Expect(runInfo.KernelPatchVersion).To(Equal(kernelPatchVersion))
+		Expect(runInfo.KernelVersion).To(Equal(kernelVersion))
 		Expect(runInfo.Platform).To(Equal(platform))
 		Expect(runInfo.ClusterVersion).To(Equal(clusterVersion))
 		Expect(runInfo.ClusterVersionMajorMinor).To(Equal(clusterVersionMajorMinor))
 		Expect(runInfo.ClusterUpgradeInfo).To(Equal(clusterUpgradeInfo))
 		Expect(runInfo.PushSecretName).To(Equal("builder-dockercfg"))
 		Expect(runInfo.OSImageURL).To(Equal(osImageURL))
+		Expect(runInfo.MachineConfigPools).To(Equal(machineConfigPools))
 		Expect(runInfo.Proxy).To(Equal(proxyConfiguration))
 	})
+
+	It("should only use the ready nodes when asked to", func() {
+		sr := &srov1beta1.SpecialResource{}
+		sr.Spec.NodeSelector = map[string]string{"key": "value"}
+		sr.Spec.ReadyNodesOnly = true
+
+		ready := v1.NodeCondition{Type: v1.NodeReady, Status: v1.ConditionTrue}
+		notReady := v1.NodeCondition{Type: v1.NodeReady, Status: v1.ConditionFalse}
+
+		readyNode := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "ready"}}
+		readyNode.Status.Conditions = []v1.NodeCondition{ready}
+		notReadyNode := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "not-ready"}}
+		notReadyNode.Status.Conditions = []v1.NodeCondition{notReady}
+		cordonedNode := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cordoned"}}
+		cordonedNode.Spec.Unschedulable = true
+		cordonedNode.Status.Conditions = []v1.NodeCondition{ready}
+
+		nodeList := v1.NodeList{Items: []v1.Node{notReadyNode, readyNode, cordonedNode}}
+
+		mockKubeClient.EXPECT().GetNodesByLabels(gomock.Any(), sr.Spec.NodeSelector).Return(&nodeList, nil)
+		mockCluster.EXPECT().OperatingSystem(&v1.NodeList{Items: []v1.Node{readyNode}}).Return("", "", "", randomError)
+
+		_, err := runtimeStruct.GetRuntimeInformation(context.TODO(), sr)
+		Expect(errors.Is(err, randomError)).To(BeTrue())
+	})
+
+	It("should return an error when no node is ready", func() {
+		sr := &srov1beta1.SpecialResource{}
+		sr.Spec.ReadyNodesOnly = true
+
+		cordonedNode := v1.Node{}
+		cordonedNode.Spec.Unschedulable = true
+		cordonedNode.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}
+
+		mockKubeClient.EXPECT().GetNodesByLabels(gomock.Any(), gomock.Any()).Return(&v1.NodeList{Items: []v1.Node{cordonedNode}}, nil)
+
+		_, err := runtimeStruct.GetRuntimeInformation(context.TODO(), sr)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("getNodeHardware", func() {
+	nodeList := &v1.NodeList{
+		Items: []v1.Node{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node1",
+					Labels: map[string]string{
+						"feature.node.kubernetes.io/pci-10de.present":                  "true",
+						"feature.node.kubernetes.io/pci-0300_1a03.present":             "true",
+						"inventory.specialresource.openshift.io/pci-0300_1a03.present": "true",
+						"inventory.specialresource.openshift.io/pci-0302_10de.present": "true",
+						"feature.node.kubernetes.io/pci-15b3.sriov.capable":            "true",
+						"feature.node.kubernetes.io/memory-numa":                       "true",
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+			},
+		},
+	}
+
+	It("collects nothing when no facts are selected", func() {
+		Expect(getNodeHardware(nodeList, nil)).To(BeEmpty())
+	})
+
+	It("collects only the selected facts", func() {
+		res := getNodeHardware(nodeList, []srov1beta1.HardwareFact{srov1beta1.HardwareFactPCI})
+		Expect(res).To(HaveLen(2))
+		Expect(res["node1"].PCIDevices).To(Equal([]string{"0300_1a03", "0302_10de", "10de"}))
+		Expect(res["node1"].NUMA).To(BeFalse())
+		Expect(res["node2"].PCIDevices).To(BeEmpty())
+	})
+
+	It("collects NUMA", func() {
+		res := getNodeHardware(nodeList, []srov1beta1.HardwareFact{srov1beta1.HardwareFactNUMA})
+		Expect(res["node1"].NUMA).To(BeTrue())
+		Expect(res["node1"].PCIDevices).To(BeEmpty())
+		Expect(res["node2"].NUMA).To(BeFalse())
+	})
+})
+
+var _ = Describe("getNodeCounts", func() {
+	It("counts the nodes per kernel and label", func() {
+		node := func(name, kernelVersion string, labels map[string]string) v1.Node {
+			return v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+				Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KernelVersion: kernelVersion}},
+			}
+		}
+
+		nodeList := &v1.NodeList{
+			Items: []v1.Node{
+				node("node1", "4.18.0-305.el8.x86_64", map[string]string{"gpu": "true", "zone": "a"}),
+				node("node2", "4.18.0-305.el8.x86_64", map[string]string{"gpu": "true", "zone": "b"}),
+				node("node3", "4.18.0-348.el8.x86_64", map[string]string{"zone": "a"}),
+				node("node4", "", nil),
+			},
+		}
+
+		byKernel, byLabel := getNodeCounts(nodeList)
+		Expect(byKernel).To(Equal(map[string]int{
+			"4.18.0-305.el8.x86_64": 2,
+			"4.18.0-348.el8.x86_64": 1,
+		}))
+		Expect(byLabel).To(Equal(map[string]int{
+			"gpu=true": 2,
+			"zone=a":   2,
+			"zone=b":   1,
+		}))
+	})
+})
+
+var _ = Describe("Values", func() {
+	It("returns exactly the keys charts rely on", func() {
+		info := &RuntimeInformation{
+			ClusterUpgradeInfo: make(map[string]upgrade.NodeVersion),
+			NodeHardware:       make(map[string]NodeHardware),
+			NodesByKernel:      make(map[string]int),
+			NodesByLabel:       make(map[string]int),
+			MachineConfigPools: make(map[string]cluster.MachineConfigPool),
+		}
+
+		values, err := info.Values()
+		Expect(err).NotTo(HaveOccurred())
+
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+
+		Expect(keys).To(ConsistOf(
+			"kind",
+			"operatingSystemMajor",
+			"operatingSystemMajorMinor",
+			"operatingSystemDecimal",
+			"kernelFullVersion",
+			"kernelPatchVersion",
+			"kernelVersion",
+			"driverToolkitImage",
+			"platform",
+			"clusterVersion",
+			"clusterVersionMajorMinor",
+			"clusterUpgradeInfo",
+			"nodeHardware",
+			"nodesByKernel",
+			"nodesByLabel",
+			"pushSecretName",
+			"osImageURL",
+			"machineConfigPools",
+			"proxy",
+			"csiSidecars",
+			"groupName",
+			"specialresource",
+		))
+		Expect(values["groupName"]).To(HaveLen(8))
+	})
 })
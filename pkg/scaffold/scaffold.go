@@ -0,0 +1,177 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// The recipes of the catalog are Helm charts, their own parameters use other
+// delimiters than the Helm templates.
+const (
+	leftDelim  = "[["
+	rightDelim = "]]"
+
+	// crFile is the SpecialResource of a recipe, it is written next to the
+	// chart under the name of the recipe.
+	crFile = "specialresource.yaml"
+)
+
+//go:embed catalog
+var catalog embed.FS
+
+// Options are the parameters of a new recipe.
+type Options struct {
+	// Template is the skeleton of the catalog the recipe starts from.
+	Template string
+	// Name is the name of the recipe, of its chart and of its SpecialResource.
+	Name string
+	// Image is the repository of the driver-container image, tagged with the
+	// kernel version for every kernel of the cluster.
+	Image string
+	// Module is the kernel module loaded by the driver-container, defaults to
+	// Name.
+	Module string
+}
+
+// File is a file of a new recipe, Path is relative to the output directory.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+//go:generate mockgen -source=scaffold.go -package=scaffold -destination=mock_scaffold_api.go
+
+// Scaffold generates starter recipes from the skeletons embedded in the
+// operator: a chart and the SpecialResource deploying it.
+type Scaffold interface {
+	Generate(opts Options) ([]File, error)
+	Templates() []string
+}
+
+type scaffold struct {
+	catalog fs.FS
+}
+
+func New() Scaffold {
+	sub, err := fs.Sub(catalog, "catalog")
+	if err != nil {
+		panic(err)
+	}
+
+	return &scaffold{catalog: sub}
+}
+
+// Templates returns the names of the skeletons of the catalog.
+func (s *scaffold) Templates() []string {
+	entries, err := fs.ReadDir(s.catalog, ".")
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Generate returns the files of a new recipe: its chart in <name>-0.0.1, and
+// the SpecialResource deploying the chart in <name>-0.0.1/<name>.yaml, the
+// layout of the example charts.
+func (s *scaffold) Generate(opts Options) ([]File, error) {
+	if opts.Module == "" {
+		opts.Module = opts.Name
+	}
+
+	if err := s.validate(opts); err != nil {
+		return nil, err
+	}
+
+	chartDir := opts.Name + "-0.0.1"
+
+	var files []File
+
+	err := fs.WalkDir(s.catalog, opts.Template, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		raw, err := fs.ReadFile(s.catalog, p)
+		if err != nil {
+			return err
+		}
+
+		tmpl, err := template.New(p).Delims(leftDelim, rightDelim).Option("missingkey=error").Parse(string(raw))
+		if err != nil {
+			return fmt.Errorf("invalid skeleton %s: %w", p, err)
+		}
+
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, opts); err != nil {
+			return fmt.Errorf("could not render %s: %w", p, err)
+		}
+
+		rel := strings.TrimPrefix(p, opts.Template+"/")
+		if rel == crFile {
+			rel = opts.Name + ".yaml"
+		}
+
+		files = append(files, File{Path: path.Join(chartDir, rel), Content: buf.Bytes()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func (s *scaffold) validate(opts Options) error {
+	found := false
+	for _, t := range s.Templates() {
+		if t == opts.Template {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("unknown template %q, expected one of %s", opts.Template, strings.Join(s.Templates(), ", "))
+	}
+
+	if errs := validation.IsDNS1123Label(opts.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s", opts.Name, strings.Join(errs, ", "))
+	}
+
+	if opts.Image == "" {
+		return fmt.Errorf("the driver-container image is required")
+	}
+
+	// The recipe tags the repository with the kernel version, and the image
+	// ends up in the YAML of the SpecialResource
+	if _, err := name.NewRepository(opts.Image); err != nil {
+		return fmt.Errorf("invalid image repository %q, expected one without tag or digest: %w", opts.Image, err)
+	}
+
+	// The module name ends up in a shell command of the driver-container
+	if strings.ContainsAny(opts.Module, " \t\n;&|$`'\"\\") {
+		return fmt.Errorf("invalid module name %q", opts.Module)
+	}
+
+	return nil
+}
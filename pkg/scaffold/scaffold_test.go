@@ -0,0 +1,122 @@
+package scaffold_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/scaffold"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+)
+
+func TestScaffold(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scaffold Suite")
+}
+
+var _ = Describe("Templates", func() {
+	It("should list the skeletons of the catalog", func() {
+		Expect(scaffold.New().Templates()).To(Equal([]string{"csi", "kmod-device-plugin", "simple-kmod"}))
+	})
+})
+
+var _ = Describe("Generate", func() {
+	DescribeTable("should generate a chart and its SpecialResource",
+		func(template string, kinds []string) {
+			files, err := scaffold.New().Generate(scaffold.Options{
+				Template: template,
+				Name:     "my-driver",
+				Image:    "quay.io/acme/my-driver",
+				Module:   "my_driver",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			var (
+				chartFiles []*loader.BufferedFile
+				sr         v1beta1.SpecialResource
+			)
+
+			for _, f := range files {
+				Expect(f.Path).To(HavePrefix("my-driver-0.0.1/"))
+				Expect(string(f.Content)).NotTo(ContainSubstring("[["))
+
+				if f.Path == "my-driver-0.0.1/my-driver.yaml" {
+					Expect(yaml.UnmarshalStrict(f.Content, &sr)).To(Succeed())
+					continue
+				}
+
+				chartFiles = append(chartFiles, &loader.BufferedFile{
+					Name: strings.TrimPrefix(f.Path, "my-driver-0.0.1/"),
+					Data: f.Content,
+				})
+			}
+
+			Expect(sr.Name).To(Equal("my-driver"))
+			Expect(sr.Spec.Chart.Name).To(Equal("my-driver"))
+
+			c, err := loader.LoadFiles(chartFiles)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Metadata.Name).To(Equal("my-driver"))
+
+			values := sr.Spec.Set.Object
+			values["kernelFullVersion"] = "4.18.0-305.el8.x86_64"
+			values["csiSidecars"] = map[string]interface{}{"nodeDriverRegistrar": "registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.5.1"}
+			values["groupName"] = map[string]interface{}{"driverContainer": "driver-container", "devicePlugin": "device-plugin"}
+			values["specialresource"] = map[string]interface{}{
+				"metadata": map[string]interface{}{"name": sr.Name},
+				"spec":     map[string]interface{}{"namespace": sr.Spec.Namespace},
+			}
+
+			renderValues, err := chartutil.ToRenderValues(c, values, chartutil.ReleaseOptions{Name: sr.Name}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			rendered, err := engine.Render(c, renderValues)
+			Expect(err).NotTo(HaveOccurred())
+
+			var found []string
+			for _, manifest := range rendered {
+				for _, doc := range releaseutil.SplitManifests(manifest) {
+					obj := map[string]interface{}{}
+					Expect(yaml.Unmarshal([]byte(doc), &obj)).To(Succeed())
+					found = append(found, obj["kind"].(string))
+				}
+			}
+
+			Expect(found).To(ContainElements(kinds))
+		},
+		Entry("simple kmod", "simple-kmod", []string{"DaemonSet"}),
+		Entry("kmod and device plugin", "kmod-device-plugin", []string{"DaemonSet", "DaemonSet"}),
+		Entry("CSI", "csi", []string{"CSIDriver", "DaemonSet", "DaemonSet"}),
+	)
+
+	It("should load the module named after the recipe per default", func() {
+		files, err := scaffold.New().Generate(scaffold.Options{Template: "simple-kmod", Name: "my-driver", Image: "quay.io/acme/my-driver"})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, f := range files {
+			if f.Path == "my-driver-0.0.1/my-driver.yaml" {
+				Expect(string(f.Content)).To(ContainSubstring(`kmodNames: ["my-driver"]`))
+			}
+		}
+	})
+
+	DescribeTable("should reject invalid options",
+		func(opts scaffold.Options) {
+			_, err := scaffold.New().Generate(opts)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unknown template", scaffold.Options{Template: "gpu", Name: "my-driver", Image: "quay.io/acme/my-driver"}),
+		Entry("invalid name", scaffold.Options{Template: "simple-kmod", Name: "My_Driver", Image: "quay.io/acme/my-driver"}),
+		Entry("no image", scaffold.Options{Template: "simple-kmod", Name: "my-driver"}),
+		Entry("tagged image", scaffold.Options{Template: "simple-kmod", Name: "my-driver", Image: "quay.io/acme/my-driver:v1"}),
+		Entry("image with a digest", scaffold.Options{Template: "simple-kmod", Name: "my-driver", Image: "quay.io/acme/my-driver@sha256:" + strings.Repeat("0", 64)}),
+		Entry("YAML in the image", scaffold.Options{Template: "simple-kmod", Name: "my-driver", Image: "quay.io/acme/my-driver\n    evil: true"}),
+		Entry("shell in the module name", scaffold.Options{Template: "simple-kmod", Name: "my-driver", Image: "quay.io/acme/my-driver", Module: "kmod; reboot"}),
+	)
+})
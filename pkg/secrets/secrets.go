@@ -0,0 +1,118 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ProviderKubernetes reads the values from the Secrets of the namespace of the
+// SpecialResource, it is the provider of the secretRefs without provider.
+const ProviderKubernetes = "kubernetes"
+
+//go:generate mockgen -source=secrets.go -package=secrets -destination=mock_secrets_api.go
+
+// Provider fetches secret values from a secret store, e.g. Vault.
+type Provider interface {
+	// Fetch returns the value of ref, namespace is the one of the SpecialResource.
+	Fetch(ctx context.Context, namespace string, ref v1beta1.SpecialResourceSecretRef) (string, error)
+}
+
+type Secrets interface {
+	Values(ctx context.Context, sr *v1beta1.SpecialResource) (map[string]interface{}, error)
+}
+
+type secrets struct {
+	providers map[string]Provider
+}
+
+// New returns Secrets fetching the values with the named providers, in
+// addition to the built-in kubernetes provider.
+func New(kubeClient clients.ClientsInterface, providers map[string]Provider) Secrets {
+	s := &secrets{
+		providers: map[string]Provider{ProviderKubernetes: &kubernetesProvider{kubeClient: kubeClient}},
+	}
+
+	for name, p := range providers {
+		s.providers[name] = p
+	}
+
+	return s
+}
+
+// Values returns the set values of sr with the valuesFrom of sr fetched from
+// their provider. The values are only held in memory, sr is not modified.
+func (s *secrets) Values(ctx context.Context, sr *v1beta1.SpecialResource) (map[string]interface{}, error) {
+	if len(sr.Spec.ValuesFrom) == 0 {
+		return sr.Spec.Set.Object, nil
+	}
+
+	values := make(map[string]interface{})
+	if sr.Spec.Set.Object != nil {
+		values = runtime.DeepCopyJSON(sr.Spec.Set.Object)
+	}
+
+	for _, from := range sr.Spec.ValuesFrom {
+		name := from.SecretRef.Provider
+		if name == "" {
+			name = ProviderKubernetes
+		}
+
+		p, ok := s.providers[name]
+		if !ok {
+			return nil, fmt.Errorf("value %s: unknown secret provider %q", from.Key, name)
+		}
+
+		value, err := p.Fetch(ctx, sr.Spec.Namespace, from.SecretRef)
+		if err != nil {
+			return nil, fmt.Errorf("value %s: could not fetch %s from %s: %w", from.Key, from.SecretRef.Name, name, err)
+		}
+
+		if err = unstructured.SetNestedField(values, value, strings.Split(from.Key, ".")...); err != nil {
+			return nil, fmt.Errorf("value %s: %w", from.Key, err)
+		}
+	}
+
+	return values, nil
+}
+
+// Fetched returns the values of values fetched for the valuesFrom of sr, values
+// being the set values returned by Values or the values coalesced from them.
+// They are to be redacted wherever the values or the manifests rendered from
+// them are shown.
+func Fetched(sr *v1beta1.SpecialResource, values map[string]interface{}) []string {
+	fetched := make([]string, 0, len(sr.Spec.ValuesFrom))
+
+	for _, from := range sr.Spec.ValuesFrom {
+		value, found, err := unstructured.NestedString(values, strings.Split(from.Key, ".")...)
+		if err == nil && found && value != "" {
+			fetched = append(fetched, value)
+		}
+	}
+
+	return fetched
+}
+
+type kubernetesProvider struct {
+	kubeClient clients.ClientsInterface
+}
+
+func (k *kubernetesProvider) Fetch(ctx context.Context, namespace string, ref v1beta1.SpecialResourceSecretRef) (string, error) {
+	secret, err := k.kubeClient.GetSecret(ctx, namespace, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("Secret %s/%s has no key %s", namespace, ref.Name, ref.Key)
+	}
+
+	return string(value), nil
+}
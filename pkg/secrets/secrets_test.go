@@ -0,0 +1,121 @@
+package secrets_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/secrets"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	ctrl         *gomock.Controller
+	mockClient   *clients.MockClientsInterface
+	mockProvider *secrets.MockProvider
+)
+
+func TestSecrets(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = clients.NewMockClientsInterface(ctrl)
+		mockProvider = secrets.NewMockProvider(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "Secrets Suite")
+}
+
+var _ = Describe("Values", func() {
+	var sr *v1beta1.SpecialResource
+
+	BeforeEach(func() {
+		sr = &v1beta1.SpecialResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "simple-kmod"},
+			Spec: v1beta1.SpecialResourceSpec{
+				Namespace: "simple-kmod",
+				Set: unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"license": map[string]interface{}{"server": "license.example.com"},
+					},
+				},
+			},
+		}
+	})
+
+	It("should return the set values without valuesFrom", func() {
+		values, err := secrets.New(mockClient, nil).Values(context.Background(), sr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(Equal(sr.Spec.Set.Object))
+	})
+
+	It("should fetch the values from their provider", func() {
+		vaultRef := v1beta1.SpecialResourceSecretRef{Provider: "vault", Name: "secret/simple-kmod", Key: "activation"}
+
+		sr.Spec.ValuesFrom = []v1beta1.SpecialResourceValueFrom{
+			{Key: "license.token", SecretRef: v1beta1.SpecialResourceSecretRef{Name: "simple-kmod-license", Key: "token"}},
+			{Key: "activation", SecretRef: vaultRef},
+		}
+
+		mockClient.EXPECT().GetSecret(context.Background(), "simple-kmod", "simple-kmod-license", metav1.GetOptions{}).
+			Return(&v1.Secret{Data: map[string][]byte{"token": []byte("s3cr3t")}}, nil)
+		mockProvider.EXPECT().Fetch(context.Background(), "simple-kmod", vaultRef).Return("4ct1v4t3", nil)
+
+		values, err := secrets.New(mockClient, map[string]secrets.Provider{"vault": mockProvider}).Values(context.Background(), sr)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(values).To(Equal(map[string]interface{}{
+			"license":    map[string]interface{}{"server": "license.example.com", "token": "s3cr3t"},
+			"activation": "4ct1v4t3",
+		}))
+		Expect(sr.Spec.Set.Object).To(Equal(map[string]interface{}{
+			"license": map[string]interface{}{"server": "license.example.com"},
+		}))
+
+		Expect(secrets.Fetched(sr, values)).To(Equal([]string{"s3cr3t", "4ct1v4t3"}))
+	})
+
+	It("should fail if the Secret has no such key", func() {
+		sr.Spec.ValuesFrom = []v1beta1.SpecialResourceValueFrom{
+			{Key: "license.token", SecretRef: v1beta1.SpecialResourceSecretRef{Name: "simple-kmod-license", Key: "token"}},
+		}
+
+		mockClient.EXPECT().GetSecret(context.Background(), "simple-kmod", "simple-kmod-license", metav1.GetOptions{}).
+			Return(&v1.Secret{}, nil)
+
+		_, err := secrets.New(mockClient, nil).Values(context.Background(), sr)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail for an unknown provider", func() {
+		sr.Spec.ValuesFrom = []v1beta1.SpecialResourceValueFrom{
+			{Key: "token", SecretRef: v1beta1.SpecialResourceSecretRef{Provider: "vault", Name: "secret/simple-kmod", Key: "token"}},
+		}
+
+		_, err := secrets.New(mockClient, nil).Values(context.Background(), sr)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if the provider fails", func() {
+		sr.Spec.ValuesFrom = []v1beta1.SpecialResourceValueFrom{
+			{Key: "token", SecretRef: v1beta1.SpecialResourceSecretRef{Provider: "vault", Name: "secret/simple-kmod", Key: "token"}},
+		}
+
+		mockProvider.EXPECT().Fetch(context.Background(), "simple-kmod", gomock.Any()).Return("", errors.New("random error"))
+
+		_, err := secrets.New(mockClient, map[string]secrets.Provider{"vault": mockProvider}).Values(context.Background(), sr)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,40 @@
+package selinux
+
+import (
+	_ "embed"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/states"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// StateName is the name of the built-in state, as listed in
+// spec.disableStates.
+const StateName = "selinux-policy"
+
+// stateTemplate is the path of the built-in state in the chart, it runs before
+// the states of the chart, the drivers may need the policy to load.
+const stateTemplate = "templates/0000-" + StateName + ".yaml"
+
+// state installs the SELinux policy module of .Values.selinuxPolicy on the
+// nodes, and renders nothing without one.
+//
+//go:embed state.yaml
+var state []byte
+
+// WithState returns a copy of ch with the built-in selinux-policy state if its
+// values or set, the values of the SpecialResource, have a policy module. ch
+// is returned as is without policy or if it already has a state of that name.
+func WithState(ch *chart.Chart, set map[string]interface{}) *chart.Chart {
+	if !hasPolicy(ch.Values) && !hasPolicy(set) {
+		return ch
+	}
+
+	return states.Add(ch, stateTemplate, state)
+}
+
+func hasPolicy(values map[string]interface{}) bool {
+	cil, _, _ := unstructured.NestedFieldNoCopy(values, "selinuxPolicy", "cil")
+	policy, ok := cil.(string)
+	return ok && policy != ""
+}
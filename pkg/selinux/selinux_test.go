@@ -0,0 +1,109 @@
+package selinux_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/selinux"
+	"github.com/openshift-psap/special-resource-operator/pkg/states"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestSELinux(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SELinux Suite")
+}
+
+const cil = "(allow container_t simple_kmod_device_t (chr_file (open read write ioctl)))\n"
+
+func policy(module string) map[string]interface{} {
+	return map[string]interface{}{"selinuxPolicy": map[string]interface{}{"module": module, "cil": cil}}
+}
+
+func newChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "simple-kmod", Version: "0.0.1", APIVersion: chart.APIVersionV2},
+		Templates: []*chart.File{{Name: "templates/1000-driver-container.yaml"}},
+	}
+}
+
+// render renders the selinux-policy state of ch.
+func render(ch *chart.Chart, set map[string]interface{}) (string, error) {
+	values := chartutil.CoalesceTables(map[string]interface{}{
+		"specialresource": map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "simple-kmod"},
+			"spec": map[string]interface{}{
+				"namespace":    "simple-kmod",
+				"nodeSelector": map[string]interface{}{"node-role.kubernetes.io/worker": ""},
+			},
+		},
+	}, set)
+
+	return states.Render(ch, values, "templates/0000-selinux-policy.yaml")
+}
+
+var _ = Describe("WithState", func() {
+	It("should not add the state without policy", func() {
+		ch := newChart()
+		Expect(selinux.WithState(ch, nil)).To(BeIdenticalTo(ch))
+	})
+
+	It("should add the state before the states of the chart", func() {
+		ch := newChart()
+
+		stateYAMLS, _ := states.Split(selinux.WithState(ch, policy("")), func(string) bool { return true })
+
+		Expect(ch.Templates).To(HaveLen(1))
+		Expect(stateYAMLS).To(HaveLen(2))
+		Expect(states.Name(stateYAMLS[0].Name)).To(Equal(selinux.StateName))
+	})
+
+	It("should install and remove the module on the selected nodes", func() {
+		manifest, err := render(selinux.WithState(newChart(), policy("")), policy(""))
+		Expect(err).NotTo(HaveOccurred())
+
+		var (
+			cm      corev1.ConfigMap
+			ds      appsv1.DaemonSet
+			removal appsv1.DaemonSet
+		)
+		for _, doc := range releaseutil.SplitManifests(manifest) {
+			switch {
+			case strings.Contains(doc, "kind: ConfigMap"):
+				Expect(yaml.UnmarshalStrict([]byte(doc), &cm)).To(Succeed())
+			case strings.Contains(doc, "helm.sh/hook: pre-delete"):
+				Expect(yaml.UnmarshalStrict([]byte(doc), &removal)).To(Succeed())
+			case strings.Contains(doc, "kind: DaemonSet"):
+				Expect(yaml.UnmarshalStrict([]byte(doc), &ds)).To(Succeed())
+			}
+		}
+
+		Expect(cm.Data).To(HaveKeyWithValue("simple_kmod.cil", strings.TrimSpace(cil)))
+
+		Expect(ds.Name).To(Equal("simple-kmod-selinux-policy"))
+		Expect(ds.Annotations).To(HaveKeyWithValue("specialresource.openshift.io/wait", "true"))
+		Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"node-role.kubernetes.io/worker": ""}))
+
+		container := ds.Spec.Template.Spec.Containers[0]
+		Expect(container.Args[0]).To(ContainSubstring("semodule -i /var/lib/specialresource/selinux/simple_kmod.cil"))
+		Expect(container.Args[0]).To(HaveSuffix("exec sleep infinity"))
+		Expect(container.Lifecycle).To(BeNil())
+
+		Expect(removal.Name).To(Equal("simple-kmod-selinux-policy-removal"))
+		Expect(removal.Annotations).To(HaveKeyWithValue("specialresource.openshift.io/wait", "true"))
+		Expect(removal.Spec.Template.Spec.NodeSelector).To(Equal(ds.Spec.Template.Spec.NodeSelector))
+		Expect(removal.Spec.Template.Spec.Containers[0].Args[0]).To(ContainSubstring("semodule -r simple_kmod"))
+	})
+
+	It("should reject an invalid module name", func() {
+		_, err := render(selinux.WithState(newChart(), policy("kmod; reboot")), policy("kmod; reboot"))
+		Expect(err).To(HaveOccurred())
+	})
+})
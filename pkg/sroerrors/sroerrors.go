@@ -0,0 +1,79 @@
+package sroerrors
+
+import (
+	"errors"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// Category tells where a reconcile failed, so that a broken chart can be told
+// apart from a broken cluster. It is used as the reason of the Errored
+// condition and as a label of the reconcile errors metric.
+type Category string
+
+const (
+	// ChartLoad is a chart that cannot be fetched or loaded.
+	ChartLoad Category = "ChartLoadError"
+	// Render is a chart whose templates cannot be rendered.
+	Render Category = "RenderError"
+	// Registry is an image lookup that failed, e.g. the registry is not
+	// reachable or the pull secret has no credentials for it.
+	Registry Category = "RegistryError"
+	// Apply is an object that the API server did not accept.
+	Apply Category = "ApplyError"
+	// WaitTimeout is an object that did not become ready in time.
+	WaitTimeout Category = "WaitTimeout"
+	// Blocked is an object the resource budget of the operator, or a state the
+	// decision plugin, does not allow.
+	Blocked Category = "Blocked"
+	// Unknown is an error that was not categorized.
+	Unknown Category = "Unknown"
+)
+
+// Error is an error of a Category. Its message is the one of the wrapped error.
+type Error struct {
+	Category Category
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap returns err with the category, nil if err is nil. An error that already
+// has a category keeps it.
+func Wrap(category Category, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if CategoryOf(err) != Unknown {
+		return err
+	}
+
+	return &Error{Category: category, Err: err}
+}
+
+// CategoryOf returns the category of err, or of the first error it aggregates
+// that has one, Unknown if there is none.
+func CategoryOf(err error) Category {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Category
+	}
+
+	var agg utilerrors.Aggregate
+	if errors.As(err, &agg) {
+		for _, ae := range agg.Errors() {
+			if category := CategoryOf(ae); category != Unknown {
+				return category
+			}
+		}
+	}
+
+	return Unknown
+}
@@ -0,0 +1,13 @@
+package sroerrors_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSROErrors(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SRO Errors Suite")
+}
@@ -0,0 +1,49 @@
+package sroerrors_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/sroerrors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+var _ = Describe("Wrap", func() {
+	It("should return nil for nil", func() {
+		Expect(sroerrors.Wrap(sroerrors.Apply, nil)).To(BeNil())
+	})
+
+	It("should keep the message and the wrapped error", func() {
+		cause := errors.New("random error")
+
+		err := sroerrors.Wrap(sroerrors.Render, cause)
+		Expect(err.Error()).To(Equal("random error"))
+		Expect(errors.Is(err, cause)).To(BeTrue())
+	})
+
+	It("should keep the first category", func() {
+		err := sroerrors.Wrap(sroerrors.Apply, sroerrors.Wrap(sroerrors.WaitTimeout, errors.New("random error")))
+		Expect(sroerrors.CategoryOf(err)).To(Equal(sroerrors.WaitTimeout))
+	})
+})
+
+var _ = Describe("CategoryOf", func() {
+	DescribeTable("should find the category",
+		func(err error, expected sroerrors.Category) {
+			Expect(sroerrors.CategoryOf(err)).To(Equal(expected))
+		},
+		Entry("uncategorized", errors.New("random error"), sroerrors.Unknown),
+		Entry("categorized", sroerrors.Wrap(sroerrors.ChartLoad, errors.New("random error")), sroerrors.ChartLoad),
+		Entry("wrapped",
+			fmt.Errorf("reconcile: %w", sroerrors.Wrap(sroerrors.Registry, errors.New("random error"))),
+			sroerrors.Registry),
+		Entry("aggregated",
+			fmt.Errorf("apply: %w", utilerrors.NewAggregate([]error{
+				errors.New("random error"),
+				sroerrors.Wrap(sroerrors.Apply, errors.New("random error")),
+			})),
+			sroerrors.Apply),
+	)
+})
@@ -0,0 +1,63 @@
+package srotest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// simulatedCluster returns the OpenShift facts of the fixtures instead of
+// reading them from the API server, which has none of the OpenShift APIs.
+type simulatedCluster struct {
+	cluster.Cluster
+	fixtures *Fixtures
+}
+
+// NewCluster returns a cluster.Cluster answering from fixtures. The operating
+// system is read from the NFD labels of the nodes as on a real cluster.
+func NewCluster(fixtures *Fixtures) cluster.Cluster {
+	return &simulatedCluster{
+		Cluster:  cluster.NewCluster(nil),
+		fixtures: fixtures,
+	}
+}
+
+func (c *simulatedCluster) Version(context.Context) (string, string, error) {
+	if c.fixtures.ClusterVersion == "" {
+		return "", "", nil
+	}
+
+	s := strings.Split(c.fixtures.ClusterVersion, ".")
+	if len(s) < 2 {
+		return c.fixtures.ClusterVersion, s[0], nil
+	}
+
+	return c.fixtures.ClusterVersion, s[0] + "." + s[1], nil
+}
+
+// VersionHistory returns the release image as both the desired one and the one
+// of the only completed update.
+func (c *simulatedCluster) VersionHistory(context.Context) ([]string, error) {
+	if c.fixtures.ReleaseImage == "" {
+		return []string{}, nil
+	}
+	return []string{c.fixtures.ReleaseImage, c.fixtures.ReleaseImage}, nil
+}
+
+func (c *simulatedCluster) OSImageURL(context.Context) (string, error) {
+	return c.fixtures.OSImageURL, nil
+}
+
+func (c *simulatedCluster) MachineConfigPools(context.Context) (map[string]cluster.MachineConfigPool, error) {
+	return c.fixtures.MachineConfigPools, nil
+}
+
+func (c *simulatedCluster) OperatingSystem(nodeList *corev1.NodeList) (string, string, string, error) {
+	return c.Cluster.OperatingSystem(nodeList)
+}
+
+func (c *simulatedCluster) DefaultNodeSelector(context.Context) (map[string]string, error) {
+	return c.fixtures.DefaultNodeSelector, nil
+}
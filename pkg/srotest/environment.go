@@ -0,0 +1,234 @@
+// Package srotest runs the SpecialResource controller against a simulated
+// cluster, so that recipe authors and CI can test the full reconcile of their
+// charts without an OpenShift cluster.
+package srotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"time"
+
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/controllers"
+	"github.com/openshift-psap/special-resource-operator/internal/controllers/state"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	sroscheme "github.com/openshift-psap/special-resource-operator/pkg/scheme"
+	"github.com/openshift-psap/special-resource-operator/pkg/storage"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// DefaultOperatorNamespace is the namespace of the simulated operator.
+const DefaultOperatorNamespace = "openshift-special-resource-operator"
+
+// Environment runs the SpecialResource controller against an envtest API
+// server. pkg/cluster and pkg/registry answer from the fixtures, and the nodes
+// of the fixtures are created with their NFD labels.
+//
+// envtest runs no kubelet nor controllers: the workloads the charts create
+// never run, and are considered ready as soon as they are applied. The
+// binaries of envtest are found through KUBEBUILDER_ASSETS.
+type Environment struct {
+	Fixtures Fixtures
+	// CRDDirectoryPaths are installed besides the CRDs of SRO, e.g. the ones
+	// of the custom resources the charts create.
+	CRDDirectoryPaths []string
+	// FeatureGates are the gates of the operator, as --feature-gates.
+	FeatureGates string
+	// OperatorNamespace defaults to DefaultOperatorNamespace. It is set as
+	// OPERATOR_NAMESPACE for the process.
+	OperatorNamespace string
+
+	env    *envtest.Environment
+	client client.Client
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// crdPath returns the directory of the CRDs of SRO, relative to this file so
+// that it is found from the module cache as well.
+func crdPath() string {
+	_, file, _, _ := goruntime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "config", "crd", "bases")
+}
+
+// Start starts the API server, creates the nodes of the fixtures and starts
+// the controller.
+func (e *Environment) Start() error {
+	if e.OperatorNamespace == "" {
+		e.OperatorNamespace = DefaultOperatorNamespace
+	}
+
+	if err := os.Setenv("OPERATOR_NAMESPACE", e.OperatorNamespace); err != nil {
+		return err
+	}
+
+	e.env = &envtest.Environment{
+		CRDDirectoryPaths:     append([]string{crdPath()}, e.CRDDirectoryPaths...),
+		ErrorIfCRDPathMissing: true,
+	}
+
+	cfg, err := e.env.Start()
+	if err != nil {
+		return fmt.Errorf("could not start the API server: %w", err)
+	}
+
+	scheme := k8sruntime.NewScheme()
+	utilruntime.Must(sroscheme.AddToScheme(scheme))
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(srov1beta1.AddToScheme(scheme))
+
+	if e.client, err = client.New(cfg, client.Options{Scheme: scheme}); err != nil {
+		return e.abort(fmt.Errorf("could not create the client: %w", err))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+
+	if err = e.createFixtures(ctx); err != nil {
+		return e.abort(err)
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		HealthProbeBindAddress: "0",
+		MetricsBindAddress:     "0",
+		NewClient:              clients.NewClientFunc(false),
+		Scheme:                 scheme,
+	})
+	if err != nil {
+		return e.abort(fmt.Errorf("could not create the manager: %w", err))
+	}
+
+	if err = e.setupReconciler(mgr, scheme); err != nil {
+		return e.abort(err)
+	}
+
+	e.done = make(chan error, 1)
+
+	go func() {
+		e.done <- mgr.Start(ctx)
+	}()
+
+	return nil
+}
+
+// Stop stops the controller and the API server.
+func (e *Environment) Stop() error {
+	var err error
+
+	if e.cancel != nil {
+		e.cancel()
+	}
+
+	if e.done != nil {
+		err = <-e.done
+	}
+
+	if e.env != nil {
+		if stopErr := e.env.Stop(); stopErr != nil && err == nil {
+			err = stopErr
+		}
+	}
+
+	return err
+}
+
+// Client returns a client of the API server, it does not read from a cache.
+func (e *Environment) Client() client.Client {
+	return e.client
+}
+
+// WaitForReady waits up to timeout for the SpecialResource to be ready. It
+// returns the last error of its reconciles otherwise.
+func (e *Environment) WaitForReady(ctx context.Context, name string, timeout time.Duration) error {
+	sr := &srov1beta1.SpecialResource{}
+	lastError := ""
+
+	err := wait.PollImmediate(100*time.Millisecond, timeout, func() (bool, error) {
+		if err := e.client.Get(ctx, types.NamespacedName{Name: name}, sr); err != nil {
+			return false, fmt.Errorf("could not get SpecialResource %s: %w", name, err)
+		}
+
+		if cond := meta.FindStatusCondition(sr.Status.Conditions, state.Errored); cond != nil && cond.Status == "True" {
+			lastError = cond.Message
+		}
+
+		return meta.IsStatusConditionTrue(sr.Status.Conditions, state.Ready), nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) && lastError != "" {
+		return fmt.Errorf("SpecialResource %s was not ready after %s: %s", name, timeout, lastError)
+	}
+
+	return err
+}
+
+func (e *Environment) abort(err error) error {
+	if e.cancel != nil {
+		e.cancel()
+	}
+
+	env := e.env
+	e.env, e.cancel = nil, nil
+
+	if stopErr := env.Stop(); stopErr != nil {
+		return fmt.Errorf("%v, and could not stop the API server: %w", err, stopErr)
+	}
+	return err
+}
+
+func (e *Environment) createFixtures(ctx context.Context) error {
+	ns := &corev1.Namespace{}
+	ns.SetName(e.OperatorNamespace)
+
+	if err := e.client.Create(ctx, ns); err != nil {
+		return fmt.Errorf("could not create namespace %s: %w", e.OperatorNamespace, err)
+	}
+
+	for _, n := range e.Fixtures.Nodes {
+		node := n.Object()
+		status := node.Status
+
+		if err := e.client.Create(ctx, node); err != nil {
+			return fmt.Errorf("could not create node %s: %w", n.Name, err)
+		}
+
+		// The status is dropped on create
+		node.Status = status
+		if err := e.client.Status().Update(ctx, node); err != nil {
+			return fmt.Errorf("could not update the status of node %s: %w", n.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// setupReconciler wires the controller as the operator does, with the
+// simulated cluster and registries.
+func (e *Environment) setupReconciler(mgr ctrl.Manager, scheme *k8sruntime.Scheme) error {
+	r, _, err := controllers.NewSpecialResourceReconciler(mgr, scheme, controllers.Options{
+		ChartUpgradeInterval: time.Hour,
+		FeatureGates:         e.FeatureGates,
+		StorageBackend:       storage.BackendConfigMap,
+
+		Cluster:     NewCluster(&e.Fixtures),
+		Registry:    NewRegistry(&e.Fixtures),
+		PollActions: workloadsReady{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.SetupWithManager(mgr)
+}
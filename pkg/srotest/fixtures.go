@@ -0,0 +1,115 @@
+package srotest
+
+import (
+	"strings"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/cluster"
+	"github.com/openshift-psap/special-resource-operator/pkg/dtk"
+	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
+	"github.com/openshift-psap/special-resource-operator/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const nfdOSReleaseLabel = "feature.node.kubernetes.io/system-os_release"
+
+// Fixtures describe the simulated cluster: what pkg/cluster reads from the
+// OpenShift APIs, the images of the registries and the nodes with their NFD
+// labels.
+type Fixtures struct {
+	// ClusterVersion is the version of OpenShift, e.g. 4.10.3. It is empty
+	// for a vanilla k8s cluster.
+	ClusterVersion string
+	// ReleaseImage is the release image of ClusterVersion.
+	ReleaseImage string
+	// OSImageURL is the OS image the cluster upgrades to.
+	OSImageURL string
+	// MachineConfigPools is the OS every MachineConfigPool runs, by name.
+	MachineConfigPools map[string]cluster.MachineConfigPool
+	// DefaultNodeSelector is the one of the cluster Scheduler config.
+	DefaultNodeSelector map[string]string
+	// Images are the images of the registries, by reference.
+	Images map[string]Image
+	// Nodes are created before the controller starts.
+	Nodes []Node
+}
+
+// Image is an image of the simulated registries. The last layer of a release
+// or of a Driver Toolkit image holds its metadata.
+type Image struct {
+	Digest string
+	// ReleaseVersion makes the image a release image of that version, it
+	// references DriverToolkitImage.
+	ReleaseVersion     string
+	DriverToolkitImage string
+	// DriverToolkit makes the image a Driver Toolkit image.
+	DriverToolkit *dtk.Release
+}
+
+// Node is a node with the labels NFD advertises on RHCOS.
+type Node struct {
+	Name              string
+	KernelFullVersion string
+	// OSReleaseID defaults to rhcos.
+	OSReleaseID string
+	// OSVersion is the VERSION_ID of the os-release, e.g. 4.10.
+	OSVersion string
+	// RHELVersion is the version of RHEL RHCOS is based on, e.g. 8.4.
+	RHELVersion string
+	// Labels are added to the NFD labels and to the worker role.
+	Labels map[string]string
+	// NotReady and Unschedulable simulate a node in the middle of an upgrade.
+	NotReady      bool
+	Unschedulable bool
+}
+
+// Object returns the node as created in the simulated cluster.
+func (n Node) Object() *corev1.Node {
+	labels := make(map[string]string)
+
+	for k, v := range webhook.DefaultNodeSelector {
+		labels[k] = v
+	}
+
+	osReleaseID := n.OSReleaseID
+	if osReleaseID == "" {
+		osReleaseID = "rhcos"
+	}
+
+	labels[kernel.FullVersionLabel] = n.KernelFullVersion
+	labels[nfdOSReleaseLabel+".ID"] = osReleaseID
+	labels[nfdOSReleaseLabel+".VERSION_ID"] = n.OSVersion
+	labels[nfdOSReleaseLabel+".VERSION_ID.major"], labels[nfdOSReleaseLabel+".VERSION_ID.minor"], _ = strings.Cut(n.OSVersion, ".")
+	if n.RHELVersion != "" {
+		labels[nfdOSReleaseLabel+".RHEL_VERSION"] = n.RHELVersion
+	}
+
+	for k, v := range n.Labels {
+		labels[k] = v
+	}
+
+	ready := corev1.ConditionTrue
+	if n.NotReady {
+		ready = corev1.ConditionFalse
+	}
+
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   n.Name,
+			Labels: labels,
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: n.Unschedulable,
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             ready,
+					LastHeartbeatTime:  metav1.Now(),
+					LastTransitionTime: metav1.Now(),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package srotest
+
+import (
+	"context"
+
+	"github.com/openshift-psap/special-resource-operator/pkg/poll"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// workloadsReady does not wait for the workloads: no kubelet runs them in the
+// simulated cluster, they are ready as soon as they are applied.
+type workloadsReady struct{}
+
+var _ poll.PollActions = workloadsReady{}
+
+func (workloadsReady) ForResourceUnavailability(context.Context, *unstructured.Unstructured) error {
+	return nil
+}
+
+func (workloadsReady) ForResource(context.Context, *unstructured.Unstructured) error {
+	return nil
+}
+
+func (workloadsReady) ForDaemonSet(context.Context, *unstructured.Unstructured) error {
+	return nil
+}
+
+func (workloadsReady) ForDaemonSetLogs(context.Context, *unstructured.Unstructured, string) error {
+	return nil
+}
@@ -0,0 +1,132 @@
+package srotest
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/openshift-psap/special-resource-operator/pkg/dtk"
+	"github.com/openshift-psap/special-resource-operator/pkg/registry"
+)
+
+// ErrImageNotFound is returned for the images missing from the fixtures.
+var ErrImageNotFound = errors.New("image not found in the simulated registries")
+
+// simulatedRegistry serves the images of the fixtures. Their last layer is
+// generated from the fixtures and parsed by the actual registry code.
+type simulatedRegistry struct {
+	registry.Registry
+	fixtures *Fixtures
+}
+
+// NewRegistry returns a registry.Registry serving the images of fixtures.
+func NewRegistry(fixtures *Fixtures) registry.Registry {
+	return &simulatedRegistry{
+		Registry: registry.NewRegistry(nil, 0, 0),
+		fixtures: fixtures,
+	}
+}
+
+func (r *simulatedRegistry) image(ref string) (Image, error) {
+	img, ok := r.fixtures.Images[ref]
+	if !ok {
+		return Image{}, fmt.Errorf("%s: %w", ref, ErrImageNotFound)
+	}
+	return img, nil
+}
+
+func (r *simulatedRegistry) Digest(_ context.Context, ref string) (string, error) {
+	img, err := r.image(ref)
+	if err != nil {
+		return "", err
+	}
+	return img.Digest, nil
+}
+
+func (r *simulatedRegistry) LastLayer(_ context.Context, ref string) (v1.Layer, error) {
+	img, err := r.image(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+
+	if img.ReleaseVersion != "" {
+		if files["release-manifests/release-metadata"], err = json.Marshal(map[string]interface{}{
+			"version": img.ReleaseVersion,
+		}); err != nil {
+			return nil, err
+		}
+
+		if files["release-manifests/image-references"], err = json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"tags": []interface{}{
+					map[string]interface{}{
+						"name": "driver-toolkit",
+						"from": map[string]interface{}{"name": img.DriverToolkitImage},
+					},
+				},
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if img.DriverToolkit != nil {
+		release := map[string]string{
+			"KERNEL_VERSION": img.DriverToolkit.KernelFullVersion,
+			"RHEL_VERSION":   img.DriverToolkit.OSVersion,
+		}
+		if img.DriverToolkit.SchemaVersion >= dtk.SchemaRTKernel {
+			release["RT_KERNEL_VERSION"] = img.DriverToolkit.RTKernelFullVersion
+		}
+		if img.DriverToolkit.SchemaVersion >= dtk.SchemaOCP {
+			release["OCP_VERSION"] = img.DriverToolkit.OCPVersion
+		}
+
+		if files[dtk.ReleaseFile], err = json.Marshal(release); err != nil {
+			return nil, err
+		}
+	}
+
+	archive, err := tarFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(archive)), nil
+	})
+}
+
+// Reachable always succeeds, the registries are in memory.
+func (r *simulatedRegistry) Reachable() error {
+	return nil
+}
+
+func tarFiles(files map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,242 @@
+package srotest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/dtk"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/kernel"
+	"github.com/openshift-psap/special-resource-operator/pkg/registry"
+	"github.com/openshift-psap/special-resource-operator/pkg/srotest"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	kernelFullVersion = "4.18.0-305.19.1.el8_4.x86_64"
+	releaseImage      = "quay.io/openshift-release-dev/ocp-release@sha256:release"
+	dtkImage          = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:dtk"
+)
+
+func TestSROTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SROTest Suite")
+}
+
+func fixtures() *srotest.Fixtures {
+	return &srotest.Fixtures{
+		ClusterVersion: "4.10.3",
+		ReleaseImage:   releaseImage,
+		Images: map[string]srotest.Image{
+			releaseImage: {
+				Digest:             "sha256:release",
+				ReleaseVersion:     "4.10.3",
+				DriverToolkitImage: dtkImage,
+			},
+			dtkImage: {
+				Digest: "sha256:dtk",
+				DriverToolkit: &dtk.Release{
+					SchemaVersion:       dtk.SchemaRTKernel,
+					KernelFullVersion:   kernelFullVersion,
+					RTKernelFullVersion: "4.18.0-305.19.1.rt7.91.el8_4.x86_64",
+					OSVersion:           "8.4",
+				},
+			},
+		},
+		Nodes: []srotest.Node{
+			{Name: "worker-0", KernelFullVersion: kernelFullVersion, OSVersion: "4.10", RHELVersion: "8.4"},
+			{Name: "worker-1", KernelFullVersion: kernelFullVersion, OSVersion: "4.10", RHELVersion: "8.4", NotReady: true},
+		},
+	}
+}
+
+var _ = Describe("Node", func() {
+	It("should have the NFD labels of RHCOS", func() {
+		node := fixtures().Nodes[0].Object()
+
+		Expect(node.Labels).To(HaveKeyWithValue(kernel.FullVersionLabel, kernelFullVersion))
+		Expect(node.Labels).To(HaveKeyWithValue("feature.node.kubernetes.io/system-os_release.ID", "rhcos"))
+		Expect(node.Labels).To(HaveKeyWithValue("feature.node.kubernetes.io/system-os_release.VERSION_ID.major", "4"))
+		Expect(node.Labels).To(HaveKeyWithValue("feature.node.kubernetes.io/system-os_release.VERSION_ID.minor", "10"))
+		Expect(node.Labels).To(HaveKeyWithValue("node-role.kubernetes.io/worker", ""))
+		Expect(utils.NodeReady(node)).To(BeTrue())
+	})
+
+	It("should not be ready when asked to", func() {
+		Expect(utils.NodeReady(fixtures().Nodes[1].Object())).To(BeFalse())
+	})
+})
+
+var _ = Describe("NewCluster", func() {
+	It("should answer from the fixtures", func() {
+		c := srotest.NewCluster(fixtures())
+
+		version, majorMinor, err := c.Version(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("4.10.3"))
+		Expect(majorMinor).To(Equal("4.10"))
+
+		history, err := c.VersionHistory(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(ContainElement(releaseImage))
+	})
+
+	It("should read the operating system from the NFD labels", func() {
+		nodes := &corev1.NodeList{Items: []corev1.Node{*fixtures().Nodes[0].Object()}}
+
+		major, majorMinor, decimal, err := srotest.NewCluster(fixtures()).OperatingSystem(nodes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(major).To(Equal("rhel8"))
+		Expect(majorMinor).To(Equal("rhel8.4"))
+		Expect(decimal).To(Equal("8.4"))
+	})
+
+	It("should be a vanilla k8s cluster without a cluster version", func() {
+		version, majorMinor, err := srotest.NewCluster(&srotest.Fixtures{}).Version(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(BeEmpty())
+		Expect(majorMinor).To(BeEmpty())
+	})
+})
+
+var _ = Describe("NewRegistry", func() {
+	var r registry.Registry
+
+	BeforeEach(func() {
+		r = srotest.NewRegistry(fixtures())
+	})
+
+	It("should return the digests of the images", func() {
+		digest, err := r.Digest(context.Background(), dtkImage)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal("sha256:dtk"))
+
+		_, err = r.Digest(context.Background(), "quay.io/missing:latest")
+		Expect(errors.Is(err, srotest.ErrImageNotFound)).To(BeTrue())
+	})
+
+	It("should serve the release manifests of a release image", func() {
+		layer, err := r.LastLayer(context.Background(), releaseImage)
+		Expect(err).NotTo(HaveOccurred())
+
+		version, imageURL, err := r.ReleaseManifests(layer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("4.10.3"))
+		Expect(imageURL).To(Equal(dtkImage))
+	})
+
+	It("should serve the metadata of a Driver Toolkit image", func() {
+		layer, err := r.LastLayer(context.Background(), dtkImage)
+		Expect(err).NotTo(HaveOccurred())
+
+		entry, err := r.ExtractToolkitRelease(layer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entry.KernelFullVersion).To(Equal(kernelFullVersion))
+		Expect(entry.RTKernelFullVersion).To(Equal("4.18.0-305.19.1.rt7.91.el8_4.x86_64"))
+		Expect(entry.OSVersion).To(Equal("8.4"))
+	})
+
+	It("should report the release images as not Driver Toolkit ones", func() {
+		layer, err := r.LastLayer(context.Background(), releaseImage)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = r.ExtractToolkitRelease(layer)
+		Expect(errors.Is(err, dtk.ErrNotDriverToolkit)).To(BeTrue())
+	})
+})
+
+// serveChart packages a chart with a single state creating a ConfigMap in a
+// temporary Helm repository, and serves it.
+func serveChart() *httptest.Server {
+	dir := GinkgoT().TempDir()
+
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "ping", Version: "0.0.1"},
+		Templates: []*chart.File{
+			{
+				Name: "templates/0000-config.yaml",
+				Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{.Values.specialresource.metadata.name}}-config
+data:
+  kernel: {{.Values.kernelFullVersion}}
+`),
+			},
+		},
+	}
+	_, err := chartutil.Save(ch, dir)
+	Expect(err).NotTo(HaveOccurred())
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	DeferCleanup(server.Close)
+
+	index, err := repo.IndexDirectory(dir, server.URL)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(index.WriteFile(filepath.Join(dir, "index.yaml"), 0644)).To(Succeed())
+
+	return server
+}
+
+var _ = Describe("Environment", func() {
+	var env *srotest.Environment
+
+	BeforeEach(func() {
+		if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+			Skip("KUBEBUILDER_ASSETS is not set")
+		}
+
+		env = &srotest.Environment{Fixtures: *fixtures()}
+		Expect(env.Start()).To(Succeed())
+		DeferCleanup(func() {
+			Expect(env.Stop()).To(Succeed())
+		})
+	})
+
+	It("should create the nodes of the fixtures", func() {
+		nodes := &corev1.NodeList{}
+		Eventually(func() ([]corev1.Node, error) {
+			err := env.Client().List(context.Background(), nodes)
+			return nodes.Items, err
+		}, 10*time.Second).Should(HaveLen(2))
+	})
+
+	It("should reconcile a SpecialResource until it is ready", func() {
+		ctx := context.Background()
+		server := serveChart()
+
+		sr := &srov1beta1.SpecialResource{
+			Spec: srov1beta1.SpecialResourceSpec{
+				Namespace:    "ping",
+				NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+				Chart: helmerv1beta1.HelmChart{
+					Name:       "ping",
+					Version:    "0.0.1",
+					Repository: helmerv1beta1.HelmRepo{Name: "srotest", URL: server.URL},
+				},
+			},
+		}
+		sr.SetName("ping")
+		Expect(env.Client().Create(ctx, sr)).To(Succeed())
+
+		Expect(env.WaitForReady(ctx, "ping", time.Minute)).To(Succeed())
+
+		cm := &corev1.ConfigMap{}
+		Expect(env.Client().Get(ctx, types.NamespacedName{Namespace: "ping", Name: "ping-config"}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("kernel", kernelFullVersion))
+	})
+})
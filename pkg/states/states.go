@@ -0,0 +1,185 @@
+package states
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/hashstructure/v2"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+// Split separates the state templates of a chart from the other templates.
+// The states are sorted in execution order, the other templates are returned
+// as a copy of the chart without any state.
+func Split(ch *chart.Chart, isState func(name string) bool) ([]*chart.File, chart.Chart) {
+
+	nostate := *ch
+	nostate.Templates = []*chart.File{}
+
+	states := []*chart.File{}
+
+	for _, template := range ch.Templates {
+		if isState(template.Name) {
+			states = append(states, template)
+		} else {
+			nostate.Templates = append(nostate.Templates, template)
+		}
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+
+	return states, nostate
+}
+
+// Name returns the name of a state template without its directory, order
+// prefix and extension, e.g. device-monitoring for
+// templates/3000-device-monitoring.yaml.
+func Name(state string) string {
+	name := strings.TrimSuffix(path.Base(state), ".yaml")
+	if len(name) > 5 {
+		name = name[5:]
+	}
+	return name
+}
+
+// Disable separates the states whose Name is in names from the other ones,
+// the order of the states is kept.
+func Disable(states []*chart.File, names []string) ([]*chart.File, []*chart.File) {
+	disabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		disabled[name] = true
+	}
+
+	kept := make([]*chart.File, 0, len(states))
+	removed := make([]*chart.File, 0)
+
+	for _, state := range states {
+		if disabled[Name(state.Name)] {
+			removed = append(removed, state)
+		} else {
+			kept = append(kept, state)
+		}
+	}
+
+	return kept, removed
+}
+
+// Add returns a copy of ch with the built-in state template, unless ch already
+// has a state of the same Name. ch is never modified, it may be shared with
+// other reconciles.
+func Add(ch *chart.Chart, template string, data []byte) *chart.Chart {
+	for _, t := range ch.Templates {
+		if Name(t.Name) == Name(template) {
+			return ch
+		}
+	}
+
+	withState := *ch
+	withState.Templates = append(append([]*chart.File{}, ch.Templates...), &chart.File{Name: template, Data: data})
+
+	return &withState
+}
+
+// Step returns the chart executing a single state, the other templates are
+// rendered along with it.
+func Step(nostate chart.Chart, state *chart.File) chart.Chart {
+	step := nostate
+	step.Templates = append(append([]*chart.File{}, nostate.Templates...), state)
+	return step
+}
+
+// Extract removes the templates named names from ch and returns them by name.
+// Names that are not templates of ch are ignored.
+func Extract(ch *chart.Chart, names []string) map[string]*chart.File {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	extracted := make(map[string]*chart.File)
+	templates := make([]*chart.File, 0, len(ch.Templates))
+
+	for _, template := range ch.Templates {
+		if wanted[template.Name] {
+			extracted[template.Name] = template
+		} else {
+			templates = append(templates, template)
+		}
+	}
+
+	ch.Templates = templates
+
+	return extracted
+}
+
+// Render renders ch with values, with the name of ch as the release name, and
+// returns the manifests of its template, e.g. of a built-in state.
+func Render(ch *chart.Chart, values map[string]interface{}, template string) (string, error) {
+	renderValues, err := chartutil.ToRenderValues(ch, values, chartutil.ReleaseOptions{Name: ch.Name()}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := engine.Render(ch, renderValues)
+	if err != nil {
+		return "", err
+	}
+
+	return rendered[path.Join(ch.Name(), template)], nil
+}
+
+// Values returns the values a chart is rendered with: the runtime values
+// override the set values of the CR, which override the values of the chart.
+// runtimeValues is modified.
+func Values(ch *chart.Chart, set map[string]interface{}, runtimeValues map[string]interface{}) (map[string]interface{}, error) {
+	return chartutil.CoalesceValues(ch, chartutil.CoalesceTables(runtimeValues, set))
+}
+
+// Inputs are what the objects applied for a state depend on besides its
+// templates and values.
+type Inputs struct {
+	Namespace    string
+	NodeSelector map[string]string
+	// PostRenderer identifies the post-renderers of the state and their
+	// configuration.
+	PostRenderer string
+}
+
+// Hash returns a hash of the templates and dependencies of ch, of the extra
+// templates, of the values and of the inputs. It changes whenever the objects
+// applied from them may change.
+func Hash(ch *chart.Chart, values map[string]interface{}, inputs Inputs, extra ...*chart.File) (string, error) {
+	templates := make(map[string]string, len(ch.Templates)+len(extra))
+	for _, template := range append(append([]*chart.File{}, ch.Templates...), extra...) {
+		templates[template.Name] = string(template.Data)
+	}
+
+	dependencies := make(map[string]string)
+	for _, dependency := range ch.Dependencies() {
+		dependencies[dependency.Name()] = dependency.Metadata.Version
+	}
+
+	var version string
+	if ch.Metadata != nil {
+		version = ch.Metadata.Version
+	}
+
+	hash, err := hashstructure.Hash(struct {
+		Version      string
+		Templates    map[string]string
+		Dependencies map[string]string
+		Values       map[string]interface{}
+		Inputs       Inputs
+	}{version, templates, dependencies, values, inputs}, hashstructure.FormatV2, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatUint(hash, 10), nil
+}
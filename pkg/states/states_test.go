@@ -0,0 +1,219 @@
+package states_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/states"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestStates(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "States Suite")
+}
+
+var _ = Describe("Split", func() {
+	It("should sort the states and keep the other templates", func() {
+		ch := &chart.Chart{
+			Templates: []*chart.File{
+				{Name: "templates/0001-driver-container.yaml"},
+				{Name: "templates/_helpers.tpl"},
+				{Name: "templates/0000-buildconfig.yaml"},
+			},
+		}
+
+		stateTemplates, nostate := states.Split(ch, func(name string) bool {
+			return !strings.HasSuffix(name, ".tpl")
+		})
+
+		Expect(stateTemplates).To(HaveLen(2))
+		Expect(stateTemplates[0].Name).To(Equal("templates/0000-buildconfig.yaml"))
+		Expect(stateTemplates[1].Name).To(Equal("templates/0001-driver-container.yaml"))
+		Expect(nostate.Templates).To(HaveLen(1))
+		Expect(nostate.Templates[0].Name).To(Equal("templates/_helpers.tpl"))
+		Expect(ch.Templates).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("Name", func() {
+	It("should strip the directory, the order prefix and the extension", func() {
+		Expect(states.Name("templates/3000-device-monitoring.yaml")).To(Equal("device-monitoring"))
+		Expect(states.Name("templates/0004_server.yaml")).To(Equal("server"))
+	})
+})
+
+var _ = Describe("Disable", func() {
+	It("should only keep the states that are not disabled, in order", func() {
+		stateTemplates := []*chart.File{
+			{Name: "templates/0000-driver-container.yaml"},
+			{Name: "templates/3000-device-monitoring.yaml"},
+			{Name: "templates/4000-device-feature-discovery.yaml"},
+			{Name: "templates/5000-device-dashboard.yaml"},
+		}
+
+		kept, disabled := states.Disable(stateTemplates, []string{"device-dashboard", "device-monitoring", "missing"})
+
+		Expect(kept).To(HaveLen(2))
+		Expect(kept[0].Name).To(Equal("templates/0000-driver-container.yaml"))
+		Expect(kept[1].Name).To(Equal("templates/4000-device-feature-discovery.yaml"))
+		Expect(disabled).To(HaveLen(2))
+		Expect(disabled[0].Name).To(Equal("templates/3000-device-monitoring.yaml"))
+		Expect(disabled[1].Name).To(Equal("templates/5000-device-dashboard.yaml"))
+	})
+
+	It("should keep all the states without disabled names", func() {
+		stateTemplates := []*chart.File{{Name: "templates/0000-driver-container.yaml"}}
+
+		kept, disabled := states.Disable(stateTemplates, nil)
+
+		Expect(kept).To(Equal(stateTemplates))
+		Expect(disabled).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Add", func() {
+	It("should add the state to a copy of the chart", func() {
+		ch := &chart.Chart{Templates: []*chart.File{{Name: "templates/1000-driver-container.yaml"}}}
+
+		withState := states.Add(ch, "templates/9999-device-dashboard.yaml", []byte("data"))
+
+		Expect(ch.Templates).To(HaveLen(1))
+		Expect(withState.Templates).To(HaveLen(2))
+		Expect(string(withState.Templates[1].Data)).To(Equal("data"))
+	})
+
+	It("should not replace a state of the same name", func() {
+		ch := &chart.Chart{Templates: []*chart.File{{Name: "templates/5000-device-dashboard.yaml"}}}
+
+		Expect(states.Add(ch, "templates/9999-device-dashboard.yaml", nil)).To(BeIdenticalTo(ch))
+	})
+})
+
+var _ = Describe("Step", func() {
+	It("should not share the templates between steps", func() {
+		nostate := chart.Chart{Templates: make([]*chart.File, 1, 4)}
+
+		first := states.Step(nostate, &chart.File{Name: "0000.yaml"})
+		second := states.Step(nostate, &chart.File{Name: "0001.yaml"})
+
+		Expect(first.Templates[1].Name).To(Equal("0000.yaml"))
+		Expect(second.Templates[1].Name).To(Equal("0001.yaml"))
+	})
+})
+
+var _ = Describe("Extract", func() {
+	It("should remove the named templates from the chart", func() {
+		nostate := chart.Chart{
+			Templates: []*chart.File{
+				{Name: "templates/_helpers.tpl"},
+				{Name: "templates/verify-driver.yaml"},
+				{Name: "templates/service.yaml"},
+			},
+		}
+		templates := nostate.Templates
+
+		extracted := states.Extract(&nostate, []string{"templates/verify-driver.yaml", "templates/missing.yaml"})
+
+		Expect(extracted).To(HaveLen(1))
+		Expect(extracted).To(HaveKey("templates/verify-driver.yaml"))
+		Expect(nostate.Templates).To(HaveLen(2))
+		Expect(nostate.Templates[0].Name).To(Equal("templates/_helpers.tpl"))
+		Expect(nostate.Templates[1].Name).To(Equal("templates/service.yaml"))
+		Expect(templates[1].Name).To(Equal("templates/verify-driver.yaml"))
+	})
+})
+
+var _ = Describe("Render", func() {
+	It("should only return the manifests of the template", func() {
+		ch := &chart.Chart{
+			Metadata: &chart.Metadata{Name: "simple-kmod", Version: "0.0.1", APIVersion: chart.APIVersionV2},
+			Templates: []*chart.File{
+				{Name: "templates/0000-driver-container.yaml", Data: []byte("name: {{ .Release.Name }}-{{ .Values.kernel }}")},
+				{Name: "templates/1000-device-plugin.yaml", Data: []byte("name: plugin")},
+			},
+		}
+
+		manifest, err := states.Render(ch, map[string]interface{}{"kernel": "4.18"}, "templates/0000-driver-container.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest).To(Equal("name: simple-kmod-4.18"))
+	})
+})
+
+var _ = Describe("Values", func() {
+	It("should let the runtime information override the set values", func() {
+		ch := &chart.Chart{
+			Metadata: &chart.Metadata{Name: "test"},
+			Values:   map[string]interface{}{"a": "chart", "b": "chart", "c": "chart"},
+		}
+
+		runtimeValues := map[string]interface{}{"c": "runtime"}
+
+		vals, err := states.Values(ch, map[string]interface{}{"b": "set", "c": "set"}, runtimeValues)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(vals).To(HaveKeyWithValue("a", "chart"))
+		Expect(vals).To(HaveKeyWithValue("b", "set"))
+		Expect(vals).To(HaveKeyWithValue("c", "runtime"))
+		Expect(ch.Values).To(HaveKeyWithValue("c", "chart"))
+	})
+})
+
+var _ = Describe("Hash", func() {
+	ch := &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "test", Version: "0.0.1"},
+		Templates: []*chart.File{{Name: "templates/0000-state.yaml", Data: []byte("kind: Pod")}},
+	}
+	values := map[string]interface{}{"a": map[string]interface{}{"b": "c"}}
+
+	inputs := states.Inputs{Namespace: "test", NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""}}
+
+	hashInputs := func(inputs states.Inputs) string {
+		h, err := states.Hash(ch, values, inputs)
+		Expect(err).NotTo(HaveOccurred())
+		return h
+	}
+
+	hash := func(ch *chart.Chart, values map[string]interface{}, extra ...*chart.File) string {
+		h, err := states.Hash(ch, values, inputs, extra...)
+		Expect(err).NotTo(HaveOccurred())
+		return h
+	}
+
+	It("should be stable", func() {
+		Expect(hash(ch, values)).To(Equal(hash(ch, map[string]interface{}{"a": map[string]interface{}{"b": "c"}})))
+	})
+
+	It("should change with the templates, the values and the version", func() {
+		h := hash(ch, values)
+
+		Expect(hash(ch, map[string]interface{}{"a": map[string]interface{}{"b": "d"}})).NotTo(Equal(h))
+		Expect(hash(ch, values, &chart.File{Name: "templates/verify.yaml", Data: []byte("kind: Job")})).NotTo(Equal(h))
+
+		changed := *ch
+		changed.Templates = []*chart.File{{Name: "templates/0000-state.yaml", Data: []byte("kind: Service")}}
+		Expect(hash(&changed, values)).NotTo(Equal(h))
+
+		changed = *ch
+		changed.Metadata = &chart.Metadata{Name: "test", Version: "0.0.2"}
+		Expect(hash(&changed, values)).NotTo(Equal(h))
+	})
+
+	It("should change with the namespace, the node selector and the post-renderer", func() {
+		h := hash(ch, values)
+
+		changed := inputs
+		changed.Namespace = "other"
+		Expect(hashInputs(changed)).NotTo(Equal(h))
+
+		changed = inputs
+		changed.NodeSelector = map[string]string{"node-role.kubernetes.io/infra": ""}
+		Expect(hashInputs(changed)).NotTo(Equal(h))
+
+		changed = inputs
+		changed.PostRenderer = "kustomize"
+		Expect(hashInputs(changed)).NotTo(Equal(h))
+	})
+})
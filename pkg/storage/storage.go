@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
 	"github.com/openshift-psap/special-resource-operator/pkg/utils"
 	v1 "k8s.io/api/core/v1"
@@ -11,6 +13,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	BackendConfigMap = "configmap"
+	BackendCRD       = "crd"
+)
+
 //go:generate mockgen -source=storage.go -package=storage -destination=mock_storage_api.go
 
 type Storage interface {
@@ -19,38 +26,73 @@ type Storage interface {
 	DeleteConfigMapEntry(context.Context, string, types.NamespacedName) error
 }
 
+// backend loads and stores the objects holding the entries.
+type backend interface {
+	// get returns the object backing the store ins and its entries.
+	get(ctx context.Context, ins types.NamespacedName) (client.Object, map[string]string, error)
+	// set replaces the entries of an object returned by get.
+	set(obj client.Object, data map[string]string)
+}
+
 type storage struct {
 	kubeClient clients.ClientsInterface
+	backend    backend
 }
 
+// NewStorage returns a Storage keeping its entries in ConfigMaps.
 func NewStorage(kubeClient clients.ClientsInterface) Storage {
-	return &storage{kubeClient: kubeClient}
+	return &storage{
+		kubeClient: kubeClient,
+		backend:    &configMapBackend{kubeClient: kubeClient},
+	}
+}
+
+// NewCRDStorage returns a Storage keeping its entries in cluster-scoped SpecialResourceStores.
+// The namespace of the stores is ignored.
+func NewCRDStorage(kubeClient clients.ClientsInterface) Storage {
+	return &storage{
+		kubeClient: kubeClient,
+		backend:    &crdBackend{kubeClient: kubeClient},
+	}
+}
+
+// New returns the Storage of the named backend.
+func New(name string, kubeClient clients.ClientsInterface) (Storage, error) {
+	switch name {
+	case BackendConfigMap:
+		return NewStorage(kubeClient), nil
+	case BackendCRD:
+		return NewCRDStorage(kubeClient), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", name)
+	}
 }
 
 func (s *storage) CheckConfigMapEntry(ctx context.Context, key string, ins types.NamespacedName) (string, error) {
-	cm, err := s.getConfigMap(ctx, ins.Namespace, ins.Name)
+	_, data, err := s.backend.get(ctx, ins)
 	if err != nil {
 		return "", err
 	}
 
-	return cm.Data[key], nil
+	return data[key], nil
 }
 
 func (s *storage) UpdateConfigMapEntry(ctx context.Context, key string, value string, ins types.NamespacedName) error {
-	cm, err := s.getConfigMap(ctx, ins.Namespace, ins.Name)
+	obj, data, err := s.backend.get(ctx, ins)
 	if err != nil {
 		utils.WarnOnError(err)
 		return err
 	}
 
-	if cm.Data == nil {
-		cm.Data = make(map[string]string)
+	if data == nil {
+		data = make(map[string]string)
 	}
 
-	if cm.Data[key] != value {
-		cm.Data[key] = value
+	if data[key] != value {
+		data[key] = value
+		s.backend.set(obj, data)
 
-		if err = s.updateObject(ctx, cm); err != nil {
+		if err = s.updateObject(ctx, obj); err != nil {
 			utils.WarnOnError(err)
 			return err
 		}
@@ -60,16 +102,17 @@ func (s *storage) UpdateConfigMapEntry(ctx context.Context, key string, value st
 }
 
 func (s *storage) DeleteConfigMapEntry(ctx context.Context, key string, ins types.NamespacedName) error {
-	cm, err := s.getConfigMap(ctx, ins.Namespace, ins.Name)
+	obj, data, err := s.backend.get(ctx, ins)
 	if err != nil {
 		utils.WarnOnError(err)
 		return err
 	}
 
-	if _, ok := cm.Data[key]; ok {
-		delete(cm.Data, key)
+	if _, ok := data[key]; ok {
+		delete(data, key)
+		s.backend.set(obj, data)
 
-		if err = s.updateObject(ctx, cm); err != nil {
+		if err = s.updateObject(ctx, obj); err != nil {
 			utils.WarnOnError(err)
 			return err
 		}
@@ -78,11 +121,82 @@ func (s *storage) DeleteConfigMapEntry(ctx context.Context, key string, ins type
 	return nil
 }
 
-func (s *storage) getConfigMap(ctx context.Context, namespace string, name string) (*v1.ConfigMap, error) {
+func (s *storage) updateObject(ctx context.Context, obj client.Object) error {
+	return s.kubeClient.Update(ctx, obj)
+}
+
+type configMapBackend struct {
+	kubeClient clients.ClientsInterface
+}
+
+func (b *configMapBackend) get(ctx context.Context, ins types.NamespacedName) (client.Object, map[string]string, error) {
+	cm, err := getConfigMap(ctx, b.kubeClient, ins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cm, cm.Data, nil
+}
+
+func (b *configMapBackend) set(obj client.Object, data map[string]string) {
+	obj.(*v1.ConfigMap).Data = data
+}
+
+type crdBackend struct {
+	kubeClient clients.ClientsInterface
+}
+
+// get creates the store if it does not exist yet, seeded with the entries of
+// the ConfigMap of the same name so that switching backends keeps the state.
+func (b *crdBackend) get(ctx context.Context, ins types.NamespacedName) (client.Object, map[string]string, error) {
+	sto := &v1beta1.SpecialResourceStore{}
+
+	err := b.kubeClient.Get(ctx, types.NamespacedName{Name: ins.Name}, sto)
+	if err == nil {
+		return sto, sto.Data, nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return nil, nil, err
+	}
+
+	sto.SetName(ins.Name)
+
+	if cm, err := getConfigMap(ctx, b.kubeClient, ins); err == nil {
+		sto.Data = cm.Data
+	} else if !apierrors.IsNotFound(err) {
+		return nil, nil, err
+	}
+
+	if err = b.kubeClient.Create(ctx, sto); err != nil {
+		return nil, nil, fmt.Errorf("could not create SpecialResourceStore %s: %w", ins.Name, err)
+	}
+
+	return sto, sto.Data, nil
+}
+
+func (b *crdBackend) set(obj client.Object, data map[string]string) {
+	obj.(*v1beta1.SpecialResourceStore).Data = data
+}
+
+// CreateConfigMap creates the empty ConfigMap ins, for the stores that are not
+// shipped with the operator. It is not an error if it exists already.
+func CreateConfigMap(ctx context.Context, kubeClient clients.ClientsInterface, ins types.NamespacedName) error {
+	cm := &v1.ConfigMap{}
+	cm.SetName(ins.Name)
+	cm.SetNamespace(ins.Namespace)
+
+	if err := kubeClient.Create(ctx, cm); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("could not create ConfigMap %s: %w", ins, err)
+	}
+
+	return nil
+}
+
+func getConfigMap(ctx context.Context, kubeClient clients.ClientsInterface, ins types.NamespacedName) (*v1.ConfigMap, error) {
 	cm := &v1.ConfigMap{}
-	dep := types.NamespacedName{Namespace: namespace, Name: name}
 
-	err := s.kubeClient.Get(ctx, dep, cm)
+	err := kubeClient.Get(ctx, ins, cm)
 
 	if apierrors.IsNotFound(err) {
 		utils.WarnOnError(err)
@@ -91,7 +205,3 @@ func (s *storage) getConfigMap(ctx context.Context, namespace string, name strin
 
 	return cm, err
 }
-
-func (s *storage) updateObject(ctx context.Context, cm client.Object) error {
-	return s.kubeClient.Update(ctx, cm)
-}
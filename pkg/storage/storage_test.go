@@ -7,6 +7,7 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
 	"github.com/openshift-psap/special-resource-operator/pkg/clients"
 	"github.com/openshift-psap/special-resource-operator/pkg/storage"
 	v1 "k8s.io/api/core/v1"
@@ -182,3 +183,94 @@ var _ = Describe("DeleteConfigMapEntry", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 })
+
+var _ = Describe("New", func() {
+	It("should return an error for an unknown backend", func() {
+		_, err := storage.New("etcd", mockClient)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should accept the known backends", func() {
+		for _, backend := range []string{storage.BackendConfigMap, storage.BackendCRD} {
+			_, err := storage.New(backend, mockClient)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
+
+var _ = Describe("CRDStorage", func() {
+	storeNsn := types.NamespacedName{Name: resourceName}
+	storeMatcher := gomock.AssignableToTypeOf(&v1beta1.SpecialResourceStore{})
+	storeNotFound := k8serrors.NewNotFound(v1beta1.GroupVersion.WithResource("specialresourcestores").GroupResource(), resourceName)
+
+	It("should return the expected value with a good store", func() {
+		mockClient.
+			EXPECT().
+			Get(context.TODO(), storeNsn, &v1beta1.SpecialResourceStore{}).
+			Do(func(_ context.Context, _ types.NamespacedName, sto *v1beta1.SpecialResourceStore) {
+				sto.Data = map[string]string{"key": "value"}
+			})
+
+		v, err := storage.NewCRDStorage(mockClient).CheckConfigMapEntry(context.TODO(), "key", nsn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal("value"))
+	})
+
+	It("should create the store from the ConfigMap of the same name", func() {
+		gomock.InOrder(
+			mockClient.EXPECT().Get(context.TODO(), storeNsn, &v1beta1.SpecialResourceStore{}).Return(storeNotFound),
+			mockClient.EXPECT().
+				Get(context.TODO(), nsn, &v1.ConfigMap{}).
+				Do(func(_ context.Context, _ types.NamespacedName, cm *v1.ConfigMap) {
+					cm.Data = map[string]string{"key": "value"}
+				}),
+			mockClient.EXPECT().
+				Create(context.TODO(), storeMatcher).
+				Do(func(_ context.Context, sto *v1beta1.SpecialResourceStore) {
+					Expect(sto.GetName()).To(Equal(resourceName))
+					Expect(sto.GetNamespace()).To(BeEmpty())
+					Expect(sto.Data).To(HaveKeyWithValue("key", "value"))
+				}),
+		)
+
+		v, err := storage.NewCRDStorage(mockClient).CheckConfigMapEntry(context.TODO(), "key", nsn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal("value"))
+	})
+
+	It("should create an empty store without ConfigMap and update it", func() {
+		gomock.InOrder(
+			mockClient.EXPECT().Get(context.TODO(), storeNsn, &v1beta1.SpecialResourceStore{}).Return(storeNotFound),
+			mockClient.EXPECT().Get(context.TODO(), nsn, &v1.ConfigMap{}).Return(notFound),
+			mockClient.EXPECT().Create(context.TODO(), storeMatcher),
+			mockClient.EXPECT().
+				Update(context.TODO(), storeMatcher).
+				Do(func(_ context.Context, sto *v1beta1.SpecialResourceStore) {
+					Expect(sto.Data).To(HaveKeyWithValue("key", "value"))
+				}),
+		)
+
+		err := storage.NewCRDStorage(mockClient).UpdateConfigMapEntry(context.TODO(), "key", "value", nsn)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("CreateConfigMap", func() {
+	It("should create the empty ConfigMap", func() {
+		mockClient.EXPECT().
+			Create(context.TODO(), cmMatcher).
+			Do(func(_ context.Context, cm *v1.ConfigMap) {
+				Expect(cm.GetName()).To(Equal(resourceName))
+				Expect(cm.GetNamespace()).To(Equal(namespaceName))
+				Expect(cm.Data).To(BeEmpty())
+			})
+
+		Expect(storage.CreateConfigMap(context.TODO(), mockClient, nsn)).To(Succeed())
+	})
+
+	It("should not return an error when the ConfigMap already exists", func() {
+		mockClient.EXPECT().Create(context.TODO(), cmMatcher).Return(k8serrors.NewAlreadyExists(v1.Resource("configmap"), resourceName))
+
+		Expect(storage.CreateConfigMap(context.TODO(), mockClient, nsn)).To(Succeed())
+	})
+})
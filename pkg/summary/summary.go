@@ -0,0 +1,165 @@
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Path is where the summary is served, on the metrics server so that it is
+// behind the same RBAC proxy as the metrics.
+const Path = "/summary"
+
+// Alert is something of a SpecialResource that needs the attention of the
+// cluster administrator.
+type Alert struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// Progress counts the states of a SpecialResource completed by its last
+// reconcile.
+type Progress struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+}
+
+// SpecialResource is the summary of a SpecialResource.
+type SpecialResource struct {
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Chart     string    `json:"chart,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	State     string    `json:"state"`
+	Progress  Progress  `json:"progress"`
+	Alerts    []Alert   `json:"alerts,omitempty"`
+	Updated   time.Time `json:"updated"`
+}
+
+type summaryDump struct {
+	SpecialResources []*SpecialResource `json:"specialResources"`
+}
+
+//go:generate mockgen -source=summary.go -package=summary -destination=mock_summary_api.go
+
+// Summary aggregates the SpecialResources for the console plugin. It is
+// updated by the reconciles of every SpecialResource, one at a time.
+type Summary interface {
+	Forget(specialResource string)
+	Handler() http.Handler
+	SetProgress(specialResource string, completed, total int)
+	Update(sr *v1beta1.SpecialResource)
+}
+
+type summary struct {
+	mu               sync.Mutex
+	specialResources map[string]*SpecialResource
+}
+
+func New() Summary {
+	return &summary{
+		specialResources: make(map[string]*SpecialResource),
+	}
+}
+
+func (s *summary) get(specialResource string) *SpecialResource {
+	sr, ok := s.specialResources[specialResource]
+	if !ok {
+		sr = &SpecialResource{Name: specialResource}
+		s.specialResources[specialResource] = sr
+	}
+	return sr
+}
+
+// Forget drops a deleted SpecialResource.
+func (s *summary) Forget(specialResource string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.specialResources, specialResource)
+}
+
+// SetProgress records the number of states of the SpecialResource completed
+// out of the ones of its chart.
+func (s *summary) SetProgress(specialResource string, completed, total int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sr := s.get(specialResource)
+	sr.Progress = Progress{Completed: completed, Total: total}
+	sr.Updated = time.Now().UTC()
+}
+
+// Update refreshes the summary of sr from its spec and status: the chart it
+// runs, its state, and as alerts the last error and the workloads matching no
+// node.
+func (s *summary) Update(sr *v1beta1.SpecialResource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sum := s.get(sr.Name)
+	sum.Namespace = sr.Spec.Namespace
+	sum.Chart = sr.Spec.Chart.Name
+	sum.Version = sr.Spec.Chart.Version
+	if sr.Status.Chart != nil && sr.Status.Chart.Version != "" {
+		sum.Version = sr.Status.Chart.Version
+	}
+	sum.State = sr.Status.State
+	sum.Alerts = alerts(sr)
+	sum.Updated = time.Now().UTC()
+}
+
+func alerts(sr *v1beta1.SpecialResource) []Alert {
+	var alerts []Alert
+
+	if cond := meta.FindStatusCondition(sr.Status.Conditions, v1beta1.SpecialResourceErrored); cond != nil && cond.Status == "True" {
+		alerts = append(alerts, Alert{Reason: cond.Reason, Message: cond.Message})
+	}
+
+	workloads := make([]string, 0)
+	for workload, nodes := range sr.Status.MatchingNodes {
+		if nodes == 0 {
+			workloads = append(workloads, workload)
+		}
+	}
+
+	sort.Strings(workloads)
+
+	for _, workload := range workloads {
+		alerts = append(alerts, Alert{Reason: "NoMatchingNodes", Message: fmt.Sprintf("%s matches no node", workload)})
+	}
+
+	return alerts
+}
+
+func (s *summary) serve(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dump := summaryDump{SpecialResources: make([]*SpecialResource, 0, len(s.specialResources))}
+	for _, sr := range s.specialResources {
+		dump.SpecialResources = append(dump.SpecialResources, sr)
+	}
+
+	sort.Slice(dump.SpecialResources, func(i, j int) bool {
+		return dump.SpecialResources[i].Name < dump.SpecialResources[j].Name
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(dump); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Handler serves the summary of all the SpecialResources as JSON, sorted by
+// name.
+func (s *summary) Handler() http.Handler {
+	return http.HandlerFunc(s.serve)
+}
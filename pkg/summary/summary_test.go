@@ -0,0 +1,85 @@
+package summary_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/summary"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSummary(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Summary Suite")
+}
+
+var _ = Describe("Handler", func() {
+	get := func(s summary.Summary) []summary.SpecialResource {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest("GET", summary.Path, nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var dump struct {
+			SpecialResources []summary.SpecialResource
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &dump)).To(Succeed())
+
+		return dump.SpecialResources
+	}
+
+	newSpecialResource := func(name string) *v1beta1.SpecialResource {
+		sr := &v1beta1.SpecialResource{}
+		sr.SetName(name)
+		sr.Spec.Namespace = name
+		sr.Spec.Chart.Name = name
+		sr.Spec.Chart.Version = "0.0.1"
+
+		return sr
+	}
+
+	It("should list the SpecialResources by name", func() {
+		s := summary.New()
+
+		sr := newSpecialResource("simple-kmod")
+		sr.Status.State = "Ready: "
+		sr.Status.Chart = &v1beta1.ResolvedChart{Name: "simple-kmod", Version: "0.0.2"}
+		s.Update(sr)
+		s.SetProgress("simple-kmod", 2, 2)
+
+		s.Update(newSpecialResource("ping-pong"))
+		s.Update(newSpecialResource("other"))
+		s.Forget("other")
+
+		srs := get(s)
+		Expect(srs).To(HaveLen(2))
+		Expect(srs[0].Name).To(Equal("ping-pong"))
+		Expect(srs[0].Version).To(Equal("0.0.1"))
+		Expect(srs[1].Name).To(Equal("simple-kmod"))
+		Expect(srs[1].Namespace).To(Equal("simple-kmod"))
+		Expect(srs[1].Version).To(Equal("0.0.2"))
+		Expect(srs[1].State).To(Equal("Ready: "))
+		Expect(srs[1].Progress).To(Equal(summary.Progress{Completed: 2, Total: 2}))
+		Expect(srs[1].Alerts).To(BeEmpty())
+	})
+
+	It("should raise alerts for the errors and the workloads matching no node", func() {
+		s := summary.New()
+
+		sr := newSpecialResource("simple-kmod")
+		sr.Status.Conditions = []metav1.Condition{
+			{Type: v1beta1.SpecialResourceErrored, Status: metav1.ConditionTrue, Reason: "FailedToDeployChart", Message: "build failed"},
+		}
+		sr.Status.MatchingNodes = map[string]int32{"DaemonSet simple-kmod/driver": 0, "DaemonSet simple-kmod/plugin": 2}
+		s.Update(sr)
+
+		Expect(get(s)[0].Alerts).To(Equal([]summary.Alert{
+			{Reason: "FailedToDeployChart", Message: "build failed"},
+			{Reason: "NoMatchingNodes", Message: "DaemonSet simple-kmod/driver matches no node"},
+		}))
+	})
+})
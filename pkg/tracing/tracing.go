@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// enabled is set once at startup, spans are no-ops until then
+var enabled int32
+
+var log logr.Logger = zap.New(zap.UseDevMode(true)).WithName(utils.Print("spans", utils.Green))
+
+type spanKey struct{}
+
+// Span is a timed step of a reconcile. Spans started from the context of
+// another span are its children, their name is prefixed with the parent's.
+type Span struct {
+	name          string
+	start         time.Time
+	keysAndValues []interface{}
+}
+
+// Enable turns on span logging. Spans are logged with their duration when they
+// end, they are not exported to a tracing backend.
+func Enable() {
+	atomic.StoreInt32(&enabled, 1)
+}
+
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// Start begins a span and returns a context carrying it. The span is nil if
+// span logging is disabled, End can still be called on it.
+func Start(ctx context.Context, name string, keysAndValues ...interface{}) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+		name = parent.name + "/" + name
+	}
+
+	span := &Span{
+		name:          name,
+		start:         time.Now(),
+		keysAndValues: keysAndValues,
+	}
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Name returns the name of the span, prefixed with the names of its parents.
+func (s *Span) Name() string {
+	if s == nil {
+		return ""
+	}
+	return s.name
+}
+
+// End logs the duration of the span, err is the outcome of the step.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	kv := append([]interface{}{"span", s.name, "duration", time.Since(s.start).String()}, s.keysAndValues...)
+	if err != nil {
+		kv = append(kv, "error", err.Error())
+	}
+
+	log.Info("Span ended", kv...)
+}
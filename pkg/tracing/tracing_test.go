@@ -0,0 +1,39 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/tracing"
+)
+
+func TestTracing(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tracing Suite")
+}
+
+var _ = Describe("Start", func() {
+	It("should not record spans while disabled", func() {
+		ctx, span := tracing.Start(context.TODO(), "reconcile")
+
+		Expect(span).To(BeNil())
+		Expect(ctx).To(Equal(context.TODO()))
+		span.End(errors.New("random error"))
+	})
+
+	It("should nest spans started from the context of another span", func() {
+		tracing.Enable()
+
+		ctx, parent := tracing.Start(context.TODO(), "reconcile", "specialresource", "test")
+		_, child := tracing.Start(ctx, "apply")
+
+		Expect(parent.Name()).To(Equal("reconcile"))
+		Expect(child.Name()).To(Equal("reconcile/apply"))
+
+		child.End(nil)
+		parent.End(nil)
+	})
+})
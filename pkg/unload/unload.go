@@ -0,0 +1,217 @@
+package unload
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/ownership"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	// Taint keeps the driver Pods off a node that is about to reboot, the
+	// DaemonSets do not tolerate it.
+	Taint = "specialresource.openshift.io/unload"
+
+	// The MachineConfig annotations of a node, a reboot is pending when the
+	// desired config or drain differs from the current one.
+	currentConfigAnnotation    = "machineconfiguration.openshift.io/currentConfig"
+	desiredConfigAnnotation    = "machineconfiguration.openshift.io/desiredConfig"
+	desiredDrainAnnotation     = "machineconfiguration.openshift.io/desiredDrain"
+	lastAppliedDrainAnnotation = "machineconfiguration.openshift.io/lastAppliedDrain"
+
+	// gracePeriodMargin is how long a Pod may stay terminating after its grace
+	// period before its unload is considered failed.
+	gracePeriodMargin = time.Minute
+)
+
+// States of the unload of a node.
+const (
+	Pending  = "Pending"
+	Unloaded = "Unloaded"
+	Failed   = "Failed"
+)
+
+//go:generate mockgen -source=unload.go -package=unload -destination=mock_unload_api.go
+
+type Unload interface {
+	Reconcile(ctx context.Context, sr *v1beta1.SpecialResource) error
+}
+
+type unload struct {
+	kubeClient clients.ClientsInterface
+	log        logr.Logger
+}
+
+func New(kubeClient clients.ClientsInterface) Unload {
+	return &unload{
+		kubeClient: kubeClient,
+		log:        zap.New(zap.UseDevMode(true)).WithName(utils.Print("unload", utils.Brown)),
+	}
+}
+
+// RebootPending returns true if the MachineConfig daemon is going to drain and
+// reboot the node.
+func RebootPending(node client.Object) bool {
+	annotations := node.GetAnnotations()
+
+	if annotations[desiredConfigAnnotation] != annotations[currentConfigAnnotation] {
+		return true
+	}
+
+	return annotations[desiredDrainAnnotation] != annotations[lastAppliedDrainAnnotation]
+}
+
+// Reconcile unloads the driver from the nodes of sr that are about to reboot:
+// the nodes are tainted and the Pods of the DaemonSets of sr deleted, so that
+// their preStop hook, e.g. rmmod, runs before the drain. The taint is removed
+// once the node rebooted, when the unload failed, so that the driver is not
+// kept off the node, and when sr is deleted. The state of every node is set in
+// the status of sr.
+func (u *unload) Reconcile(ctx context.Context, sr *v1beta1.SpecialResource) error {
+	nodes, err := u.kubeClient.GetNodesByLabels(ctx, sr.Spec.NodeSelector)
+	if err != nil {
+		return fmt.Errorf("could not get the nodes of %s: %w", sr.Name, err)
+	}
+
+	if sr.GetDeletionTimestamp() != nil {
+		for i := range nodes.Items {
+			if err = u.setTaint(ctx, &nodes.Items[i], false); err != nil {
+				return err
+			}
+		}
+		sr.Status.NodeUnload = nil
+
+		return nil
+	}
+
+	pods := &v1.PodList{}
+	opts := []client.ListOption{
+		client.InNamespace(sr.Spec.Namespace),
+		client.HasLabels{ownership.SpecialResourceOwnedLabel},
+	}
+	if err = u.kubeClient.List(ctx, pods, opts...); err != nil {
+		return fmt.Errorf("could not list the Pods of %s: %w", sr.Name, err)
+	}
+
+	states := make(map[string]string)
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+
+		if !RebootPending(node) {
+			if err = u.setTaint(ctx, node, false); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// A failed unload is not retried before the node rebooted
+		if sr.Status.NodeUnload[node.Name] == Failed {
+			if err = u.setTaint(ctx, node, false); err != nil {
+				return err
+			}
+			states[node.Name] = Failed
+			continue
+		}
+
+		if err = u.setTaint(ctx, node, true); err != nil {
+			return err
+		}
+
+		states[node.Name] = u.unloadNode(ctx, node.Name, pods.Items)
+
+		if states[node.Name] == Failed {
+			if err = u.setTaint(ctx, node, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(states) == 0 {
+		states = nil
+	}
+	sr.Status.NodeUnload = states
+
+	return nil
+}
+
+// unloadNode deletes the DaemonSet Pods running on the node and returns the
+// state of the unload.
+func (u *unload) unloadNode(ctx context.Context, nodeName string, pods []v1.Pod) string {
+	state := Unloaded
+
+	for i := range pods {
+		pod := &pods[i]
+
+		if pod.Spec.NodeName != nodeName || !ownedByDaemonSet(pod) {
+			continue
+		}
+
+		if deleted := pod.GetDeletionTimestamp(); deleted != nil {
+			if time.Since(deleted.Time) > gracePeriodMargin {
+				u.log.Info("Pod did not terminate", "node", nodeName, "pod", pod.Name)
+				return Failed
+			}
+			state = Pending
+			continue
+		}
+
+		u.log.Info("Unloading", "node", nodeName, "pod", pod.Name)
+
+		if err := u.kubeClient.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			u.log.Error(err, "could not delete Pod", "node", nodeName, "pod", pod.Name)
+			return Failed
+		}
+		state = Pending
+	}
+
+	return state
+}
+
+func (u *unload) setTaint(ctx context.Context, node *v1.Node, tainted bool) error {
+	taints := make([]v1.Taint, 0, len(node.Spec.Taints)+1)
+	found := false
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == Taint {
+			found = true
+			continue
+		}
+		taints = append(taints, taint)
+	}
+
+	if found == tainted {
+		return nil
+	}
+
+	if tainted {
+		taints = append(taints, v1.Taint{Key: Taint, Value: "true", Effect: v1.TaintEffectNoSchedule})
+	}
+	node.Spec.Taints = taints
+
+	u.log.Info("Updating node", "node", node.Name, Taint, tainted)
+
+	if err := u.kubeClient.Update(ctx, node); err != nil {
+		return fmt.Errorf("could not update node %s: %w", node.Name, err)
+	}
+
+	return nil
+}
+
+func ownedByDaemonSet(pod *v1.Pod) bool {
+	for _, owner := range pod.GetOwnerReferences() {
+		if owner.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
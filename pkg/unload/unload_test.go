@@ -0,0 +1,196 @@
+package unload_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/clients"
+	"github.com/openshift-psap/special-resource-operator/pkg/unload"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	ctrl       *gomock.Controller
+	mockClient *clients.MockClientsInterface
+)
+
+func TestUnload(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = clients.NewMockClientsInterface(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "Unload Suite")
+}
+
+func node(name, current, desired string, taints ...v1.Taint) v1.Node {
+	return v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				"machineconfiguration.openshift.io/currentConfig": current,
+				"machineconfiguration.openshift.io/desiredConfig": desired,
+			},
+		},
+		Spec: v1.NodeSpec{Taints: taints},
+	}
+}
+
+func driverPod(name, nodeName string) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "simple-kmod",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "simple-kmod-driver-container"}},
+		},
+		Spec: v1.PodSpec{NodeName: nodeName},
+	}
+}
+
+var _ = Describe("RebootPending", func() {
+	It("should compare the current and the desired config", func() {
+		n := node("worker-0", "rendered-worker-1", "rendered-worker-1")
+		Expect(unload.RebootPending(&n)).To(BeFalse())
+
+		n = node("worker-0", "rendered-worker-1", "rendered-worker-2")
+		Expect(unload.RebootPending(&n)).To(BeTrue())
+	})
+
+	It("should compare the desired and the last applied drain", func() {
+		n := node("worker-0", "rendered-worker-1", "rendered-worker-1")
+		n.Annotations["machineconfiguration.openshift.io/desiredDrain"] = "drain-rendered-worker-2"
+		n.Annotations["machineconfiguration.openshift.io/lastAppliedDrain"] = "uncordon-rendered-worker-1"
+
+		Expect(unload.RebootPending(&n)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Reconcile", func() {
+	var sr *v1beta1.SpecialResource
+
+	BeforeEach(func() {
+		sr = &v1beta1.SpecialResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "simple-kmod"},
+			Spec:       v1beta1.SpecialResourceSpec{Namespace: "simple-kmod"},
+		}
+	})
+
+	expectNodesAndPods := func(nodes []v1.Node, pods []v1.Pod) {
+		mockClient.EXPECT().GetNodesByLabels(context.Background(), sr.Spec.NodeSelector).Return(&v1.NodeList{Items: nodes}, nil)
+		mockClient.EXPECT().List(context.Background(), gomock.AssignableToTypeOf(&v1.PodList{}), gomock.Any()).
+			Do(func(_ context.Context, list *v1.PodList, _ ...client.ListOption) {
+				list.Items = pods
+			})
+	}
+
+	It("should taint the rebooting node and delete its driver Pods", func() {
+		rebooting := node("worker-0", "rendered-worker-1", "rendered-worker-2")
+		pod := driverPod("driver-0", "worker-0")
+
+		expectNodesAndPods(
+			[]v1.Node{rebooting, node("worker-1", "rendered-worker-1", "rendered-worker-1")},
+			[]v1.Pod{pod, driverPod("driver-1", "worker-1")},
+		)
+
+		mockClient.EXPECT().Update(context.Background(), gomock.AssignableToTypeOf(&v1.Node{})).
+			Do(func(_ context.Context, obj client.Object) {
+				n := obj.(*v1.Node)
+				Expect(n.Name).To(Equal("worker-0"))
+				Expect(n.Spec.Taints).To(ConsistOf(v1.Taint{Key: unload.Taint, Value: "true", Effect: v1.TaintEffectNoSchedule}))
+			})
+		mockClient.EXPECT().Delete(context.Background(), &pod)
+
+		Expect(unload.New(mockClient).Reconcile(context.Background(), sr)).To(Succeed())
+		Expect(sr.Status.NodeUnload).To(Equal(map[string]string{"worker-0": unload.Pending}))
+	})
+
+	It("should report the node as unloaded once the Pods are gone", func() {
+		taint := v1.Taint{Key: unload.Taint, Value: "true", Effect: v1.TaintEffectNoSchedule}
+
+		expectNodesAndPods([]v1.Node{node("worker-0", "rendered-worker-1", "rendered-worker-2", taint)}, nil)
+
+		Expect(unload.New(mockClient).Reconcile(context.Background(), sr)).To(Succeed())
+		Expect(sr.Status.NodeUnload).To(Equal(map[string]string{"worker-0": unload.Unloaded}))
+	})
+
+	It("should report the node as failed if a Pod does not terminate", func() {
+		pod := driverPod("driver-0", "worker-0")
+		pod.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+
+		expectNodesAndPods([]v1.Node{node("worker-0", "rendered-worker-1", "rendered-worker-2")}, []v1.Pod{pod})
+		gomock.InOrder(
+			mockClient.EXPECT().Update(context.Background(), gomock.AssignableToTypeOf(&v1.Node{})).
+				Do(func(_ context.Context, obj client.Object) {
+					Expect(obj.(*v1.Node).Spec.Taints).To(HaveLen(1))
+				}),
+			mockClient.EXPECT().Update(context.Background(), gomock.AssignableToTypeOf(&v1.Node{})).
+				Do(func(_ context.Context, obj client.Object) {
+					Expect(obj.(*v1.Node).Spec.Taints).To(BeEmpty())
+				}),
+		)
+
+		Expect(unload.New(mockClient).Reconcile(context.Background(), sr)).To(Succeed())
+		Expect(sr.Status.NodeUnload).To(Equal(map[string]string{"worker-0": unload.Failed}))
+	})
+
+	It("should not unload a failed node again before it rebooted", func() {
+		sr.Status.NodeUnload = map[string]string{"worker-0": unload.Failed}
+		expectNodesAndPods([]v1.Node{node("worker-0", "rendered-worker-1", "rendered-worker-2")}, []v1.Pod{driverPod("driver-0", "worker-0")})
+
+		Expect(unload.New(mockClient).Reconcile(context.Background(), sr)).To(Succeed())
+		Expect(sr.Status.NodeUnload).To(Equal(map[string]string{"worker-0": unload.Failed}))
+	})
+
+	It("should remove the taint when the SpecialResource is deleted", func() {
+		taint := v1.Taint{Key: unload.Taint, Value: "true", Effect: v1.TaintEffectNoSchedule}
+
+		sr.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		sr.Status.NodeUnload = map[string]string{"worker-0": unload.Unloaded}
+		mockClient.EXPECT().GetNodesByLabels(context.Background(), sr.Spec.NodeSelector).
+			Return(&v1.NodeList{Items: []v1.Node{node("worker-0", "rendered-worker-1", "rendered-worker-2", taint)}}, nil)
+
+		mockClient.EXPECT().Update(context.Background(), gomock.AssignableToTypeOf(&v1.Node{})).
+			Do(func(_ context.Context, obj client.Object) {
+				Expect(obj.(*v1.Node).Spec.Taints).To(BeEmpty())
+			})
+
+		Expect(unload.New(mockClient).Reconcile(context.Background(), sr)).To(Succeed())
+		Expect(sr.Status.NodeUnload).To(BeNil())
+	})
+
+	It("should remove the taint once the node rebooted", func() {
+		other := v1.Taint{Key: "node-role.kubernetes.io/master", Effect: v1.TaintEffectNoSchedule}
+		taint := v1.Taint{Key: unload.Taint, Value: "true", Effect: v1.TaintEffectNoSchedule}
+
+		sr.Status.NodeUnload = map[string]string{"worker-0": unload.Unloaded}
+		expectNodesAndPods([]v1.Node{node("worker-0", "rendered-worker-2", "rendered-worker-2", other, taint)}, nil)
+
+		mockClient.EXPECT().Update(context.Background(), gomock.AssignableToTypeOf(&v1.Node{})).
+			Do(func(_ context.Context, obj client.Object) {
+				Expect(obj.(*v1.Node).Spec.Taints).To(ConsistOf(other))
+			})
+
+		Expect(unload.New(mockClient).Reconcile(context.Background(), sr)).To(Succeed())
+		Expect(sr.Status.NodeUnload).To(BeNil())
+	})
+
+	It("should fail if the nodes cannot be listed", func() {
+		mockClient.EXPECT().GetNodesByLabels(context.Background(), gomock.Any()).Return(nil, errors.New("random error"))
+
+		Expect(unload.New(mockClient).Reconcile(context.Background(), sr)).NotTo(Succeed())
+	})
+})
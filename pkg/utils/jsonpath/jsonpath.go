@@ -0,0 +1,198 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse splits path into the keys of the fields it selects. The path may be
+// wrapped in braces and start with $, keys are separated by dots or written in
+// brackets, quoted or not. A backslash escapes the next character, e.g. the
+// dots of a label:
+//
+//	{.metadata.labels['feature.node.kubernetes.io/kernel-version.full']}
+//	metadata.labels.feature\.node\.kubernetes\.io/kernel-version\.full
+//	spec.template.spec.containers[0].image
+func Parse(path string) ([]string, error) {
+	p := path
+
+	if strings.HasPrefix(p, "{") {
+		if !strings.HasSuffix(p, "}") {
+			return nil, fmt.Errorf("jsonpath %q: unbalanced braces", path)
+		}
+		p = p[1 : len(p)-1]
+	}
+
+	p = strings.TrimPrefix(p, "$")
+
+	keys := make([]string, 0)
+
+	var key strings.Builder
+	inKey := false
+	afterDot := false
+
+	flush := func() {
+		if inKey {
+			keys = append(keys, key.String())
+			key.Reset()
+			inKey = false
+		}
+	}
+
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+
+		switch c {
+		case '\\':
+			if i+1 == len(p) {
+				return nil, fmt.Errorf("jsonpath %q: trailing escape", path)
+			}
+			i++
+			key.WriteByte(p[i])
+			inKey = true
+			afterDot = false
+
+		case '.':
+			if afterDot {
+				return nil, fmt.Errorf("jsonpath %q: empty key", path)
+			}
+			flush()
+			afterDot = true
+
+		case '[':
+			flush()
+
+			k, n, err := parseBracket(p[i:])
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath %q: %w", path, err)
+			}
+			keys = append(keys, k)
+			i += n - 1
+			afterDot = false
+
+		case ']', '\'', '"':
+			return nil, fmt.Errorf("jsonpath %q: unexpected %q", path, c)
+
+		default:
+			key.WriteByte(c)
+			inKey = true
+			afterDot = false
+		}
+	}
+
+	if afterDot && len(keys) > 0 {
+		return nil, fmt.Errorf("jsonpath %q: trailing dot", path)
+	}
+
+	flush()
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jsonpath %q: no key", path)
+	}
+
+	return keys, nil
+}
+
+// parseBracket returns the key of the bracket s starts with and the length of
+// the bracket.
+func parseBracket(s string) (string, int, error) {
+	if len(s) > 1 && (s[1] == '\'' || s[1] == '"') {
+		end := strings.IndexByte(s[2:], s[1])
+		if end == -1 || len(s) < end+4 || s[end+3] != ']' {
+			return "", 0, fmt.Errorf("unterminated quoted key %s", s)
+		}
+		return s[2 : end+2], end + 4, nil
+	}
+
+	end := strings.IndexByte(s, ']')
+	if end == -1 {
+		return "", 0, fmt.Errorf("unterminated bracket %s", s)
+	}
+
+	key := s[1:end]
+	if key == "" || strings.ContainsAny(key, "['\"") {
+		return "", 0, fmt.Errorf("invalid key %q", key)
+	}
+
+	return key, end + 1, nil
+}
+
+// Format is the reverse of Parse, it returns the path of keys.
+func Format(keys []string) string {
+	var b strings.Builder
+
+	for _, key := range keys {
+		if key == "" {
+			b.WriteString("['']")
+			continue
+		}
+
+		b.WriteByte('.')
+		for i := 0; i < len(key); i++ {
+			if strings.IndexByte(`\.[]'"{}$`, key[i]) != -1 {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(key[i])
+		}
+	}
+
+	return b.String()
+}
+
+// Get returns the value of the field at path in obj, with its JSON type:
+// string, bool, int64, float64, []interface{} or map[string]interface{}.
+// Numeric keys select the elements of lists.
+func Get(obj map[string]interface{}, path string) (interface{}, bool, error) {
+	keys, err := Parse(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var value interface{} = obj
+
+	for i, key := range keys {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var found bool
+			if value, found = v[key]; !found {
+				return nil, false, nil
+			}
+
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, false, fmt.Errorf("jsonpath %q: %s is a list, %q is not an index", path, Format(keys[:i]), key)
+			}
+			if index < 0 || index >= len(v) {
+				return nil, false, nil
+			}
+			value = v[index]
+
+		default:
+			return nil, false, fmt.Errorf("jsonpath %q: %s is of the type %T, not an object", path, Format(keys[:i]), value)
+		}
+	}
+
+	return value, true, nil
+}
+
+// GetString returns the field at path formatted as a string, e.g. "true" for
+// a bool. Objects and lists cannot be formatted.
+func GetString(obj map[string]interface{}, path string) (string, bool, error) {
+	value, found, err := Get(obj, path)
+	if err != nil || !found {
+		return "", found, err
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", true, nil
+	case string:
+		return v, true, nil
+	case bool, int64, int, float64:
+		return fmt.Sprint(v), true, nil
+	default:
+		return "", true, fmt.Errorf("jsonpath %q: value of the type %T is not a scalar", path, value)
+	}
+}
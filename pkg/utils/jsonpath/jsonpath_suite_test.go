@@ -0,0 +1,13 @@
+package jsonpath_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestJSONPath(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "jsonpath tests")
+}
@@ -0,0 +1,164 @@
+package jsonpath_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils/jsonpath"
+)
+
+var node = map[string]interface{}{
+	"metadata": map[string]interface{}{
+		"name": "worker-0",
+		"labels": map[string]interface{}{
+			"feature.node.kubernetes.io/kernel-version.full": "4.18.0-305.el8.x86_64",
+		},
+	},
+	"spec": map[string]interface{}{
+		"unschedulable": true,
+		"taints": []interface{}{
+			map[string]interface{}{"key": "unload", "effect": "NoSchedule"},
+		},
+	},
+	"status": map[string]interface{}{
+		"capacity": map[string]interface{}{"pods": int64(250)},
+		"ratio":    0.5,
+		"phase":    nil,
+	},
+}
+
+var _ = Describe("Parse", func() {
+	DescribeTable("should split the path into keys",
+		func(path string, keys ...string) {
+			Expect(jsonpath.Parse(path)).To(Equal(keys))
+		},
+		Entry("dots", "metadata.name", "metadata", "name"),
+		Entry("leading dot", ".metadata.name", "metadata", "name"),
+		Entry("braces and root", "{$.metadata.name}", "metadata", "name"),
+		Entry("quoted bracket", "{.metadata.labels['feature.node.kubernetes.io/kernel-version.full']}",
+			"metadata", "labels", "feature.node.kubernetes.io/kernel-version.full"),
+		Entry("double quoted bracket", `metadata.labels["a.b/c"]`, "metadata", "labels", "a.b/c"),
+		Entry("escaped dots", `metadata.labels.a\.b/c`, "metadata", "labels", "a.b/c"),
+		Entry("index", "spec.containers[0].image", "spec", "containers", "0", "image"),
+		Entry("empty quoted key", "data['']", "data", ""),
+	)
+
+	DescribeTable("should reject invalid paths",
+		func(path string) {
+			_, err := jsonpath.Parse(path)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("root only", "{$}"),
+		Entry("unbalanced braces", "{.metadata"),
+		Entry("empty key", "metadata..name"),
+		Entry("trailing dot", "metadata."),
+		Entry("trailing escape", `metadata\`),
+		Entry("unterminated bracket", "metadata[name"),
+		Entry("unterminated quote", "metadata['name]"),
+		Entry("empty bracket", "metadata[]"),
+		Entry("stray quote", "meta'data"),
+	)
+})
+
+var _ = Describe("Format", func() {
+	It("should escape the keys", func() {
+		keys := []string{"metadata", "labels", "a.b/c", "", "[x]"}
+
+		path := jsonpath.Format(keys)
+		Expect(path).To(Equal(`.metadata.labels.a\.b/c[''].\[x\]`))
+		Expect(jsonpath.Parse(path)).To(Equal(keys))
+	})
+})
+
+var _ = Describe("Get", func() {
+	DescribeTable("should return typed values",
+		func(path string, expected interface{}) {
+			v, found, err := jsonpath.Get(node, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(v).To(Equal(expected))
+		},
+		Entry("string", "metadata.labels['feature.node.kubernetes.io/kernel-version.full']", "4.18.0-305.el8.x86_64"),
+		Entry("bool", "spec.unschedulable", true),
+		Entry("int", "status.capacity.pods", int64(250)),
+		Entry("float", "status.ratio", 0.5),
+		Entry("list element", "spec.taints[0].key", "unload"),
+	)
+
+	It("should not find missing fields", func() {
+		for _, path := range []string{"metadata.namespace", "spec.taints[1].key", "spec.taints[-1]"} {
+			_, found, err := jsonpath.Get(node, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse(), path)
+		}
+	})
+
+	It("should fail to select the fields of a scalar or a list", func() {
+		_, _, err := jsonpath.Get(node, "metadata.name.first")
+		Expect(err).To(HaveOccurred())
+
+		_, _, err = jsonpath.Get(node, "spec.taints.key")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetString", func() {
+	DescribeTable("should format scalars",
+		func(path, expected string) {
+			s, found, err := jsonpath.GetString(node, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(s).To(Equal(expected))
+		},
+		Entry("string", "metadata.name", "worker-0"),
+		Entry("bool", "spec.unschedulable", "true"),
+		Entry("int", "status.capacity.pods", "250"),
+		Entry("null", "status.phase", ""),
+	)
+
+	It("should not format objects", func() {
+		_, _, err := jsonpath.GetString(node, "metadata.labels")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"metadata.name",
+		"{$.metadata.labels['feature.node.kubernetes.io/kernel-version.full']}",
+		`metadata.labels.a\.b/c`,
+		"spec.containers[0].image",
+		`data[""]`,
+		"{.a[",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, path string) {
+		keys, err := jsonpath.Parse(path)
+		if err != nil {
+			return
+		}
+
+		formatted := jsonpath.Format(keys)
+
+		again, err := jsonpath.Parse(formatted)
+		if err != nil {
+			t.Fatalf("Parse(%q) of Format(%q) failed: %v", formatted, keys, err)
+		}
+
+		if len(again) != len(keys) {
+			t.Fatalf("Parse(Format(%q)) = %q", keys, again)
+		}
+		for i := range keys {
+			if again[i] != keys[i] {
+				t.Fatalf("Parse(Format(%q)) = %q", keys, again)
+			}
+		}
+
+		// Must not panic
+		_, _, _ = jsonpath.Get(node, path)
+	})
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodeSelectorFields is the path of the nodeSelector of the Pods of a kind
+var NodeSelectorFields = map[string][]string{
+	"Pod":         {"spec", "nodeSelector"},
+	"Build":       {"spec", "nodeSelector"},
+	"BuildConfig": {"spec", "nodeSelector"},
+	"DaemonSet":   {"spec", "template", "spec", "nodeSelector"},
+	"Deployment":  {"spec", "template", "spec", "nodeSelector"},
+	"StatefulSet": {"spec", "template", "spec", "nodeSelector"},
+	"Job":         {"spec", "template", "spec", "nodeSelector"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template", "spec", "nodeSelector"},
+}
+
+// NodeReady tells whether node can run workloads: its Ready condition is true
+// and it is not cordoned.
+func NodeReady(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
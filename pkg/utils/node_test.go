@@ -0,0 +1,32 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("NodeReady", func() {
+	node := func(unschedulable bool, conditions ...corev1.NodeCondition) *corev1.Node {
+		n := &corev1.Node{}
+		n.Spec.Unschedulable = unschedulable
+		n.Status.Conditions = conditions
+		return n
+	}
+
+	ready := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue}
+	notReady := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionFalse}
+	pressure := corev1.NodeCondition{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse}
+
+	DescribeTable(
+		"should only accept the ready and schedulable nodes",
+		func(n *corev1.Node, m types.GomegaMatcher) {
+			Expect(NodeReady(n)).To(m)
+		},
+		Entry("ready", node(false, pressure, ready), BeTrue()),
+		Entry("not ready", node(false, notReady), BeFalse()),
+		Entry("cordoned", node(true, ready), BeFalse()),
+		Entry("no Ready condition", node(false, pressure), BeFalse()),
+	)
+})
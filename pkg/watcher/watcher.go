@@ -0,0 +1,104 @@
+package watcher
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/go-logr/logr"
+	srov1beta1 "github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/debug"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var ErrNoController = errors.New("the controller is not set up yet")
+
+//go:generate mockgen -source=watcher.go -package=watcher -destination=mock_watcher_api.go
+
+// Watcher adds watches to the SpecialResource controller for the kinds a chart
+// creates that the controller does not own statically, e.g. Routes or
+// ServiceMonitors, so that deleting such an object triggers a reconcile.
+type Watcher interface {
+	SetController(c controller.Controller, prct predicate.Predicate, watched []schema.GroupVersionKind)
+	Watch(gvk schema.GroupVersionKind) error
+}
+
+type watcher struct {
+	mu         sync.Mutex
+	log        logr.Logger
+	debug      debug.Debug
+	controller controller.Controller
+	predicate  predicate.Predicate
+	watched    map[schema.GroupKind]bool
+	kinds      []string
+}
+
+func New(dbg debug.Debug) Watcher {
+	return &watcher{
+		log:     zap.New(zap.UseDevMode(true)).WithName(utils.Print("watcher", utils.Green)),
+		debug:   dbg,
+		watched: make(map[schema.GroupKind]bool),
+	}
+}
+
+// SetController sets the controller the watches are added to, along with the
+// kinds it already watches and the predicate filtering the events.
+func (w *watcher) SetController(c controller.Controller, prct predicate.Predicate, watched []schema.GroupVersionKind) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.controller = c
+	w.predicate = prct
+
+	for _, gvk := range watched {
+		w.add(gvk)
+	}
+
+	w.debug.SetWatches(w.kinds)
+}
+
+// Watch makes sure the objects of kind gvk owned by a SpecialResource are
+// watched, the watch of a kind is only added once.
+func (w *watcher) Watch(gvk schema.GroupVersionKind) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.watched[gvk.GroupKind()] {
+		return nil
+	}
+
+	if w.controller == nil {
+		return ErrNoController
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+
+	err := w.controller.Watch(
+		&source.Kind{Type: obj},
+		&handler.EnqueueRequestForOwner{OwnerType: &srov1beta1.SpecialResource{}, IsController: true},
+		w.predicate)
+	if err != nil {
+		return err
+	}
+
+	w.log.Info("Watching", "kind", gvk.String())
+
+	w.add(gvk)
+	w.debug.SetWatches(w.kinds)
+
+	return nil
+}
+
+func (w *watcher) add(gvk schema.GroupVersionKind) {
+	if !w.watched[gvk.GroupKind()] {
+		w.watched[gvk.GroupKind()] = true
+		w.kinds = append(w.kinds, gvk.Kind)
+	}
+}
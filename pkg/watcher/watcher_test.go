@@ -0,0 +1,88 @@
+package watcher_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/pkg/debug"
+	"github.com/openshift-psap/special-resource-operator/pkg/watcher"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var (
+	ctrl      *gomock.Controller
+	mockDebug *debug.MockDebug
+)
+
+func TestWatcher(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockDebug = debug.NewMockDebug(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "Watcher Suite")
+}
+
+// fakeController records the kinds it is asked to watch.
+type fakeController struct {
+	kinds []schema.GroupVersionKind
+}
+
+func (f *fakeController) Reconcile(context.Context, reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+func (f *fakeController) Watch(src source.Source, _ handler.EventHandler, _ ...predicate.Predicate) error {
+	f.kinds = append(f.kinds, src.(*source.Kind).Type.(*unstructured.Unstructured).GroupVersionKind())
+	return nil
+}
+
+func (f *fakeController) Start(context.Context) error {
+	return nil
+}
+
+func (f *fakeController) GetLogger() logr.Logger {
+	return logr.Discard()
+}
+
+var _ = Describe("Watch", func() {
+	pod := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	route := schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+	It("should fail before the controller is set", func() {
+		Expect(watcher.New(mockDebug).Watch(route)).To(MatchError(watcher.ErrNoController))
+	})
+
+	It("should only watch the kinds that are not watched yet", func() {
+		c := &fakeController{}
+		w := watcher.New(mockDebug)
+
+		gomock.InOrder(
+			mockDebug.EXPECT().SetWatches([]string{"Pod"}),
+			mockDebug.EXPECT().SetWatches([]string{"Pod", "Route"}),
+		)
+
+		w.SetController(c, predicate.Funcs{}, []schema.GroupVersionKind{pod})
+
+		Expect(w.Watch(pod)).To(Succeed())
+		Expect(w.Watch(route)).To(Succeed())
+		Expect(w.Watch(schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1beta1", Kind: "Route"})).To(Succeed())
+
+		Expect(c.kinds).To(Equal([]schema.GroupVersionKind{route}))
+	})
+})
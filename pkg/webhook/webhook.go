@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/registry"
+	"github.com/openshift-psap/special-resource-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Path is where the mutating webhook of the SpecialResources is served.
+const Path = "/mutate-sro-openshift-io-v1beta1-specialresource"
+
+// DefaultNodeSelector selects the nodes of a SpecialResource without nodeSelector.
+var DefaultNodeSelector = map[string]string{"node-role.kubernetes.io/worker": ""}
+
+// +kubebuilder:webhook:path=/mutate-sro-openshift-io-v1beta1-specialresource,mutating=true,failurePolicy=ignore,sideEffects=None,groups=sro.openshift.io,resources=specialresources,verbs=create;update,versions=v1beta1,name=mspecialresource.sro.openshift.io,admissionReviewVersions=v1
+
+//go:generate mockgen -source=webhook.go -package=webhook -destination=mock_webhook_api.go
+
+type Defaulter interface {
+	Default(ctx context.Context, obj runtime.Object) error
+}
+
+type defaulter struct {
+	helmer   helmer.Helmer
+	log      logr.Logger
+	registry registry.Registry
+}
+
+func NewDefaulter(helmer helmer.Helmer, registry registry.Registry) Defaulter {
+	return &defaulter{
+		helmer:   helmer,
+		log:      zap.New(zap.UseDevMode(true)).WithName(utils.Print("webhook", utils.Purple)),
+		registry: registry,
+	}
+}
+
+// NewWebhook returns the mutating webhook defaulting the SpecialResources.
+func NewWebhook(d Defaulter) *admission.Webhook {
+	return admission.WithCustomDefaulter(&v1beta1.SpecialResource{}, d)
+}
+
+// Default fills the fields omitted from a SpecialResource:
+//   - the namespace is the name of the SpecialResource
+//   - the nodeSelector is DefaultNodeSelector
+//   - the chart version is the latest one of the repository
+//   - the image and *Image values of set are pinned to their digest
+//
+// The chart and image lookups are best effort, the SpecialResource is
+// admitted without those defaults if they fail.
+func (d *defaulter) Default(ctx context.Context, obj runtime.Object) error {
+	sr, ok := obj.(*v1beta1.SpecialResource)
+	if !ok {
+		return fmt.Errorf("expected a SpecialResource, got %T", obj)
+	}
+
+	if sr.Spec.Namespace == "" {
+		sr.Spec.Namespace = sr.Name
+	}
+
+	if len(sr.Spec.NodeSelector) == 0 {
+		sr.Spec.NodeSelector = make(map[string]string, len(DefaultNodeSelector))
+		for k, v := range DefaultNodeSelector {
+			sr.Spec.NodeSelector[k] = v
+		}
+	}
+
+	// Only the index of the repository is fetched, the chart is not loaded
+	// during the admission. The latest version may not be the one a pinned
+	// digest belongs to, which must be set.
+	if sr.Spec.Manifests == nil && sr.Spec.Chart.Name != "" && sr.Spec.Chart.Version == "" && sr.Spec.Chart.Digest == "" {
+		latest := sr.Spec.Chart
+		latest.UpgradePolicy = helmerv1beta1.UpgradeLatest
+
+		if version, err := d.helmer.LatestVersion(ctx, latest); err != nil {
+			d.log.Info("Could not default the chart version", "specialresource", sr.Name, "error", err)
+		} else {
+			sr.Spec.Chart.Version = version
+		}
+	}
+
+	if sr.Spec.Set.Object != nil {
+		d.pinImages(ctx, sr.Name, sr.Spec.Set.Object)
+	}
+
+	return nil
+}
+
+// pinImages replaces the tags of the image and *Image values of values by the
+// digests they point to.
+func (d *defaulter) pinImages(ctx context.Context, name string, values map[string]interface{}) {
+	for key, value := range values {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			d.pinImages(ctx, name, v)
+		case string:
+			if !isImageKey(key) || v == "" || strings.Contains(v, "@") {
+				continue
+			}
+
+			pinned, err := d.registry.Digest(ctx, v)
+			if err != nil {
+				d.log.Info("Could not pin image", "specialresource", name, "image", v, "error", err)
+				continue
+			}
+			values[key] = pinned
+		}
+	}
+}
+
+func isImageKey(key string) bool {
+	return key == "image" || strings.HasSuffix(key, "Image")
+}
@@ -0,0 +1,133 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-psap/special-resource-operator/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/helmer"
+	helmerv1beta1 "github.com/openshift-psap/special-resource-operator/pkg/helmer/api/v1beta1"
+	"github.com/openshift-psap/special-resource-operator/pkg/registry"
+	"github.com/openshift-psap/special-resource-operator/pkg/webhook"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	ctrl         *gomock.Controller
+	mockHelmer   *helmer.MockHelmer
+	mockRegistry *registry.MockRegistry
+)
+
+func TestWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockHelmer = helmer.NewMockHelmer(ctrl)
+		mockRegistry = registry.NewMockRegistry(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	RunSpecs(t, "Webhook Suite")
+}
+
+var _ = Describe("Default", func() {
+	var sr *v1beta1.SpecialResource
+
+	BeforeEach(func() {
+		sr = &v1beta1.SpecialResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "simple-kmod"},
+			Spec: v1beta1.SpecialResourceSpec{
+				Chart: helmerv1beta1.HelmChart{Name: "simple-kmod", Version: "0.0.1"},
+			},
+		}
+	})
+
+	It("should default the namespace and the nodeSelector", func() {
+		Expect(webhook.NewDefaulter(mockHelmer, mockRegistry).Default(context.Background(), sr)).To(Succeed())
+
+		Expect(sr.Spec.Namespace).To(Equal("simple-kmod"))
+		Expect(sr.Spec.NodeSelector).To(Equal(map[string]string{"node-role.kubernetes.io/worker": ""}))
+	})
+
+	It("should keep the fields that are set", func() {
+		sr.Spec.Namespace = "driver"
+		sr.Spec.NodeSelector = map[string]string{"feature.node.kubernetes.io/pci-10de.present": "true"}
+
+		Expect(webhook.NewDefaulter(mockHelmer, mockRegistry).Default(context.Background(), sr)).To(Succeed())
+
+		Expect(sr.Spec.Namespace).To(Equal("driver"))
+		Expect(sr.Spec.NodeSelector).To(Equal(map[string]string{"feature.node.kubernetes.io/pci-10de.present": "true"}))
+	})
+
+	It("should default the chart version to the latest one", func() {
+		sr.Spec.Chart.Version = ""
+
+		latest := sr.Spec.Chart
+		latest.UpgradePolicy = helmerv1beta1.UpgradeLatest
+
+		mockHelmer.EXPECT().LatestVersion(gomock.Any(), latest).Return("0.0.2", nil)
+
+		Expect(webhook.NewDefaulter(mockHelmer, mockRegistry).Default(context.Background(), sr)).To(Succeed())
+		Expect(sr.Spec.Chart.Version).To(Equal("0.0.2"))
+	})
+
+	It("should admit the SpecialResource if the chart version cannot be found", func() {
+		sr.Spec.Chart.Version = ""
+
+		mockHelmer.EXPECT().LatestVersion(gomock.Any(), gomock.Any()).Return("", errors.New("random error"))
+
+		Expect(webhook.NewDefaulter(mockHelmer, mockRegistry).Default(context.Background(), sr)).To(Succeed())
+		Expect(sr.Spec.Chart.Version).To(BeEmpty())
+	})
+
+	It("should not default the version of a chart pinned to a digest", func() {
+		sr.Spec.Chart.Version = ""
+		sr.Spec.Chart.Digest = "sha256:0123"
+
+		Expect(webhook.NewDefaulter(mockHelmer, mockRegistry).Default(context.Background(), sr)).To(Succeed())
+		Expect(sr.Spec.Chart.Version).To(BeEmpty())
+	})
+
+	It("should pin the images to their digest", func() {
+		const pinned = "quay.io/org/driver@sha256:0123"
+
+		sr.Spec.Set = unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"image": "quay.io/org/driver:latest",
+				"operator": map[string]interface{}{
+					"sidecarImage": "quay.io/org/sidecar@sha256:4567",
+					"pluginImage":  "quay.io/org/plugin:v1",
+				},
+				"message": "quay.io/org/driver:latest",
+			},
+		}
+
+		mockRegistry.EXPECT().Digest(context.Background(), "quay.io/org/driver:latest").Return(pinned, nil)
+		mockRegistry.EXPECT().Digest(context.Background(), "quay.io/org/plugin:v1").Return("", errors.New("random error"))
+
+		Expect(webhook.NewDefaulter(mockHelmer, mockRegistry).Default(context.Background(), sr)).To(Succeed())
+
+		Expect(sr.Spec.Set.Object).To(Equal(map[string]interface{}{
+			"image": pinned,
+			"operator": map[string]interface{}{
+				"sidecarImage": "quay.io/org/sidecar@sha256:4567",
+				"pluginImage":  "quay.io/org/plugin:v1",
+			},
+			"message": "quay.io/org/driver:latest",
+		}))
+	})
+
+	It("should fail for other objects", func() {
+		Expect(webhook.NewDefaulter(mockHelmer, mockRegistry).Default(context.Background(), &v1.Pod{})).NotTo(Succeed())
+	})
+})